@@ -0,0 +1,61 @@
+// Command fxload imports exchange rates into the exchange_rates table. Rates
+// that already exist for a date are replaced, so files can be reloaded:
+//
+//	go run ./cmd/fxload -file rates.csv
+//	go run ./cmd/fxload -file eurofxref-hist.xml
+//	go run ./cmd/fxload -file rates.txt -format ecb
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shaikhjunaidx/pennywise-backend/db"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/fx"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+)
+
+func main() {
+	file := flag.String("file", "", "path to the rate file")
+	format := flag.String("format", "", "file format, csv or ecb (default: from the file extension)")
+	flag.Parse()
+
+	if *file == "" {
+		log.Fatal("Missing -file")
+	}
+	if *format == "" {
+		*format = "csv"
+		if strings.EqualFold(filepath.Ext(*file), ".xml") {
+			*format = "ecb"
+		}
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Could not open %s: %v", *file, err)
+	}
+	defer f.Close()
+
+	var rates []models.ExchangeRate
+	switch *format {
+	case "csv":
+		rates, err = fx.ParseCSV(f)
+	case "ecb":
+		rates, err = fx.ParseECBXML(f)
+	default:
+		log.Fatalf("Unknown -format %q: expected csv or ecb", *format)
+	}
+	if err != nil {
+		log.Fatalf("Could not read %s: %v", *file, err)
+	}
+
+	database := db.InitDB()
+	if err := fx.NewRateRepository(database).Upsert(rates); err != nil {
+		log.Fatalf("Could not store exchange rates: %v", err)
+	}
+
+	log.Printf("Loaded %d exchange rates from %s", len(rates), *file)
+}
@@ -1,17 +1,26 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"time"
+	// Embed the time zone database so that users' time zones resolve even
+	// where the host has none installed.
+	_ "time/tzdata"
 
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	"github.com/shaikhjunaidx/pennywise-backend/db"
 	_ "github.com/shaikhjunaidx/pennywise-backend/docs"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/budget"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/recurring"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/routes"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/scheduler"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -22,10 +31,20 @@ func main() {
 
 	fmt.Println("Connected to the database:", database.Name())
 
+	checkBudgetDrift(database)
+	startScheduler(database)
+
 	router := mux.NewRouter()
 
 	routes.SetupUserRoutes(router, database)
 	routes.SetupTransactionRoutes(router, database)
+	routes.SetupTransferRoutes(router, database)
+	routes.SetupImportRoutes(router, database)
+	routes.SetupExportRoutes(router, database)
+	routes.SetupReportRoutes(router, database)
+	routes.SetupAccountRoutes(router, database)
+	routes.SetupRecurringRoutes(router, database)
+	routes.SetupInsightRoutes(router, database)
 	routes.SetupCategoryRoutes(router, database)
 	routes.SetupBudgetRoutes(router, database)
 
@@ -51,3 +70,42 @@ func main() {
 
 	select {}
 }
+
+// checkBudgetDrift compares stored budgets with the transactions ledger when
+// RECONCILE_ON_STARTUP is "report" or "repair". Drift is only logged unless
+// repair is requested.
+func checkBudgetDrift(database *gorm.DB) {
+	mode := os.Getenv("RECONCILE_ON_STARTUP")
+	if mode != "report" && mode != "repair" {
+		return
+	}
+
+	service := routes.NewBudgetService(database)
+	report, err := service.ReconcileAll(mode == "repair")
+	if err != nil {
+		log.Printf("Budget reconciliation failed: %v", err)
+		return
+	}
+
+	log.Printf("Budget reconciliation: %s", report.Summary())
+}
+
+// startScheduler runs background jobs such as the monthly budget rollover
+// every SCHEDULER_INTERVAL (default one hour). Set it to "off" when the jobs
+// are run from cron instead.
+func startScheduler(database *gorm.DB) {
+	interval := time.Hour
+	if value := os.Getenv("SCHEDULER_INTERVAL"); value == "off" {
+		return
+	} else if value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			log.Fatalf("Invalid SCHEDULER_INTERVAL %q", value)
+		}
+		interval = parsed
+	}
+
+	budgetService := routes.NewBudgetService(database)
+	recurringService := routes.NewRecurringService(database)
+	scheduler.New(interval, budget.NewRolloverJob(budgetService), recurring.NewMaterializeJob(recurringService)).Start(context.Background())
+}
@@ -0,0 +1,36 @@
+// Command reconcile compares every budget with the transactions ledger and
+// reports any drift in the stored spent amounts. Run with -repair to rewrite
+// the drifted budgets.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/shaikhjunaidx/pennywise-backend/db"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/budget"
+)
+
+func main() {
+	repair := flag.Bool("repair", false, "rewrite budgets whose spent amount does not match the ledger")
+	flag.Parse()
+
+	database := db.InitDB()
+	service := budget.NewBudgetService(budget.NewBudgetRepository(database), nil)
+
+	report, err := service.ReconcileAll(*repair)
+	if err != nil {
+		log.Fatalf("Budget reconciliation failed: %v", err)
+	}
+
+	for _, drift := range report.Drifts {
+		log.Printf("Budget %d (user %d, %s/%d): recorded %s, ledger %s",
+			drift.BudgetID, drift.UserID, drift.BudgetMonth, drift.BudgetYear, drift.RecordedSpent, drift.LedgerSpent)
+	}
+	log.Printf("Budget reconciliation: %s", report.Summary())
+
+	if len(report.Drifts) > 0 && !*repair {
+		os.Exit(1)
+	}
+}
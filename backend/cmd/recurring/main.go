@@ -0,0 +1,27 @@
+// Command recurring books the recurring transactions that have fallen due.
+// It is safe to run repeatedly, e.g. hourly from cron when the API's
+// scheduler is off:
+//
+//	go run ./cmd/recurring
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/db"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/routes"
+)
+
+func main() {
+	database := db.InitDB()
+	service := routes.NewRecurringService(database)
+
+	result, err := service.MaterializeDue(time.Now())
+	if result != nil {
+		log.Printf("Recurring transactions: %d booked, %d skipped", result.Booked, result.Skipped)
+	}
+	if err != nil {
+		log.Fatalf("Booking recurring transactions failed: %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+// Command rollover creates the month's category budgets from the previous
+// month, carrying over remainders where budgets ask for it. Without -month it
+// rolls each user into the month that has begun in their own time zone. It is
+// safe to run repeatedly, e.g. hourly from cron:
+//
+//	go run ./cmd/rollover              # current month of each user
+//	go run ./cmd/rollover -month 2024-10
+package main
+
+import (
+	"flag"
+	"log"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/db"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/budget"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/routes"
+)
+
+func main() {
+	monthFlag := flag.String("month", "", "month to create budgets for, as YYYY-MM")
+	flag.Parse()
+
+	var period time.Time
+	if *monthFlag != "" {
+		parsed, err := time.Parse("2006-01", *monthFlag)
+		if err != nil {
+			log.Fatalf("Invalid -month %q: expected YYYY-MM", *monthFlag)
+		}
+		period = parsed
+	}
+
+	database := db.InitDB()
+	service := routes.NewBudgetService(database)
+
+	if period.IsZero() {
+		result, err := service.RolloverDue(time.Now())
+		if err != nil {
+			log.Fatalf("Budget rollover failed: %v", err)
+		}
+		logResult(result)
+		return
+	}
+
+	result, err := service.RolloverMonth(period.Format("01"), period.Year())
+	if err != nil {
+		log.Fatalf("Budget rollover failed: %v", err)
+	}
+	logResult(result)
+}
+
+func logResult(result *budget.RolloverResult) {
+	log.Printf("Budget rollover for %s/%d: %d created, %d updated, %d already rolled over, %d not yet due",
+		result.BudgetMonth, result.BudgetYear, result.Created, result.Updated, result.Skipped, result.Pending)
+}
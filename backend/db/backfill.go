@@ -0,0 +1,64 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/constants"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/user"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"gorm.io/gorm"
+)
+
+// columnBackfill fills a column for rows that existed before AutoMigrate
+// added it, where the column's zero value is not the right one.
+type columnBackfill struct {
+	model  interface{}
+	column string
+	update string
+}
+
+var columnBackfills = []columnBackfill{
+	// Every existing user starts on the default base currency, which is also
+	// the default transaction currency, so amounts carry over as is.
+	{&models.Transaction{}, "base_amount", "UPDATE transactions SET base_amount = amount"},
+	{&models.UserSettings{}, "duplicate_window_days", fmt.Sprintf("UPDATE user_settings SET duplicate_window_days = %d", user.DefaultDuplicateWindowDays)},
+	// Money received used to be stored as a negative amount. It becomes
+	// income with a positive amount, which budgets no longer count, so
+	// budgets should be reconciled afterwards (RECONCILE_ON_STARTUP=repair).
+	{&models.Transaction{}, "type", "UPDATE transactions SET type = CASE WHEN amount < 0 THEN 'income' ELSE 'expense' END, base_amount = ABS(base_amount), amount = ABS(amount)"},
+	{&models.UserSettings{}, "first_day_of_week", fmt.Sprintf("UPDATE user_settings SET first_day_of_week = %d", user.DefaultFirstDayOfWeek)},
+}
+
+// incomeCategoryRepairs move income out of expense categories, where the
+// backfill of transaction types left money received before types existed.
+// Categories that only ever received money become income categories, and
+// income in any other expense category moves to the user's Income category,
+// which is created where needed. They run on every start and change nothing
+// once the data is consistent.
+var incomeCategoryRepairs = []string{
+	"UPDATE categories SET kind = 'income' WHERE kind = 'expense' " +
+		"AND id IN (SELECT category_id FROM transactions WHERE type = 'income') " +
+		"AND id NOT IN (SELECT category_id FROM transactions WHERE type <> 'income')",
+	fmt.Sprintf("INSERT INTO categories (user_id, name, description, kind, created_at, updated_at) "+
+		"SELECT DISTINCT transactions.user_id, '%[1]s', 'Default category for income', 'income', NOW(), NOW() FROM transactions "+
+		"JOIN categories ON categories.id = transactions.category_id "+
+		"WHERE transactions.type = 'income' AND categories.kind = 'expense' AND NOT EXISTS "+
+		"(SELECT 1 FROM categories income WHERE income.user_id = transactions.user_id AND income.name = '%[1]s' AND income.kind = 'income')",
+		constants.DefaultIncomeCategoryName),
+	fmt.Sprintf("UPDATE transactions JOIN categories ON categories.id = transactions.category_id "+
+		"JOIN categories income ON income.user_id = transactions.user_id AND income.name = '%s' AND income.kind = 'income' "+
+		"SET transactions.category_id = income.id WHERE transactions.type = 'income' AND categories.kind = 'expense'",
+		constants.DefaultIncomeCategoryName),
+}
+
+// pendingBackfills returns the backfills whose column does not exist yet. It
+// has to run before AutoMigrate creates the columns.
+func pendingBackfills(db *gorm.DB) []columnBackfill {
+	var pending []columnBackfill
+	for _, backfill := range columnBackfills {
+		if db.Migrator().HasTable(backfill.model) && !db.Migrator().HasColumn(backfill.model, backfill.column) {
+			pending = append(pending, backfill)
+		}
+	}
+	return pending
+}
@@ -5,7 +5,9 @@ import (
 	"log"
 	"os"
 
+	"github.com/shaikhjunaidx/pennywise-backend/internal/budget"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/config"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
 	"github.com/shaikhjunaidx/pennywise-backend/models"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -74,7 +76,32 @@ func connectToDatabase(cfg *config.Config) *gorm.DB {
 }
 
 func applyMigrations(db *gorm.DB) {
-	if err := db.AutoMigrate(&models.User{}, &models.Category{}, &models.Transaction{}, &models.Budget{}); err != nil {
+	if err := migrateMoneyColumns(db); err != nil {
+		log.Fatalf("Could not migrate money columns: %v", err)
+	}
+
+	pending := pendingBackfills(db)
+
+	if err := db.AutoMigrate(&models.User{}, &models.Category{}, &models.Transaction{}, &models.TransactionSplit{}, &models.Budget{}, &models.PasswordResetToken{}, &models.UserSettings{}, &models.ExchangeRate{}, &models.ImportMapping{}, &models.Account{}, &models.Reconciliation{}, &models.RecurringRule{}, &models.RecurringOccurrence{}); err != nil {
 		log.Fatalf("Could not migrate database schema: %v", err)
 	}
+
+	for _, backfill := range pending {
+		if err := db.Exec(backfill.update).Error; err != nil {
+			log.Fatalf("Could not backfill %s: %v", backfill.column, err)
+		}
+	}
+
+	for _, repair := range incomeCategoryRepairs {
+		if err := db.Exec(repair).Error; err != nil {
+			log.Fatalf("Could not move income out of expense categories: %v", err)
+		}
+	}
+
+	if err := transaction.EnsureSearchIndex(db); err != nil {
+		log.Fatalf("Could not create transaction search index: %v", err)
+	}
+	if err := budget.EnsureUniqueIndex(db); err != nil {
+		log.Fatalf("Could not create budget period index: %v", err)
+	}
 }
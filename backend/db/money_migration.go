@@ -0,0 +1,86 @@
+package db
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// moneyColumns lists the columns that used to hold DOUBLE amounts in major
+// units and now hold BIGINT minor units.
+var moneyColumns = map[string][]string{
+	"transactions": {"amount"},
+	"budgets":      {"amount_limit", "spent_amount", "remaining_amount", "carry_over_amount"},
+}
+
+// migrateMoneyColumns converts existing floating point amounts to integer
+// minor units. It runs before AutoMigrate so that AutoMigrate never narrows a
+// DOUBLE column holding major units straight to BIGINT.
+//
+// Each column is copied into a temporary "<column>_minor" column and then
+// swapped in with a single ALTER TABLE, so an interrupted run can be resumed
+// without scaling any value twice.
+func migrateMoneyColumns(db *gorm.DB) error {
+	for table, columns := range moneyColumns {
+		if !db.Migrator().HasTable(table) {
+			continue
+		}
+
+		for _, column := range columns {
+			if err := migrateMoneyColumn(db, table, column); err != nil {
+				return fmt.Errorf("migrating %s.%s to minor units: %w", table, column, err)
+			}
+		}
+	}
+	return nil
+}
+
+func migrateMoneyColumn(db *gorm.DB, table, column string) error {
+	minorColumn := column + "_minor"
+	hasMinor := db.Migrator().HasColumn(table, minorColumn)
+
+	if !hasMinor {
+		floating, err := isFloatingColumn(db, table, column)
+		if err != nil || !floating {
+			return err
+		}
+
+		if err := db.Exec(fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN `%s` BIGINT NULL", table, minorColumn)).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := db.Exec(fmt.Sprintf("UPDATE `%s` SET `%s` = ROUND(`%s` * 100) WHERE `%s` IS NULL",
+		table, minorColumn, column, minorColumn)).Error; err != nil {
+		return err
+	}
+
+	if err := db.Exec(fmt.Sprintf("ALTER TABLE `%s` DROP COLUMN `%s`, RENAME COLUMN `%s` TO `%s`",
+		table, column, minorColumn, column)).Error; err != nil {
+		return err
+	}
+
+	log.Printf("Converted %s.%s to minor units", table, column)
+	return nil
+}
+
+func isFloatingColumn(db *gorm.DB, table, column string) (bool, error) {
+	columnTypes, err := db.Migrator().ColumnTypes(table)
+	if err != nil {
+		return false, err
+	}
+
+	for _, columnType := range columnTypes {
+		if columnType.Name() != column {
+			continue
+		}
+		switch strings.ToUpper(columnType.DatabaseTypeName()) {
+		case "DOUBLE", "FLOAT", "DECIMAL", "REAL":
+			return true, nil
+		}
+		return false, nil
+	}
+	return false, nil
+}
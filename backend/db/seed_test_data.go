@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 
+	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
 	"github.com/shaikhjunaidx/pennywise-backend/models"
 	"github.com/shaikhjunaidx/pennywise-backend/utils"
 )
@@ -94,11 +95,9 @@ func SeedTestDataAndVerify() {
 		{"category_id": 3, "amount": 200.0, "description": "Groceries Transaction", "transaction_date": "2024-09-01T00:00:00Z"},
 	}
 
-	for _, transaction := range transactions {
-		_, err := utils.MakeAPICall("POST", "http://localhost:8080/api/transactions", token, transaction)
-		if err != nil {
-			log.Fatalf("Error creating transaction: %v", err)
-		}
+	_, err = utils.MakeAPICall("POST", "http://localhost:8080/api/imports/commit", token, map[string]interface{}{"rows": transactions})
+	if err != nil {
+		log.Fatalf("Error importing transactions: %v", err)
 	}
 
 	resp, err = utils.MakeAPICall("GET", "http://localhost:8080/api/transactions", token, nil)
@@ -106,7 +105,7 @@ func SeedTestDataAndVerify() {
 		log.Fatalf("Error fetching transactions: %v", err)
 	}
 
-	var fetchedTransactions []models.Transaction
+	var fetchedTransactions transaction.TransactionPage
 	if err := json.Unmarshal(resp, &fetchedTransactions); err != nil {
 		log.Fatalf("Error decoding transactions: %v", err)
 	}
@@ -15,26 +15,33 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/budgets": {
+        "/api/accounts": {
             "get": {
-                "description": "Retrieves all budgets associated with the authenticated user.",
+                "description": "Retrieves the authenticated user's accounts with their current balances: the opening balance plus every transaction booked to the account.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "budgets"
+                    "accounts"
                 ],
-                "summary": "Get Budgets for User",
+                "summary": "Get Accounts",
                 "responses": {
                     "200": {
-                        "description": "List of Budgets",
+                        "description": "Accounts",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Budget"
+                                "$ref": "#/definitions/account.AccountBalance"
                             }
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -45,7 +52,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Creates a new budget for a user, either overall or for a specific category.",
+                "description": "Creates an account. The type is checking (the default), savings, credit_card or cash, and the currency defaults to the user's base currency. A credit card that is owed money has a negative balance.",
                 "consumes": [
                     "application/json"
                 ],
@@ -53,29 +60,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "budgets"
+                    "accounts"
                 ],
-                "summary": "Create Budget",
+                "summary": "Create Account",
                 "parameters": [
                     {
-                        "description": "Budget Data",
-                        "name": "budget",
+                        "description": "Account",
+                        "name": "account",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.BudgetRequest"
+                            "$ref": "#/definitions/handlers.AccountRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created Budget",
+                        "description": "Created account",
                         "schema": {
-                            "$ref": "#/definitions/models.Budget"
+                            "$ref": "#/definitions/models.Account"
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload",
+                        "description": "Invalid account",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -91,34 +105,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/budgets/category/{categoryID}": {
+        "/api/accounts/{id}": {
             "get": {
-                "description": "Retrieves the budget for the specified category ID for the current month and year for the logged-in user.",
+                "description": "Retrieves an account with its current balance. The account must belong to the authenticated user.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "budgets"
+                    "accounts"
                 ],
-                "summary": "Get Budget by Category ID",
+                "summary": "Get Account By ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Category ID",
-                        "name": "categoryID",
+                        "description": "Account ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Budget",
+                        "description": "Account",
                         "schema": {
-                            "$ref": "#/definitions/models.Budget"
+                            "$ref": "#/definitions/account.AccountBalance"
                         }
                     },
                     "400": {
-                        "description": "Invalid Category ID",
+                        "description": "Invalid account ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -131,8 +145,15 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Account belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "404": {
-                        "description": "Budget not found",
+                        "description": "Account not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -146,36 +167,67 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/budgets/category/{categoryID}/history": {
-            "get": {
-                "description": "Retrieves the last 4 months of budget and spending for the given category.",
+            },
+            "put": {
+                "description": "Replaces an account's name, type, currency and opening balance. The currency cannot change once transactions are booked to the account.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "budgets"
+                    "accounts"
                 ],
-                "summary": "Get Budget History by Category",
+                "summary": "Update Account",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Category ID",
-                        "name": "categoryID",
+                        "description": "Account ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Account",
+                        "name": "account",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AccountRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Budget History",
+                        "description": "Updated account",
                         "schema": {
-                            "$ref": "#/definitions/budget.CategoryBudgetHistoryResponse"
+                            "$ref": "#/definitions/models.Account"
                         }
                     },
                     "400": {
-                        "description": "Invalid Category ID",
+                        "description": "Invalid account",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Account belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Account not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -189,23 +241,52 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/budgets/overall": {
-            "get": {
-                "description": "Retrieves the overall budget, including all categories, for the authenticated user.",
-                "produces": [
-                    "application/json"
-                ],
+            },
+            "delete": {
+                "description": "Deletes an account and its reconciliations. Accounts with transactions cannot be deleted.",
                 "tags": [
-                    "budgets"
+                    "accounts"
+                ],
+                "summary": "Delete Account",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get Overall Budget",
                 "responses": {
-                    "200": {
-                        "description": "Overall Budget",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid account ID, or the account has transactions",
                         "schema": {
-                            "$ref": "#/definitions/models.Budget"
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Account belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Account not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
@@ -218,41 +299,67 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/budgets/{id}": {
-            "get": {
-                "description": "Retrieves a budget by its ID.",
+        "/api/accounts/{id}/reconcile": {
+            "post": {
+                "description": "Compares a statement's ending balance with the account's balance at the end of the statement date. When they agree the account is marked reconciled through that date; otherwise nothing is saved and the response shows the difference and the transactions since the last reconciliation.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "budgets"
+                    "accounts"
                 ],
-                "summary": "Get Budget by ID",
+                "summary": "Reconcile Account",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Budget ID",
+                        "description": "Account ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Statement ending date and balance",
+                        "name": "statement",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReconcileRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Budget",
+                        "description": "Reconciliation result",
                         "schema": {
-                            "$ref": "#/definitions/models.Budget"
+                            "$ref": "#/definitions/account.ReconcileResult"
                         }
                     },
                     "400": {
-                        "description": "Invalid Budget ID",
+                        "description": "Invalid date, or before the last reconciliation",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Account belongs to another user",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "404": {
-                        "description": "Budget not found",
+                        "description": "Account not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -266,53 +373,60 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Updates an existing budget by ID.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/accounts/{id}/reconciliations": {
+            "get": {
+                "description": "Retrieves the statements an account has been reconciled against, latest first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "budgets"
+                    "accounts"
                 ],
-                "summary": "Update Budget",
+                "summary": "Get Account Reconciliations",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Budget ID",
+                        "description": "Account ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated Budget Data",
-                        "name": "budget",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.UpdateBudgetRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated Budget",
+                        "description": "Reconciliations",
                         "schema": {
-                            "$ref": "#/definitions/models.Budget"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Reconciliation"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload",
+                        "description": "Invalid account ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Account belongs to another user",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "404": {
-                        "description": "Budget not found",
+                        "description": "Account not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -326,38 +440,72 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Deletes a budget by its ID.",
+            }
+        },
+        "/api/accounts/{id}/register": {
+            "get": {
+                "description": "Retrieves the transactions booked to an account, oldest first, each with the balance of the account after it. The optional from and to dates are days in the user's time zone and limit the register to that range.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "budgets"
+                    "accounts"
                 ],
-                "summary": "Delete Budget",
+                "summary": "Get Account Register",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Budget ID",
+                        "description": "Account ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day, as YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, as YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "Register",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/account.RegisterEntry"
+                            }
+                        }
                     },
                     "400": {
-                        "description": "Invalid Budget ID",
+                        "description": "Invalid account ID or date",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Account belongs to another user",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "404": {
-                        "description": "Budget not found",
+                        "description": "Account not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -373,23 +521,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/categories": {
+        "/api/budgets": {
             "get": {
-                "description": "Retrieves all categories for the authenticated user.",
+                "description": "Retrieves the authenticated user's budgets for their current month, in their time zone.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "budgets"
                 ],
-                "summary": "Get All Categories",
+                "summary": "Get Budgets for User",
                 "responses": {
                     "200": {
-                        "description": "List of Categories",
+                        "description": "List of Budgets",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Category"
+                                "$ref": "#/definitions/models.Budget"
                             }
                         }
                     },
@@ -403,7 +551,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Creates a new category for transactions and budgets.",
+                "description": "Creates a new budget for a user, either overall or for a specific category. A budget for a category with subcategories counts their spending too.",
                 "consumes": [
                     "application/json"
                 ],
@@ -411,29 +559,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "budgets"
                 ],
-                "summary": "Create Category",
+                "summary": "Create Budget",
                 "parameters": [
                     {
-                        "description": "Category",
-                        "name": "category",
+                        "description": "Budget Data",
+                        "name": "budget",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.CategoryRequest"
+                            "$ref": "#/definitions/handlers.BudgetRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created Category",
+                        "description": "Created Budget",
                         "schema": {
-                            "$ref": "#/definitions/models.Category"
+                            "$ref": "#/definitions/models.Budget"
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload",
+                        "description": "Invalid request payload or month",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "A budget for the category and month already exists",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -449,30 +604,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/categories/{id}": {
+        "/api/budgets/category/{categoryID}": {
             "get": {
-                "description": "Retrieves a category by its ID.",
+                "description": "Retrieves the budget for the specified category ID for the logged-in user's current month, in their time zone.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "budgets"
                 ],
-                "summary": "Get Category by ID",
+                "summary": "Get Budget by Category ID",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "Category ID",
-                        "name": "id",
+                        "name": "categoryID",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Category",
+                        "description": "Budget",
                         "schema": {
-                            "$ref": "#/definitions/models.Category"
+                            "$ref": "#/definitions/models.Budget"
                         }
                     },
                     "400": {
@@ -482,8 +637,15 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "404": {
-                        "description": "Category not found",
+                        "description": "Budget not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -497,52 +659,36 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Updates an existing category by ID.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/budgets/category/{categoryID}/history": {
+            "get": {
+                "description": "Retrieves the last 4 months of budget and spending for the given category, with the history of each of its subcategories nested under children. A category's spending includes that of its subcategories.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "budgets"
                 ],
-                "summary": "Update Category",
+                "summary": "Get Budget History by Category",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "Category ID",
-                        "name": "id",
+                        "name": "categoryID",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Category",
-                        "name": "category",
-                        "in": "body",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.CategoryRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated Category",
+                        "description": "Budget History",
                         "schema": {
-                            "$ref": "#/definitions/models.Category"
+                            "$ref": "#/definitions/budget.CategoryBudgetHistoryResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "404": {
-                        "description": "Category not found",
+                        "description": "Invalid Category ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -556,41 +702,23 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Deletes a category by its ID.",
+            }
+        },
+        "/api/budgets/overall": {
+            "get": {
+                "description": "Retrieves the overall budget, including all categories, for the authenticated user.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
-                ],
-                "summary": "Delete Category",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Category ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "budgets"
                 ],
+                "summary": "Get Overall Budget",
                 "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "400": {
-                        "description": "Invalid Category ID",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "404": {
-                        "description": "Category not found",
+                    "200": {
+                        "description": "Overall Budget",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.Budget"
                         }
                     },
                     "500": {
@@ -603,42 +731,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/login": {
+        "/api/budgets/reconcile": {
             "post": {
-                "description": "Authenticates a user and returns a JWT token.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Recomputes spent amounts from the transactions ledger and reports budgets that have drifted. Pass repair=true to fix them.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "budgets"
                 ],
-                "summary": "User Login",
+                "summary": "Reconcile Budgets",
                 "parameters": [
                     {
-                        "description": "Login Data",
-                        "name": "loginData",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.LoginRequest"
-                        }
+                        "type": "boolean",
+                        "description": "Rewrite drifted budgets",
+                        "name": "repair",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "token",
+                        "description": "Reconciliation report",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/budget.ReconciliationReport"
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload",
+                        "description": "Invalid repair flag",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -650,89 +769,66 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "/api/signup": {
-            "post": {
-                "description": "Registers a new user with the given username, email, and password.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/budgets/{id}": {
+            "get": {
+                "description": "Retrieves a budget by its ID. The budget must belong to the authenticated user.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "budgets"
                 ],
-                "summary": "User Registration",
+                "summary": "Get Budget by ID",
                 "parameters": [
                     {
-                        "description": "Sign Up Data",
-                        "name": "signupData",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.SignUpRequest"
-                        }
+                        "type": "integer",
+                        "description": "Budget ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created User",
+                    "200": {
+                        "description": "Budget",
                         "schema": {
-                            "$ref": "#/definitions/handlers.UserResponse"
+                            "$ref": "#/definitions/models.Budget"
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload",
+                        "description": "Invalid Budget ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
-                    }
-                }
-            }
-        },
-        "/api/transactions": {
-            "get": {
-                "description": "Retrieves a list of transactions for the authenticated user, with optional filtering by date, category, etc.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "transactions"
-                ],
-                "summary": "Get Transactions",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "user_id",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "List of Transactions",
+                    },
+                    "403": {
+                        "description": "Budget belongs to another user",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Transaction"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Invalid or missing user ID",
+                    "404": {
+                        "description": "Budget not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -747,8 +843,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "Creates a new transaction for the authenticated user, linking it to a specific category.",
+            "put": {
+                "description": "Updates an existing budget by ID. The budget must belong to the authenticated user.",
                 "consumes": [
                     "application/json"
                 ],
@@ -756,25 +852,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "budgets"
                 ],
-                "summary": "Create Transaction",
+                "summary": "Update Budget",
                 "parameters": [
                     {
-                        "description": "Transaction Data",
-                        "name": "transaction",
+                        "type": "integer",
+                        "description": "Budget ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Budget Data",
+                        "name": "budget",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.TransactionRequest"
+                            "$ref": "#/definitions/handlers.UpdateBudgetRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created Transaction",
+                    "200": {
+                        "description": "Updated Budget",
                         "schema": {
-                            "$ref": "#/definitions/models.Transaction"
+                            "$ref": "#/definitions/models.Budget"
                         }
                     },
                     "400": {
@@ -784,6 +887,27 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Budget belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Budget not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -792,39 +916,107 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/transactions/category/{category_id}": {
-            "get": {
-                "description": "Retrieves all transactions associated with a specific category for the authenticated user.",
+            },
+            "delete": {
+                "description": "Deletes a budget by its ID. The budget must belong to the authenticated user.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "budgets"
                 ],
-                "summary": "Get Transactions by Category ID",
+                "summary": "Delete Budget",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Category ID",
-                        "name": "category_id",
+                        "description": "Budget ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "List of Transactions",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid Budget ID",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Transaction"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Budget belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Budget not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/budgets/{id}/rollover": {
+            "put": {
+                "description": "Controls whether unspent (positive) and overspent (negative) amounts are carried into next month's budget when it is created.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "budgets"
+                ],
+                "summary": "Update Budget Rollover",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Budget ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rollover settings",
+                        "name": "rollover",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RolloverRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated Budget",
+                        "schema": {
+                            "$ref": "#/definitions/models.Budget"
                         }
                     },
                     "400": {
-                        "description": "Invalid Category ID",
+                        "description": "Invalid request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -837,8 +1029,22 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Budget belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Budget not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
-                        "description": "Failed to retrieve transactions",
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -847,23 +1053,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/transactions/weekly": {
+        "/api/categories": {
             "get": {
-                "description": "Retrieves the weekly spending for the authenticated user.",
+                "description": "Retrieves all categories for the authenticated user as a tree: the top-level categories, each with its subcategories nested under children.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "categories"
                 ],
-                "summary": "Get Weekly Spending (Past 6 weeks)",
+                "summary": "Get All Categories",
                 "responses": {
                     "200": {
-                        "description": "Weekly Spending",
+                        "description": "Category tree",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/transaction.WeeklySpending"
+                                "$ref": "#/definitions/category.CategoryNode"
                             }
                         }
                     },
@@ -875,22 +1081,75 @@ const docTemplate = `{
                         }
                     }
                 }
+            },
+            "post": {
+                "description": "Creates a new category for transactions and budgets. The kind is expense (the default) or income, and is fixed once the category exists. Give parent_id to file the category under another of the same kind, such as Groceries under Food; a subcategory takes its parent's kind by default. Creating a category that already exists with the same parent and kind returns it; one with that name elsewhere is a conflict.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Create Category",
+                "parameters": [
+                    {
+                        "description": "Category",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created Category",
+                        "schema": {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload, kind or parent",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "A category with this name exists under another parent or with another kind",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
             }
         },
-        "/api/transactions/{id}": {
+        "/api/categories/{id}": {
             "get": {
-                "description": "Retrieves a single transaction by its ID for the authenticated user.",
+                "description": "Retrieves a category by its ID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "categories"
                 ],
-                "summary": "Get Transaction by ID",
+                "summary": "Get Category by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Transaction ID",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -898,20 +1157,20 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Transaction data",
+                        "description": "Category",
                         "schema": {
-                            "$ref": "#/definitions/models.Transaction"
+                            "$ref": "#/definitions/models.Category"
                         }
                     },
                     "400": {
-                        "description": "Invalid transaction ID",
+                        "description": "Invalid Category ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "404": {
-                        "description": "Transaction not found",
+                        "description": "Category not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -927,7 +1186,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Updates an existing transaction, allowing changes to the amount, category, description, or date.",
+                "description": "Updates an existing category by ID. parent_id files it under another category of the same kind, and a parent_id of 0 moves it to the top level; leaving parent_id out keeps the category where it is. A category cannot be moved under itself or one of its subcategories. Budgets of the categories it leaves and joins are recalculated along with the move.",
                 "consumes": [
                     "application/json"
                 ],
@@ -935,43 +1194,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transactions"
+                    "categories"
                 ],
-                "summary": "Update Transaction",
+                "summary": "Update Category",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Transaction ID",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated Transaction Data",
-                        "name": "transaction",
+                        "description": "Category",
+                        "name": "category",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.TransactionRequest"
+                            "$ref": "#/definitions/handlers.CategoryRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated Transaction",
+                        "description": "Updated Category",
                         "schema": {
-                            "$ref": "#/definitions/models.Transaction"
+                            "$ref": "#/definitions/models.Category"
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload",
+                        "description": "Invalid request payload or parent",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "404": {
-                        "description": "Transaction not found",
+                        "description": "Category not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -987,15 +1245,18 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Deletes a specific transaction by its ID.",
+                "description": "Deletes a category by its ID. A category with subcategories cannot be deleted until they are moved or deleted.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "transactions"
+                    "categories"
                 ],
-                "summary": "Delete Transaction",
+                "summary": "Delete Category",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Transaction ID",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1006,14 +1267,14 @@ const docTemplate = `{
                         "description": "No Content"
                     },
                     "400": {
-                        "description": "Invalid transaction ID",
+                        "description": "Invalid Category ID, or the category has subcategories",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "404": {
-                        "description": "Transaction not found",
+                        "description": "Category not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -1028,187 +1289,4247 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "budget.CategoryBudgetHistoryResponse": {
-            "type": "object",
-            "properties": {
-                "category_id": {
-                    "type": "integer"
-                },
-                "history": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/budget.MonthlyBudgetResponse"
+        },
+        "/api/export": {
+            "get": {
+                "description": "Exports the user's transactions, categories and budgets. JSON exports contain everything and can be imported again through /api/imports/preview. CSV exports contain one dataset, with transactions in the columns read by the CSV importer. OFX exports contain transactions only, in the base currency.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ofx"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export Data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "json (default), csv or ofx",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "For CSV: transactions (default), categories or budgets",
+                        "name": "dataset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day included, YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day included, YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Only these categories; may be repeated or comma-separated",
+                        "name": "category_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Exported data",
+                        "schema": {
+                            "$ref": "#/definitions/export.Document"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid format, dataset or filter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "budget.MonthlyBudgetResponse": {
-            "type": "object",
-            "properties": {
-                "amount_limit": {
-                    "type": "number"
+        "/api/imports/commit": {
+            "post": {
+                "description": "Creates transactions for the given rows, usually the rows returned by the preview, and updates each affected budget once. Rows with an error are skipped. Rows without a category use category_id, or the default category. Rows without an account are booked to account_id, if given, and must then be in the account's currency.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Commit Statement Import",
+                "parameters": [
+                    {
+                        "description": "Rows to import",
+                        "name": "import",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommitImportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Import summary",
+                        "schema": {
+                            "$ref": "#/definitions/transaction.ImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid row, category or currency, or no exchange rate for a date",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "A row is in a reconciled statement period",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/imports/mappings": {
+            "get": {
+                "description": "Retrieves the CSV column mappings saved by the authenticated user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Get Import Mappings",
+                "responses": {
+                    "200": {
+                        "description": "Import mappings",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ImportMapping"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Saves how the columns of a bank's CSV export map onto transaction fields. Date formats use YYYY, YY, MM, DD, M and D.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Create Import Mapping",
+                "parameters": [
+                    {
+                        "description": "Import mapping",
+                        "name": "mapping",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportMapping"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created mapping",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportMapping"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid mapping",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/imports/mappings/{id}": {
+            "put": {
+                "description": "Replaces a CSV column mapping. The mapping must belong to the authenticated user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Update Import Mapping",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Mapping ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Import mapping",
+                        "name": "mapping",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportMapping"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated mapping",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportMapping"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid mapping",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Mapping belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Mapping not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a CSV column mapping. The mapping must belong to the authenticated user.",
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Delete Import Mapping",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Mapping ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid mapping ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Mapping belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Mapping not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/imports/preview": {
+            "post": {
+                "description": "Parses a CSV, OFX/QFX or QIF statement, or a PennyWise JSON export, and returns the rows that would be imported. Rows that could not be read carry an error and are skipped on commit.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "imports"
+                ],
+                "summary": "Preview Statement Import",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Statement file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv, ofx, qif or json (default: from the file extension)",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Saved CSV column mapping",
+                        "name": "mapping_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date format for QIF files, e.g. DD/MM/YYYY",
+                        "name": "date_format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency for rows whose file does not name one",
+                        "name": "currency",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Parsed rows",
+                        "schema": {
+                            "$ref": "#/definitions/importer.Preview"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid file, format or option",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Mapping belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Mapping not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/insights/subscriptions": {
+            "get": {
+                "description": "Looks through the authenticated user's expenses for charges with the same merchant and a similar amount at a weekly, fortnightly, monthly, quarterly or yearly cadence, and lists them with their next expected date and annual cost, most expensive first. Merchants that already have a recurring rule and subscriptions that seem to have lapsed are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "insights"
+                ],
+                "summary": "Get Detected Subscriptions",
+                "responses": {
+                    "200": {
+                        "description": "Detected subscriptions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/insights.Subscription"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/insights/subscriptions/convert": {
+            "post": {
+                "description": "Creates a monthly, weekly or yearly recurring rule for the detected subscription with the given key, with the latest charge's amount, category and account, on the day of the month it is billed on, starting with the next charge due after today.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "insights"
+                ],
+                "summary": "Convert Subscription to Recurring Rule",
+                "parameters": [
+                    {
+                        "description": "Subscription key",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ConvertSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created rule",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecurringRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload, or the rule could not be created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "No subscription with that key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/login": {
+            "post": {
+                "description": "Authenticates a user and returns a JWT token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "User Login",
+                "parameters": [
+                    {
+                        "description": "Login Data",
+                        "name": "loginData",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/password-reset/confirm": {
+            "post": {
+                "description": "Sets a new password using a valid, unused password reset token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Confirm Password Reset",
+                "parameters": [
+                    {
+                        "description": "Password Reset Confirmation",
+                        "name": "confirmData",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PasswordResetConfirmRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or expired token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/password-reset/request": {
+            "post": {
+                "description": "Emails a single-use password reset token to the account registered with the given email. The response is the same whether or not the email exists or the email could be sent.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Request Password Reset",
+                "parameters": [
+                    {
+                        "description": "Password Reset Request",
+                        "name": "resetData",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PasswordResetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/recurring": {
+            "get": {
+                "description": "Retrieves the authenticated user's recurring rules, soonest due first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Get Recurring Rules",
+                "responses": {
+                    "200": {
+                        "description": "Recurring rules",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RecurringRule"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a rule that books a transaction every interval days, weeks, months or years from start_date until end_date, if given. Monthly and yearly rules fall on day_of_month, or on the start date's day, and on the last day of shorter months. The transaction fields are checked as for a new transaction; transfers cannot recur. Occurrences are booked as they fall due, including any since a start date in the past.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Create Recurring Rule",
+                "parameters": [
+                    {
+                        "description": "Recurring rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RecurringRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created rule",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecurringRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rule, category, account or currency",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/recurring/upcoming": {
+            "get": {
+                "description": "Lists the occurrences of the authenticated user's recurring rules from today until the given number of days ahead, in date order, with any change made to them. Skipped occurrences are included and marked.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Get Upcoming Occurrences",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Days ahead, 1 to 366 (default 30)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Upcoming occurrences",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/recurring.Occurrence"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid days",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/recurring/{id}": {
+            "get": {
+                "description": "Retrieves a recurring rule belonging to the authenticated user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Get Recurring Rule By ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recurring rule",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecurringRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rule ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Rule belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces a recurring rule. Occurrences already booked or skipped are left alone; the rule carries on from the first date after them on its new schedule.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Update Recurring Rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Recurring rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RecurringRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated rule",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecurringRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rule, category, account or currency",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Rule belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a recurring rule and its skipped and changed occurrences. Transactions it has already booked are kept.",
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Delete Recurring Rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid rule ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Rule belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/recurring/{id}/occurrences/{date}": {
+            "put": {
+                "description": "Skips the rule's occurrence on the given date, or books it with a different amount, description or notes. Only occurrences that have not been booked yet can be changed; sending the request again replaces the earlier change.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Update Occurrence",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Occurrence date, YYYY-MM-DD",
+                        "name": "date",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Change",
+                        "name": "occurrence",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OccurrenceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Changed occurrence",
+                        "schema": {
+                            "$ref": "#/definitions/recurring.Occurrence"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date or amount, or the occurrence is not pending",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Rule belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Undoes a skip or change to the rule's occurrence on the given date, so that it is booked as the rule says.",
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Reset Occurrence",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Occurrence date, YYYY-MM-DD",
+                        "name": "date",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid date, or the occurrence is not pending",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Rule belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/cash-flow": {
+            "get": {
+                "description": "Returns the income, spending (expenses less refunds), net cash flow and savings rate over the range, with the same figures per month. The savings rate is the share of income not spent and is omitted without income. The range defaults to the last twelve months.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Net Cash Flow",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day included, YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day included, YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cash flow",
+                        "schema": {
+                            "$ref": "#/definitions/reports.CashFlow"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/categories": {
+            "get": {
+                "description": "Returns the net spending in each category for every month of the range, in the base currency, with the change from the month before. Every category used in the range is listed for every month. Months follow the user's time zone. The range defaults to the last twelve months.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Spending by Category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day included, YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day included, YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Spending per category and month",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/reports.CategoryMonthTotal"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/merchants": {
+            "get": {
+                "description": "Returns the merchants, taken from transaction descriptions, the user spent most with in the range. Descriptions that differ only in case, digits or punctuation count as one merchant.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Top Merchants",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day included, YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day included, YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of merchants, at most 50 (default 5)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Top merchants",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/reports.MerchantTotal"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range or limit",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/monthly": {
+            "get": {
+                "description": "Returns money spent (expenses less refunds) and received as income in every month of the range, the net result, the savings rate and the change in spending from the previous month. Transfers are left out. The range defaults to the last twelve months.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Monthly Income and Expense",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day included, YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day included, YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Monthly summaries",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/reports.MonthlySummary"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/top-categories": {
+            "get": {
+                "description": "Returns the categories with the highest net spending in the range, with their share of all spending.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Top Categories",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day included, YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day included, YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of categories, at most 50 (default 5)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Top categories",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/reports.CategoryTotal"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range or limit",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/reports/trends": {
+            "get": {
+                "description": "Returns the net spending in each day, week, month, quarter or year of the range, including periods without transactions. Periods follow the calendar of the time zone tz, and weeks start on the user's first_day_of_week setting.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Spending Trend",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "day, week, month (default), quarter or year",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day included, YYYY-MM-DD (default depends on granularity)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day included, YYYY-MM-DD (default: today)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Only these categories; may be repeated or comma-separated",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone, e.g. Europe/Berlin (default: the user's time_zone setting)",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Spending per period",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/reports.TrendPoint"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid granularity, range, category or time zone",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/settings": {
+            "get": {
+                "description": "Retrieves the settings of the authenticated user. Users who have never saved settings get the defaults.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Get Settings",
+                "responses": {
+                    "200": {
+                        "description": "Settings",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserSettings"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates the settings of the authenticated user. Fields left out of the request keep their current value. Changing base_currency converts existing transactions and budgets using the loaded exchange rates. The duplicate_* settings control how likely duplicate transactions are matched and whether imports flag, skip or allow them. time_zone (an IANA name, empty for the server's zone) and first_day_of_week (0 for Sunday) decide where budget months and report weeks begin; locale is a BCP 47 tag for clients to format with.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Update Settings",
+                "parameters": [
+                    {
+                        "description": "Settings to change",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.SettingsUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated settings",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserSettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload or setting, unknown currency or missing exchange rate",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/signup": {
+            "post": {
+                "description": "Registers a new user with the given username, email, and password. The optional time_zone (an IANA name) sets the calendar the user's budgets follow; it defaults to the server's zone.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "User Registration",
+                "parameters": [
+                    {
+                        "description": "Sign Up Data",
+                        "name": "signupData",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SignUpRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created User",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transactions": {
+            "get": {
+                "description": "Retrieves a page of the authenticated user's transactions, newest first unless sort says otherwise. Pass next_cursor from the response as cursor to fetch the following page with the same filters and sort. Amount bounds are in the base currency.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get Transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day included, YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day included, YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Only these categories; may be repeated or comma-separated",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Only these accounts; may be repeated or comma-separated",
+                        "name": "account_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Only these types (expense, income, transfer, refund); may be repeated or comma-separated",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Smallest amount included",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Largest amount included",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text the description contains",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "date (default), amount, description or created_at; prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, overriding the - prefix",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, at most 200 (default 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of transactions",
+                        "schema": {
+                            "$ref": "#/definitions/transaction.TransactionPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter, sort or cursor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new transaction for the authenticated user, linking it to a specific category. The type is expense (the default), income or refund; transfers are recorded with /api/transfers. Amounts are never negative. Income is booked against an income category, by default one called Income, and expenses and refunds against expense categories. Only expenses and refunds count towards budgets. A transaction booked to an account must be in the account's currency, which it defaults to. A transaction that looks like a duplicate of a stored one is saved with duplicate_of set, unless allow_duplicate is set or the user allows duplicates. To divide an expense, refund or income between categories, give at least two splits whose amounts add up to the amount instead of category_id; each split counts towards its own category's budget, and the transaction is filed under the first split's category.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Create Transaction",
+                "parameters": [
+                    {
+                        "description": "Transaction Data",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TransactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created Transaction",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload, category, currency or splits, or no exchange rate for the date",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Transaction is in a reconciled statement period",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transactions/category/{category_id}": {
+            "get": {
+                "description": "Retrieves all transactions associated with a specific category for the authenticated user, including split transactions with a split in the category; for those, split_amount is the part in the category.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get Transactions by Category ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of Transactions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Transaction"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Category ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve transactions",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transactions/duplicates": {
+            "get": {
+                "description": "Lists the authenticated user's transactions flagged as likely duplicates, each paired with the transaction it appears to repeat. Delete the duplicate, or dismiss the flag if it is genuine.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get Suspected Duplicates",
+                "responses": {
+                    "200": {
+                        "description": "Suspected duplicate pairs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/transaction.DuplicatePair"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transactions/search": {
+            "get": {
+                "description": "Finds the authenticated user's transactions whose description or notes contain words starting with each word of q, tolerating small typos. Results are ordered best match first, then newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Search Transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search text",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of results, at most 100 (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Matching transactions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/transaction.SearchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Missing search text or invalid limit",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transactions/weekly": {
+            "get": {
+                "description": "Retrieves the authenticated user's spending in the current week and the five before it, newest first. Weeks start on the user's first_day_of_week, at midnight in their time zone.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get Weekly Spending (Past 6 weeks)",
+                "responses": {
+                    "200": {
+                        "description": "Weekly Spending",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/transaction.WeeklySpending"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transactions/{id}": {
+            "get": {
+                "description": "Retrieves a single transaction by its ID for the authenticated user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get Transaction by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Transaction data",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid transaction ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Transaction belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates an existing transaction, allowing changes to the type, amount, category, splits, description, or date. The stored splits are replaced by the ones given, so leaving splits out turns a split transaction back into a single-category one. Either side of a transfer is edited through /api/transfers/{id} instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Update Transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Transaction Data",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TransactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated Transaction",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload, category, currency or splits, no exchange rate for the date, or the transaction is part of a transfer",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Transaction belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Transaction is in a reconciled statement period",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a specific transaction by its ID. Deleting either side of a transfer deletes both.",
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Delete Transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid transaction ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Transaction belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Transaction is in a reconciled statement period",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transactions/{id}/dismiss-duplicate": {
+            "post": {
+                "description": "Marks a transaction flagged as a likely duplicate as genuine.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Dismiss Duplicate",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated Transaction",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid transaction ID or transaction not flagged",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Transaction belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transfers": {
+            "post": {
+                "description": "Moves money from one of the authenticated user's accounts to another, booked as two linked transfer transactions that are saved together. Amount leaves the from account in its currency; to_amount is what arrives when the accounts' currencies differ, and defaults to amount converted at the rate for the date. Transfers are booked against the Transfers category unless category_id is given, and never count towards budgets or spending.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Create Transfer",
+                "parameters": [
+                    {
+                        "description": "Transfer Data",
+                        "name": "transfer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created Transfer",
+                        "schema": {
+                            "$ref": "#/definitions/transaction.Transfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload, accounts, amounts or category, or no exchange rate for the date",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Transfer is in a reconciled statement period",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/transfers/{id}": {
+            "get": {
+                "description": "Retrieves the transfer that the transaction with the given ID is either side of.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Get Transfer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of either transaction in the transfer",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Transfer",
+                        "schema": {
+                            "$ref": "#/definitions/transaction.Transfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID, or the transaction is not part of a transfer",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Transfer belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces the accounts, amounts, category, description and date of the transfer that the transaction with the given ID is either side of. Both transactions are updated together.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Update Transfer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of either transaction in the transfer",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Transfer Data",
+                        "name": "transfer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated Transfer",
+                        "schema": {
+                            "$ref": "#/definitions/transaction.Transfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload, accounts, amounts or category, or the transaction is not part of a transfer",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Transfer belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Transfer is in a reconciled statement period",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes both transactions of the transfer that the transaction with the given ID is either side of.",
+                "tags": [
+                    "transfers"
+                ],
+                "summary": "Delete Transfer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of either transaction in the transfer",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid ID, or the transaction is not part of a transfer",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Transfer belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Transfer is in a reconciled statement period",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "account.AccountBalance": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Everyday Checking"
+                },
+                "opening_balance": {
+                    "type": "number",
+                    "example": 1000
+                },
+                "reconciled_balance": {
+                    "type": "number"
+                },
+                "reconciled_through": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "checking",
+                        "savings",
+                        "credit_card",
+                        "cash"
+                    ],
+                    "example": "checking"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "account.ReconcileResult": {
+            "type": "object",
+            "properties": {
+                "difference": {
+                    "type": "number"
+                },
+                "ledger_balance": {
+                    "type": "number"
+                },
+                "reconciled": {
+                    "type": "boolean"
+                },
+                "statement_balance": {
+                    "type": "number"
+                },
+                "statement_date": {
+                    "type": "string"
+                },
+                "unreconciled": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Transaction"
+                    }
+                }
+            }
+        },
+        "account.RegisterEntry": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "number",
+                    "example": 12.5
+                },
+                "balance": {
+                    "type": "number"
+                },
+                "base_amount": {
+                    "type": "number",
+                    "example": 12.5
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duplicate_of": {
+                    "type": "integer"
+                },
+                "external_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "splits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransactionSplit"
+                    }
+                },
+                "transaction_date": {
+                    "type": "string"
+                },
+                "transfer_id": {
+                    "type": "integer"
+                },
+                "transfer_in": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "expense",
+                        "income",
+                        "transfer",
+                        "refund"
+                    ],
+                    "example": "expense"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "budget.BudgetDrift": {
+            "type": "object",
+            "properties": {
+                "budget_id": {
+                    "type": "integer"
+                },
+                "budget_month": {
+                    "type": "string"
+                },
+                "budget_year": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "ledger_spent": {
+                    "type": "number"
+                },
+                "recorded_spent": {
+                    "type": "number"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "budget.CategoryBudgetHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/budget.CategoryBudgetHistoryResponse"
+                    }
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/budget.MonthlyBudgetResponse"
+                    }
+                }
+            }
+        },
+        "budget.MonthlyBudgetResponse": {
+            "type": "object",
+            "properties": {
+                "amount_limit": {
+                    "type": "number"
+                },
+                "month": {
+                    "type": "string"
+                },
+                "remaining_amount": {
+                    "type": "number"
+                },
+                "spent_amount": {
+                    "type": "number"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "budget.ReconciliationReport": {
+            "type": "object",
+            "properties": {
+                "budgets_checked": {
+                    "type": "integer"
+                },
+                "drifts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/budget.BudgetDrift"
+                    }
+                },
+                "repaired": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "category.CategoryNode": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/category.CategoryNode"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "expense",
+                        "income"
+                    ],
+                    "example": "expense"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "export.Budget": {
+            "type": "object",
+            "properties": {
+                "amount_limit": {
+                    "type": "number"
+                },
+                "budget_month": {
+                    "type": "string"
+                },
+                "budget_year": {
+                    "type": "integer"
+                },
+                "carry_over_amount": {
+                    "type": "number"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "rollover_negative": {
+                    "type": "boolean"
+                },
+                "rollover_positive": {
+                    "type": "boolean"
+                },
+                "spent_amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "export.Category": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "export.Document": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string"
+                },
+                "budgets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/export.Budget"
+                    }
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/export.Category"
+                    }
+                },
+                "exported_at": {
+                    "type": "string"
+                },
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/export.Transaction"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "export.Split": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "base_amount": {
+                    "type": "number"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                }
+            }
+        },
+        "export.Transaction": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "base_amount": {
+                    "type": "number"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "external_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "splits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/export.Split"
+                    }
+                },
+                "transaction_date": {
+                    "type": "string"
+                },
+                "transfer_id": {
+                    "type": "integer"
+                },
+                "transfer_in": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.AccountRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Everyday Checking"
+                },
+                "opening_balance": {
+                    "type": "number",
+                    "example": 1000
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "checking",
+                        "savings",
+                        "credit_card",
+                        "cash"
+                    ],
+                    "example": "checking"
+                }
+            }
+        },
+        "handlers.BudgetRequest": {
+            "type": "object",
+            "properties": {
+                "amount_limit": {
+                    "type": "number"
+                },
+                "budget_month": {
+                    "type": "string"
+                },
+                "budget_year": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.CategoryRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "expense",
+                        "income"
+                    ],
+                    "example": "expense"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "handlers.CommitImportRequest": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/importer.Row"
+                    }
+                }
+            }
+        },
+        "handlers.ConvertSubscriptionRequest": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string",
+                    "example": "USD:netflix com"
+                }
+            }
+        },
+        "handlers.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "handlers.OccurrenceRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 17.99
+                },
+                "description": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "skip": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.PasswordResetConfirmRequest": {
+            "type": "object",
+            "properties": {
+                "new_password": {
+                    "type": "string",
+                    "example": "newpassword123"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "4f3c2a1b9e8d7c6b5a4f3e2d1c0b9a8f"
+                }
+            }
+        },
+        "handlers.PasswordResetRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                }
+            }
+        },
+        "handlers.ReconcileRequest": {
+            "type": "object",
+            "properties": {
+                "statement_balance": {
+                    "type": "number",
+                    "example": 1520.75
+                },
+                "statement_date": {
+                    "type": "string",
+                    "example": "2024-10-31"
+                }
+            }
+        },
+        "handlers.RecurringRuleRequest": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "number",
+                    "example": 15.49
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "day_of_month": {
+                    "type": "integer",
+                    "example": 31
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Netflix"
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "2025-10-31"
+                },
+                "frequency": {
+                    "type": "string",
+                    "enum": [
+                        "daily",
+                        "weekly",
+                        "monthly",
+                        "yearly"
+                    ],
+                    "example": "monthly"
+                },
+                "interval": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2024-10-31"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "expense",
+                        "income",
+                        "refund"
+                    ],
+                    "example": "expense"
+                }
+            }
+        },
+        "handlers.RolloverRequest": {
+            "type": "object",
+            "properties": {
+                "rollover_negative": {
+                    "type": "boolean"
+                },
+                "rollover_positive": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.SignUpRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                },
+                "time_zone": {
+                    "type": "string",
+                    "example": "Europe/Berlin"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "handlers.SplitRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 42.1
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.TransactionRequest": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "allow_duplicate": {
+                    "type": "boolean"
+                },
+                "amount": {
+                    "type": "number",
+                    "example": 12.5
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "splits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.SplitRequest"
+                    }
+                },
+                "transaction_date": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "expense",
+                        "income",
+                        "refund"
+                    ],
+                    "example": "expense"
+                }
+            }
+        },
+        "handlers.TransferRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 250
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "from_account_id": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "to_account_id": {
+                    "type": "integer"
+                },
+                "to_amount": {
+                    "type": "number",
+                    "example": 230
+                },
+                "transaction_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.UpdateBudgetRequest": {
+            "type": "object",
+            "properties": {
+                "amount_limit": {
+                    "type": "number"
+                }
+            }
+        },
+        "handlers.UserResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "importer.Format": {
+            "type": "string",
+            "enum": [
+                "csv",
+                "ofx",
+                "qif",
+                "json"
+            ],
+            "x-enum-varnames": [
+                "FormatCSV",
+                "FormatOFX",
+                "FormatQIF",
+                "FormatJSON"
+            ]
+        },
+        "importer.Preview": {
+            "type": "object",
+            "properties": {
+                "duplicates": {
+                    "type": "integer"
+                },
+                "format": {
+                    "$ref": "#/definitions/importer.Format"
+                },
+                "invalid": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/importer.Row"
+                    }
+                },
+                "valid": {
+                    "type": "integer"
+                }
+            }
+        },
+        "importer.Row": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "allow_duplicate": {
+                    "type": "boolean"
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duplicate_of": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "external_id": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "transaction_date": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "insights.Subscription": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "number",
+                    "example": 15.49
+                },
+                "annual_cost": {
+                    "type": "number",
+                    "example": 185.88
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "charges": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "day_of_month": {
+                    "type": "integer",
+                    "example": 31
+                },
+                "first_charge": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "type": "string",
+                    "enum": [
+                        "weekly",
+                        "monthly",
+                        "yearly"
+                    ],
+                    "example": "monthly"
+                },
+                "interval": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "key": {
+                    "type": "string",
+                    "example": "USD:netflix com"
+                },
+                "last_charge": {
+                    "type": "string"
+                },
+                "merchant": {
+                    "type": "string",
+                    "example": "NETFLIX.COM"
+                },
+                "next_expected": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Account": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Everyday Checking"
+                },
+                "opening_balance": {
+                    "type": "number",
+                    "example": 1000
+                },
+                "reconciled_balance": {
+                    "type": "number"
+                },
+                "reconciled_through": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "checking",
+                        "savings",
+                        "credit_card",
+                        "cash"
+                    ],
+                    "example": "checking"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Budget": {
+            "type": "object",
+            "properties": {
+                "amount_limit": {
+                    "type": "number"
+                },
+                "budget_month": {
+                    "type": "string"
+                },
+                "budget_year": {
+                    "type": "integer"
+                },
+                "carry_over_amount": {
+                    "type": "number"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "remaining_amount": {
+                    "type": "number"
+                },
+                "rolled_over": {
+                    "type": "boolean"
+                },
+                "rollover_negative": {
+                    "type": "boolean"
+                },
+                "rollover_positive": {
+                    "type": "boolean"
+                },
+                "spent_amount": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "expense",
+                        "income"
+                    ],
+                    "example": "expense"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ImportMapping": {
+            "type": "object",
+            "properties": {
+                "amount_column": {
+                    "type": "string",
+                    "example": "Amount"
+                },
+                "category_column": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "credit_column": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "currency_column": {
+                    "type": "string"
+                },
+                "date_column": {
+                    "type": "string",
+                    "example": "Posting Date"
+                },
+                "date_format": {
+                    "type": "string",
+                    "example": "DD/MM/YYYY"
+                },
+                "debit_column": {
+                    "type": "string"
+                },
+                "delimiter": {
+                    "type": "string",
+                    "example": ","
+                },
+                "description_column": {
+                    "type": "string",
+                    "example": "Description"
+                },
+                "external_id_column": {
+                    "type": "string",
+                    "example": "Transaction ID"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "My Bank"
+                },
+                "negate_amounts": {
+                    "type": "boolean"
+                },
+                "type_column": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Reconciliation": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "statement_balance": {
+                    "type": "number"
+                },
+                "statement_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RecurringRule": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "number",
+                    "example": 15.49
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "day_of_month": {
+                    "type": "integer",
+                    "example": 31
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Netflix"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "type": "string",
+                    "enum": [
+                        "daily",
+                        "weekly",
+                        "monthly",
+                        "yearly"
+                    ],
+                    "example": "monthly"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "interval": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "last_date": {
+                    "type": "string"
+                },
+                "next_date": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "expense",
+                        "income",
+                        "refund"
+                    ],
+                    "example": "expense"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Transaction": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "number",
+                    "example": 12.5
+                },
+                "base_amount": {
+                    "type": "number",
+                    "example": 12.5
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duplicate_of": {
+                    "type": "integer"
+                },
+                "external_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "splits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransactionSplit"
+                    }
                 },
-                "month": {
+                "transaction_date": {
                     "type": "string"
                 },
-                "remaining_amount": {
-                    "type": "number"
+                "transfer_id": {
+                    "type": "integer"
                 },
-                "spent_amount": {
-                    "type": "number"
+                "transfer_in": {
+                    "type": "boolean"
                 },
-                "year": {
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "expense",
+                        "income",
+                        "transfer",
+                        "refund"
+                    ],
+                    "example": "expense"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "integer"
                 }
             }
         },
-        "handlers.BudgetRequest": {
+        "models.TransactionSplit": {
             "type": "object",
             "properties": {
-                "amount_limit": {
-                    "type": "number"
-                },
-                "budget_month": {
-                    "type": "string"
+                "amount": {
+                    "type": "number",
+                    "example": 42.1
                 },
-                "budget_year": {
-                    "type": "integer"
+                "base_amount": {
+                    "type": "number",
+                    "example": 42.1
                 },
                 "category_id": {
                     "type": "integer"
-                }
-            }
-        },
-        "handlers.CategoryRequest": {
-            "type": "object",
-            "properties": {
+                },
+                "created_at": {
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
-                "name": {
+                "id": {
+                    "type": "integer"
+                },
+                "transaction_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "handlers.LoginRequest": {
+        "models.UserSettings": {
             "type": "object",
             "properties": {
-                "password": {
+                "base_currency": {
                     "type": "string",
-                    "example": "password123"
+                    "example": "USD"
                 },
-                "username": {
-                    "type": "string",
-                    "example": "john_doe"
-                }
-            }
-        },
-        "handlers.SignUpRequest": {
-            "type": "object",
-            "properties": {
-                "email": {
+                "carry_over_budget_limits": {
+                    "type": "boolean"
+                },
+                "duplicate_action": {
                     "type": "string",
-                    "example": "john.doe@example.com"
+                    "enum": [
+                        "flag",
+                        "skip",
+                        "allow"
+                    ],
+                    "example": "flag"
                 },
-                "password": {
+                "duplicate_ignore_description": {
+                    "type": "boolean"
+                },
+                "duplicate_window_days": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "first_day_of_week": {
+                    "type": "integer",
+                    "maximum": 6,
+                    "minimum": 0,
+                    "example": 1
+                },
+                "locale": {
                     "type": "string",
-                    "example": "password123"
+                    "example": "en-US"
                 },
-                "username": {
+                "time_zone": {
                     "type": "string",
-                    "example": "john_doe"
+                    "example": "Europe/Berlin"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.TransactionRequest": {
+        "recurring.Occurrence": {
             "type": "object",
             "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
                 "amount": {
                     "type": "number"
                 },
                 "category_id": {
                     "type": "integer"
                 },
+                "currency": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
-                "transaction_date": {
+                "modified": {
+                    "type": "boolean"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "rule_id": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "boolean"
+                },
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "handlers.UpdateBudgetRequest": {
+        "reports.CashFlow": {
             "type": "object",
             "properties": {
-                "amount_limit": {
+                "expense": {
+                    "type": "number"
+                },
+                "income": {
+                    "type": "number"
+                },
+                "months": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/reports.MonthlySummary"
+                    }
+                },
+                "net": {
+                    "type": "number"
+                },
+                "savings_rate": {
                     "type": "number"
                 }
             }
         },
-        "handlers.UserResponse": {
+        "reports.CategoryMonthTotal": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string"
+                "category_id": {
+                    "type": "integer"
                 },
-                "username": {
+                "category_name": {
                     "type": "string"
+                },
+                "change": {
+                    "type": "number"
+                },
+                "change_percent": {
+                    "type": "number"
+                },
+                "month": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "year": {
+                    "type": "integer"
                 }
             }
         },
-        "models.Budget": {
+        "reports.CategoryTotal": {
             "type": "object",
             "properties": {
-                "amount_limit": {
-                    "type": "number"
+                "category_id": {
+                    "type": "integer"
                 },
-                "budget_month": {
+                "category_name": {
                     "type": "string"
                 },
-                "budget_year": {
+                "count": {
                     "type": "integer"
                 },
-                "category_id": {
+                "share": {
+                    "type": "number"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "reports.MerchantTotal": {
+            "type": "object",
+            "properties": {
+                "count": {
                     "type": "integer"
                 },
-                "created_at": {
+                "merchant": {
                     "type": "string"
                 },
-                "id": {
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "reports.MonthlySummary": {
+            "type": "object",
+            "properties": {
+                "expense": {
+                    "type": "number"
+                },
+                "expense_change": {
+                    "type": "number"
+                },
+                "expense_change_percent": {
+                    "type": "number"
+                },
+                "income": {
+                    "type": "number"
+                },
+                "month": {
                     "type": "integer"
                 },
-                "remaining_amount": {
+                "net": {
                     "type": "number"
                 },
-                "spent_amount": {
+                "savings_rate": {
                     "type": "number"
                 },
-                "updated_at": {
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "reports.TrendPoint": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "end": {
                     "type": "string"
                 },
-                "user_id": {
+                "start": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "transaction.DuplicatePair": {
+            "type": "object",
+            "properties": {
+                "duplicate_of": {
+                    "$ref": "#/definitions/models.Transaction"
+                },
+                "transaction": {
+                    "$ref": "#/definitions/models.Transaction"
+                }
+            }
+        },
+        "transaction.ImportResult": {
+            "type": "object",
+            "properties": {
+                "budgets_updated": {
+                    "type": "integer"
+                },
+                "flagged": {
+                    "type": "integer"
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "skipped": {
                     "type": "integer"
                 }
             }
         },
-        "models.Category": {
+        "transaction.SearchResult": {
             "type": "object",
             "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "number",
+                    "example": 12.5
+                },
+                "base_amount": {
+                    "type": "number",
+                    "example": 12.5
+                },
+                "category_id": {
+                    "type": "integer"
+                },
                 "created_at": {
                     "type": "string"
                 },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
                 "description": {
                     "type": "string"
                 },
+                "duplicate_of": {
+                    "type": "integer"
+                },
+                "external_id": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "name": {
+                "notes": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "splits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransactionSplit"
+                    }
+                },
+                "transaction_date": {
                     "type": "string"
                 },
+                "transfer_id": {
+                    "type": "integer"
+                },
+                "transfer_in": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "expense",
+                        "income",
+                        "transfer",
+                        "refund"
+                    ],
+                    "example": "expense"
+                },
                 "updated_at": {
                     "type": "string"
                 },
@@ -1217,27 +5538,62 @@ const docTemplate = `{
                 }
             }
         },
-        "models.Transaction": {
+        "transaction.TransactionPage": {
             "type": "object",
             "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/transaction.TransactionResponse"
+                    }
+                }
+            }
+        },
+        "transaction.TransactionResponse": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
                 "amount": {
                     "type": "number"
                 },
+                "base_amount": {
+                    "type": "number"
+                },
                 "category_id": {
                     "type": "integer"
                 },
+                "category_name": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
+                "currency": {
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
+                "split_amount": {
+                    "type": "number"
+                },
                 "transaction_date": {
                     "type": "string"
                 },
+                "type": {
+                    "type": "string"
+                },
                 "updated_at": {
                     "type": "string"
                 },
@@ -1246,9 +5602,23 @@ const docTemplate = `{
                 }
             }
         },
+        "transaction.Transfer": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "$ref": "#/definitions/models.Transaction"
+                },
+                "to": {
+                    "$ref": "#/definitions/models.Transaction"
+                }
+            }
+        },
         "transaction.WeeklySpending": {
             "type": "object",
             "properties": {
+                "start": {
+                    "type": "string"
+                },
                 "total_spent": {
                     "type": "number"
                 },
@@ -1259,6 +5629,48 @@ const docTemplate = `{
                     "type": "integer"
                 }
             }
+        },
+        "user.SettingsUpdate": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "carry_over_budget_limits": {
+                    "type": "boolean"
+                },
+                "duplicate_action": {
+                    "type": "string",
+                    "enum": [
+                        "flag",
+                        "skip",
+                        "allow"
+                    ],
+                    "example": "skip"
+                },
+                "duplicate_ignore_description": {
+                    "type": "boolean"
+                },
+                "duplicate_window_days": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "first_day_of_week": {
+                    "type": "integer",
+                    "maximum": 6,
+                    "minimum": 0,
+                    "example": 0
+                },
+                "locale": {
+                    "type": "string",
+                    "example": "en-GB"
+                },
+                "time_zone": {
+                    "type": "string",
+                    "example": "America/New_York"
+                }
+            }
         }
     }
 }`
@@ -0,0 +1,133 @@
+package account
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/user"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+)
+
+// ReconcileResult compares a statement's ending balance with the account's
+// balance at the end of the statement date. Difference is the statement
+// balance less the ledger balance; the account is reconciled only when it is
+// zero. Unreconciled lists the transactions since the previous
+// reconciliation, which are where a difference is to be found.
+type ReconcileResult struct {
+	StatementDate    time.Time             `json:"statement_date"`
+	StatementBalance money.Amount          `json:"statement_balance" swaggertype:"number"`
+	LedgerBalance    money.Amount          `json:"ledger_balance" swaggertype:"number"`
+	Difference       money.Amount          `json:"difference" swaggertype:"number"`
+	Reconciled       bool                  `json:"reconciled"`
+	Unreconciled     []*models.Transaction `json:"unreconciled"`
+}
+
+// Reconcile matches the account against a statement ending on statementDate
+// with statementBalance. When the balances agree the reconciliation is
+// recorded; otherwise nothing is stored and the result shows the difference.
+// Statements are reconciled in order, so statementDate cannot precede the
+// last reconciled one. The account stays locked while its balance is read
+// and the reconciliation stored.
+func (s *AccountService) Reconcile(username string, id uint, statementDate time.Time, statementBalance money.Amount) (*ReconcileResult, error) {
+	_, account, err := s.findOwnedAccount(username, id)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := s.location(account.UserID)
+	if err != nil {
+		return nil, err
+	}
+	statementDate = startOfDay(statementDate, loc)
+
+	var result *ReconcileResult
+	err = s.Repo.WithLockedAccount(account, func(repo AccountRepository) error {
+		result, err = reconcile(repo, account, loc, statementDate, statementBalance)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// reconcile matches the locked account against the statement through repo.
+func reconcile(repo AccountRepository, account *models.Account, loc *time.Location, statementDate time.Time, statementBalance money.Amount) (*ReconcileResult, error) {
+	end := statementDate.AddDate(0, 0, 1)
+
+	var since time.Time
+	if account.ReconciledThrough != nil {
+		last := account.ReconciledThrough.In(loc)
+		if statementDate.Before(last) {
+			return nil, fmt.Errorf("%w: the account is already reconciled through %s", ErrInvalidAccount, last.Format("2006-01-02"))
+		}
+		since = last.AddDate(0, 0, 1)
+	}
+
+	total, err := repo.LedgerTotal(account.ID, end)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReconcileResult{
+		StatementDate:    statementDate,
+		StatementBalance: statementBalance,
+		LedgerBalance:    account.OpeningBalance + total,
+		Unreconciled:     []*models.Transaction{},
+	}
+	result.Difference = statementBalance - result.LedgerBalance
+
+	transactions, err := repo.FindTransactions(account.ID, since, end)
+	if err != nil {
+		return nil, err
+	}
+	result.Unreconciled = append(result.Unreconciled, transactions...)
+
+	if result.Difference != 0 {
+		return result, nil
+	}
+
+	account.ReconciledThrough = &statementDate
+	account.ReconciledBalance = statementBalance
+	reconciliation := &models.Reconciliation{
+		AccountID:        account.ID,
+		StatementDate:    statementDate,
+		StatementBalance: statementBalance,
+	}
+	if err := repo.SaveReconciliation(account, reconciliation); err != nil {
+		return nil, err
+	}
+
+	result.Reconciled = true
+	return result, nil
+}
+
+// GetReconciliations returns the account's reconciliations, latest first.
+func (s *AccountService) GetReconciliations(username string, id uint) ([]*models.Reconciliation, error) {
+	_, account, err := s.findOwnedAccount(username, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.Repo.FindReconciliations(account.ID)
+}
+
+// startOfDay returns midnight in loc on the calendar day of date.
+func startOfDay(date time.Time, loc *time.Location) time.Time {
+	year, month, day := date.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+// location returns the time zone statement dates are read in, which is the
+// user's own.
+func (s *AccountService) location(userID uint) (*time.Location, error) {
+	if s.Settings == nil {
+		return time.Local, nil
+	}
+
+	settings, err := s.Settings.SettingsForUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return user.Location(settings), nil
+}
@@ -0,0 +1,35 @@
+package account
+
+import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+)
+
+type AccountRepository interface {
+	Create(account *models.Account) error
+	Update(account *models.Account) error
+	DeleteByID(id uint) error
+	FindByID(id uint) (*models.Account, error)
+	FindAllByUserID(userID uint) ([]*models.Account, error)
+	// LedgerTotals returns what the transactions booked to each of the
+	// user's accounts add to its balance, by account ID.
+	LedgerTotals(userID uint) (map[uint]money.Amount, error)
+	// LedgerTotal returns what the account's transactions dated before
+	// before add to its balance.
+	LedgerTotal(accountID uint, before time.Time) (money.Amount, error)
+	// FindTransactions returns the account's transactions dated from from
+	// up to before, oldest first. A zero from or before leaves that end of
+	// the range open.
+	FindTransactions(accountID uint, from, before time.Time) ([]*models.Transaction, error)
+	CountTransactions(accountID uint) (int64, error)
+	// SaveReconciliation stores the reconciliation and the account's new
+	// reconciled balance together.
+	SaveReconciliation(account *models.Account, reconciliation *models.Reconciliation) error
+	FindReconciliations(accountID uint) ([]*models.Reconciliation, error)
+	// WithLockedAccount reloads the account and locks it until fn returns,
+	// so that it is reconciled once at a time. fn is given a repository
+	// that works inside the lock.
+	WithLockedAccount(account *models.Account, fn func(repo AccountRepository) error) error
+}
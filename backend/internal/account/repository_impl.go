@@ -0,0 +1,129 @@
+package account
+
+import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AccountRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewAccountRepository(db *gorm.DB) *AccountRepositoryImpl {
+	return &AccountRepositoryImpl{DB: db}
+}
+
+func (r *AccountRepositoryImpl) Create(account *models.Account) error {
+	return r.DB.Create(account).Error
+}
+
+func (r *AccountRepositoryImpl) Update(account *models.Account) error {
+	return r.DB.Save(account).Error
+}
+
+func (r *AccountRepositoryImpl) DeleteByID(id uint) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("account_id = ?", id).Delete(&models.Reconciliation{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Account{}, id).Error
+	})
+}
+
+func (r *AccountRepositoryImpl) FindByID(id uint) (*models.Account, error) {
+	var account models.Account
+	if err := r.DB.First(&account, id).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *AccountRepositoryImpl) FindAllByUserID(userID uint) ([]*models.Account, error) {
+	var accounts []*models.Account
+	if err := r.DB.Where("user_id = ?", userID).Order("name").Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (r *AccountRepositoryImpl) LedgerTotals(userID uint) (map[uint]money.Amount, error) {
+	var rows []struct {
+		AccountID uint
+		Total     money.Amount
+	}
+	err := r.DB.Model(&models.Transaction{}).
+		Select("account_id, SUM("+BalanceSQL+") AS total").
+		Where("user_id = ? AND account_id IS NOT NULL", userID).
+		Group("account_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[uint]money.Amount, len(rows))
+	for _, row := range rows {
+		totals[row.AccountID] = row.Total
+	}
+	return totals, nil
+}
+
+func (r *AccountRepositoryImpl) LedgerTotal(accountID uint, before time.Time) (money.Amount, error) {
+	var total money.Amount
+	err := r.DB.Model(&models.Transaction{}).
+		Select("COALESCE(SUM("+BalanceSQL+"), 0)").
+		Where("account_id = ? AND transaction_date < ?", accountID, before).
+		Scan(&total).Error
+	return total, err
+}
+
+func (r *AccountRepositoryImpl) FindTransactions(accountID uint, from, before time.Time) ([]*models.Transaction, error) {
+	query := r.DB.Where("account_id = ?", accountID)
+	if !from.IsZero() {
+		query = query.Where("transaction_date >= ?", from)
+	}
+	if !before.IsZero() {
+		query = query.Where("transaction_date < ?", before)
+	}
+
+	var transactions []*models.Transaction
+	if err := query.Order("transaction_date, id").Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+func (r *AccountRepositoryImpl) CountTransactions(accountID uint) (int64, error) {
+	var count int64
+	err := r.DB.Model(&models.Transaction{}).Where("account_id = ?", accountID).Count(&count).Error
+	return count, err
+}
+
+func (r *AccountRepositoryImpl) SaveReconciliation(account *models.Account, reconciliation *models.Reconciliation) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(reconciliation).Error; err != nil {
+			return err
+		}
+		return tx.Save(account).Error
+	})
+}
+
+func (r *AccountRepositoryImpl) FindReconciliations(accountID uint) ([]*models.Reconciliation, error) {
+	var reconciliations []*models.Reconciliation
+	if err := r.DB.Where("account_id = ?", accountID).Order("statement_date DESC, id DESC").Find(&reconciliations).Error; err != nil {
+		return nil, err
+	}
+	return reconciliations, nil
+}
+
+func (r *AccountRepositoryImpl) WithLockedAccount(account *models.Account, fn func(repo AccountRepository) error) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(account, account.ID).Error; err != nil {
+			return err
+		}
+		return fn(NewAccountRepository(tx))
+	})
+}
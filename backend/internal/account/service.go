@@ -0,0 +1,296 @@
+// Package account keeps the user's accounts, such as bank accounts, credit
+// cards and cash, with their running balances and statement reconciliations.
+package account
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/user"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAccountNotFound     = errors.New("account not found")
+	ErrAccountAccessDenied = errors.New("access denied: account does not belong to the user")
+	ErrInvalidAccount      = errors.New("invalid account")
+	ErrAccountInUse        = errors.New("account has transactions")
+)
+
+// BalanceSQL is the SQL for what a transaction row adds to the balance of its
+// account, in the account's currency. It matches BalanceChange.
+var BalanceSQL = fmt.Sprintf("CASE WHEN transactions.type IN ('%s', '%s') OR transactions.transfer_in THEN transactions.amount ELSE -transactions.amount END",
+	models.TransactionTypeIncome, models.TransactionTypeRefund)
+
+// BalanceChange returns what a transaction adds to the balance of its
+// account: income, refunds and transfers into the account add to it,
+// everything else takes from it.
+func BalanceChange(t *models.Transaction) money.Amount {
+	switch {
+	case t.Type == models.TransactionTypeIncome, t.Type == models.TransactionTypeRefund, t.TransferIn:
+		return t.Amount
+	default:
+		return -t.Amount
+	}
+}
+
+// ParseType returns the named account type, defaulting to checking.
+func ParseType(name string) (string, error) {
+	switch kind := strings.ToLower(strings.TrimSpace(name)); kind {
+	case "":
+		return models.AccountTypeChecking, nil
+	case models.AccountTypeChecking, models.AccountTypeSavings, models.AccountTypeCreditCard, models.AccountTypeCash:
+		return kind, nil
+	default:
+		return "", fmt.Errorf("%w: unknown type %q", ErrInvalidAccount, name)
+	}
+}
+
+type AccountService struct {
+	Repo     AccountRepository
+	UserRepo user.UserRepository
+	Settings transaction.SettingsProvider
+}
+
+func NewAccountService(repo AccountRepository, userRepo user.UserRepository) *AccountService {
+	return &AccountService{
+		Repo:     repo,
+		UserRepo: userRepo,
+	}
+}
+
+// AccountInput carries the user-editable fields of an account. Currency
+// defaults to the user's base currency.
+type AccountInput struct {
+	Name           string
+	Type           string
+	Currency       money.Currency
+	OpeningBalance money.Amount
+}
+
+// AccountBalance is an account with its current balance.
+type AccountBalance struct {
+	*models.Account
+	Balance money.Amount `json:"balance" swaggertype:"number"`
+}
+
+// RegisterEntry is a transaction in an account's register with the balance
+// of the account after it.
+type RegisterEntry struct {
+	*models.Transaction
+	Balance money.Amount `json:"balance" swaggertype:"number"`
+}
+
+func (s *AccountService) GetAccounts(username string) ([]*AccountBalance, error) {
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.Repo.FindAllByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.Repo.LedgerTotals(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]*AccountBalance, 0, len(accounts))
+	for _, account := range accounts {
+		balances = append(balances, &AccountBalance{Account: account, Balance: account.OpeningBalance + totals[account.ID]})
+	}
+	return balances, nil
+}
+
+func (s *AccountService) GetAccount(username string, id uint) (*AccountBalance, error) {
+	_, account, err := s.findOwnedAccount(username, id)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.Repo.LedgerTotals(account.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return &AccountBalance{Account: account, Balance: account.OpeningBalance + totals[account.ID]}, nil
+}
+
+func (s *AccountService) CreateAccount(username string, input AccountInput) (*models.Account, error) {
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &models.Account{UserID: user.ID}
+	if err := s.apply(account, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.Repo.Create(account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// UpdateAccount replaces the account's fields. The currency cannot change
+// once transactions have been booked to the account.
+func (s *AccountService) UpdateAccount(username string, id uint, input AccountInput) (*models.Account, error) {
+	_, account, err := s.findOwnedAccount(username, id)
+	if err != nil {
+		return nil, err
+	}
+
+	currency := account.Currency
+	if err := s.apply(account, input); err != nil {
+		return nil, err
+	}
+
+	if account.Currency != currency {
+		count, err := s.Repo.CountTransactions(account.ID)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, fmt.Errorf("%w: cannot change the currency of an account with transactions", ErrInvalidAccount)
+		}
+	}
+
+	if err := s.Repo.Update(account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// DeleteAccount removes an account that has no transactions, along with its
+// reconciliations.
+func (s *AccountService) DeleteAccount(username string, id uint) error {
+	_, account, err := s.findOwnedAccount(username, id)
+	if err != nil {
+		return err
+	}
+
+	count, err := s.Repo.CountTransactions(account.ID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: move or delete its %d transactions first", ErrAccountInUse, count)
+	}
+
+	return s.Repo.DeleteByID(account.ID)
+}
+
+// GetRegister returns the account's transactions dated from from through
+// to, oldest first, each with the running balance after it. The dates are
+// days in the user's time zone; a zero from or to leaves that end open.
+func (s *AccountService) GetRegister(username string, id uint, from, to time.Time) ([]*RegisterEntry, error) {
+	_, account, err := s.findOwnedAccount(username, id)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := s.location(account.UserID)
+	if err != nil {
+		return nil, err
+	}
+	var before time.Time
+	if !from.IsZero() {
+		from = startOfDay(from, loc)
+	}
+	if !to.IsZero() {
+		before = startOfDay(to, loc).AddDate(0, 0, 1)
+	}
+
+	balance := account.OpeningBalance
+	if !from.IsZero() {
+		total, err := s.Repo.LedgerTotal(account.ID, from)
+		if err != nil {
+			return nil, err
+		}
+		balance += total
+	}
+
+	transactions, err := s.Repo.FindTransactions(account.ID, from, before)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*RegisterEntry, 0, len(transactions))
+	for _, t := range transactions {
+		balance += BalanceChange(t)
+		entries = append(entries, &RegisterEntry{Transaction: t, Balance: balance})
+	}
+	return entries, nil
+}
+
+// apply validates input and copies it onto account.
+func (s *AccountService) apply(account *models.Account, input AccountInput) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidAccount)
+	}
+
+	kind, err := ParseType(input.Type)
+	if err != nil {
+		return err
+	}
+
+	currency := input.Currency
+	if currency == "" {
+		if currency, err = s.baseCurrency(account.UserID); err != nil {
+			return err
+		}
+	} else if !currency.Valid() {
+		return fmt.Errorf("%w: %w", ErrInvalidAccount, money.ErrUnknownCurrency)
+	}
+	if !input.OpeningBalance.FitsCurrency(currency) {
+		return fmt.Errorf("%w: %s balances have %d decimal places", ErrInvalidAccount, currency, currency.Exponent())
+	}
+
+	account.Name = name
+	account.Type = kind
+	account.Currency = currency
+	account.OpeningBalance = input.OpeningBalance
+	return nil
+}
+
+func (s *AccountService) baseCurrency(userID uint) (money.Currency, error) {
+	if s.Settings == nil {
+		return money.DefaultCurrency, nil
+	}
+
+	settings, err := s.Settings.SettingsForUserID(userID)
+	if err != nil {
+		return "", err
+	}
+	return settings.BaseCurrency, nil
+}
+
+// findOwnedAccount loads an account and verifies that it belongs to the user.
+func (s *AccountService) findOwnedAccount(username string, id uint) (*models.User, *models.Account, error) {
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	account, err := s.Repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrAccountNotFound
+		}
+		return nil, nil, err
+	}
+
+	if account.UserID != user.ID {
+		return nil, nil, ErrAccountAccessDenied
+	}
+
+	return user, account, nil
+}
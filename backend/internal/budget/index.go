@@ -0,0 +1,98 @@
+package budget
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"gorm.io/gorm"
+)
+
+// periodIndexName is the unique index that allows a user one budget per
+// category and month. Overall budgets have no category, and MySQL lets NULLs
+// repeat in a unique index, so the index covers COALESCE(category_id, 0).
+const periodIndexName = "idx_budgets_period"
+
+// mysqlDuplicateEntry is the MySQL error number for a unique key violation.
+const mysqlDuplicateEntry = 1062
+
+// periodColumns are the columns the period index covers.
+const periodColumns = "user_id, COALESCE(category_id, 0), budget_year, budget_month"
+
+// EnsureUniqueIndex creates the unique index on budget periods on MySQL,
+// first merging the duplicates that concurrent requests could create before
+// it existed. Other databases are left without it.
+func EnsureUniqueIndex(db *gorm.DB) error {
+	if db.Dialector.Name() != "mysql" || db.Migrator().HasIndex(&models.Budget{}, periodIndexName) {
+		return nil
+	}
+
+	if err := mergeDuplicateBudgets(db); err != nil {
+		return err
+	}
+	return db.Exec(fmt.Sprintf("CREATE UNIQUE INDEX %s ON budgets (user_id, (COALESCE(category_id, 0)), budget_year, budget_month)", periodIndexName)).Error
+}
+
+// mergeDuplicateBudgets keeps one budget of each user, category and month:
+// the one with the highest limit, or the oldest of those. It takes on the
+// rollover settings of the others and the carry-over of the one that was
+// rolled over, and the others are deleted and logged. Spent amounts are not
+// moved over; RECONCILE_ON_STARTUP=repair rebuilds them.
+func mergeDuplicateBudgets(db *gorm.DB) error {
+	duplicated := db.Model(&models.Budget{}).Select(periodColumns).Group(periodColumns).Having("COUNT(*) > 1")
+
+	var budgets []*models.Budget
+	err := db.Where("("+periodColumns+") IN (?)", duplicated).
+		Order("user_id, category_id, budget_year, budget_month, amount_limit DESC, id").
+		Find(&budgets).Error
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var kept *models.Budget
+		for i, budget := range budgets {
+			if kept == nil || !samePeriod(kept, budget) {
+				kept = budget
+				continue
+			}
+
+			kept.RolloverPositive = kept.RolloverPositive || budget.RolloverPositive
+			kept.RolloverNegative = kept.RolloverNegative || budget.RolloverNegative
+			if !kept.RolledOver && budget.RolledOver {
+				kept.RolledOver = true
+				kept.CarryOverAmount = budget.CarryOverAmount
+			}
+			kept.RemainingAmount = kept.AmountLimit + kept.CarryOverAmount - kept.SpentAmount
+
+			if err := tx.Delete(&models.Budget{}, budget.ID).Error; err != nil {
+				return err
+			}
+			log.Printf("Removed duplicate budget %d (user %d, %s/%d, limit %s), merged into budget %d",
+				budget.ID, budget.UserID, budget.BudgetMonth, budget.BudgetYear, budget.AmountLimit, kept.ID)
+
+			if last := i == len(budgets)-1; last || !samePeriod(kept, budgets[i+1]) {
+				if err := tx.Save(kept).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// samePeriod reports whether a and b are budgets of the same user, category
+// and month.
+func samePeriod(a, b *models.Budget) bool {
+	sameCategory := a.CategoryID == nil && b.CategoryID == nil ||
+		a.CategoryID != nil && b.CategoryID != nil && *a.CategoryID == *b.CategoryID
+	return a.UserID == b.UserID && sameCategory && a.BudgetMonth == b.BudgetMonth && a.BudgetYear == b.BudgetYear
+}
+
+// isDuplicateKey reports whether err is a unique key violation.
+func isDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry
+}
@@ -0,0 +1,111 @@
+package budget
+
+import (
+	"fmt"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+)
+
+type BudgetDrift struct {
+	BudgetID      uint         `json:"budget_id"`
+	UserID        uint         `json:"user_id"`
+	CategoryID    *uint        `json:"category_id,omitempty"`
+	BudgetMonth   string       `json:"budget_month"`
+	BudgetYear    int          `json:"budget_year"`
+	RecordedSpent money.Amount `json:"recorded_spent" swaggertype:"number"`
+	LedgerSpent   money.Amount `json:"ledger_spent" swaggertype:"number"`
+}
+
+type ReconciliationReport struct {
+	BudgetsChecked int           `json:"budgets_checked"`
+	Drifts         []BudgetDrift `json:"drifts"`
+	Repaired       bool          `json:"repaired"`
+}
+
+// Summary returns a one-line description suitable for logs.
+func (r *ReconciliationReport) Summary() string {
+	action := "not repaired"
+	if r.Repaired {
+		action = "repaired"
+	}
+	if len(r.Drifts) == 0 {
+		return fmt.Sprintf("checked %d budgets, no drift found", r.BudgetsChecked)
+	}
+	return fmt.Sprintf("checked %d budgets, %d drifted (%s)", r.BudgetsChecked, len(r.Drifts), action)
+}
+
+// ReconcileForUser compares the user's budgets with the transactions ledger
+// and, when repair is set, rewrites any that have drifted.
+func (s *BudgetService) ReconcileForUser(username string, repair bool) (*ReconciliationReport, error) {
+	user, err := s.UserService.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	budgets, err := s.Repo.FindForReconciliation(&user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.reconcile(budgets, repair)
+}
+
+// ReconcileAll checks every budget in the database. It is used by the startup
+// check and the reconcile command.
+func (s *BudgetService) ReconcileAll(repair bool) (*ReconciliationReport, error) {
+	budgets, err := s.Repo.FindForReconciliation(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.reconcile(budgets, repair)
+}
+
+func (s *BudgetService) reconcile(budgets []*models.Budget, repair bool) (*ReconciliationReport, error) {
+	report := &ReconciliationReport{
+		BudgetsChecked: len(budgets),
+		Drifts:         []BudgetDrift{},
+		Repaired:       repair,
+	}
+
+	zones := s.zones()
+	for _, budget := range budgets {
+		loc, err := zones.location(budget.UserID)
+		if err != nil {
+			return nil, err
+		}
+		start, end, err := Period(budget.BudgetMonth, budget.BudgetYear, loc)
+		if err != nil {
+			return nil, err
+		}
+
+		spent, err := s.Repo.SumLedgerSpent(budget.UserID, budget.CategoryID, start, end)
+		if err != nil {
+			return nil, err
+		}
+
+		if spent == budget.SpentAmount &&
+			budget.AmountLimit+budget.CarryOverAmount-spent == budget.RemainingAmount {
+			continue
+		}
+
+		report.Drifts = append(report.Drifts, BudgetDrift{
+			BudgetID:      budget.ID,
+			UserID:        budget.UserID,
+			CategoryID:    budget.CategoryID,
+			BudgetMonth:   budget.BudgetMonth,
+			BudgetYear:    budget.BudgetYear,
+			RecordedSpent: budget.SpentAmount,
+			LedgerSpent:   spent,
+		})
+
+		if repair {
+			if err := s.Repo.RecalculateSpent(budget.ID, start, end); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return report, nil
+}
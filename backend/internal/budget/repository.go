@@ -1,13 +1,33 @@
 package budget
 
-import "github.com/shaikhjunaidx/pennywise-backend/models"
+import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+)
 
 type BudgetRepository interface {
 	Create(budget *models.Budget) error
 	Update(budget *models.Budget) error
+	// SumLedgerSpent and RecalculateSpent count the transactions dated from
+	// start up to but not including end, the budget month in the user's time
+	// zone.
+	SumLedgerSpent(userID uint, categoryID *uint, start, end time.Time) (money.Amount, error)
+	RecalculateSpent(id uint, start, end time.Time) error
+	UpdateAmountLimit(id uint, amountLimit money.Amount) error
+	UpdateRollover(id uint, positive, negative bool) error
 	DeleteByID(id uint) error
 	FindByID(id uint) (*models.Budget, error)
+	// FindAllByUserID returns every budget of the user, for all months.
 	FindAllByUserID(userID uint) ([]*models.Budget, error)
 	FindByUserIDAndCategoryID(userID uint, categoryID *uint, month string, year int) (*models.Budget, error)
+	// FindByUserIDAndCategoryIDForUpdate is FindByUserIDAndCategoryID as a
+	// locking read, which sees budgets committed since the surrounding
+	// database transaction began.
+	FindByUserIDAndCategoryIDForUpdate(userID uint, categoryID *uint, month string, year int) (*models.Budget, error)
 	FindAllByUserIDAndMonthYear(userID uint, month string, year int) ([]*models.Budget, error)
+	FindCategoryBudgetsForPeriod(month string, year int) ([]*models.Budget, error)
+	FindForReconciliation(userID *uint) ([]*models.Budget, error)
+	FindCategoryParents(userID uint) (map[uint]uint, error)
 }
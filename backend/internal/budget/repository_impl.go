@@ -1,13 +1,14 @@
 package budget
 
 import (
-	"errors"
 	"fmt"
 	"strconv"
 	"time"
 
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
 	"github.com/shaikhjunaidx/pennywise-backend/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BudgetRepositoryImpl struct {
@@ -18,14 +19,99 @@ func NewBudgetRepository(db *gorm.DB) *BudgetRepositoryImpl {
 	return &BudgetRepositoryImpl{DB: db}
 }
 
+// Create stores a new budget. It returns ErrBudgetExists when the user
+// already has a budget for the category and month.
 func (r *BudgetRepositoryImpl) Create(budget *models.Budget) error {
-	return r.DB.Create(budget).Error
+	err := r.DB.Create(budget).Error
+	if isDuplicateKey(err) {
+		return ErrBudgetExists
+	}
+	return err
 }
 
 func (r *BudgetRepositoryImpl) Update(budget *models.Budget) error {
 	return r.DB.Save(budget).Error
 }
 
+// SpendingSQL is the SQL for what a transaction row adds to spending, in the
+// base currency: expenses count in full, refunds are taken off, and income
+// and transfers do not count. Budgets and reports both use it so that their
+// figures agree.
+var SpendingSQL = fmt.Sprintf("CASE transactions.type WHEN '%s' THEN transactions.base_amount WHEN '%s' THEN -transactions.base_amount ELSE 0 END",
+	models.TransactionTypeExpense, models.TransactionTypeRefund)
+
+// Split transactions are counted line by line wherever spending is broken
+// down by category. SplitLinesJoin turns each split transaction into one row
+// per split, leaving other transactions as a single row; LineCategorySQL and
+// LineSpendingSQL are the category and spending of such a row.
+var (
+	SplitLinesJoin  = "LEFT JOIN transaction_splits ON transaction_splits.transaction_id = transactions.id"
+	LineCategorySQL = "COALESCE(transaction_splits.category_id, transactions.category_id)"
+	LineSpendingSQL = fmt.Sprintf("CASE transactions.type WHEN '%[1]s' THEN %[3]s WHEN '%[2]s' THEN -%[3]s ELSE 0 END",
+		models.TransactionTypeExpense, models.TransactionTypeRefund, "COALESCE(transaction_splits.base_amount, transactions.base_amount)")
+)
+
+// SumLedgerSpent totals the user's spending from start up to end. A nil
+// categoryID sums across all categories; otherwise the category's spending
+// is rolled up with that of its subcategories, and only the splits booked
+// against them count from split transactions.
+func (r *BudgetRepositoryImpl) SumLedgerSpent(userID uint, categoryID *uint, start, end time.Time) (money.Amount, error) {
+	query := r.DB.Model(&models.Transaction{}).
+		Select("COALESCE(SUM("+SpendingSQL+"), 0)").
+		Where("transactions.user_id = ? AND transactions.transaction_date >= ? AND transactions.transaction_date < ?", userID, start, end)
+
+	if categoryID != nil {
+		parents, err := r.FindCategoryParents(userID)
+		if err != nil {
+			return 0, err
+		}
+		query = query.Select("COALESCE(SUM("+LineSpendingSQL+"), 0)").
+			Joins(SplitLinesJoin).
+			Where(LineCategorySQL+" IN ?", models.CategoryTree(parents).Subtree(*categoryID))
+	}
+
+	var spent money.Amount
+	if err := query.Scan(&spent).Error; err != nil {
+		return 0, err
+	}
+	return spent, nil
+}
+
+// RecalculateSpent locks the budget row and rewrites its spent and remaining
+// amounts from the transactions ledger between start and end. Any amount
+// carried over from the previous month counts towards what is remaining.
+func (r *BudgetRepositoryImpl) RecalculateSpent(id uint, start, end time.Time) error {
+	var budget models.Budget
+	if err := r.DB.Clauses(clause.Locking{Strength: "UPDATE"}).First(&budget, id).Error; err != nil {
+		return err
+	}
+
+	spent, err := r.SumLedgerSpent(budget.UserID, budget.CategoryID, start, end)
+	if err != nil {
+		return err
+	}
+
+	return r.DB.Model(&models.Budget{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"spent_amount":     spent,
+		"remaining_amount": gorm.Expr("amount_limit + carry_over_amount - ?", spent),
+	}).Error
+}
+
+// UpdateAmountLimit changes the limit without touching the spent total.
+func (r *BudgetRepositoryImpl) UpdateAmountLimit(id uint, amountLimit money.Amount) error {
+	return r.DB.Model(&models.Budget{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"amount_limit":     amountLimit,
+		"remaining_amount": gorm.Expr("? + carry_over_amount - spent_amount", amountLimit),
+	}).Error
+}
+
+func (r *BudgetRepositoryImpl) UpdateRollover(id uint, positive, negative bool) error {
+	return r.DB.Model(&models.Budget{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"rollover_positive": positive,
+		"rollover_negative": negative,
+	}).Error
+}
+
 func (r *BudgetRepositoryImpl) DeleteByID(id uint) error {
 	return r.DB.Delete(&models.Budget{}, id).Error
 }
@@ -40,30 +126,31 @@ func (r *BudgetRepositoryImpl) FindByID(id uint) (*models.Budget, error) {
 
 func (r *BudgetRepositoryImpl) FindAllByUserID(userID uint) ([]*models.Budget, error) {
 	var budgets []*models.Budget
-	month := time.Now().Format("01")
-	year := time.Now().Year()
-
-	if err := r.DB.Where("user_id = ? AND budget_month = ? AND budget_year = ?", userID, month, year).Find(&budgets).Error; err != nil {
+	if err := r.DB.Where("user_id = ?", userID).Find(&budgets).Error; err != nil {
 		return nil, err
 	}
 	return budgets, nil
 }
 
 func (r *BudgetRepositoryImpl) FindByUserIDAndCategoryID(userID uint, categoryID *uint, month string, year int) (*models.Budget, error) {
+	return findPeriodBudget(r.DB, userID, categoryID, month, year)
+}
+
+func (r *BudgetRepositoryImpl) FindByUserIDAndCategoryIDForUpdate(userID uint, categoryID *uint, month string, year int) (*models.Budget, error) {
+	return findPeriodBudget(r.DB.Clauses(clause.Locking{Strength: "UPDATE"}), userID, categoryID, month, year)
+}
+
+// findPeriodBudget returns the user's budget for the category, or the overall
+// budget for a nil category, in the given month.
+func findPeriodBudget(db *gorm.DB, userID uint, categoryID *uint, month string, year int) (*models.Budget, error) {
 	var budget models.Budget
-	var monthFormatted string
 
-	if _, err := strconv.Atoi(month); err == nil && len(month) == 2 {
-		monthFormatted = month
-	} else {
-		parsedTime, err := time.Parse("January", month)
-		if err != nil {
-			return nil, errors.New("invalid month format")
-		}
-		monthFormatted = fmt.Sprintf("%02d", parsedTime.Month())
+	monthFormatted, err := normalizeMonth(month)
+	if err != nil {
+		return nil, err
 	}
 
-	query := r.DB.Where("user_id = ? AND budget_month = ? AND budget_year = ?", userID, monthFormatted, year)
+	query := db.Where("user_id = ? AND budget_month = ? AND budget_year = ?", userID, monthFormatted, year)
 
 	if categoryID != nil {
 		query = query.Where("category_id = ?", *categoryID)
@@ -85,3 +172,78 @@ func (r *BudgetRepositoryImpl) FindAllByUserIDAndMonthYear(userID uint, month st
 	}
 	return budgets, nil
 }
+
+// FindCategoryBudgetsForPeriod returns every user's category budgets for the
+// period. Overall budgets are left out.
+func (r *BudgetRepositoryImpl) FindCategoryBudgetsForPeriod(month string, year int) ([]*models.Budget, error) {
+	var budgets []*models.Budget
+
+	err := r.DB.Where("budget_month = ? AND budget_year = ? AND category_id IS NOT NULL", month, year).
+		Order("id").Find(&budgets).Error
+	if err != nil {
+		return nil, err
+	}
+	return budgets, nil
+}
+
+func (r *BudgetRepositoryImpl) FindForReconciliation(userID *uint) ([]*models.Budget, error) {
+	var budgets []*models.Budget
+
+	query := r.DB.Order("id")
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+	if err := query.Find(&budgets).Error; err != nil {
+		return nil, err
+	}
+	return budgets, nil
+}
+
+// FindCategoryParents maps each of the user's subcategories to its parent.
+func (r *BudgetRepositoryImpl) FindCategoryParents(userID uint) (map[uint]uint, error) {
+	var rows []struct {
+		ID       uint
+		ParentID uint
+	}
+	if err := r.DB.Model(&models.Category{}).Select("id, parent_id").
+		Where("user_id = ? AND parent_id IS NOT NULL", userID).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	parents := make(map[uint]uint, len(rows))
+	for _, row := range rows {
+		parents[row.ID] = row.ParentID
+	}
+	return parents, nil
+}
+
+// normalizeMonth accepts either a two-digit month ("09") or a month name
+// ("September") and returns the two-digit form stored on budgets.
+func normalizeMonth(month string) (string, error) {
+	if m, err := strconv.Atoi(month); err == nil && len(month) == 2 {
+		if m < 1 || m > 12 {
+			return "", fmt.Errorf("%w: %q", ErrInvalidMonth, month)
+		}
+		return month, nil
+	}
+
+	parsedTime, err := time.Parse("January", month)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q", ErrInvalidMonth, month)
+	}
+	return fmt.Sprintf("%02d", parsedTime.Month()), nil
+}
+
+// Period returns the half-open [start, end) range covered by a budget for
+// the given month, written either as "09" or "September", on the calendar of
+// loc, normally the user's time zone.
+func Period(month string, year int, loc *time.Location) (time.Time, time.Time, error) {
+	monthFormatted, err := normalizeMonth(month)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	m, _ := strconv.Atoi(monthFormatted)
+	start := time.Date(year, time.Month(m), 1, 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 1, 0), nil
+}
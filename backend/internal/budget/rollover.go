@@ -0,0 +1,259 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/scheduler"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"gorm.io/gorm"
+)
+
+// RolloverResult counts the budgets a rollover created, filled in and
+// skipped. Pending counts the budgets of users for whom the new month has not
+// started yet.
+type RolloverResult struct {
+	BudgetMonth string `json:"budget_month"`
+	BudgetYear  int    `json:"budget_year"`
+	Created     int    `json:"created"`
+	Updated     int    `json:"updated"`
+	Skipped     int    `json:"skipped"`
+	Pending     int    `json:"pending"`
+}
+
+// RolloverMonth copies every category budget from the month before the given
+// period into the period itself, for all users, whatever the date is where
+// they are. A budget the new month already has, such as one opened by a
+// transaction booked before the rollover ran, takes on the carry-over
+// instead. Budgets that have been rolled over are left untouched, so running
+// it more than once is safe.
+func (s *BudgetService) RolloverMonth(month string, year int) (*RolloverResult, error) {
+	return s.rolloverInto(month, year, nil)
+}
+
+// RolloverDue rolls each user's category budgets over into the month it is
+// for them at now, in their own time zone, as RolloverMonth does. Users
+// whose month has not turned yet are left for a later run.
+func (s *BudgetService) RolloverDue(now time.Time) (*RolloverResult, error) {
+	result := &RolloverResult{}
+	for _, month := range dueMonths(now) {
+		done, err := s.rolloverInto(month.Format("01"), month.Year(), &now)
+		if err != nil {
+			return nil, err
+		}
+		result.BudgetMonth, result.BudgetYear = done.BudgetMonth, done.BudgetYear
+		result.Created += done.Created
+		result.Updated += done.Updated
+		result.Skipped += done.Skipped
+		result.Pending += done.Pending
+	}
+	return result, nil
+}
+
+// dueMonths returns the months it is somewhere in the world at now, in
+// order. Time zones are at most 14 hours from UTC, so there are never more
+// than two.
+func dueMonths(now time.Time) []time.Time {
+	first := monthOf(now.UTC().Add(-14 * time.Hour))
+	last := monthOf(now.UTC().Add(14 * time.Hour))
+	if first.Equal(last) {
+		return []time.Time{first}
+	}
+	return []time.Time{first, last}
+}
+
+func monthOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// rolloverInto rolls the category budgets of the month before the given one
+// over into it. With now set, only users for whom the month has started by
+// then are rolled over.
+func (s *BudgetService) rolloverInto(month string, year int, now *time.Time) (*RolloverResult, error) {
+	target, _, err := Period(month, year, time.UTC)
+	if err != nil {
+		return nil, err
+	}
+	month, year = target.Format("01"), target.Year()
+	previousMonth := target.AddDate(0, -1, 0)
+
+	previousBudgets, err := s.Repo.FindCategoryBudgetsForPeriod(previousMonth.Format("01"), previousMonth.Year())
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RolloverResult{BudgetMonth: month, BudgetYear: year}
+
+	zones := s.zones()
+	for _, previous := range previousBudgets {
+		loc, err := zones.location(previous.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if now != nil {
+			start, _, _ := Period(month, year, loc)
+			if now.Before(start) {
+				result.Pending++
+				continue
+			}
+		}
+
+		outcome, err := s.rolloverBudget(previous, month, year, loc)
+		if err != nil {
+			return nil, err
+		}
+		switch outcome {
+		case rolloverCreated:
+			result.Created++
+		case rolloverUpdated:
+			result.Updated++
+		default:
+			result.Skipped++
+		}
+	}
+
+	return result, nil
+}
+
+type rolloverOutcome int
+
+const (
+	rolloverSkipped rolloverOutcome = iota
+	rolloverCreated
+	rolloverUpdated
+)
+
+// rolloverBudget carries previous into the budget for month, creating it if
+// the month has none yet. The budgets' months follow the calendar of loc, the
+// user's time zone.
+func (s *BudgetService) rolloverBudget(previous *models.Budget, month string, year int, loc *time.Location) (rolloverOutcome, error) {
+	existing, err := s.Repo.FindByUserIDAndCategoryID(previous.UserID, previous.CategoryID, month, year)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return rolloverSkipped, err
+	}
+	if err == nil && existing.RolledOver {
+		return rolloverSkipped, nil
+	}
+
+	// Settle last month against the ledger before carrying anything over.
+	if err := s.recalculate(previous.ID, previous.BudgetMonth, previous.BudgetYear, loc); err != nil {
+		return rolloverSkipped, err
+	}
+	previous, err = s.Repo.FindByID(previous.ID)
+	if err != nil {
+		return rolloverSkipped, err
+	}
+
+	if existing != nil {
+		// A budget opened with no limit of its own takes last month's limit
+		// and rollover settings, as if the rollover had created it.
+		if existing.AmountLimit == 0 {
+			existing.AmountLimit = previous.AmountLimit
+			existing.RolloverPositive = previous.RolloverPositive
+			existing.RolloverNegative = previous.RolloverNegative
+		}
+		existing.CarryOverAmount = carryOver(previous)
+		existing.RolledOver = true
+		existing.RemainingAmount = existing.AmountLimit + existing.CarryOverAmount - existing.SpentAmount
+
+		if err := s.Repo.Update(existing); err != nil {
+			return rolloverSkipped, err
+		}
+		if err := s.recalculate(existing.ID, month, year, loc); err != nil {
+			return rolloverSkipped, err
+		}
+		return rolloverUpdated, nil
+	}
+
+	budget := &models.Budget{
+		UserID:           previous.UserID,
+		CategoryID:       previous.CategoryID,
+		AmountLimit:      previous.AmountLimit,
+		Currency:         previous.Currency,
+		CarryOverAmount:  carryOver(previous),
+		RolloverPositive: previous.RolloverPositive,
+		RolloverNegative: previous.RolloverNegative,
+		RolledOver:       true,
+		BudgetMonth:      month,
+		BudgetYear:       year,
+	}
+	budget.RemainingAmount = budget.AmountLimit + budget.CarryOverAmount
+
+	if err := s.Repo.Create(budget); err != nil {
+		// A transaction opened the budget in the meantime, so carry over
+		// into that one instead.
+		if errors.Is(err, ErrBudgetExists) {
+			return s.rolloverBudget(previous, month, year, loc)
+		}
+		return rolloverSkipped, err
+	}
+
+	// Transactions may already have been booked in the new month.
+	if err := s.recalculate(budget.ID, month, year, loc); err != nil {
+		return rolloverSkipped, err
+	}
+
+	return rolloverCreated, nil
+}
+
+// carryOver returns the part of the budget's remainder that moves into the
+// next month according to its rollover settings.
+func carryOver(budget *models.Budget) money.Amount {
+	switch {
+	case budget.RemainingAmount > 0 && budget.RolloverPositive:
+		return budget.RemainingAmount
+	case budget.RemainingAmount < 0 && budget.RolloverNegative:
+		return budget.RemainingAmount
+	default:
+		return 0
+	}
+}
+
+// RolloverJob runs RolloverDue from the scheduler. It remembers the months
+// it has rolled every user into so that later ticks in the same month are
+// cheap.
+type RolloverJob struct {
+	Service *BudgetService
+
+	mu       sync.Mutex
+	lastDone string
+}
+
+var _ scheduler.Job = (*RolloverJob)(nil)
+
+func NewRolloverJob(service *BudgetService) *RolloverJob {
+	return &RolloverJob{Service: service}
+}
+
+func (j *RolloverJob) Name() string {
+	return "budget-rollover"
+}
+
+func (j *RolloverJob) Run(ctx context.Context, now time.Time) error {
+	var periods []string
+	for _, month := range dueMonths(now) {
+		periods = append(periods, month.Format("2006-01"))
+	}
+	period := strings.Join(periods, ",")
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.lastDone == period {
+		return nil
+	}
+
+	result, err := j.Service.RolloverDue(now)
+	if err != nil {
+		return err
+	}
+
+	if result.Pending == 0 {
+		j.lastDone = period
+	}
+	return nil
+}
@@ -1,10 +1,21 @@
 package budget
 
 import (
+	"errors"
 	"time"
 
+	"github.com/shaikhjunaidx/pennywise-backend/internal/fx"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/user"
 	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrBudgetNotFound     = errors.New("budget not found")
+	ErrBudgetAccessDenied = errors.New("access denied: budget does not belong to the user")
+	ErrBudgetExists       = errors.New("a budget for this category and month already exists")
+	ErrInvalidMonth       = errors.New("invalid month format")
 )
 
 type BudgetService struct {
@@ -13,26 +24,30 @@ type BudgetService struct {
 }
 
 type OverallBudgetResponse struct {
-	UserID             uint    `json:"user_id"`
-	AmountLimit        float64 `json:"amount_limit"`
-	SpentAmount        float64 `json:"spent_amount"`
-	RemainingAmount    float64 `json:"remaining_amount"`
-	BudgetMonth        string  `json:"budget_month"`
-	BudgetYear         int     `json:"budget_year"`
-	UncategorizedTotal float64 `json:"uncategorized_total"`
+	UserID             uint           `json:"user_id"`
+	AmountLimit        money.Amount   `json:"amount_limit" swaggertype:"number"`
+	SpentAmount        money.Amount   `json:"spent_amount" swaggertype:"number"`
+	RemainingAmount    money.Amount   `json:"remaining_amount" swaggertype:"number"`
+	BudgetMonth        string         `json:"budget_month"`
+	BudgetYear         int            `json:"budget_year"`
+	UncategorizedTotal money.Amount   `json:"uncategorized_total" swaggertype:"number"`
+	Currency           money.Currency `json:"currency" swaggertype:"string"`
 }
 
 type MonthlyBudgetResponse struct {
-    Month          string  `json:"month"`
-    Year           int     `json:"year"`
-    AmountLimit    float64 `json:"amount_limit"`
-    SpentAmount    float64 `json:"spent_amount"`
-    RemainingAmount float64 `json:"remaining_amount"`
+	Month           string       `json:"month"`
+	Year            int          `json:"year"`
+	AmountLimit     money.Amount `json:"amount_limit" swaggertype:"number"`
+	SpentAmount     money.Amount `json:"spent_amount" swaggertype:"number"`
+	RemainingAmount money.Amount `json:"remaining_amount" swaggertype:"number"`
 }
 
+// CategoryBudgetHistoryResponse is a category's budget history, with that of
+// each of its subcategories under Children.
 type CategoryBudgetHistoryResponse struct {
-    CategoryID      uint                    `json:"category_id"`
-    History         []MonthlyBudgetResponse `json:"history"`
+	CategoryID uint                             `json:"category_id"`
+	History    []MonthlyBudgetResponse          `json:"history"`
+	Children   []*CategoryBudgetHistoryResponse `json:"children"`
 }
 
 var _ user.UserSignUpBudgetService = (*BudgetService)(nil)
@@ -43,17 +58,37 @@ func NewBudgetService(repo BudgetRepository, userService *user.UserService) *Bud
 		UserService: userService}
 }
 
-func (s *BudgetService) CreateBudget(username string, categoryID *uint, amountLimit float64, month string, year int) (*models.Budget, error) {
+// WithRepo returns a copy of the service that reads and writes through repo,
+// typically a repository bound to an open database transaction.
+func (s *BudgetService) WithRepo(repo BudgetRepository) *BudgetService {
+	return &BudgetService{
+		Repo:        repo,
+		UserService: s.UserService,
+	}
+}
+
+func (s *BudgetService) CreateBudget(username string, categoryID *uint, amountLimit money.Amount, month string, year int) (*models.Budget, error) {
 
 	user, err := s.UserService.FindByUsername(username)
 	if err != nil {
 		return nil, err
 	}
 
+	settings, err := s.settingsFor(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	month, err = normalizeMonth(month)
+	if err != nil {
+		return nil, err
+	}
+
 	budget := &models.Budget{
 		UserID:      user.ID,
 		CategoryID:  categoryID,
 		AmountLimit: amountLimit,
+		Currency:    settings.BaseCurrency,
 		BudgetMonth: month,
 		BudgetYear:  year,
 		SpentAmount: 0,
@@ -67,29 +102,72 @@ func (s *BudgetService) CreateBudget(username string, categoryID *uint, amountLi
 	return budget, nil
 }
 
-func (s *BudgetService) UpdateBudget(budgetID uint, amountLimit float64) (*models.Budget, error) {
+// findOwnedBudget loads a budget and verifies that it belongs to the user.
+func (s *BudgetService) findOwnedBudget(username string, budgetID uint) (*models.Budget, error) {
+	user, err := s.UserService.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
 	budget, err := s.Repo.FindByID(budgetID)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBudgetNotFound
+		}
+		return nil, err
+	}
+
+	if budget.UserID != user.ID {
+		return nil, ErrBudgetAccessDenied
+	}
+
+	return budget, nil
+}
+
+func (s *BudgetService) UpdateBudget(username string, budgetID uint, amountLimit money.Amount) (*models.Budget, error) {
+	budget, err := s.findOwnedBudget(username, budgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Repo.UpdateAmountLimit(budget.ID, amountLimit); err != nil {
 		return nil, err
 	}
 
 	budget.AmountLimit = amountLimit
+	budget.RemainingAmount = amountLimit + budget.CarryOverAmount - budget.SpentAmount
 
-	budget.RemainingAmount = amountLimit - budget.SpentAmount
+	return budget, nil
+}
+
+// UpdateRolloverSettings controls whether the budget's unspent (positive) or
+// overspent (negative) remainder is carried into the next month's budget.
+func (s *BudgetService) UpdateRolloverSettings(username string, budgetID uint, positive, negative bool) (*models.Budget, error) {
+	budget, err := s.findOwnedBudget(username, budgetID)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := s.Repo.Update(budget); err != nil {
+	if err := s.Repo.UpdateRollover(budget.ID, positive, negative); err != nil {
 		return nil, err
 	}
 
+	budget.RolloverPositive = positive
+	budget.RolloverNegative = negative
+
 	return budget, nil
 }
 
-func (s *BudgetService) DeleteBudget(budgetID uint) error {
+func (s *BudgetService) DeleteBudget(username string, budgetID uint) error {
+	if _, err := s.findOwnedBudget(username, budgetID); err != nil {
+		return err
+	}
+
 	return s.Repo.DeleteByID(budgetID)
 }
 
-func (s *BudgetService) GetBudgetByID(budgetID uint) (*models.Budget, error) {
-	return s.Repo.FindByID(budgetID)
+func (s *BudgetService) GetBudgetByID(username string, budgetID uint) (*models.Budget, error) {
+	return s.findOwnedBudget(username, budgetID)
 }
 
 func (s *BudgetService) GetBudgetsForUser(username string) ([]*models.Budget, error) {
@@ -98,7 +176,12 @@ func (s *BudgetService) GetBudgetsForUser(username string) ([]*models.Budget, er
 		return nil, err
 	}
 
-	return s.Repo.FindAllByUserID(user.ID)
+	now, err := s.now(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Repo.FindAllByUserIDAndMonthYear(user.ID, now.Format("01"), now.Year())
 }
 
 func (s *BudgetService) GetBudgetForUserAndCategory(username string, categoryID *uint, month string, year int) (*models.Budget, error) {
@@ -110,30 +193,303 @@ func (s *BudgetService) GetBudgetForUserAndCategory(username string, categoryID
 	return s.Repo.FindByUserIDAndCategoryID(user.ID, categoryID, month, year)
 }
 
-func (s *BudgetService) AddTransactionToBudget(userID uint, categoryID *uint, transactionAmount float64, month string, year int) (*models.Budget, error) {
+// GetCurrentBudgetForUserAndCategory returns the category's budget for the
+// month it currently is in the user's time zone.
+func (s *BudgetService) GetCurrentBudgetForUserAndCategory(username string, categoryID *uint) (*models.Budget, error) {
+	user, err := s.UserService.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
 
+	now, err := s.now(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Repo.FindByUserIDAndCategoryID(user.ID, categoryID, now.Format("01"), now.Year())
+}
+
+// EnsureBudget returns the budget for the category and period, creating it
+// when the month has none yet. New budgets start with a zero limit, or with
+// the previous month's limit when the user has turned on carry-over.
+func (s *BudgetService) EnsureBudget(userID uint, categoryID *uint, month string, year int) (*models.Budget, error) {
 	budget, err := s.Repo.FindByUserIDAndCategoryID(userID, categoryID, month, year)
+	if err == nil {
+		return budget, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	settings, err := s.settingsFor(userID)
 	if err != nil {
 		return nil, err
 	}
 
-	budget.SpentAmount += transactionAmount
-	budget.RemainingAmount = budget.AmountLimit - budget.SpentAmount
+	start, _, err := Period(month, year, user.Location(settings))
+	if err != nil {
+		return nil, err
+	}
 
-	if err := s.Repo.Update(budget); err != nil {
+	amountLimit, err := s.openingLimit(settings, categoryID, start)
+	if err != nil {
+		return nil, err
+	}
+
+	budget = &models.Budget{
+		UserID:          userID,
+		CategoryID:      categoryID,
+		AmountLimit:     amountLimit,
+		Currency:        settings.BaseCurrency,
+		RemainingAmount: amountLimit,
+		BudgetMonth:     start.Format("01"),
+		BudgetYear:      start.Year(),
+	}
+	if err := s.Repo.Create(budget); err != nil {
+		// Another request opened the budget in the meantime. A plain read
+		// inside the caller's database transaction would not see it.
+		if errors.Is(err, ErrBudgetExists) {
+			return s.Repo.FindByUserIDAndCategoryIDForUpdate(userID, categoryID, month, year)
+		}
 		return nil, err
 	}
 
 	return budget, nil
 }
 
+// settingsFor returns the user's settings, or the defaults when the service
+// has no user service to ask.
+func (s *BudgetService) settingsFor(userID uint) (*models.UserSettings, error) {
+	if s.UserService == nil {
+		return &models.UserSettings{UserID: userID, BaseCurrency: money.DefaultCurrency, FirstDayOfWeek: user.DefaultFirstDayOfWeek}, nil
+	}
+	return s.UserService.SettingsForUserID(userID)
+}
+
+// now returns the current time in the user's time zone, which decides the
+// budget month it is for them.
+func (s *BudgetService) now(userID uint) (time.Time, error) {
+	loc, err := s.location(userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Now().In(loc), nil
+}
+
+// location returns the time zone whose calendar the user's budget months
+// follow.
+func (s *BudgetService) location(userID uint) (*time.Location, error) {
+	settings, err := s.settingsFor(userID)
+	if err != nil {
+		return nil, err
+	}
+	return user.Location(settings), nil
+}
+
+// recalculate rebuilds the budget for month from the transactions dated in it
+// on the calendar of loc, the user's time zone.
+func (s *BudgetService) recalculate(budgetID uint, month string, year int, loc *time.Location) error {
+	start, end, err := Period(month, year, loc)
+	if err != nil {
+		return err
+	}
+	return s.Repo.RecalculateSpent(budgetID, start, end)
+}
+
+// zones looks up users' time zones for a run over many budgets, asking once
+// per user.
+type zones struct {
+	service *BudgetService
+	known   map[uint]*time.Location
+}
+
+func (s *BudgetService) zones() *zones {
+	return &zones{service: s, known: map[uint]*time.Location{}}
+}
+
+func (z *zones) location(userID uint) (*time.Location, error) {
+	if loc, ok := z.known[userID]; ok {
+		return loc, nil
+	}
+	loc, err := z.service.location(userID)
+	if err != nil {
+		return nil, err
+	}
+	z.known[userID] = loc
+	return loc, nil
+}
+
+// openingLimit picks the limit for a budget created automatically for the
+// month starting at start.
+func (s *BudgetService) openingLimit(settings *models.UserSettings, categoryID *uint, start time.Time) (money.Amount, error) {
+	if !settings.CarryOverBudgetLimits {
+		return 0, nil
+	}
+
+	previousMonth := start.AddDate(0, -1, 0)
+	previous, err := s.Repo.FindByUserIDAndCategoryID(settings.UserID, categoryID, previousMonth.Format("01"), previousMonth.Year())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	return previous.AmountLimit, nil
+}
+
+// RecalculateBudget rebuilds the category budget for the given period from the
+// transactions ledger and returns it, creating the budget if the month has
+// none. The budgets of the categories above it, which roll its spending up,
+// and the user's overall (uncategorised) budget for the same period are
+// refreshed too where they exist.
+func (s *BudgetService) RecalculateBudget(userID uint, categoryID *uint, month string, year int) (*models.Budget, error) {
+	budget, err := s.EnsureBudget(userID, categoryID, month, year)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := s.location(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.recalculate(budget.ID, month, year, loc); err != nil {
+		return nil, err
+	}
+
+	if categoryID != nil {
+		parents, err := s.Repo.FindCategoryParents(userID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ancestorID := range models.CategoryTree(parents).Ancestors(*categoryID) {
+			if err := s.recalculateIfExists(userID, &ancestorID, month, year, loc); err != nil {
+				return nil, err
+			}
+		}
+		if err := s.recalculateIfExists(userID, nil, month, year, loc); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.Repo.FindByID(budget.ID)
+}
+
+// recalculateIfExists rebuilds the category budget for the period, if the
+// user has one.
+func (s *BudgetService) recalculateIfExists(userID uint, categoryID *uint, month string, year int, loc *time.Location) error {
+	budget, err := s.Repo.FindByUserIDAndCategoryID(userID, categoryID, month, year)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	return s.recalculate(budget.ID, month, year, loc)
+}
+
+// RecalculateCategories rebuilds every budget of the user's for the given
+// categories, in all months, such as after categories have moved to another
+// parent.
+func (s *BudgetService) RecalculateCategories(userID uint, categoryIDs []uint) error {
+	affected := make(map[uint]bool, len(categoryIDs))
+	for _, id := range categoryIDs {
+		affected[id] = true
+	}
+
+	budgets, err := s.Repo.FindForReconciliation(&userID)
+	if err != nil {
+		return err
+	}
+	loc, err := s.location(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, budget := range budgets {
+		if budget.CategoryID == nil || !affected[*budget.CategoryID] {
+			continue
+		}
+		if err := s.recalculate(budget.ID, budget.BudgetMonth, budget.BudgetYear, loc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ChangeCurrency moves all of the user's budgets into base, converting limits
+// and carried-over amounts at the rate of the user's today. Spent amounts are
+// rebuilt from the ledger, whose base amounts are expected to be converted
+// already.
+func (s *BudgetService) ChangeCurrency(userID uint, base money.Currency, converter *fx.Converter) error {
+	budgets, err := s.Repo.FindForReconciliation(&userID)
+	if err != nil {
+		return err
+	}
+
+	today, err := s.now(userID)
+	if err != nil {
+		return err
+	}
+	for _, budget := range budgets {
+		if budget.Currency != base {
+			amountLimit, err := converter.Convert(budget.AmountLimit, budget.Currency, base, today)
+			if err != nil {
+				return err
+			}
+			carryOverAmount, err := converter.Convert(budget.CarryOverAmount, budget.Currency, base, today)
+			if err != nil {
+				return err
+			}
+
+			budget.AmountLimit = amountLimit
+			budget.CarryOverAmount = carryOverAmount
+			budget.Currency = base
+			if err := s.Repo.Update(budget); err != nil {
+				return err
+			}
+		}
+
+		if err := s.recalculate(budget.ID, budget.BudgetMonth, budget.BudgetYear, today.Location()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TimeZoneChanged rebuilds all of the user's budgets after they have moved to
+// another time zone, since the transactions near the start and end of each
+// month may now fall in a different one.
+func (s *BudgetService) TimeZoneChanged(userID uint) error {
+	budgets, err := s.Repo.FindForReconciliation(&userID)
+	if err != nil {
+		return err
+	}
+	loc, err := s.location(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, budget := range budgets {
+		if err := s.recalculate(budget.ID, budget.BudgetMonth, budget.BudgetYear, loc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *BudgetService) CalculateOverallBudget(username string) (*OverallBudgetResponse, error) {
 	user, err := s.UserService.FindByUsername(username)
 	if err != nil {
 		return nil, err
 	}
 
-	currentTime := time.Now()
+	currentTime, err := s.now(user.ID)
+	if err != nil {
+		return nil, err
+	}
 	currentMonth := currentTime.Format("01")
 	currentYear := currentTime.Year()
 
@@ -142,8 +498,31 @@ func (s *BudgetService) CalculateOverallBudget(username string) (*OverallBudgetR
 		return nil, err
 	}
 
+	settings, err := s.settingsFor(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	parents, err := s.Repo.FindCategoryParents(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	tree := models.CategoryTree(parents)
+	budgeted := make(map[uint]bool)
+	limited := make(map[uint]bool)
+	for _, budget := range budgets {
+		if budget.CategoryID == nil {
+			continue
+		}
+		budgeted[*budget.CategoryID] = true
+		if budget.AmountLimit > 0 {
+			limited[*budget.CategoryID] = true
+		}
+	}
+
 	overallBudget := &OverallBudgetResponse{
 		UserID:          user.ID,
+		Currency:        settings.BaseCurrency,
 		AmountLimit:     0,
 		SpentAmount:     0,
 		RemainingAmount: 0,
@@ -152,11 +531,22 @@ func (s *BudgetService) CalculateOverallBudget(username string) (*OverallBudgetR
 	}
 
 	for _, budget := range budgets {
+		// A subcategory's spending is already in the budget of every category
+		// above it, so it is counted once, under the top one. A limit of its
+		// own still counts unless a category above has one too, and its
+		// spending then comes out of the unlimited total above it instead.
+		underBudget := budget.CategoryID != nil && rolledUp(tree, *budget.CategoryID, budgeted)
+		if budget.CategoryID != nil && rolledUp(tree, *budget.CategoryID, limited) {
+			continue
+		}
 		if budget.AmountLimit > 0 {
 			overallBudget.AmountLimit += budget.AmountLimit
 			overallBudget.RemainingAmount += budget.RemainingAmount
 			overallBudget.SpentAmount += budget.SpentAmount
-		} else {
+			if underBudget {
+				overallBudget.UncategorizedTotal -= budget.SpentAmount
+			}
+		} else if !underBudget {
 			overallBudget.UncategorizedTotal += budget.SpentAmount
 		}
 	}
@@ -164,21 +554,48 @@ func (s *BudgetService) CalculateOverallBudget(username string) (*OverallBudgetR
 	return overallBudget, nil
 }
 
+// rolledUp reports whether a category above id is in budgets.
+func rolledUp(tree models.CategoryTree, id uint, budgets map[uint]bool) bool {
+	for _, ancestorID := range tree.Ancestors(id) {
+		if budgets[ancestorID] {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *BudgetService) GetBudgetHistoryForCategory(username string, categoryID uint) (*CategoryBudgetHistoryResponse, error) {
 	user, err := s.UserService.FindByUsername(username)
 	if err != nil {
 		return nil, err
 	}
 
-	currentTime := time.Now()
+	currentTime, err := s.now(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	// Step back from the first of the month, since going back a month from
+	// the 31st can land in the same month.
+	monthStart := time.Date(currentTime.Year(), currentTime.Month(), 1, 0, 0, 0, 0, currentTime.Location())
+
+	parents, err := s.Repo.FindCategoryParents(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.budgetHistory(user.ID, categoryID, monthStart, models.CategoryTree(parents)), nil
+}
 
+// budgetHistory returns the category's budgets for the four months up to the
+// one starting at monthStart, and those of its subcategories below it.
+func (s *BudgetService) budgetHistory(userID, categoryID uint, monthStart time.Time, tree models.CategoryTree) *CategoryBudgetHistoryResponse {
 	history := []MonthlyBudgetResponse{}
 
 	for i := 0; i < 4; i++ {
-		month := currentTime.AddDate(0, -i, 0).Format("01")
-		year := currentTime.AddDate(0, -i, 0).Year()
+		month := monthStart.AddDate(0, -i, 0).Format("01")
+		year := monthStart.AddDate(0, -i, 0).Year()
 
-		budget, err := s.Repo.FindByUserIDAndCategoryID(user.ID, &categoryID, month, year)
+		budget, err := s.Repo.FindByUserIDAndCategoryID(userID, &categoryID, month, year)
 		if err != nil {
 			// If no budget found, still add it to history with 0 values
 			history = append(history, MonthlyBudgetResponse{
@@ -200,8 +617,13 @@ func (s *BudgetService) GetBudgetHistoryForCategory(username string, categoryID
 		})
 	}
 
-	return &CategoryBudgetHistoryResponse{
+	response := &CategoryBudgetHistoryResponse{
 		CategoryID: categoryID,
 		History:    history,
-	}, nil
-}
\ No newline at end of file
+		Children:   []*CategoryBudgetHistoryResponse{},
+	}
+	for _, childID := range tree.Children(categoryID) {
+		response.Children = append(response.Children, s.budgetHistory(userID, childID, monthStart, tree))
+	}
+	return response
+}
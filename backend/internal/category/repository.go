@@ -8,7 +8,9 @@ type CategoryRepository interface {
 	FindByName(name string) (*models.Category, error)
 	FindAll() ([]*models.Category, error)
 	FindAllByUserID(userID uint) ([]*models.Category, error)
+	FindAllByUserIDForUpdate(userID uint) ([]*models.Category, error)
 	Update(category *models.Category) error
 	DeleteByID(id uint) error
 	FindByNameAndUserID(name string, userID uint) (*models.Category, error)
+	FindByNameKindAndUserID(name, kind string, userID uint) (*models.Category, error)
 }
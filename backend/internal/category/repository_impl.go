@@ -3,6 +3,7 @@ package category
 import (
 	"github.com/shaikhjunaidx/pennywise-backend/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CategoryRepositoryImpl struct {
@@ -57,6 +58,17 @@ func (r *CategoryRepositoryImpl) FindAllByUserID(userID uint) ([]*models.Categor
 	return categories, nil
 }
 
+// FindAllByUserIDForUpdate loads the user's categories and locks them until
+// the surrounding database transaction ends, so that concurrent moves cannot
+// build a cycle between them.
+func (r *CategoryRepositoryImpl) FindAllByUserIDForUpdate(userID uint) ([]*models.Category, error) {
+	var categories []*models.Category
+	if err := r.DB.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).Order("id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
 func (r *CategoryRepositoryImpl) FindByNameAndUserID(name string, userID uint) (*models.Category, error) {
     var category models.Category
     err := r.DB.Where("name = ? AND user_id = ?", name, userID).First(&category).Error
@@ -65,3 +77,14 @@ func (r *CategoryRepositoryImpl) FindByNameAndUserID(name string, userID uint) (
     }
     return &category, nil
 }
+
+// FindByNameKindAndUserID finds the user's category with the given name and
+// kind, leaving aside a same-named category of the other kind.
+func (r *CategoryRepositoryImpl) FindByNameKindAndUserID(name, kind string, userID uint) (*models.Category, error) {
+	var category models.Category
+	err := r.DB.Where("name = ? AND kind = ? AND user_id = ?", name, kind, userID).First(&category).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
@@ -2,6 +2,8 @@ package category
 
 import (
 	"errors"
+	"sort"
+	"strings"
 
 	"github.com/shaikhjunaidx/pennywise-backend/internal/user"
 	"github.com/shaikhjunaidx/pennywise-backend/models"
@@ -9,9 +11,16 @@ import (
 
 var _ user.UserSignUpCategoryService = (*CategoryService)(nil)
 
+// BudgetRefresher rebuilds budgets from the ledger, typically
+// *budget.BudgetService.
+type BudgetRefresher interface {
+	RecalculateCategories(userID uint, categoryIDs []uint) error
+}
+
 type CategoryService struct {
 	Repo        CategoryRepository
 	UserService *user.UserService
+	UnitOfWork  UnitOfWork
 }
 
 func NewCategoryService(repo CategoryRepository, userService *user.UserService) *CategoryService {
@@ -21,21 +30,80 @@ func NewCategoryService(repo CategoryRepository, userService *user.UserService)
 	}
 }
 
+var (
+	ErrCategoryNotFound    = errors.New("category not found")
+	ErrInvalidKind         = errors.New("category kind must be expense or income")
+	ErrInvalidParent       = errors.New("parent category not found, does not belong to the user, or is of another kind")
+	ErrCategoryCycle       = errors.New("a category cannot be moved under itself or one of its subcategories")
+	ErrCategoryHasChildren = errors.New("category has subcategories; move or delete them first")
+	ErrCategoryExists      = errors.New("a category with this name already exists elsewhere in the tree or with another kind")
+)
+
+// ParseKind returns the named category kind, defaulting to expense.
+func ParseKind(name string) (string, error) {
+	switch kind := strings.ToLower(strings.TrimSpace(name)); kind {
+	case "":
+		return models.CategoryKindExpense, nil
+	case models.CategoryKindExpense, models.CategoryKindIncome:
+		return kind, nil
+	default:
+		return "", ErrInvalidKind
+	}
+}
+
 func (s *CategoryService) AddCategory(username, name, description string) (*models.Category, error) {
+	return s.AddCategoryOfKind(username, name, description, models.CategoryKindExpense)
+}
+
+// AddCategoryOfKind adds an expense or income category. A top-level category
+// of that kind the user already has by that name is returned as it is.
+func (s *CategoryService) AddCategoryOfKind(username, name, description, kind string) (*models.Category, error) {
+	return s.AddSubcategory(username, name, description, kind, nil)
+}
+
+// AddSubcategory adds a category under parentID, or at the top level when
+// parentID is nil. A subcategory has its parent's kind, which is also the
+// default. A category the user already has by that name is returned as it is
+// when it has the same parent and kind; otherwise ErrCategoryExists is
+// returned, since names are unique among the user's categories.
+func (s *CategoryService) AddSubcategory(username, name, description, kind string, parentID *uint) (*models.Category, error) {
 	user, err := s.UserService.FindByUsername(username)
 	if err != nil {
 		return nil, err
 	}
 
+	var parent *models.Category
+	if parentID != nil {
+		if parent, err = s.findParent(user, *parentID); err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(kind) == "" {
+			kind = kindOf(parent)
+		}
+	}
+
+	kind, err = ParseKind(kind)
+	if err != nil {
+		return nil, err
+	}
+	if parent != nil && kindOf(parent) != kind {
+		return nil, ErrInvalidParent
+	}
+
 	existingCategory, err := s.Repo.FindByNameAndUserID(name, user.ID)
 	if err == nil && existingCategory != nil {
+		if !sameParent(existingCategory.ParentID, parentID) || kindOf(existingCategory) != kind {
+			return nil, ErrCategoryExists
+		}
 		return existingCategory, nil
 	}
 
 	category := &models.Category{
 		UserID:      user.ID,
 		Name:        name,
+		ParentID:    parentID,
 		Description: description,
+		Kind:        kind,
 	}
 
 	if err := s.Repo.Create(category); err != nil {
@@ -77,31 +145,127 @@ func (s *CategoryService) GetAllCategories(username string) ([]*models.Category,
 	return categories, nil
 }
 
-func (s *CategoryService) UpdateCategory(username string, id uint, name, description string) (*models.Category, error) {
-	user, err := s.UserService.FindByUsername(username)
+// CategoryNode is a category with the categories filed under it.
+type CategoryNode struct {
+	*models.Category
+	Children []*CategoryNode `json:"children"`
+}
+
+// GetCategoryTree returns the user's top-level categories, each with its
+// subcategories nested under Children, in the order they were created.
+func (s *CategoryService) GetCategoryTree(username string) ([]*CategoryNode, error) {
+	categories, err := s.GetAllCategories(username)
 	if err != nil {
 		return nil, err
 	}
+	sort.SliceStable(categories, func(i, j int) bool { return categories[i].ID < categories[j].ID })
 
-	category, err := s.Repo.FindByID(id)
+	nodes := make(map[uint]*CategoryNode, len(categories))
+	for _, category := range categories {
+		nodes[category.ID] = &CategoryNode{Category: category, Children: []*CategoryNode{}}
+	}
+
+	roots := []*CategoryNode{}
+	for _, category := range categories {
+		node := nodes[category.ID]
+		if category.ParentID != nil {
+			if parent, ok := nodes[*category.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots, nil
+}
+
+// UpdateCategory renames the category and, when parentID is set, files it
+// under that category, or at the top level when parentID is zero. A nil
+// parentID leaves the category where it is. The new parent must be of the
+// same kind and cannot be the category itself or one of its subcategories.
+// When the category moves, the budgets of the categories it left and joined
+// are rebuilt, since they roll up its spending; the move and the rebuilt
+// budgets are saved together.
+func (s *CategoryService) UpdateCategory(username string, id uint, name, description string, parentID *uint) (*models.Category, error) {
+	user, err := s.UserService.FindByUsername(username)
 	if err != nil {
 		return nil, err
 	}
 
-	if category.UserID != user.ID {
-		return nil, errors.New("access denied: category does not belong to the user")
-	}
+	var category *models.Category
+	err = s.unitOfWork().Do(func(repos Repositories) error {
+		// Locking all of the user's categories serialises concurrent moves,
+		// so the cycle check below sees every other move that is saved.
+		categories, err := repos.Categories.FindAllByUserIDForUpdate(user.ID)
+		if err != nil {
+			return err
+		}
+		byID := make(map[uint]*models.Category, len(categories))
+		for _, c := range categories {
+			byID[c.ID] = c
+		}
+
+		category = byID[id]
+		if category == nil {
+			return ErrCategoryNotFound
+		}
+
+		oldParentID, newParentID := category.ParentID, category.ParentID
+		if parentID != nil {
+			newParentID = parentID
+			if *parentID == 0 {
+				newParentID = nil
+			}
+		}
+		moved := !sameParent(oldParentID, newParentID)
 
-	category.Name = name
-	category.Description = description
+		tree := models.NewCategoryTree(categories)
+		if moved && newParentID != nil {
+			parent := byID[*newParentID]
+			if parent == nil || kindOf(parent) != kindOf(category) {
+				return ErrInvalidParent
+			}
+			if *newParentID == id || tree.IsAncestor(id, *newParentID) {
+				return ErrCategoryCycle
+			}
+		}
 
-	if err := s.Repo.Update(category); err != nil {
+		category.Name = name
+		category.Description = description
+		category.ParentID = newParentID
+
+		if err := repos.Categories.Update(category); err != nil {
+			return err
+		}
+
+		if !moved || repos.Budgets == nil {
+			return nil
+		}
+		var affected []uint
+		for _, start := range []*uint{oldParentID, newParentID} {
+			if start != nil {
+				affected = append(affected, *start)
+				affected = append(affected, tree.Ancestors(*start)...)
+			}
+		}
+		return repos.Budgets.RecalculateCategories(user.ID, affected)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return category, nil
 }
 
+// unitOfWork returns the service's unit of work, or one that writes straight
+// through its repository when none is configured.
+func (s *CategoryService) unitOfWork() UnitOfWork {
+	if s.UnitOfWork == nil {
+		return directUnitOfWork{categories: s.Repo}
+	}
+	return s.UnitOfWork
+}
+
 func (s *CategoryService) DeleteCategory(username string, id uint) error {
 	user, err := s.UserService.FindByUsername(username)
 	if err != nil {
@@ -117,9 +281,51 @@ func (s *CategoryService) DeleteCategory(username string, id uint) error {
 		return errors.New("access denied: category does not belong to the user")
 	}
 
+	tree, err := s.treeOf(user.ID)
+	if err != nil {
+		return err
+	}
+	if len(tree.Children(id)) > 0 {
+		return ErrCategoryHasChildren
+	}
+
 	if err := s.Repo.DeleteByID(id); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// findParent returns the user's category that another is to be filed under.
+func (s *CategoryService) findParent(user *models.User, parentID uint) (*models.Category, error) {
+	parent, err := s.Repo.FindByID(parentID)
+	if err != nil || parent.UserID != user.ID {
+		return nil, ErrInvalidParent
+	}
+	return parent, nil
+}
+
+// treeOf returns the tree the user's categories form.
+func (s *CategoryService) treeOf(userID uint) (models.CategoryTree, error) {
+	categories, err := s.Repo.FindAllByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewCategoryTree(categories), nil
+}
+
+func sameParent(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// kindOf returns the category's kind, treating categories stored before
+// kinds existed as expense categories.
+func kindOf(category *models.Category) string {
+	if category.Kind == "" {
+		return models.CategoryKindExpense
+	}
+	return category.Kind
+}
@@ -0,0 +1,50 @@
+package category
+
+import (
+	"github.com/shaikhjunaidx/pennywise-backend/internal/budget"
+	"gorm.io/gorm"
+)
+
+// Repositories are handed to a unit of work callback. They all share the same
+// database transaction. Budgets is nil when budgets are not kept up to date.
+type Repositories struct {
+	Categories CategoryRepository
+	Budgets    BudgetRefresher
+}
+
+// UnitOfWork runs fn atomically: either every write made through the given
+// repositories is committed or none of them is.
+type UnitOfWork interface {
+	Do(fn func(repos Repositories) error) error
+}
+
+type GormUnitOfWork struct {
+	DB      *gorm.DB
+	Budgets *budget.BudgetService
+}
+
+var _ UnitOfWork = (*GormUnitOfWork)(nil)
+
+func NewUnitOfWork(db *gorm.DB, budgets *budget.BudgetService) *GormUnitOfWork {
+	return &GormUnitOfWork{DB: db, Budgets: budgets}
+}
+
+func (u *GormUnitOfWork) Do(fn func(repos Repositories) error) error {
+	return u.DB.Transaction(func(tx *gorm.DB) error {
+		repos := Repositories{Categories: NewCategoryRepository(tx)}
+		if u.Budgets != nil {
+			repos.Budgets = u.Budgets.WithRepo(budget.NewBudgetRepository(tx))
+		}
+		return fn(repos)
+	})
+}
+
+// directUnitOfWork runs fn against the service's own repository, without a
+// database transaction. It stands in when no unit of work is configured.
+type directUnitOfWork struct {
+	categories CategoryRepository
+}
+
+func (u directUnitOfWork) Do(fn func(repos Repositories) error) error {
+	return fn(Repositories{Categories: u.categories})
+}
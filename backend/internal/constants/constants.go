@@ -1,3 +1,11 @@
 package constants
 
 const DefaultCategoryName = "Uncategorized"
+
+// DefaultIncomeCategoryName is the category income is booked against when
+// none is given.
+const DefaultIncomeCategoryName = "Income"
+
+// DefaultTransferCategoryName is the category transfers between accounts are
+// booked against when none is given.
+const DefaultTransferCategoryName = "Transfers"
@@ -0,0 +1,389 @@
+// Package export writes a user's transactions, categories and budgets as
+// CSV, JSON or OFX so that they can be kept elsewhere or imported again.
+package export
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/budget"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/category"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/user"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+	FormatOFX  Format = "ofx"
+)
+
+// Dataset selects what a CSV export contains, since a CSV file holds a
+// single table. JSON exports always contain everything and OFX exports only
+// transactions.
+type Dataset string
+
+const (
+	DatasetTransactions Dataset = "transactions"
+	DatasetCategories   Dataset = "categories"
+	DatasetBudgets      Dataset = "budgets"
+)
+
+// DocumentVersion is written to JSON exports so that the importer can
+// recognise documents it knows how to read. Version 2 added transaction types;
+// in version 1 documents money received has a negative amount.
+const DocumentVersion = 2
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported export format")
+	ErrInvalidDataset    = errors.New("invalid export dataset")
+	ErrInvalidFilter     = errors.New("invalid export filter")
+)
+
+// ParseFormat returns the named format, defaulting to JSON.
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(strings.ToLower(strings.TrimSpace(name))); format {
+	case "":
+		return FormatJSON, nil
+	case FormatCSV, FormatJSON, FormatOFX:
+		return format, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, name)
+	}
+}
+
+// ParseDataset returns the named dataset, defaulting to transactions.
+func ParseDataset(name string) (Dataset, error) {
+	switch dataset := Dataset(strings.ToLower(strings.TrimSpace(name))); dataset {
+	case "":
+		return DatasetTransactions, nil
+	case DatasetTransactions, DatasetCategories, DatasetBudgets:
+		return dataset, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidDataset, name)
+	}
+}
+
+// Filter narrows an export. From and To are the first and last days
+// included, on the user's calendar; budgets are kept when the month they
+// cover overlaps that range. A zero time leaves that side open. CategoryIDs, when set, keeps only
+// transactions and budgets in those categories, along with the categories
+// themselves.
+type Filter struct {
+	From        time.Time
+	To          time.Time
+	CategoryIDs []uint
+}
+
+func (f Filter) includesCategory(id uint) bool {
+	if len(f.CategoryIDs) == 0 {
+		return true
+	}
+	for _, categoryID := range f.CategoryIDs {
+		if categoryID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// includesPeriod reports whether [start, end) overlaps the filter's range.
+func (f Filter) includesPeriod(start, end time.Time) bool {
+	if !f.From.IsZero() && !end.After(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !start.Before(f.To.AddDate(0, 0, 1)) {
+		return false
+	}
+	return true
+}
+
+// in returns the filter with its days starting at midnight in loc.
+func (f Filter) in(loc *time.Location) Filter {
+	for _, bound := range []*time.Time{&f.From, &f.To} {
+		if !bound.IsZero() {
+			year, month, day := bound.Date()
+			*bound = time.Date(year, month, day, 0, 0, 0, 0, loc)
+		}
+	}
+	return f
+}
+
+// Document is the content of an export. Its JSON form can be read back by
+// the statement importer.
+type Document struct {
+	Version      int            `json:"version"`
+	ExportedAt   time.Time      `json:"exported_at"`
+	BaseCurrency money.Currency `json:"base_currency" swaggertype:"string"`
+	Categories   []Category     `json:"categories"`
+	Budgets      []Budget       `json:"budgets"`
+	Transactions []Transaction  `json:"transactions"`
+
+	// stream, when set, reads further transactions from the store while the
+	// document is written.
+	stream func(fn func(Transaction) error) error
+	// from and to are the first and last days exported, zero when open.
+	from, to time.Time
+}
+
+// EachTransaction calls fn with each transaction of the document in order.
+// Documents made by Collect read their transactions from the store as they
+// are written, so that a large export is never held in memory.
+func (d *Document) EachTransaction(fn func(Transaction) error) error {
+	for _, t := range d.Transactions {
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	if d.stream == nil {
+		return nil
+	}
+	return d.stream(fn)
+}
+
+type Category struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Kind        string `json:"kind"`
+}
+
+// Budget is a budget as exported. Category is empty for the overall budget.
+type Budget struct {
+	ID               uint           `json:"id"`
+	CategoryID       *uint          `json:"category_id,omitempty"`
+	Category         string         `json:"category,omitempty"`
+	BudgetMonth      string         `json:"budget_month"`
+	BudgetYear       int            `json:"budget_year"`
+	AmountLimit      money.Amount   `json:"amount_limit" swaggertype:"number"`
+	SpentAmount      money.Amount   `json:"spent_amount" swaggertype:"number"`
+	CarryOverAmount  money.Amount   `json:"carry_over_amount" swaggertype:"number"`
+	Currency         money.Currency `json:"currency" swaggertype:"string"`
+	RolloverPositive bool           `json:"rollover_positive"`
+	RolloverNegative bool           `json:"rollover_negative"`
+}
+
+type Transaction struct {
+	ID              uint           `json:"id"`
+	TransactionDate time.Time      `json:"transaction_date"`
+	Type            string         `json:"type"`
+	Amount          money.Amount   `json:"amount" swaggertype:"number"`
+	Currency        money.Currency `json:"currency" swaggertype:"string"`
+	BaseAmount      money.Amount   `json:"base_amount" swaggertype:"number"`
+	Description     string         `json:"description,omitempty"`
+	Notes           string         `json:"notes,omitempty"`
+	CategoryID      uint           `json:"category_id"`
+	Category        string         `json:"category,omitempty"`
+	ExternalID      string         `json:"external_id,omitempty"`
+	TransferID      *uint          `json:"transfer_id,omitempty"`
+	TransferIn      bool           `json:"transfer_in,omitempty"`
+	Splits          []Split        `json:"splits,omitempty"`
+}
+
+// Split is one line of a split transaction as exported.
+type Split struct {
+	CategoryID  uint         `json:"category_id"`
+	Category    string       `json:"category,omitempty"`
+	Amount      money.Amount `json:"amount" swaggertype:"number"`
+	BaseAmount  money.Amount `json:"base_amount" swaggertype:"number"`
+	Description string       `json:"description,omitempty"`
+}
+
+type ExportService struct {
+	UserRepo        user.UserRepository
+	CategoryRepo    category.CategoryRepository
+	TransactionRepo transaction.TransactionRepository
+	BudgetRepo      budget.BudgetRepository
+	Settings        transaction.SettingsProvider
+}
+
+func NewExportService(userRepo user.UserRepository, categoryRepo category.CategoryRepository,
+	transactionRepo transaction.TransactionRepository, budgetRepo budget.BudgetRepository) *ExportService {
+	return &ExportService{
+		UserRepo:        userRepo,
+		CategoryRepo:    categoryRepo,
+		TransactionRepo: transactionRepo,
+		BudgetRepo:      budgetRepo,
+	}
+}
+
+// Collect gathers the user's categories and budgets matching filter. Their
+// transactions are not read until the document is written, and then follow
+// in order of date and ID so that repeated exports are stable. Dates are
+// given in the user's time zone.
+func (s *ExportService) Collect(username string, filter Filter) (*Document, error) {
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return nil, fmt.Errorf("%w: to is before from", ErrInvalidFilter)
+	}
+
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, loc, err := s.settings(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	filter = filter.in(loc)
+
+	doc := &Document{
+		Version:      DocumentVersion,
+		ExportedAt:   time.Now().In(loc),
+		BaseCurrency: settings.BaseCurrency,
+		Categories:   []Category{},
+		Budgets:      []Budget{},
+		Transactions: []Transaction{},
+	}
+
+	categories, err := s.CategoryRepo.FindAllByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uint]string, len(categories))
+	for _, c := range categories {
+		names[c.ID] = c.Name
+		if filter.includesCategory(c.ID) {
+			doc.Categories = append(doc.Categories, Category{ID: c.ID, Name: c.Name, Description: c.Description, Kind: c.Kind})
+		}
+	}
+
+	budgets, err := s.BudgetRepo.FindAllByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range budgets {
+		if !includeBudget(filter, b, loc) {
+			continue
+		}
+		exported := Budget{
+			ID:               b.ID,
+			CategoryID:       b.CategoryID,
+			BudgetMonth:      b.BudgetMonth,
+			BudgetYear:       b.BudgetYear,
+			AmountLimit:      b.AmountLimit,
+			SpentAmount:      b.SpentAmount,
+			CarryOverAmount:  b.CarryOverAmount,
+			Currency:         b.Currency,
+			RolloverPositive: b.RolloverPositive,
+			RolloverNegative: b.RolloverNegative,
+		}
+		if b.CategoryID != nil {
+			exported.Category = names[*b.CategoryID]
+		}
+		doc.Budgets = append(doc.Budgets, exported)
+	}
+	sort.SliceStable(doc.Budgets, func(i, j int) bool {
+		a, b := doc.Budgets[i], doc.Budgets[j]
+		if a.BudgetYear != b.BudgetYear {
+			return a.BudgetYear < b.BudgetYear
+		}
+		if a.BudgetMonth != b.BudgetMonth {
+			return budgetStart(a.BudgetMonth, a.BudgetYear).Before(budgetStart(b.BudgetMonth, b.BudgetYear))
+		}
+		return a.ID < b.ID
+	})
+
+	var before time.Time
+	if !filter.To.IsZero() {
+		before = filter.To.AddDate(0, 0, 1)
+	}
+	doc.from, doc.to = filter.From, filter.To
+	doc.stream = func(fn func(Transaction) error) error {
+		return s.TransactionRepo.EachInRange(user.ID, filter.From, before, filter.CategoryIDs, func(t *models.Transaction) error {
+			return fn(Transaction{
+				ID:              t.ID,
+				TransactionDate: t.TransactionDate.In(loc),
+				Type:            t.Type,
+				Amount:          t.Amount,
+				Currency:        t.Currency,
+				BaseAmount:      t.BaseAmount,
+				Description:     t.Description,
+				Notes:           t.Notes,
+				CategoryID:      t.CategoryID,
+				Category:        names[t.CategoryID],
+				ExternalID:      t.ExternalID,
+				TransferID:      t.TransferID,
+				TransferIn:      t.TransferIn,
+				Splits:          exportSplits(t.Splits, names),
+			})
+		})
+	}
+
+	return doc, nil
+}
+
+// exportSplits returns the splits of a transaction as exported, or nil when
+// it is not split.
+func exportSplits(splits []models.TransactionSplit, names map[uint]string) []Split {
+	if len(splits) == 0 {
+		return nil
+	}
+
+	exported := make([]Split, 0, len(splits))
+	for _, split := range splits {
+		exported = append(exported, Split{
+			CategoryID:  split.CategoryID,
+			Category:    names[split.CategoryID],
+			Amount:      split.Amount,
+			BaseAmount:  split.BaseAmount,
+			Description: split.Description,
+		})
+	}
+	return exported
+}
+
+// Location returns the time zone of the user's calendar, in which the days of
+// a Filter are read.
+func (s *ExportService) Location(username string) (*time.Location, error) {
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	_, loc, err := s.settings(user.ID)
+	return loc, err
+}
+
+// settings returns the user's settings, or the defaults when the service has
+// no settings to ask, along with the time zone their dates are written in.
+func (s *ExportService) settings(userID uint) (*models.UserSettings, *time.Location, error) {
+	if s.Settings == nil {
+		return &models.UserSettings{UserID: userID, BaseCurrency: money.DefaultCurrency}, time.Local, nil
+	}
+
+	settings, err := s.Settings.SettingsForUserID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return settings, user.Location(settings), nil
+}
+
+// includeBudget applies filter to a budget, whose month follows the calendar
+// of loc. The overall budget has no category and is only exported when no
+// category filter is set.
+func includeBudget(filter Filter, b *models.Budget, loc *time.Location) bool {
+	if len(filter.CategoryIDs) > 0 && (b.CategoryID == nil || !filter.includesCategory(*b.CategoryID)) {
+		return false
+	}
+
+	start, end, err := budget.Period(b.BudgetMonth, b.BudgetYear, loc)
+	if err != nil {
+		// Keep budgets with an unreadable month rather than lose them.
+		return true
+	}
+	return filter.includesPeriod(start, end)
+}
+
+// budgetStart returns the start of a budget month, for putting budgets in
+// order.
+func budgetStart(month string, year int) time.Time {
+	start, _, _ := budget.Period(month, year, time.UTC)
+	return start
+}
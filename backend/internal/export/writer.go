@@ -0,0 +1,248 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+)
+
+// ContentType returns the MIME type of an export in format.
+func ContentType(format Format) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatOFX:
+		return "application/x-ofx"
+	default:
+		return "application/json"
+	}
+}
+
+// Write encodes doc to w. CSV exports contain the chosen dataset; the other
+// formats ignore it.
+func Write(w io.Writer, format Format, dataset Dataset, doc *Document) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, dataset, doc)
+	case FormatJSON:
+		return WriteJSON(w, doc)
+	case FormatOFX:
+		return WriteOFX(w, doc)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// WriteJSON writes doc as an indented JSON document. Transactions are
+// encoded one at a time as they are read.
+func WriteJSON(w io.Writer, doc *Document) error {
+	buffered := bufio.NewWriter(w)
+	buffered.WriteString("{\n")
+
+	fields := []struct {
+		name  string
+		value interface{}
+	}{
+		{"version", doc.Version},
+		{"exported_at", doc.ExportedAt},
+		{"base_currency", doc.BaseCurrency},
+		{"categories", doc.Categories},
+		{"budgets", doc.Budgets},
+	}
+	for _, field := range fields {
+		value, err := json.MarshalIndent(field.value, "  ", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(buffered, "  %q: %s,\n", field.name, value)
+	}
+
+	buffered.WriteString(`  "transactions": [`)
+	first := true
+	err := doc.EachTransaction(func(t Transaction) error {
+		value, err := json.MarshalIndent(t, "    ", "  ")
+		if err != nil {
+			return err
+		}
+		if !first {
+			buffered.WriteString(",")
+		}
+		first = false
+		buffered.WriteString("\n    ")
+		_, err = buffered.Write(value)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if !first {
+		buffered.WriteString("\n  ")
+	}
+	buffered.WriteString("]\n}\n")
+	return buffered.Flush()
+}
+
+// WriteCSV writes one dataset of doc as CSV with a header line. Transactions
+// use the columns read by the importer's default mapping, so the file can be
+// imported again as it is.
+func WriteCSV(w io.Writer, dataset Dataset, doc *Document) error {
+	writer := csv.NewWriter(w)
+
+	switch dataset {
+	case DatasetTransactions:
+		writer.Write([]string{"date", "amount", "currency", "base_amount", "description", "category", "external_id", "type", "transfer_id", "transfer_in", "splits"})
+		err := doc.EachTransaction(func(t Transaction) error {
+			return writer.Write([]string{
+				t.TransactionDate.Format("2006-01-02"),
+				t.Amount.String(),
+				string(t.Currency),
+				t.BaseAmount.String(),
+				t.Description,
+				t.Category,
+				t.ExternalID,
+				t.Type,
+				formatOptionalID(t.TransferID),
+				formatFlag(t.TransferIn),
+				formatSplits(t.Splits),
+			})
+		})
+		if err != nil {
+			return err
+		}
+	case DatasetCategories:
+		writer.Write([]string{"id", "name", "description", "kind"})
+		for _, c := range doc.Categories {
+			writer.Write([]string{formatID(c.ID), c.Name, c.Description, c.Kind})
+		}
+	case DatasetBudgets:
+		writer.Write([]string{"id", "category", "budget_month", "budget_year", "amount_limit", "spent_amount",
+			"carry_over_amount", "currency", "rollover_positive", "rollover_negative"})
+		for _, b := range doc.Budgets {
+			writer.Write([]string{
+				formatID(b.ID),
+				b.Category,
+				b.BudgetMonth,
+				strconv.Itoa(b.BudgetYear),
+				b.AmountLimit.String(),
+				b.SpentAmount.String(),
+				b.CarryOverAmount.String(),
+				string(b.Currency),
+				strconv.FormatBool(b.RolloverPositive),
+				strconv.FormatBool(b.RolloverNegative),
+			})
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidDataset, dataset)
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteOFX writes the transactions of doc as an OFX 2 bank statement. An OFX
+// statement has a single currency, so amounts are given in the base currency.
+// Expenses and transfers out are written as a negative TRNAMT, as a bank
+// would, income, refunds and transfers in as a positive one. FITID falls back to the PennyWise ID
+// for transactions that were not imported. The statement starts on the first
+// day exported, or the first transaction, and ends on the last day exported,
+// or the day of the export.
+func WriteOFX(w io.Writer, doc *Document) error {
+	end := doc.ExportedAt
+	if !doc.to.IsZero() {
+		end = doc.to
+	}
+
+	// The header gives the statement's start, which for an open range is
+	// only known once the first transaction has been read.
+	started := false
+	start := func(from time.Time) error {
+		started = true
+		if !doc.from.IsZero() {
+			from = doc.from
+		}
+		_, err := fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="211" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS><DTSERVER>%s</DTSERVER><LANGUAGE>ENG</LANGUAGE></SONRS></SIGNONMSGSRSV1>
+<BANKMSGSRSV1><STMTTRNRS><TRNUID>0</TRNUID><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+<STMTRS><CURDEF>%s</CURDEF>
+<BANKACCTFROM><BANKID>PENNYWISE</BANKID><ACCTID>PENNYWISE</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE></BANKACCTFROM>
+<BANKTRANLIST><DTSTART>%s</DTSTART><DTEND>%s</DTEND>
+`, formatOFXDate(doc.ExportedAt), doc.BaseCurrency, formatOFXDate(from), formatOFXDate(end))
+		return err
+	}
+
+	err := doc.EachTransaction(func(t Transaction) error {
+		if !started {
+			if err := start(t.TransactionDate); err != nil {
+				return err
+			}
+		}
+
+		trnType, amount := "DEBIT", -t.BaseAmount
+		if t.Type == models.TransactionTypeIncome || t.Type == models.TransactionTypeRefund || t.TransferIn {
+			trnType, amount = "CREDIT", t.BaseAmount
+		}
+		fitID := t.ExternalID
+		if fitID == "" {
+			fitID = "PW-" + formatID(t.ID)
+		}
+
+		_, err := fmt.Fprintf(w, "<STMTTRN><TRNTYPE>%s</TRNTYPE><DTPOSTED>%s</DTPOSTED><TRNAMT>%s</TRNAMT><FITID>%s</FITID><NAME>%s</NAME></STMTTRN>\n",
+			trnType, formatOFXDate(t.TransactionDate), amount.String(), html.EscapeString(fitID), html.EscapeString(t.Description))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if !started {
+		if err := start(doc.ExportedAt); err != nil {
+			return err
+		}
+	}
+
+	_, err = io.WriteString(w, "</BANKTRANLIST>\n</STMTRS></STMTTRNRS></BANKMSGSRSV1>\n</OFX>\n")
+	return err
+}
+
+func formatOFXDate(t time.Time) string {
+	return t.Format("20060102150405")
+}
+
+func formatID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// formatOptionalID writes a missing ID as an empty field.
+func formatOptionalID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return formatID(*id)
+}
+
+// formatFlag writes a set flag as "true" and leaves an unset one empty.
+func formatFlag(set bool) string {
+	if !set {
+		return ""
+	}
+	return strconv.FormatBool(set)
+}
+
+// formatSplits writes the splits of a transaction as "category=amount" pairs
+// separated by semicolons, and leaves the field empty when it is not split.
+func formatSplits(splits []Split) string {
+	parts := make([]string, 0, len(splits))
+	for _, split := range splits {
+		parts = append(parts, split.Category+"="+split.Amount.String())
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,91 @@
+// Package fx converts money between currencies using exchange rates loaded
+// from files into the exchange_rates table.
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"gorm.io/gorm"
+)
+
+// PivotCurrency is used to cross two currencies that have no direct rate.
+// It is the euro because ECB files quote everything against it.
+const PivotCurrency money.Currency = "EUR"
+
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+type Converter struct {
+	Rates RateRepository
+}
+
+func NewConverter(rates RateRepository) *Converter {
+	return &Converter{Rates: rates}
+}
+
+// Convert returns amount, expressed in from, in the currency to using the
+// latest rate published on or before date, rounded to the minor unit of to.
+func (c *Converter) Convert(amount money.Amount, from, to money.Currency, date time.Time) (money.Amount, error) {
+	if from == to {
+		return amount, nil
+	}
+
+	rate, err := c.Rate(from, to, date)
+	if err != nil {
+		return 0, err
+	}
+	return money.Amount(math.Round(float64(amount) * rate)).RoundTo(to), nil
+}
+
+// Rate returns how many units of to one unit of from buys on date. It uses a
+// direct rate, the inverse of the opposite rate, or a cross rate through
+// PivotCurrency, in that order.
+func (c *Converter) Rate(from, to money.Currency, date time.Time) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+
+	rate, err := c.pairRate(from, to, date)
+	if err == nil || !errors.Is(err, ErrRateNotFound) {
+		return rate, err
+	}
+
+	if from != PivotCurrency && to != PivotCurrency {
+		toPivot, err := c.pairRate(from, PivotCurrency, date)
+		if err == nil {
+			fromPivot, err := c.pairRate(PivotCurrency, to, date)
+			if err == nil {
+				return toPivot * fromPivot, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %s to %s on %s", ErrRateNotFound, from, to, date.Format("2006-01-02"))
+}
+
+func (c *Converter) pairRate(from, to money.Currency, date time.Time) (float64, error) {
+	if c == nil || c.Rates == nil {
+		return 0, ErrRateNotFound
+	}
+
+	direct, err := c.Rates.FindLatest(from, to, date)
+	if err == nil {
+		return direct.Rate, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+
+	inverse, err := c.Rates.FindLatest(to, from, date)
+	if err == nil {
+		return 1 / inverse.Rate, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+
+	return 0, ErrRateNotFound
+}
@@ -0,0 +1,131 @@
+package fx
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+)
+
+var ErrInvalidRateFile = errors.New("invalid exchange rate file")
+
+// ParseCSV reads rates from a CSV file with the header
+//
+//	date,base,quote,rate
+//
+// where date is YYYY-MM-DD and one unit of base buys rate units of quote.
+func ParseCSV(r io.Reader) ([]models.ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing header: %v", ErrInvalidRateFile, err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"date", "base", "quote", "rate"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: missing %q column", ErrInvalidRateFile, name)
+		}
+	}
+
+	var rates []models.ExchangeRate
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidRateFile, line, err)
+		}
+
+		rate, err := newRate(record[columns["date"]], record[columns["base"]], record[columns["quote"]], record[columns["rate"]])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidRateFile, line, err)
+		}
+		rates = append(rates, rate)
+	}
+
+	return rates, nil
+}
+
+// ecbEnvelope matches the European Central Bank reference rate files
+// (eurofxref-daily.xml, eurofxref-hist.xml), which quote every currency
+// against the euro.
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// ParseECBXML reads rates from an ECB euro foreign exchange reference rate
+// file. Every rate has EUR as its base currency.
+func ParseECBXML(r io.Reader) ([]models.ExchangeRate, error) {
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(r).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRateFile, err)
+	}
+
+	var rates []models.ExchangeRate
+	for _, day := range envelope.Days {
+		for _, quoted := range day.Rates {
+			rate, err := newRate(day.Time, string(PivotCurrency), quoted.Currency, quoted.Rate)
+			if err != nil {
+				// The ECB publishes a few currencies we do not support.
+				if errors.Is(err, money.ErrUnknownCurrency) {
+					continue
+				}
+				return nil, fmt.Errorf("%w: %s %s: %v", ErrInvalidRateFile, day.Time, quoted.Currency, err)
+			}
+			rates = append(rates, rate)
+		}
+	}
+
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("%w: no rates found", ErrInvalidRateFile)
+	}
+	return rates, nil
+}
+
+func newRate(date, base, quote, rate string) (models.ExchangeRate, error) {
+	rateDate, err := time.Parse("2006-01-02", strings.TrimSpace(date))
+	if err != nil {
+		return models.ExchangeRate{}, fmt.Errorf("invalid date %q", date)
+	}
+
+	baseCurrency, err := money.ParseCurrency(base)
+	if err != nil {
+		return models.ExchangeRate{}, err
+	}
+	quoteCurrency, err := money.ParseCurrency(quote)
+	if err != nil {
+		return models.ExchangeRate{}, err
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(rate), 64)
+	if err != nil || value <= 0 {
+		return models.ExchangeRate{}, fmt.Errorf("invalid rate %q", rate)
+	}
+
+	return models.ExchangeRate{
+		BaseCurrency:  baseCurrency,
+		QuoteCurrency: quoteCurrency,
+		RateDate:      rateDate,
+		Rate:          value,
+	}, nil
+}
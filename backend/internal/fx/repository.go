@@ -0,0 +1,15 @@
+package fx
+
+import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+)
+
+type RateRepository interface {
+	Upsert(rates []models.ExchangeRate) error
+	// FindLatest returns the most recent rate for the pair published on or
+	// before date.
+	FindLatest(base, quote money.Currency, date time.Time) (*models.ExchangeRate, error)
+}
@@ -0,0 +1,43 @@
+package fx
+
+import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RateRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewRateRepository(db *gorm.DB) *RateRepositoryImpl {
+	return &RateRepositoryImpl{DB: db}
+}
+
+// Upsert inserts the rates, overwriting any existing rate for the same pair
+// and date so that files can be re-imported.
+func (r *RateRepositoryImpl) Upsert(rates []models.ExchangeRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "base_currency"}, {Name: "quote_currency"}, {Name: "rate_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).CreateInBatches(rates, 500).Error
+}
+
+func (r *RateRepositoryImpl) FindLatest(base, quote money.Currency, date time.Time) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+
+	err := r.DB.Where("base_currency = ? AND quote_currency = ? AND rate_date <= ?", base, quote, date).
+		Order("rate_date DESC").
+		First(&rate).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
@@ -0,0 +1,392 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/account"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/handlers"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/middleware"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+)
+
+type AccountRequest struct {
+	Name           string       `json:"name" example:"Everyday Checking"`
+	Type           string       `json:"type,omitempty" enums:"checking,savings,credit_card,cash" example:"checking"`
+	Currency       string       `json:"currency,omitempty" example:"USD"`
+	OpeningBalance money.Amount `json:"opening_balance" swaggertype:"number" example:"1000.00"`
+}
+
+// toInput converts the request for the account service. The returned
+// message is suitable for a 400 response.
+func (req AccountRequest) toInput() (account.AccountInput, string) {
+	currency, err := money.ParseCurrency(req.Currency)
+	if err != nil {
+		return account.AccountInput{}, "Invalid currency code"
+	}
+
+	return account.AccountInput{
+		Name:           req.Name,
+		Type:           req.Type,
+		Currency:       currency,
+		OpeningBalance: req.OpeningBalance,
+	}, ""
+}
+
+type ReconcileRequest struct {
+	StatementDate    string       `json:"statement_date" example:"2024-10-31"`
+	StatementBalance money.Amount `json:"statement_balance" swaggertype:"number" example:"1520.75"`
+}
+
+// GetAccountsHandler lists the user's accounts with their balances.
+// @Summary Get Accounts
+// @Description Retrieves the authenticated user's accounts with their current balances: the opening balance plus every transaction booked to the account.
+// @Tags accounts
+// @Produce  json
+// @Success 200 {array} account.AccountBalance "Accounts"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/accounts [get]
+func GetAccountsHandler(service *account.AccountService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		accounts, err := service.GetAccounts(username)
+		if err != nil {
+			sendAccountError(w, err, "Failed to retrieve accounts")
+			return
+		}
+
+		handlers.SendJSONResponse(w, accounts, http.StatusOK)
+	}
+}
+
+// CreateAccountHandler creates an account.
+// @Summary Create Account
+// @Description Creates an account. The type is checking (the default), savings, credit_card or cash, and the currency defaults to the user's base currency. A credit card that is owed money has a negative balance.
+// @Tags accounts
+// @Accept  json
+// @Produce  json
+// @Param   account  body  handlers.AccountRequest  true  "Account"
+// @Success 201 {object} models.Account "Created account"
+// @Failure 400 {object} map[string]interface{} "Invalid account"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/accounts [post]
+func CreateAccountHandler(service *account.AccountService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		var req AccountRequest
+		if err := handlers.ParseJSONRequest(w, r, &req); err != nil {
+			return
+		}
+
+		input, message := req.toInput()
+		if message != "" {
+			handlers.SendErrorResponse(w, message, http.StatusBadRequest)
+			return
+		}
+
+		created, err := service.CreateAccount(username, input)
+		if err != nil {
+			sendAccountError(w, err, "Failed to create account")
+			return
+		}
+
+		handlers.SendJSONResponse(w, created, http.StatusCreated)
+	}
+}
+
+// GetAccountByIDHandler returns one account with its balance.
+// @Summary Get Account By ID
+// @Description Retrieves an account with its current balance. The account must belong to the authenticated user.
+// @Tags accounts
+// @Produce  json
+// @Param   id  path  int  true  "Account ID"
+// @Success 200 {object} account.AccountBalance "Account"
+// @Failure 400 {object} map[string]interface{} "Invalid account ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Account belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Account not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/accounts/{id} [get]
+func GetAccountByIDHandler(service *account.AccountService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		accountID, ok := parseAccountID(w, r)
+		if !ok {
+			return
+		}
+
+		found, err := service.GetAccount(username, accountID)
+		if err != nil {
+			sendAccountError(w, err, "Failed to retrieve account")
+			return
+		}
+
+		handlers.SendJSONResponse(w, found, http.StatusOK)
+	}
+}
+
+// UpdateAccountHandler replaces an account's details.
+// @Summary Update Account
+// @Description Replaces an account's name, type, currency and opening balance. The currency cannot change once transactions are booked to the account.
+// @Tags accounts
+// @Accept  json
+// @Produce  json
+// @Param   id       path  int                      true  "Account ID"
+// @Param   account  body  handlers.AccountRequest  true  "Account"
+// @Success 200 {object} models.Account "Updated account"
+// @Failure 400 {object} map[string]interface{} "Invalid account"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Account belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Account not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/accounts/{id} [put]
+func UpdateAccountHandler(service *account.AccountService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		accountID, ok := parseAccountID(w, r)
+		if !ok {
+			return
+		}
+
+		var req AccountRequest
+		if err := handlers.ParseJSONRequest(w, r, &req); err != nil {
+			return
+		}
+
+		input, message := req.toInput()
+		if message != "" {
+			handlers.SendErrorResponse(w, message, http.StatusBadRequest)
+			return
+		}
+
+		updated, err := service.UpdateAccount(username, accountID, input)
+		if err != nil {
+			sendAccountError(w, err, "Failed to update account")
+			return
+		}
+
+		handlers.SendJSONResponse(w, updated, http.StatusOK)
+	}
+}
+
+// DeleteAccountHandler deletes an account without transactions.
+// @Summary Delete Account
+// @Description Deletes an account and its reconciliations. Accounts with transactions cannot be deleted.
+// @Tags accounts
+// @Param   id  path  int  true  "Account ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} map[string]interface{} "Invalid account ID, or the account has transactions"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Account belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Account not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/accounts/{id} [delete]
+func DeleteAccountHandler(service *account.AccountService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		accountID, ok := parseAccountID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := service.DeleteAccount(username, accountID); err != nil {
+			sendAccountError(w, err, "Failed to delete account")
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// GetAccountRegisterHandler lists an account's transactions with running
+// balances.
+// @Summary Get Account Register
+// @Description Retrieves the transactions booked to an account, oldest first, each with the balance of the account after it. The optional from and to dates are days in the user's time zone and limit the register to that range.
+// @Tags accounts
+// @Produce  json
+// @Param   id    path   int     true   "Account ID"
+// @Param   from  query  string  false  "First day, as YYYY-MM-DD"
+// @Param   to    query  string  false  "Last day, as YYYY-MM-DD"
+// @Success 200 {array} account.RegisterEntry "Register"
+// @Failure 400 {object} map[string]interface{} "Invalid account ID or date"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Account belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Account not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/accounts/{id}/register [get]
+func GetAccountRegisterHandler(service *account.AccountService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		accountID, ok := parseAccountID(w, r)
+		if !ok {
+			return
+		}
+
+		var from, to time.Time
+		for name, bound := range map[string]*time.Time{"from": &from, "to": &to} {
+			value := r.URL.Query().Get(name)
+			if value == "" {
+				continue
+			}
+			date, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				handlers.SendErrorResponse(w, fmt.Sprintf("Invalid %s date, expected YYYY-MM-DD", name), http.StatusBadRequest)
+				return
+			}
+			*bound = date
+		}
+
+		register, err := service.GetRegister(username, accountID, from, to)
+		if err != nil {
+			sendAccountError(w, err, "Failed to retrieve account register")
+			return
+		}
+
+		handlers.SendJSONResponse(w, register, http.StatusOK)
+	}
+}
+
+// ReconcileAccountHandler matches an account against a bank statement.
+// @Summary Reconcile Account
+// @Description Compares a statement's ending balance with the account's balance at the end of the statement date. When they agree the account is marked reconciled through that date; otherwise nothing is saved and the response shows the difference and the transactions since the last reconciliation.
+// @Tags accounts
+// @Accept  json
+// @Produce  json
+// @Param   id         path  int                        true  "Account ID"
+// @Param   statement  body  handlers.ReconcileRequest  true  "Statement ending date and balance"
+// @Success 200 {object} account.ReconcileResult "Reconciliation result"
+// @Failure 400 {object} map[string]interface{} "Invalid date, or before the last reconciliation"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Account belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Account not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/accounts/{id}/reconcile [post]
+func ReconcileAccountHandler(service *account.AccountService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		accountID, ok := parseAccountID(w, r)
+		if !ok {
+			return
+		}
+
+		var req ReconcileRequest
+		if err := handlers.ParseJSONRequest(w, r, &req); err != nil {
+			return
+		}
+
+		statementDate, err := time.Parse("2006-01-02", req.StatementDate)
+		if err != nil {
+			handlers.SendErrorResponse(w, "Invalid statement_date, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+
+		result, err := service.Reconcile(username, accountID, statementDate, req.StatementBalance)
+		if err != nil {
+			sendAccountError(w, err, "Failed to reconcile account")
+			return
+		}
+
+		handlers.SendJSONResponse(w, result, http.StatusOK)
+	}
+}
+
+// GetReconciliationsHandler lists an account's past reconciliations.
+// @Summary Get Account Reconciliations
+// @Description Retrieves the statements an account has been reconciled against, latest first.
+// @Tags accounts
+// @Produce  json
+// @Param   id  path  int  true  "Account ID"
+// @Success 200 {array} models.Reconciliation "Reconciliations"
+// @Failure 400 {object} map[string]interface{} "Invalid account ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Account belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Account not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/accounts/{id}/reconciliations [get]
+func GetReconciliationsHandler(service *account.AccountService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		accountID, ok := parseAccountID(w, r)
+		if !ok {
+			return
+		}
+
+		reconciliations, err := service.GetReconciliations(username, accountID)
+		if err != nil {
+			sendAccountError(w, err, "Failed to retrieve reconciliations")
+			return
+		}
+
+		handlers.SendJSONResponse(w, reconciliations, http.StatusOK)
+	}
+}
+
+// parseAccountID reads the account ID from the path, answering 400 when it is
+// not a number.
+func parseAccountID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	accountID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		handlers.SendErrorResponse(w, "Invalid account ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return uint(accountID), true
+}
+
+func sendAccountError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, account.ErrAccountNotFound):
+		handlers.SendErrorResponse(w, "Account not found", http.StatusNotFound)
+	case errors.Is(err, account.ErrAccountAccessDenied):
+		handlers.SendErrorResponse(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, account.ErrInvalidAccount), errors.Is(err, account.ErrAccountInUse):
+		handlers.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+	default:
+		handlers.SendErrorResponse(w, message, http.StatusInternalServerError)
+	}
+}
@@ -1,33 +1,40 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/budget"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/handlers"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/middleware"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
 	"github.com/shaikhjunaidx/pennywise-backend/models"
 )
 
+type RolloverRequest struct {
+	RolloverPositive bool `json:"rollover_positive"`
+	RolloverNegative bool `json:"rollover_negative"`
+}
+
 type BudgetRequest struct {
-	CategoryID  *uint   `json:"category_id,omitempty"`
-	AmountLimit float64 `json:"amount_limit"`
-	BudgetMonth string  `json:"budget_month"`
-	BudgetYear  int     `json:"budget_year"`
+	CategoryID  *uint        `json:"category_id,omitempty"`
+	AmountLimit money.Amount `json:"amount_limit" swaggertype:"number"`
+	BudgetMonth string       `json:"budget_month"`
+	BudgetYear  int          `json:"budget_year"`
 }
 
 // CreateBudgetHandler handles the creation of a new budget.
 // @Summary Create Budget
-// @Description Creates a new budget for a user, either overall or for a specific category.
+// @Description Creates a new budget for a user, either overall or for a specific category. A budget for a category with subcategories counts their spending too.
 // @Tags budgets
 // @Accept  json
 // @Produce  json
 // @Param   budget  body  handlers.BudgetRequest  true  "Budget Data"
 // @Success 201 {object} models.Budget "Created Budget"
-// @Failure 400 {object} map[string]interface{} "Invalid request payload"
+// @Failure 400 {object} map[string]interface{} "Invalid request payload or month"
+// @Failure 409 {object} map[string]interface{} "A budget for the category and month already exists"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/budgets [post]
 func CreateBudgetHandler(service *budget.BudgetService) http.HandlerFunc {
@@ -46,7 +53,7 @@ func CreateBudgetHandler(service *budget.BudgetService) http.HandlerFunc {
 
 		createdBudget, err := service.CreateBudget(username, req.CategoryID, req.AmountLimit, req.BudgetMonth, req.BudgetYear)
 		if err != nil {
-			handlers.SendErrorResponse(w, "Failed to create budget", http.StatusInternalServerError)
+			sendBudgetError(w, err, "Failed to create budget")
 			return
 		}
 
@@ -56,17 +63,25 @@ func CreateBudgetHandler(service *budget.BudgetService) http.HandlerFunc {
 
 // GetBudgetByIDHandler handles retrieving a budget by its ID.
 // @Summary Get Budget by ID
-// @Description Retrieves a budget by its ID.
+// @Description Retrieves a budget by its ID. The budget must belong to the authenticated user.
 // @Tags budgets
 // @Produce  json
 // @Param   id   path  int  true  "Budget ID"
 // @Success 200 {object} models.Budget "Budget"
 // @Failure 400 {object} map[string]interface{} "Invalid Budget ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Budget belongs to another user"
 // @Failure 404 {object} map[string]interface{} "Budget not found"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/budgets/{id} [get]
 func GetBudgetByIDHandler(service *budget.BudgetService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
 		vars := mux.Vars(r)
 		budgetID, err := strconv.ParseUint(vars["id"], 10, 32)
 		if err != nil {
@@ -74,9 +89,9 @@ func GetBudgetByIDHandler(service *budget.BudgetService) http.HandlerFunc {
 			return
 		}
 
-		budget, err := service.GetBudgetByID(uint(budgetID))
+		budget, err := service.GetBudgetByID(username, uint(budgetID))
 		if err != nil {
-			handlers.SendErrorResponse(w, "Budget not found", http.StatusNotFound)
+			sendBudgetError(w, err, "Failed to retrieve budget")
 			return
 		}
 
@@ -86,7 +101,7 @@ func GetBudgetByIDHandler(service *budget.BudgetService) http.HandlerFunc {
 
 // GetBudgetsForUserHandler retrieves all budgets for the authenticated user.
 // @Summary Get Budgets for User
-// @Description Retrieves all budgets associated with the authenticated user.
+// @Description Retrieves the authenticated user's budgets for their current month, in their time zone.
 // @Tags budgets
 // @Produce  json
 // @Success 200 {array} models.Budget "List of Budgets"
@@ -111,12 +126,12 @@ func GetBudgetsForUserHandler(service *budget.BudgetService) http.HandlerFunc {
 }
 
 type UpdateBudgetRequest struct {
-	AmountLimit float64 `json:"amount_limit,omitempty"`
+	AmountLimit money.Amount `json:"amount_limit,omitempty" swaggertype:"number"`
 }
 
 // UpdateBudgetHandler handles updating an existing budget.
 // @Summary Update Budget
-// @Description Updates an existing budget by ID.
+// @Description Updates an existing budget by ID. The budget must belong to the authenticated user.
 // @Tags budgets
 // @Accept  json
 // @Produce  json
@@ -124,11 +139,19 @@ type UpdateBudgetRequest struct {
 // @Param   budget  body  handlers.UpdateBudgetRequest  true  "Updated Budget Data"
 // @Success 200 {object} models.Budget "Updated Budget"
 // @Failure 400 {object} map[string]interface{} "Invalid request payload"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Budget belongs to another user"
 // @Failure 404 {object} map[string]interface{} "Budget not found"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/budgets/{id} [put]
 func UpdateBudgetHandler(service *budget.BudgetService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
 		vars := mux.Vars(r)
 		budgetID, err := strconv.ParseUint(vars["id"], 10, 32)
 		if err != nil {
@@ -137,16 +160,61 @@ func UpdateBudgetHandler(service *budget.BudgetService) http.HandlerFunc {
 		}
 
 		var req struct {
-			AmountLimit float64 `json:"amount_limit,omitempty"`
+			AmountLimit money.Amount `json:"amount_limit,omitempty" swaggertype:"number"`
+		}
+
+		if err := handlers.ParseJSONRequest(w, r, &req); err != nil {
+			return
+		}
+
+		budget, err := service.UpdateBudget(username, uint(budgetID), req.AmountLimit)
+		if err != nil {
+			sendBudgetError(w, err, "Failed to update budget")
+			return
+		}
+
+		handlers.SendJSONResponse(w, budget, http.StatusOK)
+	}
+}
+
+// UpdateBudgetRolloverHandler sets how a budget's remainder rolls into next month.
+// @Summary Update Budget Rollover
+// @Description Controls whether unspent (positive) and overspent (negative) amounts are carried into next month's budget when it is created.
+// @Tags budgets
+// @Accept  json
+// @Produce  json
+// @Param   id        path  int                       true  "Budget ID"
+// @Param   rollover  body  handlers.RolloverRequest  true  "Rollover settings"
+// @Success 200 {object} models.Budget "Updated Budget"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Budget belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Budget not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/budgets/{id}/rollover [put]
+func UpdateBudgetRolloverHandler(service *budget.BudgetService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
 		}
 
+		vars := mux.Vars(r)
+		budgetID, err := strconv.ParseUint(vars["id"], 10, 32)
+		if err != nil {
+			handlers.SendErrorResponse(w, "Invalid Budget ID", http.StatusBadRequest)
+			return
+		}
+
+		var req RolloverRequest
 		if err := handlers.ParseJSONRequest(w, r, &req); err != nil {
 			return
 		}
 
-		budget, err := service.UpdateBudget(uint(budgetID), req.AmountLimit)
+		budget, err := service.UpdateRolloverSettings(username, uint(budgetID), req.RolloverPositive, req.RolloverNegative)
 		if err != nil {
-			handlers.SendErrorResponse(w, "Failed to update budget", http.StatusInternalServerError)
+			sendBudgetError(w, err, "Failed to update budget rollover")
 			return
 		}
 
@@ -156,17 +224,25 @@ func UpdateBudgetHandler(service *budget.BudgetService) http.HandlerFunc {
 
 // DeleteBudgetHandler handles deleting a budget by its ID.
 // @Summary Delete Budget
-// @Description Deletes a budget by its ID.
+// @Description Deletes a budget by its ID. The budget must belong to the authenticated user.
 // @Tags budgets
 // @Produce  json
 // @Param   id   path  int  true  "Budget ID"
 // @Success 204 "No Content"
 // @Failure 400 {object} map[string]interface{} "Invalid Budget ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Budget belongs to another user"
 // @Failure 404 {object} map[string]interface{} "Budget not found"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/budgets/{id} [delete]
 func DeleteBudgetHandler(service *budget.BudgetService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
 		vars := mux.Vars(r)
 		budgetID, err := strconv.ParseUint(vars["id"], 10, 32)
 		if err != nil {
@@ -174,8 +250,8 @@ func DeleteBudgetHandler(service *budget.BudgetService) http.HandlerFunc {
 			return
 		}
 
-		if err := service.DeleteBudget(uint(budgetID)); err != nil {
-			handlers.SendErrorResponse(w, "Failed to delete budget", http.StatusInternalServerError)
+		if err := service.DeleteBudget(username, uint(budgetID)); err != nil {
+			sendBudgetError(w, err, "Failed to delete budget")
 			return
 		}
 
@@ -211,7 +287,7 @@ func GetOverallBudgetHandler(service *budget.BudgetService) http.HandlerFunc {
 
 // GetBudgetForUserAndCategoryHandler handles retrieving a budget by category ID for a specific user.
 // @Summary Get Budget by Category ID
-// @Description Retrieves the budget for the specified category ID for the current month and year for the logged-in user.
+// @Description Retrieves the budget for the specified category ID for the logged-in user's current month, in their time zone.
 // @Tags budgets
 // @Produce  json
 // @Param categoryID path int true "Category ID"
@@ -237,10 +313,7 @@ func GetBudgetForUserAndCategoryHandler(service *budget.BudgetService) http.Hand
 			return
 		}
 
-		month := time.Now().Format("January")
-		year := time.Now().Year()
-
-		budget, err := service.GetBudgetForUserAndCategory(username, uintPtr(uint(categoryID)), month, year)
+		budget, err := service.GetCurrentBudgetForUserAndCategory(username, uintPtr(uint(categoryID)))
 		if err != nil {
 			handlers.SendErrorResponse(w, "Budget not found", http.StatusNotFound)
 			return
@@ -252,7 +325,7 @@ func GetBudgetForUserAndCategoryHandler(service *budget.BudgetService) http.Hand
 
 // GetBudgetHistoryByCategoryHandler handles retrieving the last 4 months of budget history for a category.
 // @Summary Get Budget History by Category
-// @Description Retrieves the last 4 months of budget and spending for the given category.
+// @Description Retrieves the last 4 months of budget and spending for the given category, with the history of each of its subcategories nested under children. A category's spending includes that of its subcategories.
 // @Tags budgets
 // @Produce  json
 // @Param categoryID path int true "Category ID"
@@ -286,6 +359,63 @@ func GetBudgetHistoryByCategoryHandler(service *budget.BudgetService) http.Handl
 	}
 }
 
+// ReconcileBudgetsHandler compares the user's budgets with their transactions.
+// @Summary Reconcile Budgets
+// @Description Recomputes spent amounts from the transactions ledger and reports budgets that have drifted. Pass repair=true to fix them.
+// @Tags budgets
+// @Produce  json
+// @Param repair query bool false "Rewrite drifted budgets"
+// @Success 200 {object} budget.ReconciliationReport "Reconciliation report"
+// @Failure 400 {object} map[string]interface{} "Invalid repair flag"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/budgets/reconcile [post]
+func ReconcileBudgetsHandler(service *budget.BudgetService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		repair := false
+		if value := r.URL.Query().Get("repair"); value != "" {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				handlers.SendErrorResponse(w, "Invalid repair flag", http.StatusBadRequest)
+				return
+			}
+			repair = parsed
+		}
+
+		report, err := service.ReconcileForUser(username, repair)
+		if err != nil {
+			handlers.SendErrorResponse(w, "Failed to reconcile budgets", http.StatusInternalServerError)
+			return
+		}
+
+		handlers.SendJSONResponse(w, report, http.StatusOK)
+	}
+}
+
+// sendBudgetError maps budget ownership errors to 404/403, duplicate budgets
+// to 409, invalid months to 400 and everything else to a 500 with the given
+// message.
+func sendBudgetError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, budget.ErrBudgetNotFound):
+		handlers.SendErrorResponse(w, "Budget not found", http.StatusNotFound)
+	case errors.Is(err, budget.ErrBudgetAccessDenied):
+		handlers.SendErrorResponse(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, budget.ErrBudgetExists):
+		handlers.SendErrorResponse(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, budget.ErrInvalidMonth):
+		handlers.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+	default:
+		handlers.SendErrorResponse(w, message, http.StatusInternalServerError)
+	}
+}
+
 // Helper function to return a pointer to a uint
 func uintPtr(i uint) *uint {
 	return &i
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -15,17 +16,20 @@ import (
 type CategoryRequest struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	Kind        string `json:"kind,omitempty" enums:"expense,income" example:"expense"`
+	ParentID    *uint  `json:"parent_id,omitempty" example:"3"`
 }
 
 // CreateCategoryHandler handles the creation of a new category.
 // @Summary Create Category
-// @Description Creates a new category for transactions and budgets.
+// @Description Creates a new category for transactions and budgets. The kind is expense (the default) or income, and is fixed once the category exists. Give parent_id to file the category under another of the same kind, such as Groceries under Food; a subcategory takes its parent's kind by default. Creating a category that already exists with the same parent and kind returns it; one with that name elsewhere is a conflict.
 // @Tags categories
 // @Accept  json
 // @Produce  json
 // @Param   category  body  handlers.CategoryRequest  true  "Category"
 // @Success 201 {object} models.Category "Created Category"
-// @Failure 400 {object} map[string]interface{} "Invalid request payload"
+// @Failure 400 {object} map[string]interface{} "Invalid request payload, kind or parent"
+// @Failure 409 {object} map[string]interface{} "A category with this name exists under another parent or with another kind"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/categories [post]
 func CreateCategoryHandler(service *category.CategoryService) http.HandlerFunc {
@@ -42,13 +46,20 @@ func CreateCategoryHandler(service *category.CategoryService) http.HandlerFunc {
 			return
 		}
 
-		category, err := service.AddCategory(username, req.Name, req.Description)
+		created, err := service.AddSubcategory(username, req.Name, req.Description, req.Kind, req.ParentID)
 		if err != nil {
-			handlers.SendErrorResponse(w, "Failed to create category", http.StatusInternalServerError)
+			switch {
+			case errors.Is(err, category.ErrInvalidKind), errors.Is(err, category.ErrInvalidParent):
+				handlers.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+			case errors.Is(err, category.ErrCategoryExists):
+				handlers.SendErrorResponse(w, err.Error(), http.StatusConflict)
+			default:
+				handlers.SendErrorResponse(w, "Failed to create category", http.StatusInternalServerError)
+			}
 			return
 		}
 
-		handlers.SendJSONResponse(w, category, http.StatusCreated)
+		handlers.SendJSONResponse(w, created, http.StatusCreated)
 	}
 }
 
@@ -91,10 +102,10 @@ func GetCategoryByIDHandler(service *category.CategoryService) http.HandlerFunc
 
 // GetAllCategoriesHandler handles retrieving all categories for the user.
 // @Summary Get All Categories
-// @Description Retrieves all categories for the authenticated user.
+// @Description Retrieves all categories for the authenticated user as a tree: the top-level categories, each with its subcategories nested under children.
 // @Tags categories
 // @Produce  json
-// @Success 200 {array} models.Category "List of Categories"
+// @Success 200 {array} category.CategoryNode "Category tree"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/categories [get]
 func GetAllCategoriesHandler(service *category.CategoryService) http.HandlerFunc {
@@ -105,7 +116,7 @@ func GetAllCategoriesHandler(service *category.CategoryService) http.HandlerFunc
 			return
 		}
 
-		categories, err := service.GetAllCategories(username)
+		categories, err := service.GetCategoryTree(username)
 		if err != nil {
 			handlers.SendErrorResponse(w, "Failed to retrieve categories", http.StatusInternalServerError)
 			return
@@ -117,14 +128,14 @@ func GetAllCategoriesHandler(service *category.CategoryService) http.HandlerFunc
 
 // UpdateCategoryHandler handles updating an existing category.
 // @Summary Update Category
-// @Description Updates an existing category by ID.
+// @Description Updates an existing category by ID. parent_id files it under another category of the same kind, and a parent_id of 0 moves it to the top level; leaving parent_id out keeps the category where it is. A category cannot be moved under itself or one of its subcategories. Budgets of the categories it leaves and joins are recalculated along with the move.
 // @Tags categories
 // @Accept  json
 // @Produce  json
 // @Param   id          path  int                      true  "Category ID"
 // @Param   category    body  handlers.CategoryRequest false "Category"
 // @Success 200 {object} models.Category "Updated Category"
-// @Failure 400 {object} map[string]interface{} "Invalid request payload"
+// @Failure 400 {object} map[string]interface{} "Invalid request payload or parent"
 // @Failure 404 {object} map[string]interface{} "Category not found"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/categories/{id} [put]
@@ -151,24 +162,31 @@ func UpdateCategoryHandler(service *category.CategoryService) http.HandlerFunc {
 			return
 		}
 
-		category, err := service.UpdateCategory(username, uint(id), req.Name, req.Description)
+		updated, err := service.UpdateCategory(username, uint(id), req.Name, req.Description, req.ParentID)
 		if err != nil {
-			handlers.SendErrorResponse(w, "Category not found", http.StatusNotFound)
+			switch {
+			case errors.Is(err, category.ErrInvalidParent), errors.Is(err, category.ErrCategoryCycle):
+				handlers.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+			case errors.Is(err, category.ErrCategoryNotFound):
+				handlers.SendErrorResponse(w, "Category not found", http.StatusNotFound)
+			default:
+				handlers.SendErrorResponse(w, "Failed to update category", http.StatusInternalServerError)
+			}
 			return
 		}
 
-		handlers.SendJSONResponse(w, category, http.StatusOK)
+		handlers.SendJSONResponse(w, updated, http.StatusOK)
 	}
 }
 
 // DeleteCategoryHandler handles deleting a category by its ID.
 // @Summary Delete Category
-// @Description Deletes a category by its ID.
+// @Description Deletes a category by its ID. A category with subcategories cannot be deleted until they are moved or deleted.
 // @Tags categories
 // @Produce  json
 // @Param   id   path  int  true  "Category ID"
 // @Success 204 "No Content"
-// @Failure 400 {object} map[string]interface{} "Invalid Category ID"
+// @Failure 400 {object} map[string]interface{} "Invalid Category ID, or the category has subcategories"
 // @Failure 404 {object} map[string]interface{} "Category not found"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/categories/{id} [delete]
@@ -189,6 +207,10 @@ func DeleteCategoryHandler(service *category.CategoryService) http.HandlerFunc {
 		}
 
 		if err := service.DeleteCategory(username, uint(id)); err != nil {
+			if errors.Is(err, category.ErrCategoryHasChildren) {
+				handlers.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+				return
+			}
 			handlers.SendErrorResponse(w, "Category not found", http.StatusNotFound)
 			return
 		}
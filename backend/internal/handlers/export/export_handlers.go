@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/export"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/handlers"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/middleware"
+)
+
+// ExportHandler writes the authenticated user's data as a file download.
+// @Summary Export Data
+// @Description Exports the user's transactions, categories and budgets. JSON exports contain everything and can be imported again through /api/imports/preview. CSV exports contain one dataset, with transactions in the columns read by the CSV importer. OFX exports contain transactions only, in the base currency.
+// @Tags export
+// @Produce  json
+// @Produce  text/csv
+// @Produce  application/x-ofx
+// @Param   format       query  string  false  "json (default), csv or ofx"
+// @Param   dataset      query  string  false  "For CSV: transactions (default), categories or budgets"
+// @Param   from         query  string  false  "First day included, YYYY-MM-DD"
+// @Param   to           query  string  false  "Last day included, YYYY-MM-DD"
+// @Param   category_id  query  []int   false  "Only these categories; may be repeated or comma-separated"
+// @Success 200 {object} export.Document "Exported data"
+// @Failure 400 {object} map[string]interface{} "Invalid format, dataset or filter"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/export [get]
+func ExportHandler(service *export.ExportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		query := r.URL.Query()
+		format, err := export.ParseFormat(query.Get("format"))
+		if err != nil {
+			handlers.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		dataset, err := export.ParseDataset(query.Get("dataset"))
+		if err != nil {
+			handlers.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		loc, err := service.Location(username)
+		if err != nil {
+			handlers.SendErrorResponse(w, "Failed to export data", http.StatusInternalServerError)
+			return
+		}
+		filter, message := parseFilter(r, loc)
+		if message != "" {
+			handlers.SendErrorResponse(w, message, http.StatusBadRequest)
+			return
+		}
+
+		doc, err := service.Collect(username, filter)
+		if err != nil {
+			if errors.Is(err, export.ErrInvalidFilter) {
+				handlers.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			handlers.SendErrorResponse(w, "Failed to export data", http.StatusInternalServerError)
+			return
+		}
+
+		filename := fmt.Sprintf("pennywise-%s-%s.%s", dataset, doc.ExportedAt.Format("20060102"), format)
+		if format != export.FormatCSV {
+			filename = fmt.Sprintf("pennywise-%s.%s", doc.ExportedAt.Format("20060102"), format)
+		}
+		w.Header().Set("Content-Type", export.ContentType(format))
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		w.WriteHeader(http.StatusOK)
+
+		// The status is already sent, so a failed write can only be logged.
+		if err := export.Write(w, format, dataset, doc); err != nil {
+			log.Printf("Failed to write export for %s: %v", username, err)
+		}
+	}
+}
+
+// parseFilter reads the export filter from the query string, with dates on
+// the calendar of loc, the user's time zone. The returned message is suitable
+// for a 400 response.
+func parseFilter(r *http.Request, loc *time.Location) (export.Filter, string) {
+	var filter export.Filter
+	query := r.URL.Query()
+
+	for name, bound := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		date, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			return filter, fmt.Sprintf("Invalid %s date, expected YYYY-MM-DD", name)
+		}
+		*bound = date
+	}
+
+	for _, value := range query["category_id"] {
+		for _, part := range strings.Split(value, ",") {
+			categoryID, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32)
+			if err != nil {
+				return filter, "Invalid category ID"
+			}
+			filter.CategoryIDs = append(filter.CategoryIDs, uint(categoryID))
+		}
+	}
+
+	return filter, ""
+}
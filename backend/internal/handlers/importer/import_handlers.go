@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/fx"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/handlers"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/importer"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/middleware"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+)
+
+// maxStatementSize caps the size of an uploaded statement.
+const maxStatementSize = 10 << 20
+
+// CommitImportRequest carries the rows to import. AccountID books rows that
+// name no account of their own to that account, which is usually the account
+// the statement came from.
+type CommitImportRequest struct {
+	CategoryID uint           `json:"category_id,omitempty"`
+	AccountID  uint           `json:"account_id,omitempty"`
+	Rows       []importer.Row `json:"rows"`
+}
+
+// PreviewImportHandler parses an uploaded bank statement without saving it.
+// @Summary Preview Statement Import
+// @Description Parses a CSV, OFX/QFX or QIF statement, or a PennyWise JSON export, and returns the rows that would be imported. Rows that could not be read carry an error and are skipped on commit.
+// @Tags imports
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param   file         formData  file    true   "Statement file"
+// @Param   format       formData  string  false  "csv, ofx, qif or json (default: from the file extension)"
+// @Param   mapping_id   formData  int     false  "Saved CSV column mapping"
+// @Param   date_format  formData  string  false  "Date format for QIF files, e.g. DD/MM/YYYY"
+// @Param   currency     formData  string  false  "Currency for rows whose file does not name one"
+// @Success 200 {object} importer.Preview "Parsed rows"
+// @Failure 400 {object} map[string]interface{} "Invalid file, format or option"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Mapping belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Mapping not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/imports/preview [post]
+func PreviewImportHandler(service *importer.ImportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxStatementSize)
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			handlers.SendErrorResponse(w, "Missing or oversized statement file", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		format, err := importer.ParseFormat(r.FormValue("format"), header.Filename)
+		if err != nil {
+			handlers.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var options importer.PreviewOptions
+		if value := r.FormValue("mapping_id"); value != "" {
+			mappingID, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				handlers.SendErrorResponse(w, "Invalid mapping ID", http.StatusBadRequest)
+				return
+			}
+			options.MappingID = uint(mappingID)
+		}
+		if value := r.FormValue("currency"); value != "" {
+			options.Currency, err = money.ParseCurrency(value)
+			if err != nil {
+				handlers.SendErrorResponse(w, "Invalid currency code", http.StatusBadRequest)
+				return
+			}
+		}
+		options.DateFormat = r.FormValue("date_format")
+
+		preview, err := service.Preview(username, format, file, options)
+		if err != nil {
+			sendImportError(w, err, "Failed to read statement")
+			return
+		}
+
+		handlers.SendJSONResponse(w, preview, http.StatusOK)
+	}
+}
+
+// CommitImportHandler stores previewed statement rows as transactions.
+// @Summary Commit Statement Import
+// @Description Creates transactions for the given rows, usually the rows returned by the preview, and updates each affected budget once. Rows with an error are skipped. Rows without a category use category_id, or the default category. Rows without an account are booked to account_id, if given, and must then be in the account's currency.
+// @Tags imports
+// @Accept  json
+// @Produce  json
+// @Param   import  body  handlers.CommitImportRequest  true  "Rows to import"
+// @Success 201 {object} transaction.ImportResult "Import summary"
+// @Failure 400 {object} map[string]interface{} "Invalid row, category or currency, or no exchange rate for a date"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 409 {object} map[string]interface{} "A row is in a reconciled statement period"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/imports/commit [post]
+func CommitImportHandler(service *importer.ImportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		var req CommitImportRequest
+		if err := handlers.ParseJSONRequest(w, r, &req); err != nil {
+			return
+		}
+
+		for i := range req.Rows {
+			if req.Rows[i].AccountID == 0 {
+				req.Rows[i].AccountID = req.AccountID
+			}
+		}
+
+		result, err := service.Commit(username, req.Rows, req.CategoryID)
+		if err != nil {
+			sendImportError(w, err, "Failed to import transactions")
+			return
+		}
+
+		handlers.SendJSONResponse(w, result, http.StatusCreated)
+	}
+}
+
+// GetMappingsHandler lists the user's saved CSV column mappings.
+// @Summary Get Import Mappings
+// @Description Retrieves the CSV column mappings saved by the authenticated user.
+// @Tags imports
+// @Produce  json
+// @Success 200 {array} models.ImportMapping "Import mappings"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/imports/mappings [get]
+func GetMappingsHandler(service *importer.ImportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		mappings, err := service.GetMappings(username)
+		if err != nil {
+			handlers.SendErrorResponse(w, "Failed to retrieve import mappings", http.StatusInternalServerError)
+			return
+		}
+
+		handlers.SendJSONResponse(w, mappings, http.StatusOK)
+	}
+}
+
+// CreateMappingHandler saves a CSV column mapping for a bank.
+// @Summary Create Import Mapping
+// @Description Saves how the columns of a bank's CSV export map onto transaction fields. Date formats use YYYY, YY, MM, DD, M and D.
+// @Tags imports
+// @Accept  json
+// @Produce  json
+// @Param   mapping  body  models.ImportMapping  true  "Import mapping"
+// @Success 201 {object} models.ImportMapping "Created mapping"
+// @Failure 400 {object} map[string]interface{} "Invalid mapping"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/imports/mappings [post]
+func CreateMappingHandler(service *importer.ImportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		var mapping models.ImportMapping
+		if err := handlers.ParseJSONRequest(w, r, &mapping); err != nil {
+			return
+		}
+		mapping.ID = 0
+
+		saved, err := service.SaveMapping(username, &mapping)
+		if err != nil {
+			sendImportError(w, err, "Failed to save import mapping")
+			return
+		}
+
+		handlers.SendJSONResponse(w, saved, http.StatusCreated)
+	}
+}
+
+// UpdateMappingHandler replaces a saved CSV column mapping.
+// @Summary Update Import Mapping
+// @Description Replaces a CSV column mapping. The mapping must belong to the authenticated user.
+// @Tags imports
+// @Accept  json
+// @Produce  json
+// @Param   id       path  int                   true  "Mapping ID"
+// @Param   mapping  body  models.ImportMapping  true  "Import mapping"
+// @Success 200 {object} models.ImportMapping "Updated mapping"
+// @Failure 400 {object} map[string]interface{} "Invalid mapping"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Mapping belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Mapping not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/imports/mappings/{id} [put]
+func UpdateMappingHandler(service *importer.ImportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		vars := mux.Vars(r)
+		mappingID, err := strconv.ParseUint(vars["id"], 10, 32)
+		if err != nil {
+			handlers.SendErrorResponse(w, "Invalid mapping ID", http.StatusBadRequest)
+			return
+		}
+
+		var mapping models.ImportMapping
+		if err := handlers.ParseJSONRequest(w, r, &mapping); err != nil {
+			return
+		}
+		mapping.ID = uint(mappingID)
+
+		saved, err := service.SaveMapping(username, &mapping)
+		if err != nil {
+			sendImportError(w, err, "Failed to save import mapping")
+			return
+		}
+
+		handlers.SendJSONResponse(w, saved, http.StatusOK)
+	}
+}
+
+// DeleteMappingHandler removes a saved CSV column mapping.
+// @Summary Delete Import Mapping
+// @Description Deletes a CSV column mapping. The mapping must belong to the authenticated user.
+// @Tags imports
+// @Param   id  path  int  true  "Mapping ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} map[string]interface{} "Invalid mapping ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Mapping belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Mapping not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/imports/mappings/{id} [delete]
+func DeleteMappingHandler(service *importer.ImportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		vars := mux.Vars(r)
+		mappingID, err := strconv.ParseUint(vars["id"], 10, 32)
+		if err != nil {
+			handlers.SendErrorResponse(w, "Invalid mapping ID", http.StatusBadRequest)
+			return
+		}
+
+		if err := service.DeleteMapping(username, uint(mappingID)); err != nil {
+			sendImportError(w, err, "Failed to delete import mapping")
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func sendImportError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, importer.ErrMappingNotFound):
+		handlers.SendErrorResponse(w, "Import mapping not found", http.StatusNotFound)
+	case errors.Is(err, importer.ErrMappingAccessDenied):
+		handlers.SendErrorResponse(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, importer.ErrInvalidFile),
+		errors.Is(err, importer.ErrUnsupportedFormat),
+		errors.Is(err, importer.ErrInvalidMapping),
+		errors.Is(err, importer.ErrInvalidRow),
+		errors.Is(err, transaction.ErrNothingToImport),
+		errors.Is(err, transaction.ErrInvalidCategory),
+		errors.Is(err, transaction.ErrInvalidAccount),
+		errors.Is(err, money.ErrUnknownCurrency),
+		errors.Is(err, fx.ErrRateNotFound):
+		handlers.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, transaction.ErrReconciled):
+		handlers.SendErrorResponse(w, err.Error(), http.StatusConflict)
+	default:
+		handlers.SendErrorResponse(w, message, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/handlers"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/insights"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/middleware"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/recurring"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
+)
+
+type ConvertSubscriptionRequest struct {
+	Key string `json:"key" example:"USD:netflix com"`
+}
+
+// GetSubscriptionsHandler lists the subscriptions found in the user's
+// transactions.
+// @Summary Get Detected Subscriptions
+// @Description Looks through the authenticated user's expenses for charges with the same merchant and a similar amount at a weekly, fortnightly, monthly, quarterly or yearly cadence, and lists them with their next expected date and annual cost, most expensive first. Merchants that already have a recurring rule and subscriptions that seem to have lapsed are left out.
+// @Tags insights
+// @Produce  json
+// @Success 200 {array} insights.Subscription "Detected subscriptions"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/insights/subscriptions [get]
+func GetSubscriptionsHandler(service *insights.InsightService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		subscriptions, err := service.DetectSubscriptions(username, time.Now())
+		if err != nil {
+			handlers.SendErrorResponse(w, "Failed to detect subscriptions", http.StatusInternalServerError)
+			return
+		}
+
+		handlers.SendJSONResponse(w, subscriptions, http.StatusOK)
+	}
+}
+
+// ConvertSubscriptionHandler turns a detected subscription into a recurring
+// rule.
+// @Summary Convert Subscription to Recurring Rule
+// @Description Creates a monthly, weekly or yearly recurring rule for the detected subscription with the given key, with the latest charge's amount, category and account, on the day of the month it is billed on, starting with the next charge due after today.
+// @Tags insights
+// @Accept  json
+// @Produce  json
+// @Param   subscription  body  handlers.ConvertSubscriptionRequest  true  "Subscription key"
+// @Success 201 {object} models.RecurringRule "Created rule"
+// @Failure 400 {object} map[string]interface{} "Invalid request payload, or the rule could not be created"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "No subscription with that key"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/insights/subscriptions/convert [post]
+func ConvertSubscriptionHandler(service *insights.InsightService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		var req ConvertSubscriptionRequest
+		if err := handlers.ParseJSONRequest(w, r, &req); err != nil {
+			return
+		}
+		if req.Key == "" {
+			handlers.SendErrorResponse(w, "key is required", http.StatusBadRequest)
+			return
+		}
+
+		rule, err := service.ConvertSubscription(username, req.Key, time.Now())
+		if err != nil {
+			switch {
+			case errors.Is(err, insights.ErrSubscriptionNotFound):
+				handlers.SendErrorResponse(w, err.Error(), http.StatusNotFound)
+			case errors.Is(err, recurring.ErrInvalidRule), errors.Is(err, transaction.ErrInvalidCategory),
+				errors.Is(err, transaction.ErrInvalidAccount):
+				handlers.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+			default:
+				handlers.SendErrorResponse(w, "Failed to convert subscription", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		handlers.SendJSONResponse(w, rule, http.StatusCreated)
+	}
+}
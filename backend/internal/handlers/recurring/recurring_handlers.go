@@ -0,0 +1,426 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/handlers"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/middleware"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/recurring"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
+)
+
+type RecurringRuleRequest struct {
+	Type        string       `json:"type,omitempty" enums:"expense,income,refund" example:"expense"`
+	CategoryID  uint         `json:"category_id"`
+	AccountID   uint         `json:"account_id,omitempty"`
+	Amount      money.Amount `json:"amount" swaggertype:"number" example:"15.49"`
+	Currency    string       `json:"currency,omitempty" example:"USD"`
+	Description string       `json:"description" example:"Netflix"`
+	Notes       string       `json:"notes,omitempty"`
+	Frequency   string       `json:"frequency,omitempty" enums:"daily,weekly,monthly,yearly" example:"monthly"`
+	Interval    int          `json:"interval,omitempty" example:"1"`
+	DayOfMonth  int          `json:"day_of_month,omitempty" example:"31"`
+	StartDate   string       `json:"start_date" example:"2024-10-31"`
+	EndDate     string       `json:"end_date,omitempty" example:"2025-10-31"`
+}
+
+// toInput validates the request and converts it for the recurring service.
+// The returned message is suitable for a 400 response.
+func (req RecurringRuleRequest) toInput() (recurring.RuleInput, string) {
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return recurring.RuleInput{}, "Invalid start_date, expected YYYY-MM-DD"
+	}
+
+	var endDate *time.Time
+	if req.EndDate != "" {
+		parsed, err := time.Parse("2006-01-02", req.EndDate)
+		if err != nil {
+			return recurring.RuleInput{}, "Invalid end_date, expected YYYY-MM-DD"
+		}
+		endDate = &parsed
+	}
+
+	currency, err := money.ParseCurrency(req.Currency)
+	if err != nil {
+		return recurring.RuleInput{}, "Invalid currency code"
+	}
+
+	return recurring.RuleInput{
+		Type:        req.Type,
+		CategoryID:  req.CategoryID,
+		AccountID:   req.AccountID,
+		Amount:      req.Amount,
+		Currency:    currency,
+		Description: req.Description,
+		Notes:       req.Notes,
+		Frequency:   req.Frequency,
+		Interval:    req.Interval,
+		DayOfMonth:  req.DayOfMonth,
+		StartDate:   startDate,
+		EndDate:     endDate,
+	}, ""
+}
+
+type OccurrenceRequest struct {
+	Skip        bool          `json:"skip,omitempty"`
+	Amount      *money.Amount `json:"amount,omitempty" swaggertype:"number" example:"17.99"`
+	Description *string       `json:"description,omitempty"`
+	Notes       *string       `json:"notes,omitempty"`
+}
+
+// GetRecurringRulesHandler lists the user's recurring rules.
+// @Summary Get Recurring Rules
+// @Description Retrieves the authenticated user's recurring rules, soonest due first.
+// @Tags recurring
+// @Produce  json
+// @Success 200 {array} models.RecurringRule "Recurring rules"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/recurring [get]
+func GetRecurringRulesHandler(service *recurring.RecurringService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		rules, err := service.GetRules(username)
+		if err != nil {
+			sendRecurringError(w, err, "Failed to retrieve recurring rules")
+			return
+		}
+
+		handlers.SendJSONResponse(w, rules, http.StatusOK)
+	}
+}
+
+// CreateRecurringRuleHandler creates a recurring rule.
+// @Summary Create Recurring Rule
+// @Description Creates a rule that books a transaction every interval days, weeks, months or years from start_date until end_date, if given. Monthly and yearly rules fall on day_of_month, or on the start date's day, and on the last day of shorter months. The transaction fields are checked as for a new transaction; transfers cannot recur. Occurrences are booked as they fall due, including any since a start date in the past.
+// @Tags recurring
+// @Accept  json
+// @Produce  json
+// @Param   rule  body  handlers.RecurringRuleRequest  true  "Recurring rule"
+// @Success 201 {object} models.RecurringRule "Created rule"
+// @Failure 400 {object} map[string]interface{} "Invalid rule, category, account or currency"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/recurring [post]
+func CreateRecurringRuleHandler(service *recurring.RecurringService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		var req RecurringRuleRequest
+		if err := handlers.ParseJSONRequest(w, r, &req); err != nil {
+			return
+		}
+
+		input, message := req.toInput()
+		if message != "" {
+			handlers.SendErrorResponse(w, message, http.StatusBadRequest)
+			return
+		}
+
+		rule, err := service.CreateRule(username, input)
+		if err != nil {
+			sendRecurringError(w, err, "Failed to create recurring rule")
+			return
+		}
+
+		handlers.SendJSONResponse(w, rule, http.StatusCreated)
+	}
+}
+
+// GetRecurringRuleByIDHandler returns one recurring rule.
+// @Summary Get Recurring Rule By ID
+// @Description Retrieves a recurring rule belonging to the authenticated user.
+// @Tags recurring
+// @Produce  json
+// @Param   id  path  int  true  "Rule ID"
+// @Success 200 {object} models.RecurringRule "Recurring rule"
+// @Failure 400 {object} map[string]interface{} "Invalid rule ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Rule belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Rule not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/recurring/{id} [get]
+func GetRecurringRuleByIDHandler(service *recurring.RecurringService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		ruleID, ok := parseRuleID(w, r)
+		if !ok {
+			return
+		}
+
+		rule, err := service.GetRule(username, ruleID)
+		if err != nil {
+			sendRecurringError(w, err, "Failed to retrieve recurring rule")
+			return
+		}
+
+		handlers.SendJSONResponse(w, rule, http.StatusOK)
+	}
+}
+
+// UpdateRecurringRuleHandler replaces a recurring rule.
+// @Summary Update Recurring Rule
+// @Description Replaces a recurring rule. Occurrences already booked or skipped are left alone; the rule carries on from the first date after them on its new schedule.
+// @Tags recurring
+// @Accept  json
+// @Produce  json
+// @Param   id    path  int                            true  "Rule ID"
+// @Param   rule  body  handlers.RecurringRuleRequest  true  "Recurring rule"
+// @Success 200 {object} models.RecurringRule "Updated rule"
+// @Failure 400 {object} map[string]interface{} "Invalid rule, category, account or currency"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Rule belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Rule not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/recurring/{id} [put]
+func UpdateRecurringRuleHandler(service *recurring.RecurringService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		ruleID, ok := parseRuleID(w, r)
+		if !ok {
+			return
+		}
+
+		var req RecurringRuleRequest
+		if err := handlers.ParseJSONRequest(w, r, &req); err != nil {
+			return
+		}
+
+		input, message := req.toInput()
+		if message != "" {
+			handlers.SendErrorResponse(w, message, http.StatusBadRequest)
+			return
+		}
+
+		rule, err := service.UpdateRule(username, ruleID, input)
+		if err != nil {
+			sendRecurringError(w, err, "Failed to update recurring rule")
+			return
+		}
+
+		handlers.SendJSONResponse(w, rule, http.StatusOK)
+	}
+}
+
+// DeleteRecurringRuleHandler deletes a recurring rule.
+// @Summary Delete Recurring Rule
+// @Description Deletes a recurring rule and its skipped and changed occurrences. Transactions it has already booked are kept.
+// @Tags recurring
+// @Param   id  path  int  true  "Rule ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} map[string]interface{} "Invalid rule ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Rule belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Rule not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/recurring/{id} [delete]
+func DeleteRecurringRuleHandler(service *recurring.RecurringService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		ruleID, ok := parseRuleID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := service.DeleteRule(username, ruleID); err != nil {
+			sendRecurringError(w, err, "Failed to delete recurring rule")
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// GetUpcomingOccurrencesHandler lists the occurrences coming up.
+// @Summary Get Upcoming Occurrences
+// @Description Lists the occurrences of the authenticated user's recurring rules from today until the given number of days ahead, in date order, with any change made to them. Skipped occurrences are included and marked.
+// @Tags recurring
+// @Produce  json
+// @Param   days  query  int  false  "Days ahead, 1 to 366 (default 30)"
+// @Success 200 {array} recurring.Occurrence "Upcoming occurrences"
+// @Failure 400 {object} map[string]interface{} "Invalid days"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/recurring/upcoming [get]
+func GetUpcomingOccurrencesHandler(service *recurring.RecurringService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		days := 30
+		if value := r.URL.Query().Get("days"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 1 || parsed > 366 {
+				handlers.SendErrorResponse(w, "Invalid days, expected 1 to 366", http.StatusBadRequest)
+				return
+			}
+			days = parsed
+		}
+
+		upcoming, err := service.GetUpcoming(username, time.Now(), days)
+		if err != nil {
+			sendRecurringError(w, err, "Failed to retrieve upcoming occurrences")
+			return
+		}
+
+		handlers.SendJSONResponse(w, upcoming, http.StatusOK)
+	}
+}
+
+// UpdateOccurrenceHandler skips or changes one occurrence of a rule.
+// @Summary Update Occurrence
+// @Description Skips the rule's occurrence on the given date, or books it with a different amount, description or notes. Only occurrences that have not been booked yet can be changed; sending the request again replaces the earlier change.
+// @Tags recurring
+// @Accept  json
+// @Produce  json
+// @Param   id          path  int                          true  "Rule ID"
+// @Param   date        path  string                       true  "Occurrence date, YYYY-MM-DD"
+// @Param   occurrence  body  handlers.OccurrenceRequest  true  "Change"
+// @Success 200 {object} recurring.Occurrence "Changed occurrence"
+// @Failure 400 {object} map[string]interface{} "Invalid date or amount, or the occurrence is not pending"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Rule belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Rule not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/recurring/{id}/occurrences/{date} [put]
+func UpdateOccurrenceHandler(service *recurring.RecurringService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		ruleID, ok := parseRuleID(w, r)
+		if !ok {
+			return
+		}
+		date, ok := parseOccurrenceDate(w, r)
+		if !ok {
+			return
+		}
+
+		var req OccurrenceRequest
+		if err := handlers.ParseJSONRequest(w, r, &req); err != nil {
+			return
+		}
+
+		occurrence, err := service.UpdateOccurrence(username, ruleID, date, recurring.OccurrenceInput{
+			Skip:        req.Skip,
+			Amount:      req.Amount,
+			Description: req.Description,
+			Notes:       req.Notes,
+		})
+		if err != nil {
+			sendRecurringError(w, err, "Failed to update occurrence")
+			return
+		}
+
+		handlers.SendJSONResponse(w, occurrence, http.StatusOK)
+	}
+}
+
+// ResetOccurrenceHandler undoes a change to one occurrence of a rule.
+// @Summary Reset Occurrence
+// @Description Undoes a skip or change to the rule's occurrence on the given date, so that it is booked as the rule says.
+// @Tags recurring
+// @Param   id    path  int     true  "Rule ID"
+// @Param   date  path  string  true  "Occurrence date, YYYY-MM-DD"
+// @Success 204 "No Content"
+// @Failure 400 {object} map[string]interface{} "Invalid date, or the occurrence is not pending"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Rule belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Rule not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/recurring/{id}/occurrences/{date} [delete]
+func ResetOccurrenceHandler(service *recurring.RecurringService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		ruleID, ok := parseRuleID(w, r)
+		if !ok {
+			return
+		}
+		date, ok := parseOccurrenceDate(w, r)
+		if !ok {
+			return
+		}
+
+		if err := service.ResetOccurrence(username, ruleID, date); err != nil {
+			sendRecurringError(w, err, "Failed to reset occurrence")
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func parseRuleID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	ruleID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		handlers.SendErrorResponse(w, "Invalid rule ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return uint(ruleID), true
+}
+
+func parseOccurrenceDate(w http.ResponseWriter, r *http.Request) (time.Time, bool) {
+	date, err := time.Parse("2006-01-02", mux.Vars(r)["date"])
+	if err != nil {
+		handlers.SendErrorResponse(w, "Invalid date, expected YYYY-MM-DD", http.StatusBadRequest)
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+func sendRecurringError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, recurring.ErrRuleNotFound):
+		handlers.SendErrorResponse(w, "Recurring rule not found", http.StatusNotFound)
+	case errors.Is(err, recurring.ErrRuleAccessDenied):
+		handlers.SendErrorResponse(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, recurring.ErrInvalidRule), errors.Is(err, recurring.ErrInvalidOccurrence),
+		errors.Is(err, transaction.ErrInvalidCategory), errors.Is(err, transaction.ErrInvalidAccount),
+		errors.Is(err, transaction.ErrInvalidType), errors.Is(err, transaction.ErrInvalidAmount):
+		handlers.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+	default:
+		handlers.SendErrorResponse(w, message, http.StatusInternalServerError)
+	}
+}
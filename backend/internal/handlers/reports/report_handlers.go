@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/handlers"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/middleware"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/reports"
+)
+
+// GetCategorySpendingHandler reports spending per category per month.
+// @Summary Spending by Category
+// @Description Returns the net spending in each category for every month of the range, in the base currency, with the change from the month before. Every category used in the range is listed for every month. Months follow the user's time zone. The range defaults to the last twelve months.
+// @Tags reports
+// @Produce  json
+// @Param   from  query  string  false  "First day included, YYYY-MM-DD"
+// @Param   to    query  string  false  "Last day included, YYYY-MM-DD"
+// @Success 200 {array} reports.CategoryMonthTotal "Spending per category and month"
+// @Failure 400 {object} map[string]interface{} "Invalid range"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/reports/categories [get]
+func GetCategorySpendingHandler(service *reports.ReportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, period, ok := readReportRequest(w, r, service)
+		if !ok {
+			return
+		}
+
+		totals, err := service.SpendingByCategory(username, period)
+		if err != nil {
+			sendReportError(w, err)
+			return
+		}
+
+		handlers.SendJSONResponse(w, totals, http.StatusOK)
+	}
+}
+
+// GetMonthlySummaryHandler reports income and expense per month.
+// @Summary Monthly Income and Expense
+// @Description Returns money spent (expenses less refunds) and received as income in every month of the range, the net result, the savings rate and the change in spending from the previous month. Transfers are left out. The range defaults to the last twelve months.
+// @Tags reports
+// @Produce  json
+// @Param   from  query  string  false  "First day included, YYYY-MM-DD"
+// @Param   to    query  string  false  "Last day included, YYYY-MM-DD"
+// @Success 200 {array} reports.MonthlySummary "Monthly summaries"
+// @Failure 400 {object} map[string]interface{} "Invalid range"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/reports/monthly [get]
+func GetMonthlySummaryHandler(service *reports.ReportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, period, ok := readReportRequest(w, r, service)
+		if !ok {
+			return
+		}
+
+		summaries, err := service.MonthlySummaries(username, period)
+		if err != nil {
+			sendReportError(w, err)
+			return
+		}
+
+		handlers.SendJSONResponse(w, summaries, http.StatusOK)
+	}
+}
+
+// GetCashFlowHandler reports net cash flow and the savings rate.
+// @Summary Net Cash Flow
+// @Description Returns the income, spending (expenses less refunds), net cash flow and savings rate over the range, with the same figures per month. The savings rate is the share of income not spent and is omitted without income. The range defaults to the last twelve months.
+// @Tags reports
+// @Produce  json
+// @Param   from  query  string  false  "First day included, YYYY-MM-DD"
+// @Param   to    query  string  false  "Last day included, YYYY-MM-DD"
+// @Success 200 {object} reports.CashFlow "Cash flow"
+// @Failure 400 {object} map[string]interface{} "Invalid range"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/reports/cash-flow [get]
+func GetCashFlowHandler(service *reports.ReportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, period, ok := readReportRequest(w, r, service)
+		if !ok {
+			return
+		}
+
+		flow, err := service.CashFlow(username, period)
+		if err != nil {
+			sendReportError(w, err)
+			return
+		}
+
+		handlers.SendJSONResponse(w, flow, http.StatusOK)
+	}
+}
+
+// GetTopCategoriesHandler reports the categories with the most spending.
+// @Summary Top Categories
+// @Description Returns the categories with the highest net spending in the range, with their share of all spending.
+// @Tags reports
+// @Produce  json
+// @Param   from   query  string  false  "First day included, YYYY-MM-DD"
+// @Param   to     query  string  false  "Last day included, YYYY-MM-DD"
+// @Param   limit  query  int     false  "Number of categories, at most 50 (default 5)"
+// @Success 200 {array} reports.CategoryTotal "Top categories"
+// @Failure 400 {object} map[string]interface{} "Invalid range or limit"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/reports/top-categories [get]
+func GetTopCategoriesHandler(service *reports.ReportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, period, ok := readReportRequest(w, r, service)
+		if !ok {
+			return
+		}
+		limit, ok := readLimit(w, r)
+		if !ok {
+			return
+		}
+
+		totals, err := service.TopCategories(username, period, limit)
+		if err != nil {
+			sendReportError(w, err)
+			return
+		}
+
+		handlers.SendJSONResponse(w, totals, http.StatusOK)
+	}
+}
+
+// GetTopMerchantsHandler reports the merchants with the most spending.
+// @Summary Top Merchants
+// @Description Returns the merchants, taken from transaction descriptions, the user spent most with in the range. Descriptions that differ only in case, digits or punctuation count as one merchant.
+// @Tags reports
+// @Produce  json
+// @Param   from   query  string  false  "First day included, YYYY-MM-DD"
+// @Param   to     query  string  false  "Last day included, YYYY-MM-DD"
+// @Param   limit  query  int     false  "Number of merchants, at most 50 (default 5)"
+// @Success 200 {array} reports.MerchantTotal "Top merchants"
+// @Failure 400 {object} map[string]interface{} "Invalid range or limit"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/reports/merchants [get]
+func GetTopMerchantsHandler(service *reports.ReportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, period, ok := readReportRequest(w, r, service)
+		if !ok {
+			return
+		}
+		limit, ok := readLimit(w, r)
+		if !ok {
+			return
+		}
+
+		totals, err := service.TopMerchants(username, period, limit)
+		if err != nil {
+			sendReportError(w, err)
+			return
+		}
+
+		handlers.SendJSONResponse(w, totals, http.StatusOK)
+	}
+}
+
+// GetTrendHandler reports spending over time.
+// @Summary Spending Trend
+// @Description Returns the net spending in each day, week, month, quarter or year of the range, including periods without transactions. Periods follow the calendar of the time zone tz, and weeks start on the user's first_day_of_week setting.
+// @Tags reports
+// @Produce  json
+// @Param   granularity  query  string  false  "day, week, month (default), quarter or year"
+// @Param   from         query  string  false  "First day included, YYYY-MM-DD (default depends on granularity)"
+// @Param   to           query  string  false  "Last day included, YYYY-MM-DD (default: today)"
+// @Param   category_id  query  []int   false  "Only these categories; may be repeated or comma-separated"
+// @Param   tz           query  string  false  "IANA time zone, e.g. Europe/Berlin (default: the user's time_zone setting)"
+// @Success 200 {array} reports.TrendPoint "Spending per period"
+// @Failure 400 {object} map[string]interface{} "Invalid granularity, range, category or time zone"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/reports/trends [get]
+func GetTrendHandler(service *reports.ReportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		query := r.URL.Query()
+		var trend reports.TrendQuery
+		var err error
+
+		trend.Granularity, err = reports.ParseGranularity(query.Get("granularity"))
+		if err != nil {
+			handlers.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		trend.Location, trend.WeekStart, err = service.Calendar(username)
+		if err != nil {
+			sendReportError(w, err)
+			return
+		}
+		if name := query.Get("tz"); name != "" {
+			trend.Location, err = time.LoadLocation(name)
+			if err != nil {
+				handlers.SendErrorResponse(w, "Invalid time zone", http.StatusBadRequest)
+				return
+			}
+		}
+
+		trend.Range = reports.DefaultTrendRange(trend.Granularity, time.Now().In(trend.Location), trend.WeekStart)
+		if value := query.Get("from"); value != "" {
+			trend.Range.From, err = time.ParseInLocation("2006-01-02", value, trend.Location)
+			if err != nil {
+				handlers.SendErrorResponse(w, "Invalid from date, expected YYYY-MM-DD", http.StatusBadRequest)
+				return
+			}
+		}
+		if value := query.Get("to"); value != "" {
+			to, err := time.ParseInLocation("2006-01-02", value, trend.Location)
+			if err != nil {
+				handlers.SendErrorResponse(w, "Invalid to date, expected YYYY-MM-DD", http.StatusBadRequest)
+				return
+			}
+			trend.Range.To = to.AddDate(0, 0, 1)
+		}
+
+		for _, value := range query["category_id"] {
+			for _, part := range strings.Split(value, ",") {
+				categoryID, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32)
+				if err != nil {
+					handlers.SendErrorResponse(w, "Invalid category ID", http.StatusBadRequest)
+					return
+				}
+				trend.CategoryIDs = append(trend.CategoryIDs, uint(categoryID))
+			}
+		}
+
+		points, err := service.Trend(username, trend)
+		if err != nil {
+			sendReportError(w, err)
+			return
+		}
+
+		handlers.SendJSONResponse(w, points, http.StatusOK)
+	}
+}
+
+// readReportRequest reads the username and range common to every report,
+// writing an error response and returning false when either is missing or
+// invalid. Dates are days on the user's calendar, and the range defaults to
+// the last twelve months there.
+func readReportRequest(w http.ResponseWriter, r *http.Request, service *reports.ReportService) (string, reports.Range, bool) {
+	username, ok := r.Context().Value(middleware.UsernameKey).(string)
+	if !ok || username == "" {
+		handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+		return "", reports.Range{}, false
+	}
+
+	loc, _, err := service.Calendar(username)
+	if err != nil {
+		sendReportError(w, err)
+		return "", reports.Range{}, false
+	}
+
+	period := reports.DefaultRange(time.Now().In(loc))
+	query := r.URL.Query()
+	if value := query.Get("from"); value != "" {
+		from, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			handlers.SendErrorResponse(w, "Invalid from date, expected YYYY-MM-DD", http.StatusBadRequest)
+			return "", reports.Range{}, false
+		}
+		period.From = from
+	}
+	if value := query.Get("to"); value != "" {
+		to, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			handlers.SendErrorResponse(w, "Invalid to date, expected YYYY-MM-DD", http.StatusBadRequest)
+			return "", reports.Range{}, false
+		}
+		period.To = to.AddDate(0, 0, 1)
+	}
+
+	return username, period, true
+}
+
+func readLimit(w http.ResponseWriter, r *http.Request) (int, bool) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return 0, true
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil {
+		handlers.SendErrorResponse(w, fmt.Sprintf("Invalid limit %q", value), http.StatusBadRequest)
+		return 0, false
+	}
+	return limit, true
+}
+
+func sendReportError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, reports.ErrInvalidRange), errors.Is(err, reports.ErrInvalidLimit), errors.Is(err, reports.ErrInvalidGranularity):
+		handlers.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+	default:
+		handlers.SendErrorResponse(w, "Failed to build report", http.StatusInternalServerError)
+	}
+}
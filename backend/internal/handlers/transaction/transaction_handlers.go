@@ -1,32 +1,86 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/fx"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/handlers"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/middleware"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
 )
 
 type TransactionRequest struct {
-	CategoryID      uint    `json:"category_id"`
-	Amount          float64 `json:"amount"`
-	Description     string  `json:"description"`
-	TransactionDate string  `json:"transaction_date"`
+	Type            string         `json:"type,omitempty" enums:"expense,income,refund" example:"expense"`
+	CategoryID      uint           `json:"category_id"`
+	AccountID       uint           `json:"account_id,omitempty"`
+	Amount          money.Amount   `json:"amount" swaggertype:"number" example:"12.50"`
+	Currency        string         `json:"currency,omitempty" example:"USD"`
+	Description     string         `json:"description"`
+	Notes           string         `json:"notes,omitempty"`
+	TransactionDate string         `json:"transaction_date"`
+	AllowDuplicate  bool           `json:"allow_duplicate,omitempty"`
+	Splits          []SplitRequest `json:"splits,omitempty"`
+}
+
+type SplitRequest struct {
+	CategoryID  uint         `json:"category_id"`
+	Amount      money.Amount `json:"amount" swaggertype:"number" example:"42.10"`
+	Description string       `json:"description,omitempty"`
+}
+
+// toInput validates the request and converts it for the transaction service.
+// The returned message is suitable for a 400 response.
+func (req TransactionRequest) toInput() (transaction.TransactionInput, string) {
+	transactionDate, err := time.Parse(time.RFC3339, req.TransactionDate)
+	if err != nil {
+		return transaction.TransactionInput{}, "Invalid date format"
+	}
+
+	currency, err := money.ParseCurrency(req.Currency)
+	if err != nil {
+		return transaction.TransactionInput{}, "Invalid currency code"
+	}
+
+	var splits []transaction.SplitInput
+	for _, split := range req.Splits {
+		splits = append(splits, transaction.SplitInput{
+			CategoryID:  split.CategoryID,
+			Amount:      split.Amount,
+			Description: split.Description,
+		})
+	}
+
+	return transaction.TransactionInput{
+		Type:            req.Type,
+		CategoryID:      req.CategoryID,
+		AccountID:       req.AccountID,
+		Amount:          req.Amount,
+		Currency:        currency,
+		Description:     req.Description,
+		Notes:           req.Notes,
+		TransactionDate: transactionDate,
+		AllowDuplicate:  req.AllowDuplicate,
+		Splits:          splits,
+	}, ""
 }
 
 // CreateTransactionHandler handles the creation of a new transaction.
 // @Summary Create Transaction
-// @Description Creates a new transaction for the authenticated user, linking it to a specific category.
+// @Description Creates a new transaction for the authenticated user, linking it to a specific category. The type is expense (the default), income or refund; transfers are recorded with /api/transfers. Amounts are never negative. Income is booked against an income category, by default one called Income, and expenses and refunds against expense categories. Only expenses and refunds count towards budgets. A transaction booked to an account must be in the account's currency, which it defaults to. A transaction that looks like a duplicate of a stored one is saved with duplicate_of set, unless allow_duplicate is set or the user allows duplicates. To divide an expense, refund or income between categories, give at least two splits whose amounts add up to the amount instead of category_id; each split counts towards its own category's budget, and the transaction is filed under the first split's category.
 // @Tags transactions
 // @Accept  json
 // @Produce  json
 // @Param   transaction  body  handlers.TransactionRequest  true  "Transaction Data"
 // @Success 201 {object} models.Transaction "Created Transaction"
-// @Failure 400 {object} map[string]interface{} "Invalid request payload"
+// @Failure 400 {object} map[string]interface{} "Invalid request payload, category, currency or splits, or no exchange rate for the date"
+// @Failure 409 {object} map[string]interface{} "Transaction is in a reconciled statement period"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/transactions [post]
 func CreateTransactionHandler(service *transaction.TransactionService) http.HandlerFunc {
@@ -37,9 +91,9 @@ func CreateTransactionHandler(service *transaction.TransactionService) http.Hand
 			return
 		}
 
-		transactionDate, err := time.Parse(time.RFC3339, req.TransactionDate)
-		if err != nil {
-			handlers.SendErrorResponse(w, "Invalid date format", http.StatusBadRequest)
+		input, msg := req.toInput()
+		if msg != "" {
+			handlers.SendErrorResponse(w, msg, http.StatusBadRequest)
 			return
 		}
 
@@ -49,9 +103,9 @@ func CreateTransactionHandler(service *transaction.TransactionService) http.Hand
 			return
 		}
 
-		transaction, err := service.AddTransaction(username, req.CategoryID, req.Amount, req.Description, transactionDate)
+		transaction, err := service.AddTransaction(username, input)
 		if err != nil {
-			handlers.SendErrorResponse(w, "Failed to create transaction", http.StatusInternalServerError)
+			sendTransactionError(w, err, "Failed to create transaction")
 			return
 		}
 
@@ -61,12 +115,24 @@ func CreateTransactionHandler(service *transaction.TransactionService) http.Hand
 
 // GetTransactionsHandler handles retrieving transactions for the authenticated user.
 // @Summary Get Transactions
-// @Description Retrieves a list of transactions for the authenticated user, with optional filtering by date, category, etc.
+// @Description Retrieves a page of the authenticated user's transactions, newest first unless sort says otherwise. Pass next_cursor from the response as cursor to fetch the following page with the same filters and sort. Amount bounds are in the base currency.
 // @Tags transactions
 // @Produce  json
-// @Param   user_id  query uint true "User ID"
-// @Success 200 {array} models.Transaction "List of Transactions"
-// @Failure 400 {object} map[string]interface{} "Invalid or missing user ID"
+// @Param   from         query  string  false  "First day included, YYYY-MM-DD"
+// @Param   to           query  string  false  "Last day included, YYYY-MM-DD"
+// @Param   category_id  query  []int   false  "Only these categories; may be repeated or comma-separated"
+// @Param   account_id   query  []int   false  "Only these accounts; may be repeated or comma-separated"
+// @Param   type         query  []string false  "Only these types (expense, income, transfer, refund); may be repeated or comma-separated"
+// @Param   min_amount   query  number  false  "Smallest amount included"
+// @Param   max_amount   query  number  false  "Largest amount included"
+// @Param   q            query  string  false  "Text the description contains"
+// @Param   sort         query  string  false  "date (default), amount, description or created_at; prefix with - for descending"
+// @Param   order        query  string  false  "asc or desc, overriding the - prefix"
+// @Param   limit        query  int     false  "Page size, at most 200 (default 50)"
+// @Param   cursor       query  string  false  "next_cursor from the previous page"
+// @Success 200 {object} transaction.TransactionPage "Page of transactions"
+// @Failure 400 {object} map[string]interface{} "Invalid filter, sort or cursor"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/transactions [get]
 func GetTransactionsHandler(service *transaction.TransactionService) http.HandlerFunc {
@@ -78,14 +144,110 @@ func GetTransactionsHandler(service *transaction.TransactionService) http.Handle
 			return
 		}
 
-		transactions, err := service.GetTransactionsForUser(username)
+		loc, err := service.Location(username)
 		if err != nil {
-			handlers.SendErrorResponse(w, "Failed to retrieve transactions", http.StatusInternalServerError)
+			sendTransactionError(w, err, "Failed to retrieve transactions")
 			return
 		}
 
-		handlers.SendJSONResponse(w, transactions, http.StatusOK)
+		query, message := parseTransactionQuery(r, loc)
+		if message != "" {
+			handlers.SendErrorResponse(w, message, http.StatusBadRequest)
+			return
+		}
+
+		page, err := service.ListTransactions(username, query)
+		if err != nil {
+			sendTransactionError(w, err, "Failed to retrieve transactions")
+			return
+		}
+
+		handlers.SendJSONResponse(w, page, http.StatusOK)
+	}
+}
+
+// parseTransactionQuery reads the listing filters from the query string, with
+// dates on the calendar of loc, the user's time zone. The returned message is
+// suitable for a 400 response.
+func parseTransactionQuery(r *http.Request, loc *time.Location) (transaction.TransactionQuery, string) {
+	var query transaction.TransactionQuery
+	values := r.URL.Query()
+
+	for name, bound := range map[string]*time.Time{"from": &query.From, "to": &query.To} {
+		if value := values.Get(name); value != "" {
+			date, err := time.ParseInLocation("2006-01-02", value, loc)
+			if err != nil {
+				return query, fmt.Sprintf("Invalid %s date, expected YYYY-MM-DD", name)
+			}
+			*bound = date
+		}
+	}
+
+	for _, value := range values["category_id"] {
+		for _, part := range strings.Split(value, ",") {
+			categoryID, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32)
+			if err != nil {
+				return query, "Invalid category ID"
+			}
+			query.CategoryIDs = append(query.CategoryIDs, uint(categoryID))
+		}
+	}
+
+	for _, value := range values["account_id"] {
+		for _, part := range strings.Split(value, ",") {
+			accountID, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32)
+			if err != nil {
+				return query, "Invalid account ID"
+			}
+			query.AccountIDs = append(query.AccountIDs, uint(accountID))
+		}
+	}
+
+	for _, value := range values["type"] {
+		for _, part := range strings.Split(value, ",") {
+			query.Types = append(query.Types, strings.TrimSpace(part))
+		}
+	}
+
+	for name, bound := range map[string]**money.Amount{"min_amount": &query.MinAmount, "max_amount": &query.MaxAmount} {
+		if value := values.Get(name); value != "" {
+			amount, err := money.Parse(value)
+			if err != nil {
+				return query, fmt.Sprintf("Invalid %s", name)
+			}
+			*bound = &amount
+		}
+	}
+
+	query.Search = strings.TrimSpace(values.Get("q"))
+
+	if sort := values.Get("sort"); sort != "" {
+		query.Descending = strings.HasPrefix(sort, "-")
+		query.Sort = transaction.SortField(strings.TrimPrefix(sort, "-"))
+	}
+	switch strings.ToLower(values.Get("order")) {
+	case "":
+	case "asc":
+		query.Descending = false
+	case "desc":
+		query.Descending = true
+	default:
+		return query, "Invalid order, expected asc or desc"
+	}
+	if query.Sort == "" && values.Get("order") != "" {
+		query.Sort = transaction.SortByDate
 	}
+
+	if value := values.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return query, "Invalid limit"
+		}
+		query.Limit = limit
+	}
+	query.Cursor = values.Get("cursor")
+
+	return query, ""
 }
 
 // GetTransactionByIDHandler handles retrieving a single transaction by its ID.
@@ -96,11 +258,19 @@ func GetTransactionsHandler(service *transaction.TransactionService) http.Handle
 // @Param   id  path uint true "Transaction ID"
 // @Success 200 {object} models.Transaction "Transaction data"
 // @Failure 400 {object} map[string]interface{} "Invalid transaction ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Transaction belongs to another user"
 // @Failure 404 {object} map[string]interface{} "Transaction not found"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/transactions/{id} [get]
 func GetTransactionByIDHandler(service *transaction.TransactionService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
 		vars := mux.Vars(r)
 		transactionID, err := strconv.ParseUint(vars["id"], 10, 32)
 		if err != nil || transactionID == 0 {
@@ -108,13 +278,9 @@ func GetTransactionByIDHandler(service *transaction.TransactionService) http.Han
 			return
 		}
 
-		transaction, err := service.GetTransactionByID(uint(transactionID))
+		transaction, err := service.GetTransactionByID(username, uint(transactionID))
 		if err != nil {
-			if err.Error() == "record not found" {
-				handlers.SendErrorResponse(w, "Transaction not found", http.StatusNotFound)
-			} else {
-				handlers.SendErrorResponse(w, "Failed to retrieve transaction", http.StatusInternalServerError)
-			}
+			sendTransactionError(w, err, "Failed to retrieve transaction")
 			return
 		}
 
@@ -124,19 +290,28 @@ func GetTransactionByIDHandler(service *transaction.TransactionService) http.Han
 
 // UpdateTransactionHandler handles updating an existing transaction.
 // @Summary Update Transaction
-// @Description Updates an existing transaction, allowing changes to the amount, category, description, or date.
+// @Description Updates an existing transaction, allowing changes to the type, amount, category, splits, description, or date. The stored splits are replaced by the ones given, so leaving splits out turns a split transaction back into a single-category one. Either side of a transfer is edited through /api/transfers/{id} instead.
 // @Tags transactions
 // @Accept  json
 // @Produce  json
 // @Param   id            path  uint                       true  "Transaction ID"
 // @Param   transaction   body  handlers.TransactionRequest  true  "Updated Transaction Data"
 // @Success 200 {object} models.Transaction "Updated Transaction"
-// @Failure 400 {object} map[string]interface{} "Invalid request payload"
+// @Failure 400 {object} map[string]interface{} "Invalid request payload, category, currency or splits, no exchange rate for the date, or the transaction is part of a transfer"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Transaction belongs to another user"
 // @Failure 404 {object} map[string]interface{} "Transaction not found"
+// @Failure 409 {object} map[string]interface{} "Transaction is in a reconciled statement period"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/transactions/{id} [put]
 func UpdateTransactionHandler(service *transaction.TransactionService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
 		var req TransactionRequest
 
 		vars := mux.Vars(r)
@@ -150,19 +325,15 @@ func UpdateTransactionHandler(service *transaction.TransactionService) http.Hand
 			return
 		}
 
-		transactionDate, err := time.Parse(time.RFC3339, req.TransactionDate)
-		if err != nil {
-			handlers.SendErrorResponse(w, "Invalid date format", http.StatusBadRequest)
+		input, msg := req.toInput()
+		if msg != "" {
+			handlers.SendErrorResponse(w, msg, http.StatusBadRequest)
 			return
 		}
 
-		transaction, err := service.UpdateTransaction(uint(transactionID), req.Amount, req.CategoryID, req.Description, transactionDate)
+		transaction, err := service.UpdateTransaction(username, uint(transactionID), input)
 		if err != nil {
-			if err.Error() == "record not found" {
-				handlers.SendErrorResponse(w, "Transaction not found", http.StatusNotFound)
-			} else {
-				handlers.SendErrorResponse(w, "Failed to update transaction", http.StatusInternalServerError)
-			}
+			sendTransactionError(w, err, "Failed to update transaction")
 			return
 		}
 
@@ -172,16 +343,25 @@ func UpdateTransactionHandler(service *transaction.TransactionService) http.Hand
 
 // DeleteTransactionHandler handles deleting an existing transaction.
 // @Summary Delete Transaction
-// @Description Deletes a specific transaction by its ID.
+// @Description Deletes a specific transaction by its ID. Deleting either side of a transfer deletes both.
 // @Tags transactions
 // @Param   id  path  uint  true  "Transaction ID"
 // @Success 204 "No Content"
 // @Failure 400 {object} map[string]interface{} "Invalid transaction ID"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Transaction belongs to another user"
 // @Failure 404 {object} map[string]interface{} "Transaction not found"
+// @Failure 409 {object} map[string]interface{} "Transaction is in a reconciled statement period"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/transactions/{id} [delete]
 func DeleteTransactionHandler(service *transaction.TransactionService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
 		vars := mux.Vars(r)
 		transactionID, err := strconv.ParseUint(vars["id"], 10, 32)
 		if err != nil {
@@ -189,12 +369,8 @@ func DeleteTransactionHandler(service *transaction.TransactionService) http.Hand
 			return
 		}
 
-		if err := service.DeleteTransaction(uint(transactionID)); err != nil {
-			if err.Error() == "record not found" {
-				handlers.SendErrorResponse(w, "Transaction not found", http.StatusNotFound)
-			} else {
-				handlers.SendErrorResponse(w, "Failed to delete transaction", http.StatusInternalServerError)
-			}
+		if err := service.DeleteTransaction(username, uint(transactionID)); err != nil {
+			sendTransactionError(w, err, "Failed to delete transaction")
 			return
 		}
 
@@ -204,7 +380,7 @@ func DeleteTransactionHandler(service *transaction.TransactionService) http.Hand
 
 // GetTransactionsByCategoryHandler handles retrieving transactions for a specific category.
 // @Summary Get Transactions by Category ID
-// @Description Retrieves all transactions associated with a specific category for the authenticated user.
+// @Description Retrieves all transactions associated with a specific category for the authenticated user, including split transactions with a split in the category; for those, split_amount is the part in the category.
 // @Tags transactions
 // @Produce  json
 // @Param category_id path int true "Category ID"
@@ -242,7 +418,7 @@ func GetTransactionsByCategoryHandler(service *transaction.TransactionService) h
 
 // GetWeeklySpendingHandler returns the weekly spending for a user.
 // @Summary Get Weekly Spending (Past 6 weeks)
-// @Description Retrieves the weekly spending for the authenticated user.
+// @Description Retrieves the authenticated user's spending in the current week and the five before it, newest first. Weeks start on the user's first_day_of_week, at midnight in their time zone.
 // @Tags transactions
 // @Produce  json
 // @Success 200 {array} transaction.WeeklySpending "Weekly Spending"
@@ -265,3 +441,130 @@ func GetWeeklySpendingHandler(service *transaction.TransactionService) http.Hand
 		handlers.SendJSONResponse(w, weeklySpending, http.StatusOK)
 	}
 }
+
+// SearchTransactionsHandler searches the descriptions and notes of the
+// user's transactions.
+// @Summary Search Transactions
+// @Description Finds the authenticated user's transactions whose description or notes contain words starting with each word of q, tolerating small typos. Results are ordered best match first, then newest first.
+// @Tags transactions
+// @Produce  json
+// @Param   q      query  string  true   "Search text"
+// @Param   limit  query  int     false  "Maximum number of results, at most 100 (default 20)"
+// @Success 200 {array} transaction.SearchResult "Matching transactions"
+// @Failure 400 {object} map[string]interface{} "Missing search text or invalid limit"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/transactions/search [get]
+func SearchTransactionsHandler(service *transaction.TransactionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		var limit int
+		if value := r.URL.Query().Get("limit"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				handlers.SendErrorResponse(w, "Invalid limit", http.StatusBadRequest)
+				return
+			}
+			limit = parsed
+		}
+
+		results, err := service.SearchTransactions(username, r.URL.Query().Get("q"), limit)
+		if err != nil {
+			sendTransactionError(w, err, "Failed to search transactions")
+			return
+		}
+
+		handlers.SendJSONResponse(w, results, http.StatusOK)
+	}
+}
+
+// GetDuplicatesHandler lists transactions flagged as likely duplicates.
+// @Summary Get Suspected Duplicates
+// @Description Lists the authenticated user's transactions flagged as likely duplicates, each paired with the transaction it appears to repeat. Delete the duplicate, or dismiss the flag if it is genuine.
+// @Tags transactions
+// @Produce  json
+// @Success 200 {array} transaction.DuplicatePair "Suspected duplicate pairs"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/transactions/duplicates [get]
+func GetDuplicatesHandler(service *transaction.TransactionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		pairs, err := service.GetDuplicatePairs(username)
+		if err != nil {
+			handlers.SendErrorResponse(w, "Failed to retrieve duplicates", http.StatusInternalServerError)
+			return
+		}
+
+		handlers.SendJSONResponse(w, pairs, http.StatusOK)
+	}
+}
+
+// DismissDuplicateHandler clears the duplicate flag on a transaction.
+// @Summary Dismiss Duplicate
+// @Description Marks a transaction flagged as a likely duplicate as genuine.
+// @Tags transactions
+// @Produce  json
+// @Param   id  path  int  true  "Transaction ID"
+// @Success 200 {object} models.Transaction "Updated Transaction"
+// @Failure 400 {object} map[string]interface{} "Invalid transaction ID or transaction not flagged"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Transaction belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Transaction not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/transactions/{id}/dismiss-duplicate [post]
+func DismissDuplicateHandler(service *transaction.TransactionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		vars := mux.Vars(r)
+		transactionID, err := strconv.ParseUint(vars["id"], 10, 32)
+		if err != nil {
+			handlers.SendErrorResponse(w, "Invalid transaction ID", http.StatusBadRequest)
+			return
+		}
+
+		transaction, err := service.DismissDuplicate(username, uint(transactionID))
+		if err != nil {
+			sendTransactionError(w, err, "Failed to dismiss duplicate")
+			return
+		}
+
+		handlers.SendJSONResponse(w, transaction, http.StatusOK)
+	}
+}
+
+// sendTransactionError maps transaction ownership, reconciliation and
+// validation errors to 404/403/409/400 and everything else to a 500 with the
+// given message.
+func sendTransactionError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, transaction.ErrTransactionNotFound):
+		handlers.SendErrorResponse(w, "Transaction not found", http.StatusNotFound)
+	case errors.Is(err, transaction.ErrTransactionAccessDenied):
+		handlers.SendErrorResponse(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, transaction.ErrReconciled):
+		handlers.SendErrorResponse(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, transaction.ErrInvalidCategory), errors.Is(err, transaction.ErrNotDuplicate), errors.Is(err, fx.ErrRateNotFound),
+		errors.Is(err, transaction.ErrInvalidQuery), errors.Is(err, transaction.ErrInvalidCursor),
+		errors.Is(err, transaction.ErrInvalidType), errors.Is(err, transaction.ErrInvalidAmount), errors.Is(err, transaction.ErrInvalidAccount),
+		errors.Is(err, transaction.ErrInvalidTransfer), errors.Is(err, transaction.ErrInvalidSplit):
+		handlers.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+	default:
+		handlers.SendErrorResponse(w, message, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/handlers"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/middleware"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
+)
+
+type TransferRequest struct {
+	FromAccountID   uint         `json:"from_account_id"`
+	ToAccountID     uint         `json:"to_account_id"`
+	CategoryID      uint         `json:"category_id,omitempty"`
+	Amount          money.Amount `json:"amount" swaggertype:"number" example:"250.00"`
+	ToAmount        money.Amount `json:"to_amount,omitempty" swaggertype:"number" example:"230.00"`
+	Description     string       `json:"description"`
+	Notes           string       `json:"notes,omitempty"`
+	TransactionDate string       `json:"transaction_date"`
+}
+
+// toInput validates the request and converts it for the transaction service.
+// The returned message is suitable for a 400 response.
+func (req TransferRequest) toInput() (transaction.TransferInput, string) {
+	transactionDate, err := time.Parse(time.RFC3339, req.TransactionDate)
+	if err != nil {
+		return transaction.TransferInput{}, "Invalid date format"
+	}
+
+	return transaction.TransferInput{
+		FromAccountID:   req.FromAccountID,
+		ToAccountID:     req.ToAccountID,
+		CategoryID:      req.CategoryID,
+		Amount:          req.Amount,
+		ToAmount:        req.ToAmount,
+		Description:     req.Description,
+		Notes:           req.Notes,
+		TransactionDate: transactionDate,
+	}, ""
+}
+
+// CreateTransferHandler handles moving money between two accounts.
+// @Summary Create Transfer
+// @Description Moves money from one of the authenticated user's accounts to another, booked as two linked transfer transactions that are saved together. Amount leaves the from account in its currency; to_amount is what arrives when the accounts' currencies differ, and defaults to amount converted at the rate for the date. Transfers are booked against the Transfers category unless category_id is given, and never count towards budgets or spending.
+// @Tags transfers
+// @Accept  json
+// @Produce  json
+// @Param   transfer  body  handlers.TransferRequest  true  "Transfer Data"
+// @Success 201 {object} transaction.Transfer "Created Transfer"
+// @Failure 400 {object} map[string]interface{} "Invalid request payload, accounts, amounts or category, or no exchange rate for the date"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 409 {object} map[string]interface{} "Transfer is in a reconciled statement period"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/transfers [post]
+func CreateTransferHandler(service *transaction.TransactionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req TransferRequest
+
+		if err := handlers.ParseJSONRequest(w, r, &req); err != nil {
+			return
+		}
+
+		input, msg := req.toInput()
+		if msg != "" {
+			handlers.SendErrorResponse(w, msg, http.StatusBadRequest)
+			return
+		}
+
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		transfer, err := service.CreateTransfer(username, input)
+		if err != nil {
+			sendTransactionError(w, err, "Failed to create transfer")
+			return
+		}
+
+		handlers.SendJSONResponse(w, transfer, http.StatusCreated)
+	}
+}
+
+// GetTransferHandler handles retrieving a transfer.
+// @Summary Get Transfer
+// @Description Retrieves the transfer that the transaction with the given ID is either side of.
+// @Tags transfers
+// @Produce  json
+// @Param   id  path  uint  true  "ID of either transaction in the transfer"
+// @Success 200 {object} transaction.Transfer "Transfer"
+// @Failure 400 {object} map[string]interface{} "Invalid ID, or the transaction is not part of a transfer"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Transfer belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Transaction not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/transfers/{id} [get]
+func GetTransferHandler(service *transaction.TransactionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		vars := mux.Vars(r)
+		transactionID, err := strconv.ParseUint(vars["id"], 10, 32)
+		if err != nil {
+			handlers.SendErrorResponse(w, "Invalid transaction ID", http.StatusBadRequest)
+			return
+		}
+
+		transfer, err := service.GetTransfer(username, uint(transactionID))
+		if err != nil {
+			sendTransactionError(w, err, "Failed to retrieve transfer")
+			return
+		}
+
+		handlers.SendJSONResponse(w, transfer, http.StatusOK)
+	}
+}
+
+// UpdateTransferHandler handles editing both sides of a transfer.
+// @Summary Update Transfer
+// @Description Replaces the accounts, amounts, category, description and date of the transfer that the transaction with the given ID is either side of. Both transactions are updated together.
+// @Tags transfers
+// @Accept  json
+// @Produce  json
+// @Param   id        path  uint                      true  "ID of either transaction in the transfer"
+// @Param   transfer  body  handlers.TransferRequest  true  "Updated Transfer Data"
+// @Success 200 {object} transaction.Transfer "Updated Transfer"
+// @Failure 400 {object} map[string]interface{} "Invalid request payload, accounts, amounts or category, or the transaction is not part of a transfer"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Transfer belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Transaction not found"
+// @Failure 409 {object} map[string]interface{} "Transfer is in a reconciled statement period"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/transfers/{id} [put]
+func UpdateTransferHandler(service *transaction.TransactionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		vars := mux.Vars(r)
+		transactionID, err := strconv.ParseUint(vars["id"], 10, 32)
+		if err != nil {
+			handlers.SendErrorResponse(w, "Invalid transaction ID", http.StatusBadRequest)
+			return
+		}
+
+		var req TransferRequest
+		if err := handlers.ParseJSONRequest(w, r, &req); err != nil {
+			return
+		}
+
+		input, msg := req.toInput()
+		if msg != "" {
+			handlers.SendErrorResponse(w, msg, http.StatusBadRequest)
+			return
+		}
+
+		transfer, err := service.UpdateTransfer(username, uint(transactionID), input)
+		if err != nil {
+			sendTransactionError(w, err, "Failed to update transfer")
+			return
+		}
+
+		handlers.SendJSONResponse(w, transfer, http.StatusOK)
+	}
+}
+
+// DeleteTransferHandler handles deleting both sides of a transfer.
+// @Summary Delete Transfer
+// @Description Deletes both transactions of the transfer that the transaction with the given ID is either side of.
+// @Tags transfers
+// @Param   id  path  uint  true  "ID of either transaction in the transfer"
+// @Success 204 "No Content"
+// @Failure 400 {object} map[string]interface{} "Invalid ID, or the transaction is not part of a transfer"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Transfer belongs to another user"
+// @Failure 404 {object} map[string]interface{} "Transaction not found"
+// @Failure 409 {object} map[string]interface{} "Transfer is in a reconciled statement period"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/transfers/{id} [delete]
+func DeleteTransferHandler(service *transaction.TransactionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		vars := mux.Vars(r)
+		transactionID, err := strconv.ParseUint(vars["id"], 10, 32)
+		if err != nil {
+			handlers.SendErrorResponse(w, "Invalid transaction ID", http.StatusBadRequest)
+			return
+		}
+
+		if err := service.DeleteTransfer(username, uint(transactionID)); err != nil {
+			sendTransactionError(w, err, "Failed to delete transfer")
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
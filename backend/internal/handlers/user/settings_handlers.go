@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/fx"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/handlers"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/middleware"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/user"
+)
+
+// GetSettingsHandler returns the authenticated user's settings.
+// @Summary Get Settings
+// @Description Retrieves the settings of the authenticated user. Users who have never saved settings get the defaults.
+// @Tags settings
+// @Produce  json
+// @Success 200 {object} models.UserSettings "Settings"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/settings [get]
+func GetSettingsHandler(s *user.UserService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		settings, err := s.GetSettings(username)
+		if err != nil {
+			handlers.SendErrorResponse(w, "Failed to retrieve settings", http.StatusInternalServerError)
+			return
+		}
+
+		handlers.SendJSONResponse(w, settings, http.StatusOK)
+	}
+}
+
+// UpdateSettingsHandler changes the authenticated user's settings.
+// @Summary Update Settings
+// @Description Updates the settings of the authenticated user. Fields left out of the request keep their current value. Changing base_currency converts existing transactions and budgets using the loaded exchange rates. The duplicate_* settings control how likely duplicate transactions are matched and whether imports flag, skip or allow them. time_zone (an IANA name, empty for the server's zone) and first_day_of_week (0 for Sunday) decide where budget months and report weeks begin; locale is a BCP 47 tag for clients to format with.
+// @Tags settings
+// @Accept  json
+// @Produce  json
+// @Param   settings  body  user.SettingsUpdate  true  "Settings to change"
+// @Success 200 {object} models.UserSettings "Updated settings"
+// @Failure 400 {object} map[string]interface{} "Invalid request payload or setting, unknown currency or missing exchange rate"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/settings [put]
+func UpdateSettingsHandler(s *user.UserService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := r.Context().Value(middleware.UsernameKey).(string)
+		if !ok || username == "" {
+			handlers.SendErrorResponse(w, "Username not found in context", http.StatusUnauthorized)
+			return
+		}
+
+		var req user.SettingsUpdate
+		if err := handlers.ParseJSONRequest(w, r, &req); err != nil {
+			return
+		}
+
+		settings, err := s.UpdateSettings(username, req)
+		if err != nil {
+			switch {
+			case errors.Is(err, money.ErrUnknownCurrency), errors.Is(err, fx.ErrRateNotFound), errors.Is(err, user.ErrInvalidSettings):
+				handlers.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+			default:
+				handlers.SendErrorResponse(w, "Failed to update settings", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		handlers.SendJSONResponse(w, settings, http.StatusOK)
+	}
+}
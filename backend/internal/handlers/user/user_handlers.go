@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+	"log"
 	"net/http"
 
 	"github.com/shaikhjunaidx/pennywise-backend/internal/handlers"
@@ -21,11 +23,21 @@ type SignUpRequest struct {
 	Username string `json:"username" example:"john_doe"`
 	Email    string `json:"email" example:"john.doe@example.com"`
 	Password string `json:"password" example:"password123"`
+	TimeZone string `json:"time_zone,omitempty" example:"Europe/Berlin"`
+}
+
+type PasswordResetRequest struct {
+	Email string `json:"email" example:"john.doe@example.com"`
+}
+
+type PasswordResetConfirmRequest struct {
+	Token       string `json:"token" example:"4f3c2a1b9e8d7c6b5a4f3e2d1c0b9a8f"`
+	NewPassword string `json:"new_password" example:"newpassword123"`
 }
 
 // SignUpHandler handles user registration requests.
 // @Summary User Registration
-// @Description Registers a new user with the given username, email, and password.
+// @Description Registers a new user with the given username, email, and password. The optional time_zone (an IANA name) sets the calendar the user's budgets follow; it defaults to the server's zone.
 // @Tags auth
 // @Accept  json
 // @Produce  json
@@ -42,13 +54,17 @@ func SignUpHandler(s *user.UserService) http.HandlerFunc {
 			return
 		}
 
-		user, err := s.SignUp(req.Username, req.Email, req.Password)
+		newUser, err := s.SignUpInTimeZone(req.Username, req.Email, req.Password, req.TimeZone)
 		if err != nil {
-			handlers.SendErrorResponse(w, err.Error(), http.StatusInternalServerError)
+			status := http.StatusInternalServerError
+			if errors.Is(err, user.ErrInvalidSettings) {
+				status = http.StatusBadRequest
+			}
+			handlers.SendErrorResponse(w, err.Error(), status)
 			return
 		}
 
-		handlers.SendJSONResponse(w, user, http.StatusCreated)
+		handlers.SendJSONResponse(w, newUser, http.StatusCreated)
 	}
 }
 
@@ -80,3 +96,73 @@ func LoginHandler(s *user.UserService) http.HandlerFunc {
 		handlers.SendJSONResponse(w, map[string]string{"token": token}, http.StatusOK)
 	}
 }
+
+// RequestPasswordResetHandler handles password reset requests.
+// @Summary Request Password Reset
+// @Description Emails a single-use password reset token to the account registered with the given email. The response is the same whether or not the email exists or the email could be sent.
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param   resetData  body  PasswordResetRequest  true  "Password Reset Request"
+// @Success 202 {object} map[string]string "message"
+// @Failure 400 {object} map[string]interface{} "Invalid request payload"
+// @Router /api/password-reset/request [post]
+func RequestPasswordResetHandler(s *user.UserService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req PasswordResetRequest
+
+		if err := handlers.ParseJSONRequest(w, r, &req); err != nil {
+			return
+		}
+
+		if req.Email == "" {
+			handlers.SendErrorResponse(w, "Email is required", http.StatusBadRequest)
+			return
+		}
+
+		// Answer the same way whatever happened, so that the response does
+		// not reveal which emails are registered.
+		if err := s.RequestPasswordReset(req.Email); err != nil && !errors.Is(err, user.ErrEmailNotFound) {
+			log.Printf("Failed to request password reset: %v", err)
+		}
+
+		handlers.SendJSONResponse(w, map[string]string{"message": "If the email is registered, a password reset email has been sent"}, http.StatusAccepted)
+	}
+}
+
+// ConfirmPasswordResetHandler handles setting a new password with a reset token.
+// @Summary Confirm Password Reset
+// @Description Sets a new password using a valid, unused password reset token.
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param   confirmData  body  PasswordResetConfirmRequest  true  "Password Reset Confirmation"
+// @Success 200 {object} map[string]string "message"
+// @Failure 400 {object} map[string]interface{} "Invalid or expired token"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/password-reset/confirm [post]
+func ConfirmPasswordResetHandler(s *user.UserService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req PasswordResetConfirmRequest
+
+		if err := handlers.ParseJSONRequest(w, r, &req); err != nil {
+			return
+		}
+
+		if req.Token == "" || req.NewPassword == "" {
+			handlers.SendErrorResponse(w, "Token and new password are required", http.StatusBadRequest)
+			return
+		}
+
+		if err := s.ResetPassword(req.Token, req.NewPassword); err != nil {
+			if errors.Is(err, user.ErrInvalidResetToken) {
+				handlers.SendErrorResponse(w, err.Error(), http.StatusBadRequest)
+			} else {
+				handlers.SendErrorResponse(w, "Failed to reset password", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		handlers.SendJSONResponse(w, map[string]string{"message": "Password has been reset"}, http.StatusOK)
+	}
+}
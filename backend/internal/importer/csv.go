@@ -0,0 +1,162 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+)
+
+// DefaultMapping is used for CSV files when the user picks no saved mapping.
+// It expects the columns produced by the PennyWise export.
+var DefaultMapping = models.ImportMapping{
+	Name:              "default",
+	DateColumn:        "date",
+	AmountColumn:      "amount",
+	DescriptionColumn: "description",
+	CategoryColumn:    "category",
+	CurrencyColumn:    "currency",
+	ExternalIDColumn:  "external_id",
+	TypeColumn:        "type",
+}
+
+// ParseCSV reads a CSV statement whose first line is a header, mapping
+// columns to fields as described by mapping. The date column and an amount,
+// debit or credit column must be present; other mapped columns are read when
+// the file has them. Dates without a time are days on the calendar of loc.
+func ParseCSV(r io.Reader, mapping *models.ImportMapping, loc *time.Location) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if mapping.Delimiter != "" {
+		reader.Comma = []rune(mapping.Delimiter)[0]
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing header: %v", ErrInvalidFile, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	// column returns the index of the named column, or -1 when the mapping
+	// does not use it or the file lacks it.
+	column := func(name string) int {
+		if i, ok := columns[strings.ToLower(strings.TrimSpace(name))]; ok && name != "" {
+			return i
+		}
+		return -1
+	}
+
+	dateIndex := column(mapping.DateColumn)
+	if dateIndex < 0 {
+		return nil, fmt.Errorf("%w: missing date column %q", ErrInvalidFile, mapping.DateColumn)
+	}
+	amountIndex, debitIndex, creditIndex := column(mapping.AmountColumn), column(mapping.DebitColumn), column(mapping.CreditColumn)
+	if amountIndex < 0 && debitIndex < 0 && creditIndex < 0 {
+		return nil, fmt.Errorf("%w: missing amount column %q", ErrInvalidFile, mapping.AmountColumn)
+	}
+	descriptionIndex, categoryIndex, currencyIndex := column(mapping.DescriptionColumn), column(mapping.CategoryColumn), column(mapping.CurrencyColumn)
+	externalIDIndex, typeIndex := column(mapping.ExternalIDColumn), column(mapping.TypeColumn)
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		if isBlank(record) {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		field := func(i int) string {
+			if i < 0 || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		row := Row{
+			Line:        line,
+			Description: field(descriptionIndex),
+			Category:    field(categoryIndex),
+			ExternalID:  field(externalIDIndex),
+			Type:        field(typeIndex),
+			Currency:    mapping.Currency,
+		}
+
+		if err := parseCSVRow(&row, mapping, loc, field(dateIndex), field(amountIndex),
+			field(debitIndex), field(creditIndex), field(currencyIndex)); err != nil {
+			row.Error = err.Error()
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func parseCSVRow(row *Row, mapping *models.ImportMapping, loc *time.Location, date, amount, debit, credit, currency string) error {
+	var err error
+	row.TransactionDate, err = parseDate(date, mapping.DateFormat, loc)
+	if err != nil {
+		return fmt.Errorf("invalid date %q", date)
+	}
+
+	switch {
+	case amount == "" && debit == "" && credit == "":
+		return fmt.Errorf("missing amount")
+	case amount != "":
+		row.Amount, err = parseStatementAmount(amount)
+		if err != nil {
+			return fmt.Errorf("invalid amount %q", amount)
+		}
+	default:
+		// Debits are money spent and credits money received.
+		for _, part := range []struct {
+			value string
+			sign  money.Amount
+		}{{debit, 1}, {credit, -1}} {
+			if part.value == "" {
+				continue
+			}
+			value, err := parseStatementAmount(part.value)
+			if err != nil {
+				return fmt.Errorf("invalid amount %q", part.value)
+			}
+			row.Amount += part.sign * value.Abs()
+		}
+	}
+	if mapping.NegateAmounts {
+		row.Amount = -row.Amount
+	}
+
+	if currency != "" {
+		row.Currency, err = money.ParseCurrency(currency)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isBlank(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
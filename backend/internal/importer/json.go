@@ -0,0 +1,47 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/export"
+)
+
+// ParseJSON reads the transactions of a PennyWise JSON export. Categories
+// are matched by name, so data can be moved between accounts; budgets in the
+// document are not imported.
+func ParseJSON(r io.Reader) ([]Row, error) {
+	var doc export.Document
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	if doc.Version < 1 || doc.Version > export.DocumentVersion {
+		return nil, fmt.Errorf("%w: unsupported export version %d", ErrInvalidFile, doc.Version)
+	}
+	if len(doc.Transactions) == 0 {
+		return nil, fmt.Errorf("%w: no transactions found", ErrInvalidFile)
+	}
+
+	rows := make([]Row, 0, len(doc.Transactions))
+	for i, t := range doc.Transactions {
+		row := Row{
+			Line:            i + 1,
+			TransactionDate: t.TransactionDate,
+			Type:            t.Type,
+			Amount:          t.Amount,
+			Currency:        t.Currency,
+			Description:     t.Description,
+			Notes:           t.Notes,
+			Category:        t.Category,
+			ExternalID:      t.ExternalID,
+		}
+		if t.Currency != "" && !t.Currency.Valid() {
+			row.Error = fmt.Sprintf("invalid currency %q", t.Currency)
+		} else if t.TransactionDate.IsZero() {
+			row.Error = "missing transaction date"
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
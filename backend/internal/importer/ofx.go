@@ -0,0 +1,109 @@
+package importer
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+)
+
+var (
+	ofxTransactionPattern = regexp.MustCompile(`(?is)<STMTTRN>(.*?)</STMTTRN>`)
+	ofxFieldPattern       = regexp.MustCompile(`(?i)<([A-Z0-9.]+)>([^<]*)`)
+)
+
+// ParseOFX reads the transactions from an OFX or QFX statement. Both the
+// SGML flavour of OFX 1.x, where elements are not closed, and the XML of OFX
+// 2.x are accepted. Only the day of each date is read, on the calendar of
+// loc.
+//
+// OFX amounts are signed from the account's point of view, so they are
+// negated to make withdrawals positive spending.
+func ParseOFX(r io.Reader, loc *time.Location) ([]Row, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	content := string(body)
+
+	if !strings.Contains(strings.ToUpper(content), "<OFX>") {
+		return nil, fmt.Errorf("%w: no OFX element", ErrInvalidFile)
+	}
+
+	var currency money.Currency
+	if fields := ofxFields(content); fields["CURDEF"] != "" {
+		if parsed, err := money.ParseCurrency(fields["CURDEF"]); err == nil {
+			currency = parsed
+		}
+	}
+
+	var rows []Row
+	for i, match := range ofxTransactionPattern.FindAllStringSubmatch(content, -1) {
+		fields := ofxFields(match[1])
+		row := Row{
+			Line:        i + 1,
+			Currency:    currency,
+			Description: ofxDescription(fields),
+			ExternalID:  fields["FITID"],
+		}
+		if err := parseOFXRow(&row, fields, loc); err != nil {
+			row.Error = err.Error()
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no transactions found", ErrInvalidFile)
+	}
+	return rows, nil
+}
+
+func parseOFXRow(row *Row, fields map[string]string, loc *time.Location) error {
+	date := fields["DTPOSTED"]
+	if len(date) < 8 {
+		return fmt.Errorf("invalid date %q", date)
+	}
+	// Dates look like 20241001120000.000[-5:EST]; only the day matters.
+	transactionDate, err := time.ParseInLocation("20060102", date[:8], loc)
+	if err != nil {
+		return fmt.Errorf("invalid date %q", date)
+	}
+	row.TransactionDate = transactionDate
+
+	amount, err := parseStatementAmount(fields["TRNAMT"])
+	if err != nil {
+		return fmt.Errorf("invalid amount %q", fields["TRNAMT"])
+	}
+	row.Amount = -amount
+
+	return nil
+}
+
+// ofxFields collects the leaf elements of an OFX fragment. The first
+// occurrence of each element wins.
+func ofxFields(fragment string) map[string]string {
+	fields := make(map[string]string)
+	for _, match := range ofxFieldPattern.FindAllStringSubmatch(fragment, -1) {
+		name := strings.ToUpper(match[1])
+		if _, seen := fields[name]; !seen {
+			fields[name] = html.UnescapeString(strings.TrimSpace(match[2]))
+		}
+	}
+	return fields
+}
+
+func ofxDescription(fields map[string]string) string {
+	name, memo := fields["NAME"], fields["MEMO"]
+	switch {
+	case name == "":
+		return memo
+	case memo == "" || memo == name:
+		return name
+	default:
+		return name + " - " + memo
+	}
+}
@@ -0,0 +1,118 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ParseQIF reads a QIF statement. QIF dates carry no fixed order, so
+// dateFormat (for example "DD/MM/YYYY") says how to read them; US month-first
+// dates are assumed when it is empty. Two-digit years written as 1/2'24 are
+// understood as well. Dates are days on the calendar of loc.
+//
+// Like OFX, QIF amounts are negated so that withdrawals become positive
+// spending.
+func ParseQIF(r io.Reader, dateFormat string, loc *time.Location) ([]Row, error) {
+	scanner := bufio.NewScanner(r)
+
+	var (
+		rows    []Row
+		fields  = map[byte]string{}
+		start   int
+		sawType bool
+	)
+	flush := func() {
+		if len(fields) == 0 {
+			return
+		}
+		row := Row{
+			Line:        start,
+			Description: qifDescription(fields),
+			Category:    fields['L'],
+		}
+		if err := parseQIFRow(&row, fields, dateFormat, loc); err != nil {
+			row.Error = err.Error()
+		}
+		rows = append(rows, row)
+		fields = map[byte]string{}
+	}
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		text = strings.TrimPrefix(text, "\ufeff")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		switch code := text[0]; code {
+		case '!':
+			sawType = true
+		case '^':
+			flush()
+		default:
+			if len(fields) == 0 {
+				start = line
+			}
+			// Split lines repeat codes; only the first value is kept.
+			if _, seen := fields[code]; !seen {
+				fields[code] = strings.TrimSpace(text[1:])
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	flush()
+
+	if !sawType {
+		return nil, fmt.Errorf("%w: missing !Type header", ErrInvalidFile)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no transactions found", ErrInvalidFile)
+	}
+	return rows, nil
+}
+
+func parseQIFRow(row *Row, fields map[byte]string, dateFormat string, loc *time.Location) error {
+	date := fields['D']
+	if dateFormat == "" {
+		dateFormat = "M/D/YYYY"
+	}
+	normalized := strings.ReplaceAll(strings.ReplaceAll(date, " ", ""), "'", "/")
+	transactionDate, err := parseDate(normalized, dateFormat, loc)
+	if err != nil {
+		// 1/2'24 style dates have a two-digit year.
+		transactionDate, err = parseDate(normalized, strings.Replace(dateFormat, "YYYY", "YY", 1), loc)
+		if err != nil {
+			return fmt.Errorf("invalid date %q", date)
+		}
+	}
+	row.TransactionDate = transactionDate
+
+	amount := fields['T']
+	if amount == "" {
+		amount = fields['U']
+	}
+	value, err := parseStatementAmount(amount)
+	if err != nil {
+		return fmt.Errorf("invalid amount %q", amount)
+	}
+	row.Amount = -value
+
+	return nil
+}
+
+func qifDescription(fields map[byte]string) string {
+	payee, memo := fields['P'], fields['M']
+	switch {
+	case payee == "":
+		return memo
+	case memo == "" || memo == payee:
+		return payee
+	default:
+		return payee + " - " + memo
+	}
+}
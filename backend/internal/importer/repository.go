@@ -0,0 +1,10 @@
+package importer
+
+import "github.com/shaikhjunaidx/pennywise-backend/models"
+
+type MappingRepository interface {
+	Save(mapping *models.ImportMapping) error
+	DeleteByID(id uint) error
+	FindByID(id uint) (*models.ImportMapping, error)
+	FindAllByUserID(userID uint) ([]*models.ImportMapping, error)
+}
@@ -0,0 +1,38 @@
+package importer
+
+import (
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"gorm.io/gorm"
+)
+
+type MappingRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewMappingRepository(db *gorm.DB) *MappingRepositoryImpl {
+	return &MappingRepositoryImpl{DB: db}
+}
+
+func (r *MappingRepositoryImpl) Save(mapping *models.ImportMapping) error {
+	return r.DB.Save(mapping).Error
+}
+
+func (r *MappingRepositoryImpl) DeleteByID(id uint) error {
+	return r.DB.Delete(&models.ImportMapping{}, id).Error
+}
+
+func (r *MappingRepositoryImpl) FindByID(id uint) (*models.ImportMapping, error) {
+	var mapping models.ImportMapping
+	if err := r.DB.First(&mapping, id).Error; err != nil {
+		return nil, err
+	}
+	return &mapping, nil
+}
+
+func (r *MappingRepositoryImpl) FindAllByUserID(userID uint) ([]*models.ImportMapping, error) {
+	var mappings []*models.ImportMapping
+	if err := r.DB.Where("user_id = ?", userID).Order("name").Find(&mappings).Error; err != nil {
+		return nil, err
+	}
+	return mappings, nil
+}
@@ -0,0 +1,126 @@
+// Package importer reads bank statements in CSV, OFX and QIF format, as well
+// as PennyWise JSON exports, previews the parsed rows and commits them as
+// transactions in bulk.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatOFX  Format = "ofx"
+	FormatQIF  Format = "qif"
+	FormatJSON Format = "json"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported import format")
+	ErrInvalidFile       = errors.New("invalid import file")
+)
+
+// ParseFormat returns the format named by name, falling back to the
+// extension of filename when name is empty.
+func ParseFormat(name, filename string) (Format, error) {
+	if name == "" {
+		name = strings.TrimPrefix(filepath.Ext(filename), ".")
+	}
+
+	switch format := Format(strings.ToLower(name)); format {
+	case FormatCSV, FormatOFX, FormatQIF, FormatJSON:
+		return format, nil
+	case "qfx":
+		return FormatOFX, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, name)
+	}
+}
+
+// Row is one transaction read from a statement. Rows that could not be
+// parsed carry an Error and are left out of the commit. DuplicateOf names a
+// stored transaction the row probably repeats; AllowDuplicate imports it
+// regardless of the user's duplicate policy.
+//
+// Type is the transaction type when the statement names one. Without it,
+// amounts follow the statement convention: money spent is positive and money
+// received is negative, and is imported as income.
+type Row struct {
+	Line            int            `json:"line"`
+	TransactionDate time.Time      `json:"transaction_date"`
+	Type            string         `json:"type,omitempty"`
+	Amount          money.Amount   `json:"amount" swaggertype:"number"`
+	Currency        money.Currency `json:"currency,omitempty" swaggertype:"string"`
+	Description     string         `json:"description"`
+	Notes           string         `json:"notes,omitempty"`
+	Category        string         `json:"category,omitempty"`
+	CategoryID      uint           `json:"category_id,omitempty"`
+	AccountID       uint           `json:"account_id,omitempty"`
+	ExternalID      string         `json:"external_id,omitempty"`
+	DuplicateOf     uint           `json:"duplicate_of,omitempty"`
+	AllowDuplicate  bool           `json:"allow_duplicate,omitempty"`
+	Error           string         `json:"error,omitempty"`
+}
+
+// parseStatementAmount reads an amount as banks tend to print it, allowing
+// currency symbols, thousands separators and accounting-style parentheses
+// for negative values.
+func parseStatementAmount(s string) (money.Amount, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")")
+	if negative {
+		s = s[1 : len(s)-1]
+	}
+
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case ',', ' ', '$', '€', '£', '¥':
+			return -1
+		}
+		return r
+	}, s)
+
+	amount, err := money.Parse(s)
+	if err != nil {
+		return 0, err
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// dateLayout turns a user-facing pattern such as "DD/MM/YYYY" into a Go time
+// layout. Single-letter M and D accept days and months without a leading
+// zero.
+func dateLayout(pattern string) string {
+	return strings.NewReplacer(
+		"YYYY", "2006",
+		"YY", "06",
+		"MM", "01",
+		"DD", "02",
+		"M", "1",
+		"D", "2",
+	).Replace(strings.ToUpper(pattern))
+}
+
+// parseDate parses s with pattern, or as an ISO 8601 date or timestamp when
+// no pattern is given. Dates without a time are midnight in loc.
+func parseDate(s, pattern string, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if pattern != "" {
+		return time.ParseInLocation(dateLayout(pattern), s, loc)
+	}
+
+	if date, err := time.ParseInLocation("2006-01-02", s, loc); err == nil {
+		return date, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,331 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/category"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/user"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrMappingNotFound     = errors.New("import mapping not found")
+	ErrMappingAccessDenied = errors.New("access denied: import mapping does not belong to the user")
+	ErrInvalidMapping      = errors.New("invalid import mapping")
+	ErrInvalidRow          = errors.New("invalid import row")
+)
+
+type ImportService struct {
+	Mappings     MappingRepository
+	UserRepo     user.UserRepository
+	CategoryRepo category.CategoryRepository
+	Transactions *transaction.TransactionService
+}
+
+func NewImportService(mappings MappingRepository, userRepo user.UserRepository,
+	categoryRepo category.CategoryRepository, transactions *transaction.TransactionService) *ImportService {
+	return &ImportService{
+		Mappings:     mappings,
+		UserRepo:     userRepo,
+		CategoryRepo: categoryRepo,
+		Transactions: transactions,
+	}
+}
+
+// PreviewOptions tune how a statement is read. MappingID picks a saved CSV
+// mapping; DateFormat applies to QIF files; Currency is used for rows whose
+// file does not name one.
+type PreviewOptions struct {
+	MappingID  uint
+	DateFormat string
+	Currency   money.Currency
+}
+
+// Preview is the parsed content of a statement, ready to be reviewed and
+// passed back to Commit.
+type Preview struct {
+	Format     Format `json:"format"`
+	Rows       []Row  `json:"rows"`
+	Valid      int    `json:"valid"`
+	Invalid    int    `json:"invalid"`
+	Duplicates int    `json:"duplicates"`
+}
+
+// Preview parses a statement without storing anything. Category names found
+// in the file are matched against the user's categories, and valid rows are
+// checked for likely duplicates of stored transactions.
+func (s *ImportService) Preview(username string, format Format, file io.Reader, options PreviewOptions) (*Preview, error) {
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := s.Transactions.Location(username)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []Row
+	switch format {
+	case FormatCSV:
+		mapping := &DefaultMapping
+		if options.MappingID != 0 {
+			mapping, err = s.findOwnedMapping(user, options.MappingID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		rows, err = ParseCSV(file, mapping, loc)
+	case FormatOFX:
+		rows, err = ParseOFX(file, loc)
+	case FormatQIF:
+		rows, err = ParseQIF(file, options.DateFormat, loc)
+	case FormatJSON:
+		rows, err = ParseJSON(file)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &Preview{Format: format, Rows: rows}
+	categoryIDs := make(map[string]uint)
+	for i := range preview.Rows {
+		row := &preview.Rows[i]
+		if row.Currency == "" {
+			row.Currency = options.Currency
+		}
+		if row.Type != "" && row.Error == "" {
+			if _, err := transaction.ParseType(row.Type); err != nil {
+				row.Error = err.Error()
+			}
+		}
+		if row.Category != "" {
+			row.CategoryID = s.matchCategory(user, row.Category, row.categoryKind(), categoryIDs)
+		}
+
+		if row.Error == "" {
+			preview.Valid++
+		} else {
+			preview.Invalid++
+		}
+	}
+
+	if err := s.markDuplicates(username, preview); err != nil {
+		return nil, err
+	}
+
+	return preview, nil
+}
+
+// markDuplicates sets DuplicateOf on the valid rows that probably repeat a
+// stored transaction.
+func (s *ImportService) markDuplicates(username string, preview *Preview) error {
+	var valid []*Row
+	var inputs []transaction.TransactionInput
+	for i := range preview.Rows {
+		if row := &preview.Rows[i]; row.Error == "" {
+			valid = append(valid, row)
+			inputs = append(inputs, row.input())
+		}
+	}
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	matches, err := s.Transactions.FindDuplicates(username, inputs)
+	if err != nil {
+		return err
+	}
+
+	for i, match := range matches {
+		if match != nil {
+			valid[i].DuplicateOf = match.ID
+			preview.Duplicates++
+		}
+	}
+	return nil
+}
+
+// matchCategory returns the ID of the user's category of the given kind
+// called name, or zero when there is none. Lookups are cached in known.
+func (s *ImportService) matchCategory(user *models.User, name, kind string, known map[string]uint) uint {
+	key := kind + ":" + strings.ToLower(name)
+	if id, ok := known[key]; ok {
+		return id
+	}
+
+	var id uint
+	if category, err := s.CategoryRepo.FindByNameKindAndUserID(name, kind, user.ID); err == nil {
+		id = category.ID
+	}
+	known[key] = id
+	return id
+}
+
+// Commit stores the previewed rows as transactions. Rows that failed to parse
+// are skipped; rows without a category are booked against categoryID, or the
+// user's default category when that is zero. Income without a category goes
+// to the default income category.
+func (s *ImportService) Commit(username string, rows []Row, categoryID uint) (*transaction.ImportResult, error) {
+	inputs := make([]transaction.TransactionInput, 0, len(rows))
+	lines := make([]int, 0, len(rows))
+	for _, row := range rows {
+		if row.Error != "" {
+			continue
+		}
+		if row.TransactionDate.IsZero() {
+			return nil, fmt.Errorf("%w: line %d: missing transaction date", ErrInvalidRow, row.Line)
+		}
+		if row.Currency != "" && !row.Currency.Valid() {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrInvalidRow, row.Line, money.ErrUnknownCurrency)
+		}
+
+		input := row.input()
+		if input.CategoryID == 0 && input.Type != models.TransactionTypeIncome {
+			input.CategoryID = categoryID
+		}
+
+		inputs = append(inputs, input)
+		lines = append(lines, row.Line)
+	}
+
+	result, err := s.Transactions.ImportTransactions(username, inputs)
+
+	var rowErr *transaction.RowError
+	if errors.As(err, &rowErr) {
+		return nil, fmt.Errorf("line %d: %w", lines[rowErr.Index], rowErr.Err)
+	}
+	return result, err
+}
+
+// input converts the row to a transaction. A row without a type is an
+// expense, or income when its amount is negative; amounts are stored without
+// a sign either way.
+func (row *Row) input() transaction.TransactionInput {
+	kind := row.Type
+	if kind == "" && row.Amount < 0 {
+		kind = models.TransactionTypeIncome
+	}
+	return transaction.TransactionInput{
+		Type:            kind,
+		CategoryID:      row.CategoryID,
+		AccountID:       row.AccountID,
+		Amount:          row.Amount.Abs(),
+		Currency:        row.Currency,
+		Description:     row.Description,
+		Notes:           row.Notes,
+		TransactionDate: row.TransactionDate,
+		ExternalID:      row.ExternalID,
+		AllowDuplicate:  row.AllowDuplicate,
+	}
+}
+
+// categoryKind returns the kind of category the row can be booked against:
+// income goes into income categories and everything else into expense
+// categories. Income whose category is not an income category falls back to
+// the default income category.
+func (row *Row) categoryKind() string {
+	if strings.EqualFold(row.input().Type, models.TransactionTypeIncome) {
+		return models.CategoryKindIncome
+	}
+	return models.CategoryKindExpense
+}
+
+// findOwnedMapping loads a mapping and verifies that it belongs to the user.
+func (s *ImportService) findOwnedMapping(user *models.User, id uint) (*models.ImportMapping, error) {
+	mapping, err := s.Mappings.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMappingNotFound
+		}
+		return nil, err
+	}
+
+	if mapping.UserID != user.ID {
+		return nil, ErrMappingAccessDenied
+	}
+
+	return mapping, nil
+}
+
+func (s *ImportService) GetMappings(username string) ([]*models.ImportMapping, error) {
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	return s.Mappings.FindAllByUserID(user.ID)
+}
+
+// SaveMapping creates the mapping, or replaces the user's existing mapping
+// when mapping.ID is set.
+func (s *ImportService) SaveMapping(username string, mapping *models.ImportMapping) (*models.ImportMapping, error) {
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if mapping.ID != 0 {
+		existing, err := s.findOwnedMapping(user, mapping.ID)
+		if err != nil {
+			return nil, err
+		}
+		mapping.CreatedAt = existing.CreatedAt
+	}
+
+	if err := validateMapping(mapping); err != nil {
+		return nil, err
+	}
+
+	mapping.UserID = user.ID
+	if err := s.Mappings.Save(mapping); err != nil {
+		return nil, err
+	}
+
+	return mapping, nil
+}
+
+func (s *ImportService) DeleteMapping(username string, id uint) error {
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.findOwnedMapping(user, id); err != nil {
+		return err
+	}
+
+	return s.Mappings.DeleteByID(id)
+}
+
+func validateMapping(mapping *models.ImportMapping) error {
+	mapping.Name = strings.TrimSpace(mapping.Name)
+
+	switch {
+	case mapping.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidMapping)
+	case mapping.DateColumn == "":
+		return fmt.Errorf("%w: date_column is required", ErrInvalidMapping)
+	case mapping.AmountColumn == "" && mapping.DebitColumn == "" && mapping.CreditColumn == "":
+		return fmt.Errorf("%w: amount_column or debit_column and credit_column are required", ErrInvalidMapping)
+	case len([]rune(mapping.Delimiter)) > 1:
+		return fmt.Errorf("%w: delimiter must be a single character", ErrInvalidMapping)
+	}
+
+	if mapping.Currency != "" {
+		currency, err := money.ParseCurrency(string(mapping.Currency))
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidMapping, err)
+		}
+		mapping.Currency = currency
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+package insights
+
+import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+)
+
+type InsightRepository interface {
+	// FindExpensesSince returns the user's expenses dated on or after since,
+	// oldest first.
+	FindExpensesSince(userID uint, since time.Time) ([]*models.Transaction, error)
+}
@@ -0,0 +1,27 @@
+package insights
+
+import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"gorm.io/gorm"
+)
+
+type InsightRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewInsightRepository(db *gorm.DB) *InsightRepositoryImpl {
+	return &InsightRepositoryImpl{DB: db}
+}
+
+func (r *InsightRepositoryImpl) FindExpensesSince(userID uint, since time.Time) ([]*models.Transaction, error) {
+	var transactions []*models.Transaction
+	err := r.DB.Where("user_id = ? AND type = ? AND transaction_date >= ?", userID, models.TransactionTypeExpense, since).
+		Order("transaction_date, id").
+		Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
@@ -0,0 +1,393 @@
+// Package insights finds patterns in a user's transactions that they have
+// not told PennyWise about, such as subscriptions.
+package insights
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/recurring"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/user"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+)
+
+const (
+	// lookbackMonths is how much history subscriptions are found in; enough
+	// for a yearly charge to have been seen twice.
+	lookbackMonths = 25
+
+	// amountTolerance is how far, as a share of the typical charge, a charge
+	// may differ from it and still count as the same subscription.
+	amountTolerance = 0.2
+)
+
+var ErrSubscriptionNotFound = errors.New("subscription not found")
+
+// RuleStore lists and creates recurring rules, typically
+// *recurring.RecurringService.
+type RuleStore interface {
+	GetRules(username string) ([]*models.RecurringRule, error)
+	CreateRule(username string, input recurring.RuleInput) (*models.RecurringRule, error)
+}
+
+type InsightService struct {
+	Repo     InsightRepository
+	UserRepo user.UserRepository
+	Rules    RuleStore
+	Settings transaction.SettingsProvider
+}
+
+func NewInsightService(repo InsightRepository, userRepo user.UserRepository, rules RuleStore) *InsightService {
+	return &InsightService{
+		Repo:     repo,
+		UserRepo: userRepo,
+		Rules:    rules,
+	}
+}
+
+// Subscription is a charge that recurs with the same merchant at a regular
+// cadence. Key identifies it for ConvertSubscription. Amount, CategoryID and
+// AccountID are those of the latest charge, and AnnualCost is Amount over a
+// year at the cadence, in Currency. DayOfMonth is the day monthly and yearly
+// subscriptions are billed on; in shorter months they are billed on the
+// last day instead.
+type Subscription struct {
+	Key          string         `json:"key" example:"USD:netflix com"`
+	Merchant     string         `json:"merchant" example:"NETFLIX.COM"`
+	CategoryID   uint           `json:"category_id"`
+	AccountID    *uint          `json:"account_id,omitempty"`
+	Amount       money.Amount   `json:"amount" swaggertype:"number" example:"15.49"`
+	Currency     money.Currency `json:"currency" swaggertype:"string" example:"USD"`
+	Frequency    string         `json:"frequency" enums:"weekly,monthly,yearly" example:"monthly"`
+	Interval     int            `json:"interval" example:"1"`
+	Charges      int            `json:"charges" example:"6"`
+	FirstCharge  time.Time      `json:"first_charge"`
+	LastCharge   time.Time      `json:"last_charge"`
+	NextExpected time.Time      `json:"next_expected"`
+	DayOfMonth   int            `json:"day_of_month,omitempty" example:"31"`
+	AnnualCost   money.Amount   `json:"annual_cost" swaggertype:"number" example:"185.88"`
+
+	cadence cadence
+}
+
+// cadence is a regular gap between charges: days long, give or take
+// tolerance.
+type cadence struct {
+	frequency string
+	interval  int
+	days      int
+	tolerance int
+	perYear   float64
+}
+
+var cadences = []cadence{
+	{models.FrequencyWeekly, 1, 7, 1, 52},
+	{models.FrequencyWeekly, 2, 14, 2, 26},
+	{models.FrequencyMonthly, 1, 30, 4, 12},
+	{models.FrequencyMonthly, 3, 91, 7, 4},
+	{models.FrequencyYearly, 1, 365, 10, 1},
+}
+
+func (c cadence) fits(days int) bool {
+	return days >= c.days-c.tolerance && days <= c.days+c.tolerance
+}
+
+// minCharges is how many charges in a row it takes to call a pattern a
+// subscription.
+func (c cadence) minCharges() int {
+	if c.frequency == models.FrequencyYearly {
+		return 2
+	}
+	return 3
+}
+
+// next returns the date the charge after date is expected on. Monthly and
+// yearly charges fall on day, or on the last day of shorter months.
+func (c cadence) next(date time.Time, day int) time.Time {
+	switch c.frequency {
+	case models.FrequencyWeekly:
+		return date.AddDate(0, 0, 7*c.interval)
+	case models.FrequencyYearly:
+		return addMonths(date, 12*c.interval, day)
+	default:
+		return addMonths(date, c.interval, day)
+	}
+}
+
+// DetectSubscriptions looks through the user's recent expenses for charges
+// with the same merchant and a similar amount at a weekly, fortnightly,
+// monthly, quarterly or yearly cadence. Merchants the user already has a
+// recurring rule for, and subscriptions that seem to have lapsed, are left
+// out. The most expensive come first.
+func (s *InsightService) DetectSubscriptions(username string, now time.Time) ([]*Subscription, error) {
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := s.location(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	today := dateIn(now, loc)
+
+	expenses, err := s.Repo.FindExpensesSince(user.ID, today.AddDate(0, -lookbackMonths, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	tracked, err := s.trackedMerchants(username)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string][]*models.Transaction)
+	var keys []string
+	for _, expense := range expenses {
+		merchant := transaction.NormalizeDescription(expense.Description)
+		if merchant == "" || tracked[merchant] {
+			continue
+		}
+		key := fmt.Sprintf("%s:%s", expense.Currency, merchant)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], expense)
+	}
+
+	subscriptions := []*Subscription{}
+	for _, key := range keys {
+		if subscription := detect(key, groups[key], today, loc); subscription != nil {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+
+	sort.SliceStable(subscriptions, func(i, j int) bool {
+		return subscriptions[i].AnnualCost > subscriptions[j].AnnualCost
+	})
+	return subscriptions, nil
+}
+
+// ConvertSubscription creates a recurring rule for the detected subscription
+// with the given key, on its billing day and starting with its next charge
+// due after today, so that a charge that is late is not booked twice. Once
+// it has a rule the subscription is no longer detected.
+func (s *InsightService) ConvertSubscription(username, key string, now time.Time) (*models.RecurringRule, error) {
+	subscriptions, err := s.DetectSubscriptions(username, now)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, subscription := range subscriptions {
+		if subscription.Key != key {
+			continue
+		}
+
+		// NextExpected is a midnight in the user's time zone.
+		today := dateIn(now, subscription.NextExpected.Location())
+		start := subscription.NextExpected
+		for !start.After(today) {
+			start = subscription.cadence.next(start, subscription.DayOfMonth)
+		}
+
+		input := recurring.RuleInput{
+			Type:        models.TransactionTypeExpense,
+			CategoryID:  subscription.CategoryID,
+			Amount:      subscription.Amount,
+			Currency:    subscription.Currency,
+			Description: subscription.Merchant,
+			Frequency:   subscription.Frequency,
+			Interval:    subscription.Interval,
+			DayOfMonth:  subscription.DayOfMonth,
+			StartDate:   start,
+		}
+		if subscription.AccountID != nil {
+			input.AccountID = *subscription.AccountID
+		}
+		return s.Rules.CreateRule(username, input)
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrSubscriptionNotFound, key)
+}
+
+// trackedMerchants returns the normalised descriptions of the user's
+// recurring rules.
+func (s *InsightService) trackedMerchants(username string) (map[string]bool, error) {
+	tracked := make(map[string]bool)
+	if s.Rules == nil {
+		return tracked, nil
+	}
+
+	rules, err := s.Rules.GetRules(username)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		tracked[transaction.NormalizeDescription(rule.Description)] = true
+	}
+	return tracked, nil
+}
+
+// detect decides whether charges, one merchant's expenses oldest first, are a
+// subscription that is still running on today.
+func detect(key string, charges []*models.Transaction, today time.Time, loc *time.Location) *Subscription {
+	charges = similarAmounts(charges)
+	if len(charges) < 2 {
+		return nil
+	}
+
+	dates := make([]time.Time, len(charges))
+	gaps := make([]int, len(charges)-1)
+	for i, charge := range charges {
+		dates[i] = dateIn(charge.TransactionDate, loc)
+		if i > 0 {
+			gaps[i-1] = daysBetween(dates[i-1], dates[i])
+		}
+	}
+
+	c, ok := cadenceOf(gaps)
+	if !ok {
+		return nil
+	}
+
+	// Count back from the latest charge for as long as the cadence holds.
+	first := len(charges) - 1
+	for first > 0 && c.fits(gaps[first-1]) {
+		first--
+	}
+	run := len(charges) - first
+	if run < c.minCharges() {
+		return nil
+	}
+
+	latest := charges[len(charges)-1]
+	day := 0
+	if c.frequency != models.FrequencyWeekly {
+		day = billingDay(dates[first:])
+	}
+	next := c.next(dates[len(dates)-1], day)
+	// A subscription that has missed a charge by more than half a cadence
+	// has probably been cancelled.
+	if today.After(next.AddDate(0, 0, c.days/2)) {
+		return nil
+	}
+
+	return &Subscription{
+		Key:          key,
+		Merchant:     latest.Description,
+		CategoryID:   latest.CategoryID,
+		AccountID:    latest.AccountID,
+		Amount:       latest.Amount,
+		Currency:     latest.Currency,
+		Frequency:    c.frequency,
+		Interval:     c.interval,
+		Charges:      run,
+		FirstCharge:  dates[first],
+		LastCharge:   dates[len(dates)-1],
+		NextExpected: next,
+		DayOfMonth:   day,
+		AnnualCost:   money.Amount(math.Round(float64(latest.Amount) * c.perYear)),
+		cadence:      c,
+	}
+}
+
+// billingDay returns the day of the month charges on dates are billed on:
+// the day most of them fall on, where a charge on the last day of a short
+// month also counts for the later days it stands in for, so that a charge
+// on the 31st is not mistaken for one on the 30th. Ties go to the earlier
+// day.
+func billingDay(dates []time.Time) int {
+	var votes [32]int
+	for _, date := range dates {
+		last := date.Day()
+		if date.AddDate(0, 0, 1).Day() == 1 {
+			last = 31
+		}
+		for day := date.Day(); day <= last; day++ {
+			votes[day]++
+		}
+	}
+
+	best := 1
+	for day := 2; day <= 31; day++ {
+		if votes[day] > votes[best] {
+			best = day
+		}
+	}
+	return best
+}
+
+// similarAmounts keeps the charges within amountTolerance of the median
+// charge, which drops one-off purchases from a merchant that also bills a
+// subscription.
+func similarAmounts(charges []*models.Transaction) []*models.Transaction {
+	amounts := make([]money.Amount, len(charges))
+	for i, charge := range charges {
+		amounts[i] = charge.Amount
+	}
+	sort.Slice(amounts, func(i, j int) bool { return amounts[i] < amounts[j] })
+	median := float64(amounts[len(amounts)/2])
+
+	similar := make([]*models.Transaction, 0, len(charges))
+	for _, charge := range charges {
+		if math.Abs(float64(charge.Amount)-median) <= median*amountTolerance {
+			similar = append(similar, charge)
+		}
+	}
+	return similar
+}
+
+// cadenceOf returns the cadence the median gap between charges fits.
+func cadenceOf(gaps []int) (cadence, bool) {
+	sorted := append([]int(nil), gaps...)
+	sort.Ints(sorted)
+	median := sorted[len(sorted)/2]
+
+	for _, c := range cadences {
+		if c.fits(median) {
+			return c, true
+		}
+	}
+	return cadence{}, false
+}
+
+// location returns the time zone charge dates are read in, which is the
+// user's own.
+func (s *InsightService) location(userID uint) (*time.Location, error) {
+	if s.Settings == nil {
+		return time.Local, nil
+	}
+
+	settings, err := s.Settings.SettingsForUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return user.Location(settings), nil
+}
+
+// addMonths moves date on by months to day of the month, falling on the
+// last day of shorter months.
+func addMonths(date time.Time, months, day int) time.Time {
+	first := time.Date(date.Year(), date.Month()+time.Month(months), 1, 0, 0, 0, 0, date.Location())
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// daysBetween counts calendar days from a to b, both midnights in the same
+// time zone.
+func daysBetween(a, b time.Time) int {
+	return int(math.Round(b.Sub(a).Hours() / 24))
+}
+
+// dateIn returns the midnight that starts t's calendar day in loc.
+func dateIn(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.In(loc).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,207 @@
+// Package money represents monetary values as integer minor units so that
+// sums and comparisons are exact.
+//
+// Every Amount uses a fixed scale of two decimal places regardless of
+// currency, which keeps amounts in different currencies directly comparable
+// once they have been converted. Currencies with fewer decimal places, such
+// as the yen, only take amounts in whole minor units of their own (see
+// FitsCurrency and RoundTo). Currencies with three, such as the Kuwaiti
+// dinar, cannot be represented and are rejected by ParseCurrency.
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of minor units in one major unit.
+const Scale = 100
+
+// decimals is the number of decimal places Scale gives.
+const decimals = 2
+
+// Amount is a monetary value in minor units (cents).
+type Amount int64
+
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// FromMinor returns the amount for a number of minor units.
+func FromMinor(minor int64) Amount {
+	return Amount(minor)
+}
+
+// FromFloat converts a float to the nearest minor unit. Use it only at the
+// edges of the system, e.g. for exchange rate arithmetic; prefer Parse for
+// user input.
+func FromFloat(f float64) Amount {
+	return Amount(math.Round(f * Scale))
+}
+
+// Parse reads a decimal string such as "12.5", "-0.05" or "1000" exactly.
+// More than two decimal places is an error rather than being rounded away.
+func Parse(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidAmount
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" && fraction == "" || len(fraction) > 2 || !isDigits(whole) || !isDigits(fraction) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	fraction += strings.Repeat("0", 2-len(fraction))
+	if whole == "" {
+		whole = "0"
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/Scale {
+		return 0, fmt.Errorf("%w: %q is out of range", ErrInvalidAmount, s)
+	}
+	cents, _ := strconv.ParseInt(fraction, 10, 64)
+
+	minor := units*Scale + cents
+	if negative {
+		minor = -minor
+	}
+	return Amount(minor), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Minor returns the amount in minor units.
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+// Float64 returns the amount in major units. It is meant for display and
+// ratios, not for further money arithmetic.
+func (a Amount) Float64() float64 {
+	return float64(a) / Scale
+}
+
+// FitsCurrency reports whether the amount is a whole number of minor units
+// of c, e.g. whether a yen amount has no fraction.
+func (a Amount) FitsCurrency(c Currency) bool {
+	return a == a.RoundTo(c)
+}
+
+// RoundTo rounds the amount, half away from zero, to the minor unit of c.
+func (a Amount) RoundTo(c Currency) Amount {
+	step := Amount(1)
+	for i := c.Exponent(); i < decimals; i++ {
+		step *= 10
+	}
+	if step == 1 {
+		return a
+	}
+
+	half := step / 2
+	if a < 0 {
+		return -((-a + half) / step * step)
+	}
+	return (a + half) / step * step
+}
+
+func (a Amount) Abs() Amount {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// String formats the amount with exactly two decimal places, e.g. "-12.50".
+func (a Amount) String() string {
+	sign := ""
+	minor := int64(a)
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, minor/Scale, minor%Scale)
+}
+
+// MarshalJSON encodes the amount as a JSON number in major units, e.g. 12.5
+// is written as 12.50.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a numeric string.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Value stores the amount as an integer number of minor units.
+func (a Amount) Value() (driver.Value, error) {
+	return int64(a), nil
+}
+
+// Scan reads minor units from an integer column or from the DECIMAL that
+// MySQL returns for SUM over integer columns.
+func (a *Amount) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*a = 0
+	case int64:
+		*a = Amount(v)
+	case float64:
+		*a = Amount(math.Round(v))
+	case []byte:
+		return a.scanString(string(v))
+	case string:
+		return a.scanString(v)
+	default:
+		return fmt.Errorf("money: cannot scan %T into Amount", value)
+	}
+	return nil
+}
+
+func (a *Amount) scanString(s string) error {
+	if minor, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*a = Amount(minor)
+		return nil
+	}
+
+	// Aggregates such as SUM can come back as "1234.0000".
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("money: cannot scan %q into Amount", s)
+	}
+	*a = Amount(math.Round(f))
+	return nil
+}
@@ -0,0 +1,69 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Currency is an ISO 4217 alphabetic currency code.
+type Currency string
+
+// DefaultCurrency is used for rows created before currencies were recorded.
+const DefaultCurrency Currency = "USD"
+
+var ErrUnknownCurrency = errors.New("unknown currency code")
+
+// currencies lists the ISO 4217 codes we accept with the number of decimal
+// places of their minor unit.
+var currencies = map[Currency]int{
+	"AED": 2, "ARS": 2, "AUD": 2, "BDT": 2, "BGN": 2, "BRL": 2, "CAD": 2, "CHF": 2,
+	"CLP": 0, "CNY": 2, "COP": 2, "CZK": 2, "DKK": 2, "EGP": 2, "EUR": 2, "GBP": 2,
+	"HKD": 2, "HUF": 2, "IDR": 2, "ILS": 2, "INR": 2, "ISK": 0, "JPY": 0, "KES": 2,
+	"KRW": 0, "LKR": 2, "MAD": 2, "MXN": 2, "MYR": 2, "NGN": 2, "NOK": 2,
+	"NPR": 2, "NZD": 2, "PEN": 2, "PHP": 2, "PKR": 2, "PLN": 2, "QAR": 2, "RON": 2,
+	"RUB": 2, "SAR": 2, "SEK": 2, "SGD": 2, "THB": 2, "TRY": 2, "TWD": 2, "UAH": 2,
+	"USD": 2, "VND": 0, "ZAR": 2,
+}
+
+// unsupportedCurrencies have three decimal places, which an Amount cannot
+// hold, so they are rejected rather than silently rounded.
+var unsupportedCurrencies = map[Currency]struct{}{
+	"BHD": {}, "IQD": {}, "JOD": {}, "KWD": {}, "LYD": {}, "OMR": {}, "TND": {},
+}
+
+// ParseCurrency normalises and validates a currency code. An empty string
+// yields DefaultCurrency.
+func ParseCurrency(code string) (Currency, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return DefaultCurrency, nil
+	}
+
+	currency := Currency(code)
+	if _, ok := unsupportedCurrencies[currency]; ok {
+		return "", fmt.Errorf("%w: %q has three decimal places, which are not supported", ErrUnknownCurrency, code)
+	}
+	if !currency.Valid() {
+		return "", fmt.Errorf("%w: %q", ErrUnknownCurrency, code)
+	}
+	return currency, nil
+}
+
+func (c Currency) Valid() bool {
+	_, ok := currencies[c]
+	return ok
+}
+
+// Exponent returns the number of decimal places of the currency's minor
+// unit: 0 for the yen, 2 for the euro.
+func (c Currency) Exponent() int {
+	if exponent, ok := currencies[c]; ok {
+		return exponent
+	}
+	return decimals
+}
+
+func (c Currency) String() string {
+	return string(c)
+}
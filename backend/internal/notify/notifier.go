@@ -0,0 +1,54 @@
+package notify
+
+import (
+	"log"
+	"os"
+)
+
+// Message is a rendered outbound notification. A Sensitive message carries a
+// secret, such as a password reset token, in its body.
+type Message struct {
+	To        string
+	Subject   string
+	Body      string
+	Sensitive bool
+}
+
+// Notifier delivers messages to users.
+type Notifier interface {
+	Send(msg Message) error
+}
+
+// NopNotifier drops every message. Services fall back to it when they have
+// no notifier configured.
+type NopNotifier struct{}
+
+var _ Notifier = NopNotifier{}
+
+func (NopNotifier) Send(msg Message) error {
+	return nil
+}
+
+// NewNotifierFromEnv builds the notifier selected by the NOTIFIER environment
+// variable: "smtp" sends real email, "file" appends messages to NOTIFY_FILE,
+// and anything else writes them to the application log.
+func NewNotifierFromEnv() Notifier {
+	switch os.Getenv("NOTIFIER") {
+	case "smtp":
+		return &SMTPNotifier{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     os.Getenv("SMTP_PORT"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+		}
+	case "file":
+		notifier, err := NewFileNotifier(os.Getenv("NOTIFY_FILE"))
+		if err != nil {
+			log.Fatalf("Could not open notification file: %v", err)
+		}
+		return notifier
+	default:
+		return NewLogNotifier(log.Writer())
+	}
+}
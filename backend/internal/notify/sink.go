@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// LogNotifier writes messages to an io.Writer instead of delivering them. It
+// stands in for a mail server during local development. When Redact is set,
+// the body of a sensitive message is not written.
+type LogNotifier struct {
+	mu     sync.Mutex
+	Writer io.Writer
+	Redact bool
+}
+
+var _ Notifier = (*LogNotifier)(nil)
+
+// NewLogNotifier writes messages to w, which is usually the application log,
+// so it withholds the body of sensitive messages.
+func NewLogNotifier(w io.Writer) *LogNotifier {
+	return &LogNotifier{Writer: w, Redact: true}
+}
+
+// NewFileNotifier appends messages to the file at path, creating it if needed.
+// The file stands in for a mailbox, so messages are written in full, reset
+// tokens included.
+func NewFileNotifier(path string) (*LogNotifier, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &LogNotifier{Writer: file}, nil
+}
+
+func (n *LogNotifier) Send(msg Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	body := msg.Body
+	if msg.Sensitive && n.Redact {
+		body = "(body withheld: it contains a secret)"
+	}
+
+	_, err := fmt.Fprintf(n.Writer, "--- %s\nTo: %s\nSubject: %s\n\n%s\n",
+		time.Now().Format(time.RFC3339), msg.To, msg.Subject, body)
+	return err
+}
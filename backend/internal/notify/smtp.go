@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// SMTPNotifier sends messages as plain text email through an SMTP relay.
+type SMTPNotifier struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+var _ Notifier = (*SMTPNotifier)(nil)
+
+func (n *SMTPNotifier) Send(msg Message) error {
+	if n.Host == "" || n.From == "" {
+		return errors.New("smtp notifier is not configured")
+	}
+
+	var auth smtp.Auth
+	if n.Username != "" {
+		auth = smtp.PlainAuth("", n.Username, n.Password, n.Host)
+	}
+
+	addr := net.JoinHostPort(n.Host, n.Port)
+	if err := smtp.SendMail(addr, auth, n.From, []string{msg.To}, n.buildEmail(msg)); err != nil {
+		return fmt.Errorf("error sending email to %s: %v", msg.To, err)
+	}
+	return nil
+}
+
+func (n *SMTPNotifier) buildEmail(msg Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", headerValue(n.From))
+	fmt.Fprintf(&b, "To: %s\r\n", headerValue(msg.To))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", headerValue(msg.Subject)))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// headerValue strips line breaks from a header value so that it cannot end
+// the header early and inject headers of its own.
+func headerValue(value string) string {
+	return strings.NewReplacer("\r", "", "\n", " ").Replace(value)
+}
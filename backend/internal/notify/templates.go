@@ -0,0 +1,80 @@
+package notify
+
+import (
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+)
+
+var (
+	welcomeTemplate = template.Must(template.New("welcome").Parse(
+		`Hi {{.Username}},
+
+Welcome to PennyWise! Your account is ready. Start by adding a few categories
+and setting a monthly budget for each of them.
+
+The PennyWise team
+`))
+
+	passwordResetTemplate = template.Must(template.New("password_reset").Parse(
+		`Hi {{.Username}},
+
+We received a request to reset your PennyWise password. Use the token below to
+choose a new password. It expires at {{.ExpiresAt.Format "2006-01-02 15:04 MST"}}
+and can only be used once.
+
+    {{.Token}}
+
+If you did not request a password reset you can ignore this email.
+`))
+
+	budgetAlertTemplate = template.Must(template.New("budget_alert").Parse(
+		`Hi {{.Username}},
+
+You have spent {{.SpentAmount}} against your {{.AmountLimit}} budget
+for {{.CategoryName}} in {{.BudgetMonth}}/{{.BudgetYear}}, which puts you over the limit.
+`))
+)
+
+type WelcomeData struct {
+	Username string
+}
+
+type PasswordResetData struct {
+	Username  string
+	Token     string
+	ExpiresAt time.Time
+}
+
+type BudgetAlertData struct {
+	Username     string
+	CategoryName string
+	AmountLimit  money.Amount
+	SpentAmount  money.Amount
+	BudgetMonth  string
+	BudgetYear   int
+}
+
+func WelcomeMessage(to string, data WelcomeData) (Message, error) {
+	return render(to, "Welcome to PennyWise", welcomeTemplate, data)
+}
+
+func PasswordResetMessage(to string, data PasswordResetData) (Message, error) {
+	msg, err := render(to, "Reset your PennyWise password", passwordResetTemplate, data)
+	msg.Sensitive = true
+	return msg, err
+}
+
+func BudgetAlertMessage(to string, data BudgetAlertData) (Message, error) {
+	return render(to, "Budget exceeded: "+data.CategoryName, budgetAlertTemplate, data)
+}
+
+func render(to, subject string, tmpl *template.Template, data interface{}) (Message, error) {
+	var body strings.Builder
+	if err := tmpl.Execute(&body, data); err != nil {
+		return Message{}, err
+	}
+	return Message{To: to, Subject: subject, Body: body.String()}, nil
+}
@@ -0,0 +1,32 @@
+package recurring
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/scheduler"
+)
+
+// MaterializeJob runs MaterializeDue from the scheduler.
+type MaterializeJob struct {
+	Service *RecurringService
+}
+
+var _ scheduler.Job = (*MaterializeJob)(nil)
+
+func NewMaterializeJob(service *RecurringService) *MaterializeJob {
+	return &MaterializeJob{Service: service}
+}
+
+func (j *MaterializeJob) Name() string {
+	return "recurring-transactions"
+}
+
+func (j *MaterializeJob) Run(ctx context.Context, now time.Time) error {
+	result, err := j.Service.MaterializeDue(now)
+	if result != nil && result.Booked+result.Skipped > 0 {
+		log.Printf("Recurring transactions: %d booked, %d skipped", result.Booked, result.Skipped)
+	}
+	return err
+}
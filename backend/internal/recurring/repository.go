@@ -0,0 +1,36 @@
+package recurring
+
+import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+)
+
+type RecurringRepository interface {
+	Create(rule *models.RecurringRule) error
+	Update(rule *models.RecurringRule) error
+	// DeleteByID removes the rule and its occurrences. Transactions already
+	// booked from it are kept.
+	DeleteByID(id uint) error
+	FindByID(id uint) (*models.RecurringRule, error)
+	FindAllByUserID(userID uint) ([]*models.RecurringRule, error)
+	// FindDue returns the rules with an occurrence due by now, with their
+	// users.
+	FindDue(now time.Time) ([]*models.RecurringRule, error)
+	FindOccurrence(ruleID uint, date time.Time) (*models.RecurringOccurrence, error)
+	// FindOccurrences returns the rules' recorded occurrences from from to
+	// to, both included.
+	FindOccurrences(ruleIDs []uint, from, to time.Time) ([]*models.RecurringOccurrence, error)
+	SaveOccurrence(occurrence *models.RecurringOccurrence) error
+	DeleteOccurrence(ruleID uint, date time.Time) error
+	// FindBookedTransactionID returns the ID of the user's transaction with
+	// the given external ID, or zero when there is none.
+	FindBookedTransactionID(userID uint, externalID string) (uint, error)
+	// SaveProgress stores the rule's new position together with the
+	// occurrence it has just handled, if any.
+	SaveProgress(rule *models.RecurringRule, occurrence *models.RecurringOccurrence) error
+	// WithLockedRule reloads the rule and locks it until fn returns, so that
+	// concurrent runs book its occurrences one at a time. fn is given a
+	// repository that works inside the lock.
+	WithLockedRule(rule *models.RecurringRule, fn func(repo RecurringRepository) error) error
+}
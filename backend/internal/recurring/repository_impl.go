@@ -0,0 +1,120 @@
+package recurring
+
+import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RecurringRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewRecurringRepository(db *gorm.DB) *RecurringRepositoryImpl {
+	return &RecurringRepositoryImpl{DB: db}
+}
+
+func (r *RecurringRepositoryImpl) Create(rule *models.RecurringRule) error {
+	return r.DB.Create(rule).Error
+}
+
+func (r *RecurringRepositoryImpl) Update(rule *models.RecurringRule) error {
+	return r.DB.Omit("User").Save(rule).Error
+}
+
+func (r *RecurringRepositoryImpl) DeleteByID(id uint) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("recurring_rule_id = ?", id).Delete(&models.RecurringOccurrence{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.RecurringRule{}, id).Error
+	})
+}
+
+func (r *RecurringRepositoryImpl) FindByID(id uint) (*models.RecurringRule, error) {
+	var rule models.RecurringRule
+	if err := r.DB.First(&rule, id).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *RecurringRepositoryImpl) FindAllByUserID(userID uint) ([]*models.RecurringRule, error) {
+	var rules []*models.RecurringRule
+	if err := r.DB.Where("user_id = ?", userID).Order("next_date IS NULL, next_date, id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *RecurringRepositoryImpl) FindDue(now time.Time) ([]*models.RecurringRule, error) {
+	var rules []*models.RecurringRule
+	if err := r.DB.Preload("User").Where("next_date <= ?", now).Order("next_date, id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *RecurringRepositoryImpl) FindOccurrence(ruleID uint, date time.Time) (*models.RecurringOccurrence, error) {
+	var occurrence models.RecurringOccurrence
+	if err := r.DB.Where("recurring_rule_id = ? AND date = ?", ruleID, date).First(&occurrence).Error; err != nil {
+		return nil, err
+	}
+	return &occurrence, nil
+}
+
+func (r *RecurringRepositoryImpl) FindOccurrences(ruleIDs []uint, from, to time.Time) ([]*models.RecurringOccurrence, error) {
+	var occurrences []*models.RecurringOccurrence
+	if len(ruleIDs) == 0 {
+		return occurrences, nil
+	}
+	err := r.DB.Where("recurring_rule_id IN ? AND date BETWEEN ? AND ?", ruleIDs, from, to).
+		Order("date, recurring_rule_id").
+		Find(&occurrences).Error
+	if err != nil {
+		return nil, err
+	}
+	return occurrences, nil
+}
+
+func (r *RecurringRepositoryImpl) SaveOccurrence(occurrence *models.RecurringOccurrence) error {
+	return r.DB.Save(occurrence).Error
+}
+
+func (r *RecurringRepositoryImpl) DeleteOccurrence(ruleID uint, date time.Time) error {
+	return r.DB.Where("recurring_rule_id = ? AND date = ?", ruleID, date).Delete(&models.RecurringOccurrence{}).Error
+}
+
+func (r *RecurringRepositoryImpl) FindBookedTransactionID(userID uint, externalID string) (uint, error) {
+	var ids []uint
+	err := r.DB.Model(&models.Transaction{}).
+		Where("user_id = ? AND external_id = ?", userID, externalID).
+		Limit(1).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+func (r *RecurringRepositoryImpl) SaveProgress(rule *models.RecurringRule, occurrence *models.RecurringOccurrence) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if occurrence != nil {
+			if err := tx.Save(occurrence).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Omit("User").Save(rule).Error
+	})
+}
+
+func (r *RecurringRepositoryImpl) WithLockedRule(rule *models.RecurringRule, fn func(repo RecurringRepository) error) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Omit("User").First(rule, rule.ID).Error; err != nil {
+			return err
+		}
+		return fn(NewRecurringRepository(tx))
+	})
+}
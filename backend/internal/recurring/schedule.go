@@ -0,0 +1,109 @@
+package recurring
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+)
+
+// ParseFrequency returns the named frequency, defaulting to monthly.
+func ParseFrequency(name string) (string, error) {
+	switch frequency := strings.ToLower(strings.TrimSpace(name)); frequency {
+	case "":
+		return models.FrequencyMonthly, nil
+	case models.FrequencyDaily, models.FrequencyWeekly, models.FrequencyMonthly, models.FrequencyYearly:
+		return frequency, nil
+	default:
+		return "", fmt.Errorf("%w: unknown frequency %q", ErrInvalidRule, name)
+	}
+}
+
+// schedule works out the dates a rule occurs on, as midnights in the user's
+// time zone.
+type schedule struct {
+	rule  *models.RecurringRule
+	start time.Time
+	end   *time.Time
+	day   int
+}
+
+func newSchedule(rule *models.RecurringRule, loc *time.Location) *schedule {
+	s := &schedule{rule: rule, start: dateIn(rule.StartDate, loc), day: rule.DayOfMonth}
+	if rule.EndDate != nil {
+		end := dateIn(*rule.EndDate, loc)
+		s.end = &end
+	}
+	if s.day == 0 {
+		s.day = s.start.Day()
+	}
+	return s
+}
+
+// at returns the rule's nth date counting from the start, which may fall
+// before StartDate for monthly and yearly rules on a different day.
+func (s *schedule) at(n int) time.Time {
+	step := n * s.rule.Interval
+	switch s.rule.Frequency {
+	case models.FrequencyDaily:
+		return s.start.AddDate(0, 0, step)
+	case models.FrequencyWeekly:
+		return s.start.AddDate(0, 0, 7*step)
+	case models.FrequencyYearly:
+		step *= 12
+	}
+
+	first := time.Date(s.start.Year(), s.start.Month()+time.Month(step), 1, 0, 0, 0, 0, s.start.Location())
+	day := s.day
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// between returns the rule's dates from from to to, both included.
+func (s *schedule) between(from, to time.Time) []time.Time {
+	var dates []time.Time
+	for n := 0; ; n++ {
+		date := s.at(n)
+		if date.After(to) || (s.end != nil && date.After(*s.end)) {
+			return dates
+		}
+		if !date.Before(from) && !date.Before(s.start) {
+			dates = append(dates, date)
+		}
+	}
+}
+
+// after returns the rule's first date after date, or false when the rule
+// has ended by then.
+func (s *schedule) after(date time.Time) (time.Time, bool) {
+	for n := 0; ; n++ {
+		next := s.at(n)
+		if s.end != nil && next.After(*s.end) {
+			return time.Time{}, false
+		}
+		if next.After(date) && !next.Before(s.start) {
+			return next, true
+		}
+	}
+}
+
+// first returns the rule's first date, or false when it ends before it
+// begins.
+func (s *schedule) first() (time.Time, bool) {
+	return s.after(s.start.AddDate(0, 0, -1))
+}
+
+// includes reports whether the rule occurs on date.
+func (s *schedule) includes(date time.Time) bool {
+	dates := s.between(date, date)
+	return len(dates) == 1
+}
+
+// dateIn returns the midnight that starts t's calendar day in loc.
+func dateIn(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.In(loc).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,563 @@
+// Package recurring books repeating transactions, such as rent and
+// subscriptions, from rules that say when they occur.
+package recurring
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/user"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRuleNotFound      = errors.New("recurring rule not found")
+	ErrRuleAccessDenied  = errors.New("access denied: recurring rule does not belong to the user")
+	ErrInvalidRule       = errors.New("invalid recurring rule")
+	ErrInvalidOccurrence = errors.New("invalid occurrence")
+)
+
+// TransactionBooker checks and books the transactions rules produce,
+// typically *transaction.TransactionService.
+type TransactionBooker interface {
+	AddTransaction(username string, input transaction.TransactionInput) (*models.Transaction, error)
+	ValidateTransaction(username string, input transaction.TransactionInput) error
+}
+
+type RecurringService struct {
+	Repo         RecurringRepository
+	UserRepo     user.UserRepository
+	Transactions TransactionBooker
+	Settings     transaction.SettingsProvider
+}
+
+func NewRecurringService(repo RecurringRepository, userRepo user.UserRepository, transactions TransactionBooker) *RecurringService {
+	return &RecurringService{
+		Repo:         repo,
+		UserRepo:     userRepo,
+		Transactions: transactions,
+	}
+}
+
+// RuleInput carries the user-editable fields of a rule. The transaction
+// fields are those of transaction.TransactionInput; transfers cannot recur.
+// Frequency defaults to monthly and Interval to 1. DayOfMonth only applies to
+// monthly and yearly rules.
+type RuleInput struct {
+	Type        string
+	CategoryID  uint
+	AccountID   uint
+	Amount      money.Amount
+	Currency    money.Currency
+	Description string
+	Notes       string
+	Frequency   string
+	Interval    int
+	DayOfMonth  int
+	StartDate   time.Time
+	EndDate     *time.Time
+}
+
+// OccurrenceInput changes a single occurrence of a rule. Skip leaves it
+// unbooked; otherwise Amount, Description and Notes, when set, replace the
+// rule's.
+type OccurrenceInput struct {
+	Skip        bool
+	Amount      *money.Amount
+	Description *string
+	Notes       *string
+}
+
+// Occurrence is a date a rule falls due on, with what it will book then.
+// Modified is set when the occurrence differs from the rule.
+type Occurrence struct {
+	RuleID      uint           `json:"rule_id"`
+	Date        time.Time      `json:"date"`
+	Type        string         `json:"type"`
+	CategoryID  uint           `json:"category_id"`
+	AccountID   *uint          `json:"account_id,omitempty"`
+	Amount      money.Amount   `json:"amount" swaggertype:"number"`
+	Currency    money.Currency `json:"currency,omitempty" swaggertype:"string"`
+	Description string         `json:"description"`
+	Notes       string         `json:"notes,omitempty"`
+	Skipped     bool           `json:"skipped"`
+	Modified    bool           `json:"modified"`
+}
+
+// MaterializeResult counts the occurrences MaterializeDue booked and
+// skipped.
+type MaterializeResult struct {
+	Booked  int `json:"booked"`
+	Skipped int `json:"skipped"`
+}
+
+func (s *RecurringService) GetRules(username string) ([]*models.RecurringRule, error) {
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	return s.Repo.FindAllByUserID(user.ID)
+}
+
+func (s *RecurringService) GetRule(username string, id uint) (*models.RecurringRule, error) {
+	_, rule, err := s.findOwnedRule(username, id)
+	return rule, err
+}
+
+// CreateRule stores a rule. Its first occurrence may be in the past, in
+// which case the occurrences since then are booked on the next run.
+func (s *RecurringService) CreateRule(username string, input RuleInput) (*models.RecurringRule, error) {
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := &models.RecurringRule{UserID: user.ID}
+	if err := s.apply(user, rule, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.Repo.Create(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateRule replaces the rule's fields. Occurrences that have already been
+// booked or skipped are left as they are; the rule carries on from the first
+// date after them on its new schedule.
+func (s *RecurringService) UpdateRule(username string, id uint, input RuleInput) (*models.RecurringRule, error) {
+	user, rule, err := s.findOwnedRule(username, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apply(user, rule, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.Repo.Update(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// DeleteRule removes the rule. Transactions it has booked are kept.
+func (s *RecurringService) DeleteRule(username string, id uint) error {
+	_, rule, err := s.findOwnedRule(username, id)
+	if err != nil {
+		return err
+	}
+	return s.Repo.DeleteByID(rule.ID)
+}
+
+// GetUpcoming returns the occurrences of the user's rules that are still to
+// be booked from today until days from now, in date order. Skipped
+// occurrences are included and marked.
+func (s *RecurringService) GetUpcoming(username string, now time.Time, days int) ([]*Occurrence, error) {
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := s.location(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	today := dateIn(now, loc)
+	to := today.AddDate(0, 0, days)
+
+	rules, err := s.Repo.FindAllByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleIDs := make([]uint, 0, len(rules))
+	for _, rule := range rules {
+		ruleIDs = append(ruleIDs, rule.ID)
+	}
+	recorded, err := s.Repo.FindOccurrences(ruleIDs, today, to)
+	if err != nil {
+		return nil, err
+	}
+	changes := make(map[occurrenceKey]*models.RecurringOccurrence, len(recorded))
+	for _, occurrence := range recorded {
+		changes[keyOf(occurrence.RecurringRuleID, dateIn(occurrence.Date, loc))] = occurrence
+	}
+
+	upcoming := []*Occurrence{}
+	for _, rule := range rules {
+		if rule.NextDate == nil {
+			continue
+		}
+		from := dateIn(*rule.NextDate, loc)
+		if from.Before(today) {
+			from = today
+		}
+		for _, date := range newSchedule(rule, loc).between(from, to) {
+			upcoming = append(upcoming, occurrenceOf(rule, date, changes[keyOf(rule.ID, date)]))
+		}
+	}
+
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		if !upcoming[i].Date.Equal(upcoming[j].Date) {
+			return upcoming[i].Date.Before(upcoming[j].Date)
+		}
+		return upcoming[i].RuleID < upcoming[j].RuleID
+	})
+	return upcoming, nil
+}
+
+// UpdateOccurrence skips or changes the rule's occurrence on date, which
+// must not have been booked yet.
+func (s *RecurringService) UpdateOccurrence(username string, id uint, date time.Time, input OccurrenceInput) (*Occurrence, error) {
+	_, rule, err := s.findOwnedRule(username, id)
+	if err != nil {
+		return nil, err
+	}
+
+	date, err = s.pendingDate(rule, date)
+	if err != nil {
+		return nil, err
+	}
+	if input.Amount != nil && *input.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidOccurrence)
+	}
+
+	occurrence, err := s.Repo.FindOccurrence(rule.ID, date)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		occurrence, err = &models.RecurringOccurrence{RecurringRuleID: rule.ID, Date: date}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	occurrence.Skipped = input.Skip
+	occurrence.Amount = input.Amount
+	occurrence.Description = input.Description
+	occurrence.Notes = input.Notes
+	if err := s.Repo.SaveOccurrence(occurrence); err != nil {
+		return nil, err
+	}
+	return occurrenceOf(rule, date, occurrence), nil
+}
+
+// ResetOccurrence undoes any change to the rule's occurrence on date, which
+// must not have been booked yet.
+func (s *RecurringService) ResetOccurrence(username string, id uint, date time.Time) error {
+	_, rule, err := s.findOwnedRule(username, id)
+	if err != nil {
+		return err
+	}
+
+	date, err = s.pendingDate(rule, date)
+	if err != nil {
+		return err
+	}
+	return s.Repo.DeleteOccurrence(rule.ID, date)
+}
+
+// MaterializeDue books every occurrence due by now through the transaction
+// service, in date order, and moves each rule on past it. Each rule is locked
+// while its occurrences are booked, so concurrent runs do not book the same
+// occurrence twice. Each occurrence is booked with an external ID naming the
+// rule and date, and one that already has a transaction is not booked again,
+// so a run that stopped part way can safely be repeated. A rule that fails
+// does not stop the others.
+func (s *RecurringService) MaterializeDue(now time.Time) (*MaterializeResult, error) {
+	rules, err := s.Repo.FindDue(now)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MaterializeResult{}
+	var errs []error
+	for _, rule := range rules {
+		err := s.Repo.WithLockedRule(rule, func(repo RecurringRepository) error {
+			return s.materialize(repo, rule, now, result)
+		})
+		// A rule deleted since it was found has nothing left to book.
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			errs = append(errs, fmt.Errorf("recurring rule %d: %w", rule.ID, err))
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// materialize books the rule's occurrences due by now, recording its
+// progress through repo.
+func (s *RecurringService) materialize(repo RecurringRepository, rule *models.RecurringRule, now time.Time, result *MaterializeResult) error {
+	loc, err := s.location(rule.UserID)
+	if err != nil {
+		return err
+	}
+	schedule := newSchedule(rule, loc)
+
+	for rule.NextDate != nil && !rule.NextDate.After(now) {
+		date := dateIn(*rule.NextDate, loc)
+
+		occurrence, err := repo.FindOccurrence(rule.ID, date)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			occurrence, err = &models.RecurringOccurrence{RecurringRuleID: rule.ID, Date: date}, nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if occurrence.Skipped {
+			result.Skipped++
+			occurrence = nil
+		} else {
+			transactionID, err := s.book(repo, rule, date, occurrence)
+			if err != nil {
+				return err
+			}
+			occurrence.TransactionID = &transactionID
+			result.Booked++
+		}
+
+		rule.LastDate = &date
+		rule.NextDate = nil
+		if next, ok := schedule.after(date); ok {
+			rule.NextDate = &next
+		}
+		if err := repo.SaveProgress(rule, occurrence); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// book adds the transaction for the rule's occurrence on date, unless an
+// earlier run already did, and returns its ID.
+func (s *RecurringService) book(repo RecurringRepository, rule *models.RecurringRule, date time.Time, occurrence *models.RecurringOccurrence) (uint, error) {
+	externalID := fmt.Sprintf("recurring:%d:%s", rule.ID, date.Format("2006-01-02"))
+	transactionID, err := repo.FindBookedTransactionID(rule.UserID, externalID)
+	if err != nil || transactionID != 0 {
+		return transactionID, err
+	}
+
+	planned := occurrenceOf(rule, date, occurrence)
+	input := transactionInput(rule, date)
+	input.Amount = planned.Amount
+	input.Description = planned.Description
+	input.Notes = planned.Notes
+	input.ExternalID = externalID
+	// A rule's occurrences look alike by design; none is a duplicate.
+	input.AllowDuplicate = true
+
+	booked, err := s.Transactions.AddTransaction(rule.User.Username, input)
+	if err != nil {
+		return 0, err
+	}
+	return booked.ID, nil
+}
+
+// apply validates input and copies it onto rule, then works out the rule's
+// next date.
+func (s *RecurringService) apply(user *models.User, rule *models.RecurringRule, input RuleInput) error {
+	kind, err := transaction.ParseType(input.Type)
+	if err != nil {
+		return err
+	}
+	if input.Amount <= 0 {
+		return fmt.Errorf("%w: amount must be positive", ErrInvalidRule)
+	}
+
+	frequency, err := ParseFrequency(input.Frequency)
+	if err != nil {
+		return err
+	}
+	interval := input.Interval
+	if interval == 0 {
+		interval = 1
+	}
+	if interval < 0 {
+		return fmt.Errorf("%w: interval must be positive", ErrInvalidRule)
+	}
+	if input.DayOfMonth != 0 && frequency != models.FrequencyMonthly && frequency != models.FrequencyYearly {
+		return fmt.Errorf("%w: day_of_month only applies to monthly and yearly rules", ErrInvalidRule)
+	}
+	if input.DayOfMonth < 0 || input.DayOfMonth > 31 {
+		return fmt.Errorf("%w: day_of_month must be between 1 and 31", ErrInvalidRule)
+	}
+
+	loc, err := s.location(user.ID)
+	if err != nil {
+		return err
+	}
+	if input.StartDate.IsZero() {
+		return fmt.Errorf("%w: start_date is required", ErrInvalidRule)
+	}
+	start := calendarDate(input.StartDate, loc)
+	var end *time.Time
+	if input.EndDate != nil {
+		date := calendarDate(*input.EndDate, loc)
+		if date.Before(start) {
+			return fmt.Errorf("%w: end_date cannot be before start_date", ErrInvalidRule)
+		}
+		end = &date
+	}
+
+	rule.Type = kind
+	rule.CategoryID = input.CategoryID
+	rule.AccountID = nil
+	if input.AccountID != 0 {
+		accountID := input.AccountID
+		rule.AccountID = &accountID
+	}
+	rule.Amount = input.Amount
+	rule.Currency = input.Currency
+	rule.Description = strings.TrimSpace(input.Description)
+	rule.Notes = input.Notes
+	rule.Frequency = frequency
+	rule.Interval = interval
+	rule.DayOfMonth = input.DayOfMonth
+	rule.StartDate = start
+	rule.EndDate = end
+
+	if err := s.Transactions.ValidateTransaction(user.Username, transactionInput(rule, start)); err != nil {
+		return err
+	}
+
+	schedule := newSchedule(rule, loc)
+	var next time.Time
+	var ok bool
+	if rule.LastDate != nil {
+		next, ok = schedule.after(dateIn(*rule.LastDate, loc))
+	} else {
+		next, ok = schedule.first()
+	}
+	rule.NextDate = nil
+	if ok {
+		rule.NextDate = &next
+	}
+	return nil
+}
+
+// pendingDate checks that the rule falls due on date and has not reached it
+// yet, and returns it as a midnight in the user's time zone.
+func (s *RecurringService) pendingDate(rule *models.RecurringRule, date time.Time) (time.Time, error) {
+	loc, err := s.location(rule.UserID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	date = calendarDate(date, loc)
+
+	if !newSchedule(rule, loc).includes(date) {
+		return time.Time{}, fmt.Errorf("%w: the rule does not fall due on %s", ErrInvalidOccurrence, date.Format("2006-01-02"))
+	}
+	if rule.NextDate == nil || date.Before(dateIn(*rule.NextDate, loc)) {
+		return time.Time{}, fmt.Errorf("%w: the occurrence on %s has already been booked or skipped; edit its transaction instead", ErrInvalidOccurrence, date.Format("2006-01-02"))
+	}
+	return date, nil
+}
+
+// findOwnedRule loads a rule and verifies that it belongs to the user.
+func (s *RecurringService) findOwnedRule(username string, id uint) (*models.User, *models.RecurringRule, error) {
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rule, err := s.Repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrRuleNotFound
+		}
+		return nil, nil, err
+	}
+
+	if rule.UserID != user.ID {
+		return nil, nil, ErrRuleAccessDenied
+	}
+
+	return user, rule, nil
+}
+
+// location returns the time zone the rule's dates fall in, which is the
+// user's own.
+func (s *RecurringService) location(userID uint) (*time.Location, error) {
+	if s.Settings == nil {
+		return time.Local, nil
+	}
+
+	settings, err := s.Settings.SettingsForUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return user.Location(settings), nil
+}
+
+// transactionInput returns the transaction the rule books on date, before
+// any change to that occurrence.
+func transactionInput(rule *models.RecurringRule, date time.Time) transaction.TransactionInput {
+	input := transaction.TransactionInput{
+		Type:            rule.Type,
+		CategoryID:      rule.CategoryID,
+		Amount:          rule.Amount,
+		Currency:        rule.Currency,
+		Description:     rule.Description,
+		Notes:           rule.Notes,
+		TransactionDate: date,
+	}
+	if rule.AccountID != nil {
+		input.AccountID = *rule.AccountID
+	}
+	return input
+}
+
+// occurrenceOf describes the rule's occurrence on date with the changes
+// recorded for it, if any.
+func occurrenceOf(rule *models.RecurringRule, date time.Time, change *models.RecurringOccurrence) *Occurrence {
+	occurrence := &Occurrence{
+		RuleID:      rule.ID,
+		Date:        date,
+		Type:        rule.Type,
+		CategoryID:  rule.CategoryID,
+		AccountID:   rule.AccountID,
+		Amount:      rule.Amount,
+		Currency:    rule.Currency,
+		Description: rule.Description,
+		Notes:       rule.Notes,
+	}
+	if change == nil {
+		return occurrence
+	}
+
+	occurrence.Skipped = change.Skipped
+	if change.Amount != nil {
+		occurrence.Amount = *change.Amount
+	}
+	if change.Description != nil {
+		occurrence.Description = *change.Description
+	}
+	if change.Notes != nil {
+		occurrence.Notes = *change.Notes
+	}
+	occurrence.Modified = change.Skipped || change.Amount != nil || change.Description != nil || change.Notes != nil
+	return occurrence
+}
+
+type occurrenceKey struct {
+	ruleID uint
+	date   string
+}
+
+func keyOf(ruleID uint, date time.Time) occurrenceKey {
+	return occurrenceKey{ruleID: ruleID, date: date.Format("2006-01-02")}
+}
+
+// calendarDate returns the midnight in loc that starts the calendar day
+// written in t, for dates given by the user rather than read back from the
+// database.
+func calendarDate(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,50 @@
+package reports
+
+import (
+	"math"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+)
+
+// CashFlow sums up money received and spent over a range. Net is income less
+// expense, and SavingsRate is the fraction of income that was not spent; it
+// is omitted when there was no income and is negative when more was spent
+// than received.
+type CashFlow struct {
+	Income      money.Amount     `json:"income" swaggertype:"number"`
+	Expense     money.Amount     `json:"expense" swaggertype:"number"`
+	Net         money.Amount     `json:"net" swaggertype:"number"`
+	SavingsRate *float64         `json:"savings_rate,omitempty"`
+	Months      []MonthlySummary `json:"months"`
+}
+
+// CashFlow returns the user's income, spending and savings rate over period,
+// with the monthly figures they are made of.
+func (s *ReportService) CashFlow(username string, period Range) (*CashFlow, error) {
+	months, err := s.MonthlySummaries(username, period)
+	if err != nil {
+		return nil, err
+	}
+
+	flow := &CashFlow{Months: months}
+	for _, month := range months {
+		flow.Income += month.Income
+		flow.Expense += month.Expense
+	}
+	flow.Net = flow.Income - flow.Expense
+	flow.SavingsRate = savingsRate(flow.Income, flow.Expense)
+	if flow.Months == nil {
+		flow.Months = []MonthlySummary{}
+	}
+	return flow, nil
+}
+
+// savingsRate returns (income - expense) / income rounded to four places, or
+// nil without income.
+func savingsRate(income, expense money.Amount) *float64 {
+	if income <= 0 {
+		return nil
+	}
+	rate := math.Round(float64(income-expense)/float64(income)*10000) / 10000
+	return &rate
+}
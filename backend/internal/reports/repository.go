@@ -0,0 +1,12 @@
+package reports
+
+import "github.com/shaikhjunaidx/pennywise-backend/internal/money"
+
+type ReportRepository interface {
+	SpendingByCategoryMonth(userID uint, period Range) ([]CategoryMonthTotal, error)
+	TotalsByMonth(userID uint, period Range) ([]MonthTotals, error)
+	TotalSpending(userID uint, period Range) (money.Amount, error)
+	TopCategories(userID uint, period Range, limit int) ([]CategoryTotal, error)
+	TopMerchants(userID uint, period Range, limit int) ([]MerchantTotal, error)
+	TotalsByBucket(userID uint, buckets []Bucket, categoryIDs []uint) ([]BucketTotal, error)
+}
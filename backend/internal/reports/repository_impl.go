@@ -0,0 +1,199 @@
+package reports
+
+import (
+	"strings"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/budget"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"gorm.io/gorm"
+)
+
+// All reports sum base_amount so that every figure is in the user's base
+// currency, as budgets are. Spending is counted the way budgets count it:
+// expenses less refunds. Transfers appear in no report. Breakdowns by
+// category count each split of a split transaction under its own category.
+type ReportRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) *ReportRepositoryImpl {
+	return &ReportRepositoryImpl{DB: db}
+}
+
+// transactions starts a query over the user's transactions in period.
+func (r *ReportRepositoryImpl) transactions(userID uint, period Range) *gorm.DB {
+	return r.DB.Table("transactions").
+		Where("transactions.user_id = ? AND transactions.transaction_date >= ? AND transactions.transaction_date < ?", userID, period.From, period.To)
+}
+
+// spending narrows transactions to the expenses and refunds in period.
+func (r *ReportRepositoryImpl) spending(userID uint, period Range) *gorm.DB {
+	return r.transactions(userID, period).Where("transactions.type IN ?", spendingTypes)
+}
+
+var (
+	spendingTypes = []string{models.TransactionTypeExpense, models.TransactionTypeRefund}
+
+	// spendingSum totals spending, with refunds taken off.
+	spendingSum = "SUM(" + budget.SpendingSQL + ")"
+
+	// lineSpendingSum is spendingSum over the rows of budget.SplitLinesJoin.
+	lineSpendingSum = "SUM(" + budget.LineSpendingSQL + ")"
+)
+
+// monthsTable returns a derived table of the calendar months of period,
+// clipped to it, and its arguments. Grouping on its rows follows the time
+// zone period was given in, where YEAR() and MONTH() would follow the
+// database session's.
+func monthsTable(period Range) (string, []interface{}) {
+	months := period.months()
+	selects := make([]string, len(months))
+	vars := make([]interface{}, 0, 4*len(months))
+	for i, start := range months {
+		end := start.AddDate(0, 1, 0)
+		if start.Before(period.From) {
+			start = period.From
+		}
+		if end.After(period.To) {
+			end = period.To
+		}
+		selects[i] = "SELECT ? AS month_year, ? AS month_number, ? AS month_start, ? AS month_end"
+		vars = append(vars, months[i].Year(), int(months[i].Month()), start, end)
+	}
+	return "(" + strings.Join(selects, " UNION ALL ") + ") AS months", vars
+}
+
+// monthsJoin joins each transaction to the month it falls in.
+const monthsJoin = " JOIN transactions ON transactions.transaction_date >= months.month_start AND transactions.transaction_date < months.month_end"
+
+func (r *ReportRepositoryImpl) SpendingByCategoryMonth(userID uint, period Range) ([]CategoryMonthTotal, error) {
+	table, vars := monthsTable(period)
+	sql := "SELECT " + budget.LineCategorySQL + " AS category_id, categories.name AS category_name, months.month_year AS year, months.month_number AS month, " + lineSpendingSum + " AS total " +
+		"FROM " + table + monthsJoin + " " + budget.SplitLinesJoin +
+		" JOIN categories ON categories.id = " + budget.LineCategorySQL +
+		" WHERE transactions.user_id = ? AND transactions.type IN ?" +
+		" GROUP BY " + budget.LineCategorySQL + ", categories.name, months.month_year, months.month_number" +
+		" ORDER BY year, month, total DESC"
+
+	var totals []CategoryMonthTotal
+	if err := r.DB.Raw(sql, append(vars, userID, spendingTypes)...).Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// TotalsByMonth splits each month into money spent (expenses less refunds)
+// and money received as income.
+func (r *ReportRepositoryImpl) TotalsByMonth(userID uint, period Range) ([]MonthTotals, error) {
+	table, vars := monthsTable(period)
+	sql := "SELECT months.month_year AS year, months.month_number AS month, " +
+		"COALESCE(" + spendingSum + ", 0) AS expense, " +
+		"COALESCE(SUM(CASE WHEN transactions.type = ? THEN transactions.base_amount ELSE 0 END), 0) AS income " +
+		"FROM " + table + monthsJoin +
+		" WHERE transactions.user_id = ?" +
+		" GROUP BY months.month_year, months.month_number" +
+		" ORDER BY year, month"
+
+	var totals []MonthTotals
+	if err := r.DB.Raw(sql, append([]interface{}{models.TransactionTypeIncome}, append(vars, userID)...)...).Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+func (r *ReportRepositoryImpl) TotalSpending(userID uint, period Range) (money.Amount, error) {
+	var total money.Amount
+
+	if err := r.spending(userID, period).Select("COALESCE(" + spendingSum + ", 0)").Scan(&total).Error; err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (r *ReportRepositoryImpl) TopCategories(userID uint, period Range, limit int) ([]CategoryTotal, error) {
+	var totals []CategoryTotal
+
+	err := r.spending(userID, period).
+		Select(budget.LineCategorySQL + " AS category_id, categories.name AS category_name, " + lineSpendingSum + " AS total, COUNT(*) AS count").
+		Joins(budget.SplitLinesJoin).
+		Joins("JOIN categories ON categories.id = " + budget.LineCategorySQL).
+		Group(budget.LineCategorySQL + ", categories.name").
+		Order("total DESC").
+		Limit(limit).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// merchantKeySQL reduces a description to lower-case letters and single
+// spaces, as transaction.NormalizeDescription does, so that card numbers,
+// reference codes and capitalisation do not split one merchant in two.
+const merchantKeySQL = "TRIM(REGEXP_REPLACE(LOWER(transactions.description), '[^[:alpha:]]+', ' '))"
+
+// TopMerchants groups transactions by description, which is where bank
+// statements name the merchant. Descriptions are grouped on their
+// normalised form and reported as written on one of them.
+func (r *ReportRepositoryImpl) TopMerchants(userID uint, period Range, limit int) ([]MerchantTotal, error) {
+	var totals []MerchantTotal
+
+	err := r.spending(userID, period).
+		Select("MAX(TRIM(transactions.description)) AS merchant, " + spendingSum + " AS total, COUNT(*) AS count").
+		Where(merchantKeySQL + " <> ''").
+		Group(merchantKeySQL).
+		Having(spendingSum + " > 0").
+		Order("total DESC").
+		Limit(limit).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// TotalsByBucket sums the user's spending in each bucket. The bucket
+// bounds are passed in as a derived table, so the grouping follows whatever
+// calendar they were computed in rather than the database's time zone.
+// Buckets without transactions are left out. When categoryIDs narrows the
+// totals, split transactions count only their splits in those categories.
+func (r *ReportRepositoryImpl) TotalsByBucket(userID uint, buckets []Bucket, categoryIDs []uint) ([]BucketTotal, error) {
+	if len(buckets) == 0 {
+		return nil, nil
+	}
+
+	selects := make([]string, len(buckets))
+	vars := make([]interface{}, 0, 3*len(buckets)+3)
+	for i, bucket := range buckets {
+		selects[i] = "SELECT ? AS bucket_index, ? AS bucket_start, ? AS bucket_end"
+		vars = append(vars, i, bucket.Start, bucket.End)
+	}
+
+	sum, lines, where := spendingSum, "", ""
+	if len(categoryIDs) > 0 {
+		sum, lines, where = lineSpendingSum, " "+budget.SplitLinesJoin, " AND "+budget.LineCategorySQL+" IN ?"
+	}
+
+	sql := "SELECT buckets.bucket_index, " + sum + " AS total, COUNT(*) AS count " +
+		"FROM (" + strings.Join(selects, " UNION ALL ") + ") AS buckets " +
+		"JOIN transactions ON transactions.transaction_date >= buckets.bucket_start AND transactions.transaction_date < buckets.bucket_end" + lines +
+		" WHERE transactions.user_id = ? AND transactions.type IN ?" + where
+	vars = append(vars, userID, spendingTypes)
+	if len(categoryIDs) > 0 {
+		vars = append(vars, categoryIDs)
+	}
+	sql += " GROUP BY buckets.bucket_index ORDER BY buckets.bucket_index"
+
+	var totals []BucketTotal
+	if err := r.DB.Raw(sql, vars...).Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
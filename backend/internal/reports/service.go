@@ -0,0 +1,319 @@
+// Package reports summarises a user's spending over a range of months. The
+// aggregation is done in SQL; the service only fills gaps and derives
+// changes between months.
+package reports
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/user"
+)
+
+const (
+	DefaultTopLimit = 5
+	MaxTopLimit     = 50
+
+	// maxRangeMonths bounds how far a report may reach.
+	maxRangeMonths = 120
+)
+
+var (
+	ErrInvalidRange = errors.New("invalid report range")
+	ErrInvalidLimit = errors.New("invalid report limit")
+
+	ErrInvalidGranularity = errors.New("invalid trend granularity")
+)
+
+// Range is the half-open interval [From, To) a report covers.
+type Range struct {
+	From time.Time
+	To   time.Time
+}
+
+// DefaultRange covers the twelve months up to and including the month of
+// now.
+func DefaultRange(now time.Time) Range {
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	return Range{From: month.AddDate(0, -11, 0), To: month.AddDate(0, 1, 0)}
+}
+
+func (r Range) validate() error {
+	if !r.From.Before(r.To) {
+		return fmt.Errorf("%w: from must be before to", ErrInvalidRange)
+	}
+	if r.From.AddDate(0, maxRangeMonths, 0).Before(r.To) {
+		return fmt.Errorf("%w: at most %d months", ErrInvalidRange, maxRangeMonths)
+	}
+	return nil
+}
+
+// months lists the calendar months the range touches, in order.
+func (r Range) months() []time.Time {
+	var months []time.Time
+	month := time.Date(r.From.Year(), r.From.Month(), 1, 0, 0, 0, 0, r.From.Location())
+	for month.Before(r.To) {
+		months = append(months, month)
+		month = month.AddDate(0, 1, 0)
+	}
+	return months
+}
+
+// CategoryMonthTotal is the net spending in a category in one month, and
+// its change from the month before. ChangePercent is omitted when nothing
+// was spent in the category the month before, and both are left out for the
+// first month of a range.
+type CategoryMonthTotal struct {
+	CategoryID    uint         `json:"category_id"`
+	CategoryName  string       `json:"category_name"`
+	Year          int          `json:"year"`
+	Month         int          `json:"month"`
+	Total         money.Amount `json:"total" swaggertype:"number"`
+	Change        money.Amount `json:"change" swaggertype:"number"`
+	ChangePercent *float64     `json:"change_percent,omitempty"`
+}
+
+// MonthTotals is what was spent and received in one month.
+type MonthTotals struct {
+	Year    int          `json:"year"`
+	Month   int          `json:"month"`
+	Expense money.Amount `json:"expense" swaggertype:"number"`
+	Income  money.Amount `json:"income" swaggertype:"number"`
+}
+
+// MonthlySummary adds the net cash flow, the savings rate and the change in
+// spending from the previous month. SavingsRate is omitted in months without
+// income, and ExpenseChangePercent when nothing was spent in the previous
+// month.
+type MonthlySummary struct {
+	MonthTotals
+	Net                  money.Amount `json:"net" swaggertype:"number"`
+	SavingsRate          *float64     `json:"savings_rate,omitempty"`
+	ExpenseChange        money.Amount `json:"expense_change" swaggertype:"number"`
+	ExpenseChangePercent *float64     `json:"expense_change_percent,omitempty"`
+}
+
+// CategoryTotal is the net spending in a category. Share is its fraction of
+// the net spending in all categories.
+type CategoryTotal struct {
+	CategoryID   uint         `json:"category_id"`
+	CategoryName string       `json:"category_name"`
+	Total        money.Amount `json:"total" swaggertype:"number"`
+	Count        int64        `json:"count"`
+	Share        float64      `json:"share"`
+}
+
+type MerchantTotal struct {
+	Merchant string       `json:"merchant"`
+	Total    money.Amount `json:"total" swaggertype:"number"`
+	Count    int64        `json:"count"`
+}
+
+type ReportService struct {
+	Repo     ReportRepository
+	UserRepo user.UserRepository
+	Settings transaction.SettingsProvider
+}
+
+func NewReportService(repo ReportRepository, userRepo user.UserRepository) *ReportService {
+	return &ReportService{Repo: repo, UserRepo: userRepo}
+}
+
+// Calendar returns the time zone and first day of the week the user's
+// reports follow. Without settings to ask, it is the server's zone and weeks
+// start on Monday.
+func (s *ReportService) Calendar(username string) (*time.Location, time.Weekday, error) {
+	if s.Settings == nil {
+		return time.Local, time.Weekday(user.DefaultFirstDayOfWeek), nil
+	}
+
+	found, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	settings, err := s.Settings.SettingsForUserID(found.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return user.Location(settings), user.WeekStart(settings), nil
+}
+
+// userID validates period and looks up the user it is reported for.
+func (s *ReportService) userID(username string, period Range) (uint, error) {
+	if err := period.validate(); err != nil {
+		return 0, err
+	}
+
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return 0, err
+	}
+	return user.ID, nil
+}
+
+// SpendingByCategory returns the net spending per category for each month
+// of period, with the change from the month before. Every category used in
+// period is reported for every month, so that a month without spending
+// shows as a drop rather than a gap. Within a month categories are ordered
+// by spending, highest first.
+func (s *ReportService) SpendingByCategory(username string, period Range) ([]CategoryMonthTotal, error) {
+	userID, err := s.userID(username, period)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.Repo.SpendingByCategoryMonth(userID, period)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		categoryID  uint
+		year, month int
+	}
+	byMonth := make(map[key]money.Amount, len(totals))
+	seen := make(map[uint]bool)
+	var categories []CategoryMonthTotal
+	for _, t := range totals {
+		if !seen[t.CategoryID] {
+			seen[t.CategoryID] = true
+			categories = append(categories, CategoryMonthTotal{CategoryID: t.CategoryID, CategoryName: t.CategoryName})
+		}
+		byMonth[key{t.CategoryID, t.Year, t.Month}] = t.Total
+	}
+
+	filled := []CategoryMonthTotal{}
+	for i, month := range period.months() {
+		start := len(filled)
+		for _, category := range categories {
+			t := category
+			t.Year, t.Month = month.Year(), int(month.Month())
+			t.Total = byMonth[key{t.CategoryID, t.Year, t.Month}]
+			if i > 0 {
+				previousMonth := month.AddDate(0, -1, 0)
+				previous := byMonth[key{t.CategoryID, previousMonth.Year(), int(previousMonth.Month())}]
+				t.Change = t.Total - previous
+				if previous != 0 {
+					percent := math.Round(float64(t.Change)/float64(previous)*10000) / 100
+					t.ChangePercent = &percent
+				}
+			}
+			filled = append(filled, t)
+		}
+		sort.SliceStable(filled[start:], func(a, b int) bool {
+			return filled[start+a].Total > filled[start+b].Total
+		})
+	}
+	return filled, nil
+}
+
+// MonthlySummaries returns income and expense for every month of period,
+// including months without transactions, with month-over-month changes.
+func (s *ReportService) MonthlySummaries(username string, period Range) ([]MonthlySummary, error) {
+	userID, err := s.userID(username, period)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.Repo.TotalsByMonth(userID, period)
+	if err != nil {
+		return nil, err
+	}
+
+	byMonth := make(map[[2]int]MonthTotals, len(totals))
+	for _, t := range totals {
+		byMonth[[2]int{t.Year, t.Month}] = t
+	}
+
+	var summaries []MonthlySummary
+	for i, month := range period.months() {
+		key := [2]int{month.Year(), int(month.Month())}
+		t, ok := byMonth[key]
+		if !ok {
+			t = MonthTotals{Year: key[0], Month: key[1]}
+		}
+
+		summary := MonthlySummary{MonthTotals: t, Net: t.Income - t.Expense, SavingsRate: savingsRate(t.Income, t.Expense)}
+		if i > 0 {
+			previous := summaries[i-1].Expense
+			summary.ExpenseChange = t.Expense - previous
+			if previous != 0 {
+				percent := math.Round(float64(summary.ExpenseChange)/float64(previous)*10000) / 100
+				summary.ExpenseChangePercent = &percent
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// TopCategories returns the limit categories with the highest net spending
+// in period.
+func (s *ReportService) TopCategories(username string, period Range, limit int) ([]CategoryTotal, error) {
+	limit, err := topLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := s.userID(username, period)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.Repo.TopCategories(userID, period, limit)
+	if err != nil {
+		return nil, err
+	}
+	overall, err := s.Repo.TotalSpending(userID, period)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range totals {
+		if overall > 0 {
+			totals[i].Share = math.Round(float64(totals[i].Total)/float64(overall)*10000) / 10000
+		}
+	}
+	if totals == nil {
+		totals = []CategoryTotal{}
+	}
+	return totals, nil
+}
+
+// TopMerchants returns the limit merchants the user spent most with in
+// period.
+func (s *ReportService) TopMerchants(username string, period Range, limit int) ([]MerchantTotal, error) {
+	limit, err := topLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := s.userID(username, period)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.Repo.TopMerchants(userID, period, limit)
+	if err != nil {
+		return nil, err
+	}
+	if totals == nil {
+		totals = []MerchantTotal{}
+	}
+	return totals, nil
+}
+
+func topLimit(limit int) (int, error) {
+	switch {
+	case limit == 0:
+		return DefaultTopLimit, nil
+	case limit < 0 || limit > MaxTopLimit:
+		return 0, fmt.Errorf("%w: must be between 1 and %d", ErrInvalidLimit, MaxTopLimit)
+	default:
+		return limit, nil
+	}
+}
@@ -0,0 +1,174 @@
+package reports
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+)
+
+type Granularity string
+
+const (
+	GranularityDay     Granularity = "day"
+	GranularityWeek    Granularity = "week"
+	GranularityMonth   Granularity = "month"
+	GranularityQuarter Granularity = "quarter"
+	GranularityYear    Granularity = "year"
+)
+
+// MaxBuckets bounds the number of points in a trend, which is about three
+// years of days.
+const MaxBuckets = 1100
+
+// ParseGranularity returns the named granularity, defaulting to month.
+func ParseGranularity(name string) (Granularity, error) {
+	switch granularity := Granularity(strings.ToLower(strings.TrimSpace(name))); granularity {
+	case "":
+		return GranularityMonth, nil
+	case GranularityDay, GranularityWeek, GranularityMonth, GranularityQuarter, GranularityYear:
+		return granularity, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidGranularity, name)
+	}
+}
+
+// start returns the beginning of the bucket containing t, in t's location.
+// Weeks start on weekStart.
+func (g Granularity) start(t time.Time, weekStart time.Weekday) time.Time {
+	year, month, day := t.Date()
+	switch g {
+	case GranularityDay:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	case GranularityWeek:
+		offset := (int(t.Weekday()) - int(weekStart) + 7) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, t.Location())
+	case GranularityQuarter:
+		return time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, t.Location())
+	case GranularityYear:
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// next returns the beginning of the bucket after the one starting at start.
+// Calendar arithmetic keeps buckets aligned across daylight saving changes.
+func (g Granularity) next(start time.Time) time.Time {
+	switch g {
+	case GranularityDay:
+		return start.AddDate(0, 0, 1)
+	case GranularityWeek:
+		return start.AddDate(0, 0, 7)
+	case GranularityQuarter:
+		return start.AddDate(0, 3, 0)
+	case GranularityYear:
+		return start.AddDate(1, 0, 0)
+	default:
+		return start.AddDate(0, 1, 0)
+	}
+}
+
+// DefaultTrendRange covers a sensible number of buckets up to and including
+// the one containing now: 30 days, 12 weeks, 12 months, 8 quarters or 5
+// years.
+func DefaultTrendRange(g Granularity, now time.Time, weekStart time.Weekday) Range {
+	end := g.next(g.start(now, weekStart))
+	switch g {
+	case GranularityDay:
+		return Range{From: end.AddDate(0, 0, -30), To: end}
+	case GranularityWeek:
+		return Range{From: end.AddDate(0, 0, -7*12), To: end}
+	case GranularityQuarter:
+		return Range{From: end.AddDate(0, -3*8, 0), To: end}
+	case GranularityYear:
+		return Range{From: end.AddDate(-5, 0, 0), To: end}
+	default:
+		return Range{From: end.AddDate(0, -12, 0), To: end}
+	}
+}
+
+// Bucket is one point of a trend, covering [Start, End).
+type Bucket struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Buckets splits period into buckets of granularity g in loc, with weeks
+// starting on weekStart. The first and last buckets are widened to whole
+// periods, so a trend by month from the 15th still starts on the 1st.
+func Buckets(g Granularity, period Range, loc *time.Location, weekStart time.Weekday) ([]Bucket, error) {
+	if !period.From.Before(period.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidRange)
+	}
+
+	var buckets []Bucket
+	for start := g.start(period.From.In(loc), weekStart); start.Before(period.To); start = g.next(start) {
+		if len(buckets) == MaxBuckets {
+			return nil, fmt.Errorf("%w: more than %d %ss", ErrInvalidRange, MaxBuckets, g)
+		}
+		buckets = append(buckets, Bucket{Start: start, End: g.next(start)})
+	}
+	return buckets, nil
+}
+
+// TrendQuery describes a spending trend. Buckets follow the calendar of
+// Location, with weeks starting on WeekStart; CategoryIDs, when set, limits
+// the trend to those categories.
+type TrendQuery struct {
+	Granularity Granularity
+	Range       Range
+	CategoryIDs []uint
+	Location    *time.Location
+	WeekStart   time.Weekday
+}
+
+// BucketTotal is the spending in the bucket at Index.
+type BucketTotal struct {
+	Index int `gorm:"column:bucket_index"`
+	Total money.Amount
+	Count int64
+}
+
+// TrendPoint is the net spending between Start and End.
+type TrendPoint struct {
+	Start time.Time    `json:"start"`
+	End   time.Time    `json:"end"`
+	Total money.Amount `json:"total" swaggertype:"number"`
+	Count int64        `json:"count"`
+}
+
+// Trend returns the net spending in each bucket of the query, including
+// buckets without transactions.
+func (s *ReportService) Trend(username string, query TrendQuery) ([]TrendPoint, error) {
+	if query.Location == nil {
+		query.Location = time.Local
+	}
+	buckets, err := Buckets(query.Granularity, query.Range, query.Location, query.WeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.Repo.TotalsByBucket(user.ID, buckets, query.CategoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]TrendPoint, len(buckets))
+	for i, bucket := range buckets {
+		points[i] = TrendPoint{Start: bucket.Start, End: bucket.End}
+	}
+	for _, total := range totals {
+		if total.Index >= 0 && total.Index < len(points) {
+			points[total.Index].Total = total.Total
+			points[total.Index].Count = total.Count
+		}
+	}
+	return points, nil
+}
@@ -3,13 +3,27 @@ package routes
 import (
 	"github.com/gorilla/mux"
 
+	"github.com/shaikhjunaidx/pennywise-backend/internal/account"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/budget"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/category"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/export"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/fx"
+	accountHandlers "github.com/shaikhjunaidx/pennywise-backend/internal/handlers/account"
 	budgetHandlers "github.com/shaikhjunaidx/pennywise-backend/internal/handlers/budget"
 	categoryHandlers "github.com/shaikhjunaidx/pennywise-backend/internal/handlers/category"
+	exportHandlers "github.com/shaikhjunaidx/pennywise-backend/internal/handlers/export"
+	importHandlers "github.com/shaikhjunaidx/pennywise-backend/internal/handlers/importer"
+	insightHandlers "github.com/shaikhjunaidx/pennywise-backend/internal/handlers/insights"
+	recurringHandlers "github.com/shaikhjunaidx/pennywise-backend/internal/handlers/recurring"
+	reportHandlers "github.com/shaikhjunaidx/pennywise-backend/internal/handlers/reports"
 	transactionHandlers "github.com/shaikhjunaidx/pennywise-backend/internal/handlers/transaction"
 	userHandlers "github.com/shaikhjunaidx/pennywise-backend/internal/handlers/user"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/importer"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/insights"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/middleware"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/notify"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/recurring"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/reports"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/user"
 	httpSwagger "github.com/swaggo/http-swagger"
@@ -25,13 +39,22 @@ func initRepositories(db *gorm.DB) (user.UserRepository, category.CategoryReposi
 func initServices(db *gorm.DB) (*user.UserService, *category.CategoryService, *budget.BudgetService, *transaction.TransactionService) {
 	userRepo, categoryRepo, budgetRepo, transactionRepo := initRepositories(db)
 
-	userService := &user.UserService{Repo: userRepo}
+	notifier := notify.NewNotifierFromEnv()
+
+	userService := &user.UserService{Repo: userRepo, ResetTokens: user.NewGormResetTokenStore(db), Notifier: notifier, Settings: user.NewSettingsRepository(db)}
 	categoryService := category.NewCategoryService(categoryRepo, userService)
 	budgetService := budget.NewBudgetService(budgetRepo, userService)
-	transactionService := transaction.NewTransactionService(transactionRepo, userRepo, categoryRepo, budgetService)
+	categoryService.UnitOfWork = category.NewUnitOfWork(db, budgetService)
+	transactionService := transaction.NewTransactionService(transactionRepo, userRepo, categoryRepo, budgetService, transaction.NewUnitOfWork(db))
+	transactionService.Notifier = notifier
+	transactionService.Settings = userService
+	transactionService.Converter = fx.NewConverter(fx.NewRateRepository(db))
+	transactionService.Accounts = account.NewAccountRepository(db)
 
 	userService.CategoryService = categoryService
 	userService.BudgetService = budgetService
+	userService.CurrencyChanges = transactionService
+	userService.TimeZoneChanges = budgetService
 
 	return userService, categoryService, budgetService, transactionService
 }
@@ -42,6 +65,14 @@ func SetupUserRoutes(router *mux.Router, db *gorm.DB) {
 	router.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
 	router.HandleFunc("/api/signup", userHandlers.SignUpHandler(userService)).Methods("POST")
 	router.HandleFunc("/api/login", userHandlers.LoginHandler(userService)).Methods("POST")
+	router.HandleFunc("/api/password-reset/request", userHandlers.RequestPasswordResetHandler(userService)).Methods("POST")
+	router.HandleFunc("/api/password-reset/confirm", userHandlers.ConfirmPasswordResetHandler(userService)).Methods("POST")
+
+	settingsRouter := router.PathPrefix("/api/settings").Subrouter()
+	settingsRouter.Use(middleware.JWTMiddleware)
+
+	settingsRouter.HandleFunc("", userHandlers.GetSettingsHandler(userService)).Methods("GET")
+	settingsRouter.HandleFunc("", userHandlers.UpdateSettingsHandler(userService)).Methods("PUT")
 }
 
 func SetupTransactionRoutes(router *mux.Router, db *gorm.DB) {
@@ -56,8 +87,83 @@ func SetupTransactionRoutes(router *mux.Router, db *gorm.DB) {
 	transactionRouter.HandleFunc("/{id:[0-9]+}", transactionHandlers.GetTransactionByIDHandler(transactionService)).Methods("GET")
 	transactionRouter.HandleFunc("", transactionHandlers.GetTransactionsHandler(transactionService)).Methods("GET")
 	transactionRouter.HandleFunc("/category/{category_id:[0-9]+}", transactionHandlers.GetTransactionsByCategoryHandler(transactionService)).Methods("GET")
+	transactionRouter.HandleFunc("/search", transactionHandlers.SearchTransactionsHandler(transactionService)).Methods("GET")
 	transactionRouter.HandleFunc("/weekly", transactionHandlers.GetWeeklySpendingHandler(transactionService)).Methods("GET")
+	transactionRouter.HandleFunc("/duplicates", transactionHandlers.GetDuplicatesHandler(transactionService)).Methods("GET")
+	transactionRouter.HandleFunc("/{id:[0-9]+}/dismiss-duplicate", transactionHandlers.DismissDuplicateHandler(transactionService)).Methods("POST")
+
+}
+
+func SetupTransferRoutes(router *mux.Router, db *gorm.DB) {
+	_, _, _, transactionService := initServices(db)
+
+	transferRouter := router.PathPrefix("/api/transfers").Subrouter()
+	transferRouter.Use(middleware.JWTMiddleware)
+
+	transferRouter.HandleFunc("", transactionHandlers.CreateTransferHandler(transactionService)).Methods("POST")
+	transferRouter.HandleFunc("/{id:[0-9]+}", transactionHandlers.GetTransferHandler(transactionService)).Methods("GET")
+	transferRouter.HandleFunc("/{id:[0-9]+}", transactionHandlers.UpdateTransferHandler(transactionService)).Methods("PUT")
+	transferRouter.HandleFunc("/{id:[0-9]+}", transactionHandlers.DeleteTransferHandler(transactionService)).Methods("DELETE")
+}
+
+func SetupImportRoutes(router *mux.Router, db *gorm.DB) {
+	_, _, _, transactionService := initServices(db)
+	importService := importer.NewImportService(importer.NewMappingRepository(db), transactionService.UserRepo, transactionService.CategoryRepo, transactionService)
+
+	importRouter := router.PathPrefix("/api/imports").Subrouter()
+	importRouter.Use(middleware.JWTMiddleware)
+
+	importRouter.HandleFunc("/preview", importHandlers.PreviewImportHandler(importService)).Methods("POST")
+	importRouter.HandleFunc("/commit", importHandlers.CommitImportHandler(importService)).Methods("POST")
+	importRouter.HandleFunc("/mappings", importHandlers.GetMappingsHandler(importService)).Methods("GET")
+	importRouter.HandleFunc("/mappings", importHandlers.CreateMappingHandler(importService)).Methods("POST")
+	importRouter.HandleFunc("/mappings/{id:[0-9]+}", importHandlers.UpdateMappingHandler(importService)).Methods("PUT")
+	importRouter.HandleFunc("/mappings/{id:[0-9]+}", importHandlers.DeleteMappingHandler(importService)).Methods("DELETE")
+}
+
+func SetupExportRoutes(router *mux.Router, db *gorm.DB) {
+	userService, _, budgetService, transactionService := initServices(db)
+	exportService := export.NewExportService(transactionService.UserRepo, transactionService.CategoryRepo, transactionService.Repo, budgetService.Repo)
+	exportService.Settings = userService
+
+	exportRouter := router.PathPrefix("/api/export").Subrouter()
+	exportRouter.Use(middleware.JWTMiddleware)
+
+	exportRouter.HandleFunc("", exportHandlers.ExportHandler(exportService)).Methods("GET")
+}
 
+func SetupReportRoutes(router *mux.Router, db *gorm.DB) {
+	userService, _, _, _ := initServices(db)
+	reportService := reports.NewReportService(reports.NewReportRepository(db), userService.Repo)
+	reportService.Settings = userService
+
+	reportRouter := router.PathPrefix("/api/reports").Subrouter()
+	reportRouter.Use(middleware.JWTMiddleware)
+
+	reportRouter.HandleFunc("/categories", reportHandlers.GetCategorySpendingHandler(reportService)).Methods("GET")
+	reportRouter.HandleFunc("/monthly", reportHandlers.GetMonthlySummaryHandler(reportService)).Methods("GET")
+	reportRouter.HandleFunc("/cash-flow", reportHandlers.GetCashFlowHandler(reportService)).Methods("GET")
+	reportRouter.HandleFunc("/top-categories", reportHandlers.GetTopCategoriesHandler(reportService)).Methods("GET")
+	reportRouter.HandleFunc("/merchants", reportHandlers.GetTopMerchantsHandler(reportService)).Methods("GET")
+	reportRouter.HandleFunc("/trends", reportHandlers.GetTrendHandler(reportService)).Methods("GET")
+}
+
+func SetupAccountRoutes(router *mux.Router, db *gorm.DB) {
+	userService, _, _, _ := initServices(db)
+	accountService := account.NewAccountService(account.NewAccountRepository(db), userService.Repo)
+	accountService.Settings = userService
+
+	accountRouter := router.PathPrefix("/api/accounts").Subrouter()
+	accountRouter.Use(middleware.JWTMiddleware)
+
+	accountRouter.HandleFunc("", accountHandlers.GetAccountsHandler(accountService)).Methods("GET")
+	accountRouter.HandleFunc("", accountHandlers.CreateAccountHandler(accountService)).Methods("POST")
+	accountRouter.HandleFunc("/{id:[0-9]+}", accountHandlers.GetAccountByIDHandler(accountService)).Methods("GET")
+	accountRouter.HandleFunc("/{id:[0-9]+}", accountHandlers.UpdateAccountHandler(accountService)).Methods("PUT")
+	accountRouter.HandleFunc("/{id:[0-9]+}", accountHandlers.DeleteAccountHandler(accountService)).Methods("DELETE")
+	accountRouter.HandleFunc("/{id:[0-9]+}/register", accountHandlers.GetAccountRegisterHandler(accountService)).Methods("GET")
+	accountRouter.HandleFunc("/{id:[0-9]+}/reconcile", accountHandlers.ReconcileAccountHandler(accountService)).Methods("POST")
+	accountRouter.HandleFunc("/{id:[0-9]+}/reconciliations", accountHandlers.GetReconciliationsHandler(accountService)).Methods("GET")
 }
 
 func SetupCategoryRoutes(router *mux.Router, db *gorm.DB) {
@@ -84,7 +190,53 @@ func SetupBudgetRoutes(router *mux.Router, db *gorm.DB) {
 	budgetRouter.HandleFunc("", budgetHandlers.GetBudgetsForUserHandler(budgetService)).Methods("GET")
 	budgetRouter.HandleFunc("/{id:[0-9]+}", budgetHandlers.UpdateBudgetHandler(budgetService)).Methods("PUT")
 	budgetRouter.HandleFunc("/{id:[0-9]+}", budgetHandlers.DeleteBudgetHandler(budgetService)).Methods("DELETE")
+	budgetRouter.HandleFunc("/{id:[0-9]+}/rollover", budgetHandlers.UpdateBudgetRolloverHandler(budgetService)).Methods("PUT")
 	budgetRouter.HandleFunc("/overall", budgetHandlers.GetOverallBudgetHandler(budgetService)).Methods("GET")
+	budgetRouter.HandleFunc("/reconcile", budgetHandlers.ReconcileBudgetsHandler(budgetService)).Methods("POST")
 	budgetRouter.HandleFunc("/category/{category_id:[0-9]+}", budgetHandlers.GetBudgetForUserAndCategoryHandler(budgetService)).Methods("GET")
 	budgetRouter.HandleFunc("/category/{category_id:[0-9]+}/history", budgetHandlers.GetBudgetHistoryByCategoryHandler(budgetService)).Methods("GET")
 }
+
+// NewBudgetService builds the budget service with access to users' settings,
+// for jobs that run outside a request such as the rollover.
+func NewBudgetService(db *gorm.DB) *budget.BudgetService {
+	_, _, budgetService, _ := initServices(db)
+	return budgetService
+}
+
+// NewRecurringService builds the recurring service on top of the transaction
+// service, for the routes and for the scheduler.
+func NewRecurringService(db *gorm.DB) *recurring.RecurringService {
+	userService, _, _, transactionService := initServices(db)
+	recurringService := recurring.NewRecurringService(recurring.NewRecurringRepository(db), userService.Repo, transactionService)
+	recurringService.Settings = userService
+	return recurringService
+}
+
+func SetupRecurringRoutes(router *mux.Router, db *gorm.DB) {
+	recurringService := NewRecurringService(db)
+
+	recurringRouter := router.PathPrefix("/api/recurring").Subrouter()
+	recurringRouter.Use(middleware.JWTMiddleware)
+
+	recurringRouter.HandleFunc("", recurringHandlers.GetRecurringRulesHandler(recurringService)).Methods("GET")
+	recurringRouter.HandleFunc("", recurringHandlers.CreateRecurringRuleHandler(recurringService)).Methods("POST")
+	recurringRouter.HandleFunc("/upcoming", recurringHandlers.GetUpcomingOccurrencesHandler(recurringService)).Methods("GET")
+	recurringRouter.HandleFunc("/{id:[0-9]+}", recurringHandlers.GetRecurringRuleByIDHandler(recurringService)).Methods("GET")
+	recurringRouter.HandleFunc("/{id:[0-9]+}", recurringHandlers.UpdateRecurringRuleHandler(recurringService)).Methods("PUT")
+	recurringRouter.HandleFunc("/{id:[0-9]+}", recurringHandlers.DeleteRecurringRuleHandler(recurringService)).Methods("DELETE")
+	recurringRouter.HandleFunc("/{id:[0-9]+}/occurrences/{date}", recurringHandlers.UpdateOccurrenceHandler(recurringService)).Methods("PUT")
+	recurringRouter.HandleFunc("/{id:[0-9]+}/occurrences/{date}", recurringHandlers.ResetOccurrenceHandler(recurringService)).Methods("DELETE")
+}
+
+func SetupInsightRoutes(router *mux.Router, db *gorm.DB) {
+	recurringService := NewRecurringService(db)
+	insightService := insights.NewInsightService(insights.NewInsightRepository(db), recurringService.UserRepo, recurringService)
+	insightService.Settings = recurringService.Settings
+
+	insightRouter := router.PathPrefix("/api/insights").Subrouter()
+	insightRouter.Use(middleware.JWTMiddleware)
+
+	insightRouter.HandleFunc("/subscriptions", insightHandlers.GetSubscriptionsHandler(insightService)).Methods("GET")
+	insightRouter.HandleFunc("/subscriptions/convert", insightHandlers.ConvertSubscriptionHandler(insightService)).Methods("POST")
+}
@@ -0,0 +1,62 @@
+// Package scheduler runs background jobs on a fixed interval inside the API
+// process. Jobs must be idempotent: the same job may run again after a
+// restart, or from a cron-driven command, for a period it already handled.
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+type Job interface {
+	Name() string
+	Run(ctx context.Context, now time.Time) error
+}
+
+type Scheduler struct {
+	Interval time.Duration
+	Jobs     []Job
+	Now      func() time.Time
+}
+
+func New(interval time.Duration, jobs ...Job) *Scheduler {
+	return &Scheduler{
+		Interval: interval,
+		Jobs:     jobs,
+		Now:      time.Now,
+	}
+}
+
+// Start runs every job once straight away and then on each tick until ctx is
+// cancelled. It returns immediately.
+func (s *Scheduler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.Interval)
+		defer ticker.Stop()
+
+		s.RunOnce(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.RunOnce(ctx)
+			}
+		}
+	}()
+}
+
+// RunOnce runs each job in turn. A failing job is logged and does not stop
+// the others.
+func (s *Scheduler) RunOnce(ctx context.Context) {
+	now := s.Now()
+	for _, job := range s.Jobs {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := job.Run(ctx, now); err != nil {
+			log.Printf("Scheduled job %s failed: %v", job.Name(), err)
+		}
+	}
+}
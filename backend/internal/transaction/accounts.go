@@ -0,0 +1,105 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+)
+
+var ErrInvalidAccount = errors.New("account not found or does not belong to the user")
+
+// ErrReconciled is returned for changes to transactions that an account has
+// already been reconciled through.
+var ErrReconciled = errors.New("transaction is in a reconciled statement period")
+
+// AccountFinder looks up accounts, typically an account.AccountRepository.
+type AccountFinder interface {
+	FindByID(id uint) (*models.Account, error)
+}
+
+// resolveAccount returns the account to book a transaction against, or nil
+// for a zero ID. The account must be one of the user's own.
+func (s *TransactionService) resolveAccount(user *models.User, accountID uint) (*models.Account, error) {
+	if accountID == 0 {
+		return nil, nil
+	}
+	if s.Accounts == nil {
+		return nil, ErrInvalidAccount
+	}
+
+	account, err := s.Accounts.FindByID(accountID)
+	if err != nil || account.UserID != user.ID {
+		return nil, ErrInvalidAccount
+	}
+	return account, nil
+}
+
+// accountCurrency returns the currency of a transaction booked to account.
+// It defaults to the account's currency and must match it, since account
+// balances are kept in a single currency.
+func accountCurrency(account *models.Account, currency money.Currency) (money.Currency, error) {
+	if account == nil {
+		return currencyOrDefault(currency), nil
+	}
+	if currency == "" {
+		return account.Currency, nil
+	}
+	if currency != account.Currency {
+		return "", fmt.Errorf("%w: %s transactions cannot be booked to a %s account", ErrInvalidAccount, currency, account.Currency)
+	}
+	return currency, nil
+}
+
+// accountIDOf returns the account ID to store on a transaction.
+func accountIDOf(account *models.Account) *uint {
+	if account == nil {
+		return nil
+	}
+	id := account.ID
+	return &id
+}
+
+// checkUnreconciled refuses new transactions, and changes to transactions,
+// booked to an account on or before the day it is reconciled through, which
+// is a day in loc, the user's time zone. Such a change would alter a balance
+// that has already been matched against a statement.
+func (s *TransactionService) checkUnreconciled(loc *time.Location, transactions ...*models.Transaction) error {
+	_, err := s.firstReconciled(loc, transactions)
+	return err
+}
+
+// firstReconciled returns the index of the first of transactions that
+// checkUnreconciled refuses, and why. Each account is looked up once.
+func (s *TransactionService) firstReconciled(loc *time.Location, transactions []*models.Transaction) (int, error) {
+	if s.Accounts == nil {
+		return -1, nil
+	}
+
+	accounts := make(map[uint]*models.Account)
+	for i, transaction := range transactions {
+		if transaction.AccountID == nil {
+			continue
+		}
+		account, ok := accounts[*transaction.AccountID]
+		if !ok {
+			var err error
+			if account, err = s.Accounts.FindByID(*transaction.AccountID); err != nil {
+				return i, err
+			}
+			accounts[account.ID] = account
+		}
+		if account.ReconciledThrough == nil {
+			continue
+		}
+
+		through := account.ReconciledThrough.In(loc)
+		end := time.Date(through.Year(), through.Month(), through.Day()+1, 0, 0, 0, 0, loc)
+		if transaction.TransactionDate.Before(end) {
+			return i, fmt.Errorf("%w: %s is reconciled through %s", ErrReconciled, account.Name, through.Format("2006-01-02"))
+		}
+	}
+	return -1, nil
+}
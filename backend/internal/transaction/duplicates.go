@@ -0,0 +1,256 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/user"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+)
+
+var ErrNotDuplicate = errors.New("transaction is not flagged as a duplicate")
+
+// DuplicatePolicy decides when a transaction probably repeats one that is
+// already stored, and what imports do about it. WindowDays counts calendar
+// days in Location, the user's time zone; a nil Location means the server's.
+type DuplicatePolicy struct {
+	WindowDays        int
+	IgnoreDescription bool
+	Action            string
+	Location          *time.Location
+}
+
+// DefaultDuplicatePolicy is used when no settings are available.
+var DefaultDuplicatePolicy = DuplicatePolicy{
+	WindowDays: user.DefaultDuplicateWindowDays,
+	Action:     user.DuplicateActionFlag,
+}
+
+func policyFromSettings(settings *models.UserSettings) DuplicatePolicy {
+	policy := DuplicatePolicy{
+		WindowDays:        settings.DuplicateWindowDays,
+		IgnoreDescription: settings.DuplicateIgnoreDescription,
+		Action:            settings.DuplicateAction,
+		Location:          user.Location(settings),
+	}
+	if policy.Action == "" {
+		policy.Action = user.DuplicateActionFlag
+	}
+	return policy
+}
+
+// DuplicatePair is a transaction flagged as a likely duplicate together with
+// the transaction it appears to repeat.
+type DuplicatePair struct {
+	Transaction *models.Transaction `json:"transaction"`
+	DuplicateOf *models.Transaction `json:"duplicate_of"`
+}
+
+// NormalizeDescription reduces a description to lower-case words, dropping
+// digits and punctuation so that card numbers, reference codes and spacing
+// differences between statements do not prevent a match.
+func NormalizeDescription(description string) string {
+	words := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// Fingerprint identifies what a transaction was for, leaving out when it
+// happened: its type, amount, currency and, unless ignored, normalised
+// description. A refund never matches the purchase it refunds.
+func (p DuplicatePolicy) Fingerprint(transaction *models.Transaction) string {
+	fingerprint := fmt.Sprintf("%s|%d|%s", typeOrDefault(transaction.Type), transaction.Amount.Minor(), transaction.Currency)
+	if !p.IgnoreDescription {
+		fingerprint += "|" + NormalizeDescription(transaction.Description)
+	}
+	return fingerprint
+}
+
+// Matches reports whether candidate is probably the same transaction as t.
+// Bank identifiers decide when both sides have one; otherwise the
+// fingerprints must agree and the dates lie within the window.
+func (p DuplicatePolicy) Matches(t, candidate *models.Transaction) bool {
+	if t.ExternalID != "" && candidate.ExternalID != "" {
+		return t.ExternalID == candidate.ExternalID
+	}
+
+	return daysApart(t.TransactionDate, candidate.TransactionDate, p.location()) <= p.WindowDays &&
+		p.Fingerprint(t) == p.Fingerprint(candidate)
+}
+
+// FindMatch returns the first candidate that t duplicates, or nil.
+func (p DuplicatePolicy) FindMatch(t *models.Transaction, candidates []*models.Transaction) *models.Transaction {
+	for _, candidate := range candidates {
+		if candidate.ID != t.ID && p.Matches(t, candidate) {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// window returns the range of dates that candidates for transactions must
+// fall in.
+func (p DuplicatePolicy) window(transactions []*models.Transaction) (from, to time.Time) {
+	for i, transaction := range transactions {
+		if i == 0 || transaction.TransactionDate.Before(from) {
+			from = transaction.TransactionDate
+		}
+		if i == 0 || transaction.TransactionDate.After(to) {
+			to = transaction.TransactionDate
+		}
+	}
+	return from.AddDate(0, 0, -p.WindowDays-1), to.AddDate(0, 0, p.WindowDays+1)
+}
+
+func (p DuplicatePolicy) location() *time.Location {
+	if p.Location == nil {
+		return time.Local
+	}
+	return p.Location
+}
+
+// daysApart counts calendar days between two dates in loc.
+func daysApart(a, b time.Time, loc *time.Location) int {
+	a, b = a.In(loc), b.In(loc)
+	dayA := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	dayB := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+
+	days := int(dayA.Sub(dayB).Hours() / 24)
+	if days < 0 {
+		days = -days
+	}
+	return days
+}
+
+// duplicatePolicy returns the user's duplicate match policy.
+func (s *TransactionService) duplicatePolicy(userID uint) (DuplicatePolicy, error) {
+	if s.Settings == nil {
+		return DefaultDuplicatePolicy, nil
+	}
+
+	settings, err := s.Settings.SettingsForUserID(userID)
+	if err != nil {
+		return DuplicatePolicy{}, err
+	}
+	return policyFromSettings(settings), nil
+}
+
+// duplicateCandidates loads the stored transactions that any of transactions
+// could duplicate.
+func duplicateCandidates(repo TransactionRepository, userID uint, policy DuplicatePolicy, transactions []*models.Transaction) ([]*models.Transaction, error) {
+	if len(transactions) == 0 {
+		return nil, nil
+	}
+
+	var externalIDs []string
+	for _, transaction := range transactions {
+		if transaction.ExternalID != "" {
+			externalIDs = append(externalIDs, transaction.ExternalID)
+		}
+	}
+
+	from, to := policy.window(transactions)
+	return repo.FindDuplicateCandidates(userID, from, to, externalIDs)
+}
+
+// FindDuplicates checks inputs against the user's stored transactions and
+// returns, for each input, the transaction it probably repeats or nil. It
+// stores nothing, which makes it suitable for import previews.
+func (s *TransactionService) FindDuplicates(username string, inputs []TransactionInput) ([]*models.Transaction, error) {
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := s.duplicatePolicy(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := make([]*models.Transaction, len(inputs))
+	for i, input := range inputs {
+		kind, _ := ParseType(input.Type)
+		transactions[i] = &models.Transaction{
+			Type:            kind,
+			Amount:          input.Amount,
+			Currency:        currencyOrDefault(input.Currency),
+			Description:     input.Description,
+			TransactionDate: input.TransactionDate,
+			ExternalID:      input.ExternalID,
+		}
+	}
+
+	candidates, err := duplicateCandidates(s.Repo, user.ID, policy, transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]*models.Transaction, len(transactions))
+	for i, transaction := range transactions {
+		matches[i] = policy.FindMatch(transaction, candidates)
+	}
+	return matches, nil
+}
+
+// GetDuplicatePairs lists the user's transactions flagged as likely
+// duplicates, each with the transaction it appears to repeat.
+func (s *TransactionService) GetDuplicatePairs(username string) ([]DuplicatePair, error) {
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	flagged, err := s.Repo.FindFlaggedDuplicates(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(flagged) == 0 {
+		return []DuplicatePair{}, nil
+	}
+
+	ids := make([]uint, 0, len(flagged))
+	for _, transaction := range flagged {
+		ids = append(ids, *transaction.DuplicateOfID)
+	}
+	originals, err := s.Repo.FindAllByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]*models.Transaction, len(originals))
+	for _, original := range originals {
+		byID[original.ID] = original
+	}
+
+	pairs := make([]DuplicatePair, 0, len(flagged))
+	for _, transaction := range flagged {
+		if original, ok := byID[*transaction.DuplicateOfID]; ok {
+			pairs = append(pairs, DuplicatePair{Transaction: transaction, DuplicateOf: original})
+		}
+	}
+	return pairs, nil
+}
+
+// DismissDuplicate clears the duplicate flag after the user has confirmed
+// that the transaction is genuine.
+func (s *TransactionService) DismissDuplicate(username string, id uint) (*models.Transaction, error) {
+	_, transaction, err := s.findOwnedTransaction(username, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.DuplicateOfID == nil {
+		return nil, ErrNotDuplicate
+	}
+
+	if err := s.Repo.ClearDuplicateFlag(transaction.ID); err != nil {
+		return nil, err
+	}
+	transaction.DuplicateOfID = nil
+
+	return transaction, nil
+}
@@ -0,0 +1,221 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/user"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+)
+
+var ErrNothingToImport = errors.New("no transactions to import")
+
+// ImportResult summarises a bulk import. Skipped counts likely duplicates
+// left out and Flagged those stored but marked for review.
+type ImportResult struct {
+	Imported       int `json:"imported"`
+	Skipped        int `json:"skipped"`
+	Flagged        int `json:"flagged"`
+	BudgetsUpdated int `json:"budgets_updated"`
+}
+
+// RowError reports which input of a bulk import was rejected.
+type RowError struct {
+	Index int
+	Err   error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Index+1, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// budgetKey identifies the category budget a transaction is booked against.
+type budgetKey struct {
+	categoryID uint
+	month      string
+	year       int
+}
+
+// categoryKey caches resolved categories during an import. A zero ID stands
+// for the default category, which differs for income.
+type categoryKey struct {
+	id     uint
+	income bool
+}
+
+// ImportTransactions stores many transactions at once. Every input is
+// validated before anything is written, the rows are inserted in one unit of
+// work, and each affected category budget is rebuilt once rather than once
+// per row. Rows that repeat stored transactions are flagged or skipped as the
+// user's duplicate policy says; rows are not compared with each other, since
+// one statement may well list two identical purchases.
+func (s *TransactionService) ImportTransactions(username string, inputs []TransactionInput) (*ImportResult, error) {
+	if len(inputs) == 0 {
+		return nil, ErrNothingToImport
+	}
+
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make(map[categoryKey]*models.Category)
+	categoryNames := make(map[uint]string)
+	accounts := make(map[uint]*models.Account)
+	transactions := make([]*models.Transaction, 0, len(inputs))
+	for i, input := range inputs {
+		kind, err := ParseType(input.Type)
+		if err != nil {
+			return nil, &RowError{Index: i, Err: err}
+		}
+
+		key := categoryKey{id: input.CategoryID, income: input.CategoryID == 0 && kind == models.TransactionTypeIncome}
+		category, ok := categories[key]
+		if !ok {
+			category, err = s.resolveCategory(user, input.CategoryID, kind)
+			if err != nil {
+				return nil, &RowError{Index: i, Err: err}
+			}
+			categories[key] = category
+			categoryNames[category.ID] = category.Name
+		}
+		if err := checkAmountAndCategory(kind, input.Amount, category); err != nil {
+			return nil, &RowError{Index: i, Err: err}
+		}
+
+		account, ok := accounts[input.AccountID]
+		if !ok {
+			account, err = s.resolveAccount(user, input.AccountID)
+			if err != nil {
+				return nil, &RowError{Index: i, Err: err}
+			}
+			accounts[input.AccountID] = account
+		}
+		currency, err := accountCurrency(account, input.Currency)
+		if err != nil {
+			return nil, &RowError{Index: i, Err: err}
+		}
+		if err := checkMinorUnits(input.Amount, nil, currency); err != nil {
+			return nil, &RowError{Index: i, Err: err}
+		}
+
+		transaction := &models.Transaction{
+			UserID:          user.ID,
+			CategoryID:      category.ID,
+			AccountID:       accountIDOf(account),
+			Type:            kind,
+			Amount:          input.Amount,
+			Currency:        currency,
+			Description:     input.Description,
+			Notes:           input.Notes,
+			TransactionDate: input.TransactionDate,
+			ExternalID:      input.ExternalID,
+		}
+		if err := s.applyBaseAmount(user.ID, transaction); err != nil {
+			return nil, &RowError{Index: i, Err: err}
+		}
+
+		transactions = append(transactions, transaction)
+	}
+
+	policy, err := s.duplicatePolicy(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := s.location(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{}
+	var keys []budgetKey
+	added := make(map[budgetKey]money.Amount)
+	var updatedBudgets []*models.Budget
+	err = s.UnitOfWork.Do(func(repos Repositories) error {
+		if i, err := s.firstReconciled(loc, transactions); err != nil {
+			return &RowError{Index: i, Err: err}
+		}
+
+		transactions, err = s.applyDuplicatePolicy(repos.Transactions, user.ID, policy, inputs, transactions, result)
+		if err != nil || len(transactions) == 0 {
+			return err
+		}
+
+		if err := repos.Transactions.CreateAll(transactions); err != nil {
+			return err
+		}
+
+		for _, transaction := range transactions {
+			if !affectsBudget(transaction.Type) {
+				continue
+			}
+			month, year := budgetPeriodOf(transaction.TransactionDate, loc)
+			key := budgetKey{categoryID: transaction.CategoryID, month: month, year: year}
+			if _, seen := added[key]; !seen {
+				keys = append(keys, key)
+			}
+			if transaction.Type == models.TransactionTypeRefund {
+				added[key] -= transaction.BaseAmount
+			} else {
+				added[key] += transaction.BaseAmount
+			}
+		}
+
+		budgets := s.BudgetService.WithRepo(repos.Budgets)
+		updatedBudgets = make([]*models.Budget, len(keys))
+		for i, key := range keys {
+			updatedBudgets[i], err = budgets.RecalculateBudget(user.ID, &key.categoryID, key.month, key.year)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, key := range keys {
+		s.notifyIfOverBudget(user, categoryNames[key.categoryID], added[key], updatedBudgets[i])
+	}
+
+	result.Imported = len(transactions)
+	result.BudgetsUpdated = len(keys)
+	return result, nil
+}
+
+// applyDuplicatePolicy flags or drops the transactions that repeat stored
+// ones and returns those left to insert, counting both in result.
+func (s *TransactionService) applyDuplicatePolicy(repo TransactionRepository, userID uint, policy DuplicatePolicy,
+	inputs []TransactionInput, transactions []*models.Transaction, result *ImportResult) ([]*models.Transaction, error) {
+	if policy.Action == user.DuplicateActionAllow {
+		return transactions, nil
+	}
+
+	candidates, err := duplicateCandidates(repo, userID, policy, transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := transactions[:0]
+	for i, transaction := range transactions {
+		match := policy.FindMatch(transaction, candidates)
+		switch {
+		case match == nil || inputs[i].AllowDuplicate:
+		case policy.Action == user.DuplicateActionSkip:
+			result.Skipped++
+			continue
+		default:
+			transaction.DuplicateOfID = &match.ID
+			result.Flagged++
+		}
+		kept = append(kept, transaction)
+	}
+	return kept, nil
+}
@@ -0,0 +1,189 @@
+package transaction
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+var (
+	ErrInvalidQuery  = errors.New("invalid transaction query")
+	ErrInvalidCursor = errors.New("invalid or expired cursor")
+)
+
+type SortField string
+
+const (
+	SortByDate        SortField = "date"
+	SortByAmount      SortField = "amount"
+	SortByDescription SortField = "description"
+	SortByCreatedAt   SortField = "created_at"
+)
+
+// sortColumns maps each sort field to the column it orders by.
+var sortColumns = map[SortField]string{
+	SortByDate:        "transactions.transaction_date",
+	SortByAmount:      "transactions.base_amount",
+	SortByDescription: "transactions.description",
+	SortByCreatedAt:   "transactions.created_at",
+}
+
+// TransactionQuery filters and orders a listing of the user's transactions.
+// From and To bound the transaction date, inclusive; To includes the whole
+// day. AccountIDs and Types, when set, keep only transactions in those
+// accounts and of those types. Amounts are compared in the base currency.
+// Search matches a substring of the description. Cursor continues a previous page and must have been
+// issued for the same sort.
+type TransactionQuery struct {
+	From        time.Time
+	To          time.Time
+	CategoryIDs []uint
+	AccountIDs  []uint
+	Types       []string
+	MinAmount   *money.Amount
+	MaxAmount   *money.Amount
+	Search      string
+	Sort        SortField
+	Descending  bool
+	Cursor      string
+	Limit       int
+}
+
+// TransactionPage is one page of a listing. Total counts every transaction
+// matching the filters, not just those on the page. NextCursor is empty on
+// the last page.
+type TransactionPage struct {
+	Transactions []*TransactionResponse `json:"transactions"`
+	Total        int64                  `json:"total"`
+	NextCursor   string                 `json:"next_cursor,omitempty"`
+}
+
+// Normalize fills in the default sort (newest first) and page size, and
+// checks that the query is consistent.
+func (q *TransactionQuery) Normalize() error {
+	if q.Sort == "" {
+		q.Sort, q.Descending = SortByDate, true
+	}
+	if _, ok := sortColumns[q.Sort]; !ok {
+		return fmt.Errorf("%w: cannot sort by %q", ErrInvalidQuery, q.Sort)
+	}
+
+	switch {
+	case q.Limit == 0:
+		q.Limit = DefaultPageSize
+	case q.Limit < 0 || q.Limit > MaxPageSize:
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidQuery, MaxPageSize)
+	}
+
+	if !q.From.IsZero() && !q.To.IsZero() && q.To.Before(q.From) {
+		return fmt.Errorf("%w: to is before from", ErrInvalidQuery)
+	}
+	for i, name := range q.Types {
+		kind, err := parseStoredType(name)
+		if err != nil || name == "" {
+			return fmt.Errorf("%w: unknown type %q", ErrInvalidQuery, name)
+		}
+		q.Types[i] = kind
+	}
+	if q.MinAmount != nil && q.MaxAmount != nil && *q.MaxAmount < *q.MinAmount {
+		return fmt.Errorf("%w: max_amount is below min_amount", ErrInvalidQuery)
+	}
+
+	if q.Cursor != "" {
+		if _, _, err := q.cursor(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SortColumn returns the column the query orders by.
+func (q *TransactionQuery) SortColumn() string {
+	return sortColumns[q.Sort]
+}
+
+// EncodeCursor returns the cursor for the page after last. It carries the
+// row's sort key as well as its ID, so the next page does not depend on the
+// row still existing or still sorting where it did.
+func (q *TransactionQuery) EncodeCursor(last *TransactionResponse) string {
+	var key string
+	switch q.Sort {
+	case SortByAmount:
+		key = strconv.FormatInt(last.BaseAmount.Minor(), 10)
+	case SortByDescription:
+		key = last.Description
+	case SortByCreatedAt:
+		key = last.CreatedAt
+	default:
+		key = last.TransactionDate
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s:%d:%s", q.Sort, q.direction(), last.ID, key)))
+}
+
+// CursorKey returns the sort key and ID of the last transaction of the
+// previous page.
+func (q *TransactionQuery) CursorKey() (interface{}, uint) {
+	key, id, _ := q.cursor()
+	return key, id
+}
+
+// cursorTimeLayouts are the formats a date key may have been scanned in.
+var cursorTimeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00", "2006-01-02 15:04:05", "2006-01-02"}
+
+func (q *TransactionQuery) cursor() (interface{}, uint, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+	if err != nil {
+		return nil, 0, ErrInvalidCursor
+	}
+
+	// The key comes last since a description may itself contain colons.
+	parts := strings.SplitN(string(decoded), ":", 4)
+	if len(parts) != 4 || SortField(parts[0]) != q.Sort || parts[1] != q.direction() {
+		return nil, 0, ErrInvalidCursor
+	}
+
+	id, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil || id == 0 {
+		return nil, 0, ErrInvalidCursor
+	}
+
+	switch q.Sort {
+	case SortByAmount:
+		minor, err := strconv.ParseInt(parts[3], 10, 64)
+		if err != nil {
+			return nil, 0, ErrInvalidCursor
+		}
+		return money.Amount(minor), uint(id), nil
+	case SortByDescription:
+		return parts[3], uint(id), nil
+	default:
+		for _, layout := range cursorTimeLayouts {
+			if at, err := time.Parse(layout, parts[3]); err == nil {
+				return at, uint(id), nil
+			}
+		}
+		return nil, 0, ErrInvalidCursor
+	}
+}
+
+func (q *TransactionQuery) direction() string {
+	if q.Descending {
+		return "desc"
+	}
+	return "asc"
+}
+
+// escapeLike escapes the LIKE wildcards in s so that it matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -1,13 +1,40 @@
 package transaction
 
-import "github.com/shaikhjunaidx/pennywise-backend/models"
+import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+)
 
 type TransactionRepository interface {
 	Create(transaction *models.Transaction) error
+	CreateAll(transactions []*models.Transaction) error
 	Update(transaction *models.Transaction) error
 	DeleteByID(id uint) error
 	FindByID(id uint) (*models.Transaction, error)
-	FindAllByUsername(username string) ([]*TransactionResponse, error)
+	FindByIDForUpdate(id uint) (*models.Transaction, error)
+	FindAllByUserID(userID uint) ([]*models.Transaction, error)
+	FindAllByIDs(ids []uint) ([]*models.Transaction, error)
+	// EachInRange calls fn with each of the user's transactions dated from
+	// from up to but not including before, oldest first and with their
+	// splits. A zero bound leaves that side open; categoryIDs, when set, keeps
+	// only transactions filed under or split into those categories.
+	EachInRange(userID uint, from, before time.Time, categoryIDs []uint, fn func(*models.Transaction) error) error
+	FindDuplicateCandidates(userID uint, from, to time.Time, externalIDs []string) ([]*models.Transaction, error)
+	FindFlaggedDuplicates(userID uint) ([]*models.Transaction, error)
+	ClearDuplicatesOf(id uint) error
+	ClearDuplicateFlag(id uint) error
+	UpdateBaseAmount(id uint, baseAmount money.Amount) error
+	UpdateSplitBaseAmount(id uint, baseAmount money.Amount) error
+	FindPage(userID uint, query TransactionQuery) (*TransactionPage, error)
+	Search(userID uint, terms []string, limit int) ([]*models.Transaction, error)
+	// SearchFragments returns up to limit of the user's transactions, newest
+	// first and leaving out exclude, whose description or notes contain at
+	// least one fragment of every group.
+	SearchFragments(userID uint, groups [][]string, exclude []uint, limit int) ([]*models.Transaction, error)
 	FindAllByUserIDAndCategoryID(userID uint, categoryID uint) ([]*TransactionResponse, error)
-	GetWeeklySpending(userID uint) ([]WeeklySpending, error)
+	// SumSpending totals the user's spending dated from start up to but not
+	// including end.
+	SumSpending(userID uint, start, end time.Time) (money.Amount, error)
 }
@@ -1,16 +1,24 @@
 package transaction
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/shaikhjunaidx/pennywise-backend/internal/budget"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
 	"github.com/shaikhjunaidx/pennywise-backend/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// WeeklySpending is the spending in the week beginning at Start, on the
+// user's calendar. Week numbers the weeks that start in Year from 1.
 type WeeklySpending struct {
-	Week       int     `json:"week"`
-	Year       int     `json:"year"`
-	TotalSpent float64 `json:"total_spent"`
+	Week       int          `json:"week"`
+	Year       int          `json:"year"`
+	Start      time.Time    `json:"start"`
+	TotalSpent money.Amount `json:"total_spent" swaggertype:"number"`
 }
 
 type TransactionRepositoryImpl struct {
@@ -26,7 +34,7 @@ func (r *TransactionRepositoryImpl) Create(transaction *models.Transaction) erro
 		return err
 	}
 
-	if err := r.DB.Preload("User").Preload("Category").
+	if err := r.DB.Preload("User").Preload("Category").Preload("Splits").
 		First(transaction, transaction.ID).Error; err != nil {
 		return err
 	}
@@ -34,12 +42,62 @@ func (r *TransactionRepositoryImpl) Create(transaction *models.Transaction) erro
 	return nil
 }
 
+func (r *TransactionRepositoryImpl) CreateAll(transactions []*models.Transaction) error {
+	return r.DB.CreateInBatches(transactions, 500).Error
+}
+
+// Update saves the transaction and brings its stored splits in line with
+// transaction.Splits, so a transaction saved without splits is no longer
+// split. A split keeps its ID when it is given one, or otherwise when a
+// stored split of the same category is left over; only the splits that are
+// gone are deleted.
 func (r *TransactionRepositoryImpl) Update(transaction *models.Transaction) error {
-	if err := r.DB.Save(transaction).Error; err != nil {
+	if err := r.DB.Omit("Splits").Save(transaction).Error; err != nil {
+		return err
+	}
+
+	var stored []models.TransactionSplit
+	if err := r.DB.Where("transaction_id = ?", transaction.ID).Order("id").Find(&stored).Error; err != nil {
 		return err
 	}
+	kept := make(map[uint]bool, len(stored))
+	for i := range transaction.Splits {
+		split := &transaction.Splits[i]
+		split.TransactionID = transaction.ID
+		if split.ID != 0 && (kept[split.ID] || !containsSplit(stored, split.ID)) {
+			split.ID = 0
+		}
+		if split.ID == 0 {
+			for _, old := range stored {
+				if !kept[old.ID] && old.CategoryID == split.CategoryID && !containsSplit(transaction.Splits, old.ID) {
+					split.ID = old.ID
+					break
+				}
+			}
+		}
+		if split.ID != 0 {
+			kept[split.ID] = true
+		}
+	}
 
-	if err := r.DB.Preload("User").Preload("Category").
+	var gone []uint
+	for _, old := range stored {
+		if !kept[old.ID] {
+			gone = append(gone, old.ID)
+		}
+	}
+	if len(gone) > 0 {
+		if err := r.DB.Delete(&models.TransactionSplit{}, gone).Error; err != nil {
+			return err
+		}
+	}
+	for i := range transaction.Splits {
+		if err := r.DB.Save(&transaction.Splits[i]).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := r.DB.Preload("User").Preload("Category").Preload("Splits").
 		First(transaction, transaction.ID).Error; err != nil {
 		return err
 	}
@@ -47,14 +105,27 @@ func (r *TransactionRepositoryImpl) Update(transaction *models.Transaction) erro
 	return nil
 }
 
+// containsSplit reports whether one of splits has the given ID.
+func containsSplit(splits []models.TransactionSplit, id uint) bool {
+	for _, split := range splits {
+		if split.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *TransactionRepositoryImpl) DeleteByID(id uint) error {
+	if err := r.DB.Where("transaction_id = ?", id).Delete(&models.TransactionSplit{}).Error; err != nil {
+		return err
+	}
 	return r.DB.Delete(&models.Transaction{}, id).Error
 }
 
 func (r *TransactionRepositoryImpl) FindByID(id uint) (*models.Transaction, error) {
 	var transaction models.Transaction
 
-	if err := r.DB.Preload("User").Preload("Category").
+	if err := r.DB.Preload("User").Preload("Category").Preload("Splits").
 		First(&transaction, id).Error; err != nil {
 		return nil, err
 	}
@@ -62,26 +133,260 @@ func (r *TransactionRepositoryImpl) FindByID(id uint) (*models.Transaction, erro
 	return &transaction, nil
 }
 
-func (r *TransactionRepositoryImpl) FindAllByUsername(username string) ([]*TransactionResponse, error) {
-	var transactions []*TransactionResponse
+// FindByIDForUpdate loads a transaction with its splits and locks its row
+// until the surrounding database transaction ends.
+func (r *TransactionRepositoryImpl) FindByIDForUpdate(id uint) (*models.Transaction, error) {
+	var transaction models.Transaction
 
-	err := r.DB.Table("transactions").
-		Select("transactions.id, transactions.user_id, transactions.category_id, categories.name as category_name, transactions.amount, transactions.description, transactions.transaction_date, transactions.created_at, transactions.updated_at").
-		Joins("JOIN users ON users.id = transactions.user_id").
+	if err := r.DB.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Splits").
+		First(&transaction, id).Error; err != nil {
+		return nil, err
+	}
+
+	return &transaction, nil
+}
+
+func (r *TransactionRepositoryImpl) FindAllByUserID(userID uint) ([]*models.Transaction, error) {
+	var transactions []*models.Transaction
+
+	if err := r.DB.Preload("Splits").Where("user_id = ?", userID).Order("id").Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// eachBatchSize is how many transactions EachInRange reads at a time.
+const eachBatchSize = 500
+
+// inCategoriesSQL keeps the transactions filed under, or split into, any of
+// the categories given twice as its parameters.
+const inCategoriesSQL = "(transactions.category_id IN ? OR transactions.id IN (SELECT transaction_id FROM transaction_splits WHERE category_id IN ?))"
+
+// EachInRange reads the range in batches keyed on date and ID, so that it is
+// never held in memory as a whole.
+func (r *TransactionRepositoryImpl) EachInRange(userID uint, from, before time.Time, categoryIDs []uint, fn func(*models.Transaction) error) error {
+	var last *models.Transaction
+	for {
+		query := r.DB.Preload("Splits").Where("user_id = ?", userID)
+		if !from.IsZero() {
+			query = query.Where("transaction_date >= ?", from)
+		}
+		if !before.IsZero() {
+			query = query.Where("transaction_date < ?", before)
+		}
+		if len(categoryIDs) > 0 {
+			query = query.Where(inCategoriesSQL, categoryIDs, categoryIDs)
+		}
+		if last != nil {
+			query = query.Where("(transaction_date, id) > (?, ?)", last.TransactionDate, last.ID)
+		}
+
+		var batch []*models.Transaction
+		if err := query.Order("transaction_date, id").Limit(eachBatchSize).Find(&batch).Error; err != nil {
+			return err
+		}
+		for _, t := range batch {
+			if err := fn(t); err != nil {
+				return err
+			}
+		}
+		if len(batch) < eachBatchSize {
+			return nil
+		}
+		last = batch[len(batch)-1]
+	}
+}
+
+func (r *TransactionRepositoryImpl) FindAllByIDs(ids []uint) ([]*models.Transaction, error) {
+	var transactions []*models.Transaction
+
+	if err := r.DB.Where("id IN ?", ids).Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// FindDuplicateCandidates returns the user's transactions dated within
+// [from, to] or carrying one of the bank identifiers.
+func (r *TransactionRepositoryImpl) FindDuplicateCandidates(userID uint, from, to time.Time, externalIDs []string) ([]*models.Transaction, error) {
+	var transactions []*models.Transaction
+
+	inWindow := r.DB.Where("transaction_date BETWEEN ? AND ?", from, to)
+	if len(externalIDs) > 0 {
+		inWindow = inWindow.Or("external_id IN ?", externalIDs)
+	}
+
+	if err := r.DB.Where("user_id = ?", userID).Where(inWindow).Order("id").Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+func (r *TransactionRepositoryImpl) FindFlaggedDuplicates(userID uint) ([]*models.Transaction, error) {
+	var transactions []*models.Transaction
+
+	if err := r.DB.Where("user_id = ? AND duplicate_of_id IS NOT NULL", userID).Order("transaction_date DESC").Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// ClearDuplicatesOf removes the duplicate flag from transactions pointing at
+// the transaction with the given ID.
+func (r *TransactionRepositoryImpl) ClearDuplicatesOf(id uint) error {
+	return r.DB.Model(&models.Transaction{}).Where("duplicate_of_id = ?", id).Update("duplicate_of_id", nil).Error
+}
+
+// ClearDuplicateFlag removes the duplicate flag from the transaction with the
+// given ID, leaving its other columns and its splits alone.
+func (r *TransactionRepositoryImpl) ClearDuplicateFlag(id uint) error {
+	return r.DB.Model(&models.Transaction{}).Where("id = ?", id).Update("duplicate_of_id", nil).Error
+}
+
+func (r *TransactionRepositoryImpl) UpdateBaseAmount(id uint, baseAmount money.Amount) error {
+	return r.DB.Model(&models.Transaction{}).Where("id = ?", id).Update("base_amount", baseAmount).Error
+}
+
+func (r *TransactionRepositoryImpl) UpdateSplitBaseAmount(id uint, baseAmount money.Amount) error {
+	return r.DB.Model(&models.TransactionSplit{}).Where("id = ?", id).Update("base_amount", baseAmount).Error
+}
+
+// FindPage returns one page of the user's transactions matching query, which
+// must have been normalized. Pages are keyed on the sort value and ID of the
+// last row of the previous page, carried in the cursor, so rows added,
+// edited or removed meanwhile do not shift later pages.
+func (r *TransactionRepositoryImpl) FindPage(userID uint, query TransactionQuery) (*TransactionPage, error) {
+	filtered := r.DB.Table("transactions").
 		Joins("JOIN categories ON categories.id = transactions.category_id").
-		Where("users.username = ?", username).
-		Scan(&transactions).Error
+		Where("transactions.user_id = ?", userID)
+
+	if !query.From.IsZero() {
+		filtered = filtered.Where("transactions.transaction_date >= ?", query.From)
+	}
+	if !query.To.IsZero() {
+		filtered = filtered.Where("transactions.transaction_date < ?", query.To.AddDate(0, 0, 1))
+	}
+	if len(query.CategoryIDs) > 0 {
+		filtered = filtered.Where(inCategoriesSQL, query.CategoryIDs, query.CategoryIDs)
+	}
+	if len(query.AccountIDs) > 0 {
+		filtered = filtered.Where("transactions.account_id IN ?", query.AccountIDs)
+	}
+	if len(query.Types) > 0 {
+		filtered = filtered.Where("transactions.type IN ?", query.Types)
+	}
+	if query.MinAmount != nil {
+		filtered = filtered.Where("transactions.base_amount >= ?", *query.MinAmount)
+	}
+	if query.MaxAmount != nil {
+		filtered = filtered.Where("transactions.base_amount <= ?", *query.MaxAmount)
+	}
+	if query.Search != "" {
+		filtered = filtered.Where("transactions.description LIKE ?", "%"+escapeLike(query.Search)+"%")
+	}
+
+	page := &TransactionPage{Transactions: []*TransactionResponse{}}
+	if err := filtered.Session(&gorm.Session{}).Count(&page.Total).Error; err != nil {
+		return nil, err
+	}
+
+	column, direction, comparison := query.SortColumn(), "ASC", ">"
+	if query.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	rows := filtered.Session(&gorm.Session{})
+	if query.Cursor != "" {
+		key, id := query.CursorKey()
+		rows = rows.Where(fmt.Sprintf("(%s, transactions.id) %s (?, ?)", column, comparison), key, id)
+	}
 
+	// One extra row tells whether another page follows.
+	err := rows.
+		Select("transactions.id, transactions.user_id, transactions.category_id, categories.name as category_name, transactions.account_id, transactions.type, transactions.amount, transactions.currency, transactions.base_amount, transactions.description, transactions.transaction_date, transactions.created_at, transactions.updated_at").
+		Order(fmt.Sprintf("%s %s, transactions.id %s", column, direction, direction)).
+		Limit(query.Limit + 1).
+		Scan(&page.Transactions).Error
 	if err != nil {
 		return nil, err
 	}
 
+	if len(page.Transactions) > query.Limit {
+		page.Transactions = page.Transactions[:query.Limit]
+		page.NextCursor = query.EncodeCursor(page.Transactions[query.Limit-1])
+	}
+
+	return page, nil
+}
+
+// Search returns up to limit of the user's transactions whose description
+// or notes contain a word starting with each of terms. On MySQL the FULLTEXT
+// index answers terms long enough to be indexed, best matches first; other
+// terms, and other databases, fall back to LIKE.
+func (r *TransactionRepositoryImpl) Search(userID uint, terms []string, limit int) ([]*models.Transaction, error) {
+	var transactions []*models.Transaction
+
+	query := r.DB.Where("user_id = ?", userID)
+
+	var indexed []string
+	for _, term := range terms {
+		if r.DB.Dialector.Name() == "mysql" && len(term) >= minFullTextTermLength {
+			indexed = append(indexed, "+"+term+"*")
+			continue
+		}
+		pattern := "%" + escapeLike(term) + "%"
+		query = query.Where("(LOWER(description) LIKE ? OR LOWER(notes) LIKE ?)", pattern, pattern)
+	}
+	if len(indexed) > 0 {
+		against := strings.Join(indexed, " ")
+		query = query.Where("MATCH(description, notes) AGAINST (? IN BOOLEAN MODE)", against).
+			Order(clause.OrderBy{Expression: clause.Expr{SQL: "MATCH(description, notes) AGAINST (? IN BOOLEAN MODE) DESC", Vars: []interface{}{against}}})
+	}
+
+	if err := query.Order("transaction_date DESC, id DESC").Limit(limit).Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+func (r *TransactionRepositoryImpl) SearchFragments(userID uint, groups [][]string, exclude []uint, limit int) ([]*models.Transaction, error) {
+	query := r.DB.Where("user_id = ?", userID)
+	if len(exclude) > 0 {
+		query = query.Where("id NOT IN ?", exclude)
+	}
+
+	for _, fragments := range groups {
+		conditions := make([]string, 0, len(fragments))
+		var args []interface{}
+		for _, fragment := range fragments {
+			pattern := "%" + escapeLike(fragment) + "%"
+			conditions = append(conditions, "LOWER(description) LIKE ? OR LOWER(notes) LIKE ?")
+			args = append(args, pattern, pattern)
+		}
+		query = query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+	}
+
+	var transactions []*models.Transaction
+	if err := query.Order("transaction_date DESC, id DESC").Limit(limit).Find(&transactions).Error; err != nil {
+		return nil, err
+	}
 	return transactions, nil
 }
 
+// FindAllByUserIDAndCategoryID returns the user's transactions filed under
+// the category or with a split booked against it. SplitAmount is the part of
+// a split transaction that is in the category.
 func (r *TransactionRepositoryImpl) FindAllByUserIDAndCategoryID(userID, categoryID uint) ([]*TransactionResponse, error) {
 	var transactions []*TransactionResponse
-	err := r.DB.Table("transactions").Where("user_id = ? AND category_id = ?", userID, categoryID).Find(&transactions).Error
+	err := r.DB.Table("transactions").
+		Select("transactions.*, (SELECT SUM(transaction_splits.amount) FROM transaction_splits WHERE transaction_splits.transaction_id = transactions.id AND transaction_splits.category_id = ?) AS split_amount", categoryID).
+		Where("transactions.user_id = ? AND (transactions.category_id = ? OR transactions.id IN (SELECT transaction_id FROM transaction_splits WHERE category_id = ?))", userID, categoryID, categoryID).
+		Find(&transactions).Error
 	if err != nil {
 		return nil, err
 	}
@@ -105,22 +410,14 @@ func (r *TransactionRepositoryImpl) FindAllByUserIDAndCategoryID(userID, categor
 // 	return weeklySpending, nil
 // }
 
-// returns last 6 weeks transaction totals
-func (r *TransactionRepositoryImpl) GetWeeklySpending(userID uint) ([]WeeklySpending, error) {
-	var weeklySpending []WeeklySpending
-
-	cutoffDate := time.Now().AddDate(0, 0, -6*7)
-
-	err := r.DB.Table("transactions").
-		Select("YEAR(transaction_date) as year, WEEK(transaction_date, 1) as week, SUM(amount) as total_spent").
-		Where("user_id = ? AND transaction_date >= ?", userID, cutoffDate).
-		Group("YEAR(transaction_date), WEEK(transaction_date, 1)").
-		Order("YEAR(transaction_date) DESC, WEEK(transaction_date, 1) DESC").
-		Scan(&weeklySpending).Error
-
+func (r *TransactionRepositoryImpl) SumSpending(userID uint, start, end time.Time) (money.Amount, error) {
+	var spent money.Amount
+	err := r.DB.Model(&models.Transaction{}).
+		Select("COALESCE(SUM("+budget.SpendingSQL+"), 0)").
+		Where("user_id = ? AND transaction_date >= ? AND transaction_date < ?", userID, start, end).
+		Scan(&spent).Error
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-
-	return weeklySpending, nil
+	return spent, nil
 }
@@ -1,13 +1,22 @@
 package transaction
 
+import (
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+)
+
 type TransactionResponse struct {
-	ID              uint    `json:"id"`
-	UserID          uint    `json:"user_id"`
-	CategoryID      uint    `json:"category_id"`
-	CategoryName    string  `json:"category_name"`
-	Amount          float64 `json:"amount"`
-	Description     string  `json:"description"`
-	TransactionDate string  `json:"transaction_date"`
-	CreatedAt       string  `json:"created_at"`
-	UpdatedAt       string  `json:"updated_at"`
+	ID              uint           `json:"id"`
+	UserID          uint           `json:"user_id"`
+	CategoryID      uint           `json:"category_id"`
+	CategoryName    string         `json:"category_name"`
+	AccountID       *uint          `json:"account_id,omitempty"`
+	Type            string         `json:"type"`
+	Amount          money.Amount   `json:"amount" swaggertype:"number"`
+	Currency        money.Currency `json:"currency" swaggertype:"string"`
+	BaseAmount      money.Amount   `json:"base_amount" swaggertype:"number"`
+	SplitAmount     *money.Amount  `json:"split_amount,omitempty" swaggertype:"number"`
+	Description     string         `json:"description"`
+	TransactionDate string         `json:"transaction_date"`
+	CreatedAt       string         `json:"created_at"`
+	UpdatedAt       string         `json:"updated_at"`
 }
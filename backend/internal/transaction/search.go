@@ -0,0 +1,225 @@
+package transaction
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+
+	// searchIndexName is the MySQL FULLTEXT index over the searchable
+	// columns.
+	searchIndexName = "idx_transactions_search"
+
+	// minFullTextTermLength is InnoDB's default innodb_ft_min_token_size.
+	// Shorter words are not indexed, so they are searched with LIKE.
+	minFullTextTermLength = 3
+
+	// fuzzyCandidatesPerResult bounds how many transactions a search loads
+	// for fuzzy scoring, per result asked for.
+	fuzzyCandidatesPerResult = 10
+)
+
+// SearchResult is a transaction found by a search, with a score between 0
+// and 1 for how well it matched.
+type SearchResult struct {
+	*models.Transaction
+	Score float64 `json:"score"`
+}
+
+// SearchTerms splits search text into lower-case words of letters and
+// digits.
+func SearchTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// searchableText is what a search looks at in a transaction.
+func searchableText(transaction *models.Transaction) string {
+	return transaction.Description + " " + transaction.Notes
+}
+
+// ScoreMatch rates how well transaction matches every term. A word equal to
+// the term scores 1, a word starting with it 0.8, a word containing it 0.7,
+// and a word within a typo or two of it 0.5. The result is the average over
+// the terms, or 0 when any term is not matched at all.
+func ScoreMatch(terms []string, transaction *models.Transaction) float64 {
+	if len(terms) == 0 {
+		return 0
+	}
+
+	words := SearchTerms(searchableText(transaction))
+	total := 0.0
+	for _, term := range terms {
+		best := 0.0
+		for _, word := range words {
+			if score := scoreWord(term, word); score > best {
+				best = score
+			}
+		}
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+	return total / float64(len(terms))
+}
+
+func scoreWord(term, word string) float64 {
+	switch {
+	case word == term:
+		return 1
+	case strings.HasPrefix(word, term):
+		return 0.8
+	case strings.Contains(word, term):
+		return 0.7
+	}
+
+	// Also compare against the start of longer words, so that "amazn"
+	// finds "amazonprime".
+	maxEdits := allowedEdits(term)
+	if maxEdits == 0 {
+		return 0
+	}
+	candidates := []string{word}
+	if runes := []rune(word); len(runes) > len([]rune(term)) {
+		candidates = append(candidates, string(runes[:len([]rune(term))]))
+	}
+	for _, candidate := range candidates {
+		if editDistance(term, candidate) <= maxEdits {
+			return 0.5
+		}
+	}
+	return 0
+}
+
+// allowedEdits is the number of typos tolerated in a term. Short terms must
+// match exactly, or almost anything would match them.
+func allowedEdits(term string) int {
+	switch n := len([]rune(term)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// fuzzyFragments splits term into one more piece than the typos it
+// tolerates. A word within that many typos of term keeps at least one of the
+// pieces intact, so only transactions containing one of them can match.
+func fuzzyFragments(term string) []string {
+	runes := []rune(term)
+	pieces := allowedEdits(term) + 1
+	fragments := make([]string, 0, pieces)
+	for i := 0; i < pieces; i++ {
+		fragments = append(fragments, string(runes[i*len(runes)/pieces:(i+1)*len(runes)/pieces]))
+	}
+	return fragments
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	previous := make([]int, len(t)+1)
+	current := make([]int, len(t)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		current[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(t)]
+}
+
+// SearchTransactions finds the user's transactions whose description or
+// notes match text. Words are matched by prefix in the database first; when
+// that finds fewer than limit transactions, the rest are filled with fuzzy
+// matches that tolerate typos, scored among the newest transactions that
+// could match. Results are ordered best match first, then
+// newest first.
+func (s *TransactionService) SearchTransactions(username, text string, limit int) ([]*SearchResult, error) {
+	terms := SearchTerms(text)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("%w: search text has no words", ErrInvalidQuery)
+	}
+	switch {
+	case limit == 0:
+		limit = DefaultSearchLimit
+	case limit < 0 || limit > MaxSearchLimit:
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidQuery, MaxSearchLimit)
+	}
+
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := s.Repo.Search(user.ID, terms, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*SearchResult, 0, limit)
+	found := make([]uint, 0, len(matches))
+	for _, transaction := range matches {
+		found = append(found, transaction.ID)
+		// The database may match a term inside a word the scorer does not
+		// count, so never score a database match as no match at all.
+		results = append(results, &SearchResult{Transaction: transaction, Score: max(ScoreMatch(terms, transaction), 0.5)})
+	}
+
+	if len(results) < limit {
+		groups := make([][]string, len(terms))
+		for i, term := range terms {
+			groups[i] = fuzzyFragments(term)
+		}
+		candidates, err := s.Repo.SearchFragments(user.ID, groups, found, limit*fuzzyCandidatesPerResult)
+		if err != nil {
+			return nil, err
+		}
+		for _, transaction := range candidates {
+			if score := ScoreMatch(terms, transaction); score > 0 {
+				results = append(results, &SearchResult{Transaction: transaction, Score: score})
+			}
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].TransactionDate.After(results[j].TransactionDate)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// EnsureSearchIndex creates the FULLTEXT index used by searches on MySQL.
+// Other databases are searched without an index.
+func EnsureSearchIndex(db *gorm.DB) error {
+	if db.Dialector.Name() != "mysql" || db.Migrator().HasIndex(&models.Transaction{}, searchIndexName) {
+		return nil
+	}
+	return db.Exec(fmt.Sprintf("CREATE FULLTEXT INDEX %s ON transactions (description, notes)", searchIndexName)).Error
+}
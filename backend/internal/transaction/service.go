@@ -2,126 +2,632 @@ package transaction
 
 import (
 	"errors"
+	"fmt"
+	"log"
 	"time"
 
 	"github.com/shaikhjunaidx/pennywise-backend/internal/budget"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/category"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/constants"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/fx"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/notify"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/user"
 	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"gorm.io/gorm"
 )
 
+var (
+	ErrTransactionNotFound     = errors.New("transaction not found")
+	ErrTransactionAccessDenied = errors.New("access denied: transaction does not belong to the user")
+	ErrInvalidCategory         = errors.New("category not found or does not belong to the user")
+)
+
+// SettingsProvider looks up a user's settings, typically *user.UserService.
+type SettingsProvider interface {
+	SettingsForUserID(userID uint) (*models.UserSettings, error)
+}
+
 type TransactionService struct {
 	Repo          TransactionRepository
 	UserRepo      user.UserRepository
 	CategoryRepo  category.CategoryRepository
 	BudgetService *budget.BudgetService
+	UnitOfWork    UnitOfWork
+	Notifier      notify.Notifier
+	Settings      SettingsProvider
+	Converter     *fx.Converter
+	Accounts      AccountFinder
 }
 
+var _ user.BaseCurrencyListener = (*TransactionService)(nil)
+
 func NewTransactionService(repo TransactionRepository, userRepo user.UserRepository,
-	categoryRepo category.CategoryRepository, budgetService *budget.BudgetService) *TransactionService {
+	categoryRepo category.CategoryRepository, budgetService *budget.BudgetService, unitOfWork UnitOfWork) *TransactionService {
 	return &TransactionService{
 		Repo:          repo,
 		UserRepo:      userRepo,
 		CategoryRepo:  categoryRepo,
 		BudgetService: budgetService,
+		UnitOfWork:    unitOfWork,
 	}
 }
 
-func (s *TransactionService) AddTransaction(username string, categoryID uint,
-	amount float64, description string, transactionDate time.Time) (*models.Transaction, error) {
+// TransactionInput carries the user-editable fields of a transaction.
+// Type defaults to expense. AccountID, when set, books the transaction to one
+// of the user's accounts. ExternalID is the bank's identifier for imported
+// rows. AllowDuplicate stores the transaction unflagged even if it looks like
+// a duplicate. Splits, when given, divide the amount between categories and
+// take the place of CategoryID.
+type TransactionInput struct {
+	Type            string
+	CategoryID      uint
+	AccountID       uint
+	Amount          money.Amount
+	Currency        money.Currency
+	Description     string
+	Notes           string
+	TransactionDate time.Time
+	ExternalID      string
+	AllowDuplicate  bool
+	Splits          []SplitInput
+}
+
+func (s *TransactionService) AddTransaction(username string, input TransactionInput) (*models.Transaction, error) {
 
 	user, err := s.UserRepo.FindByUsername(username)
 	if err != nil {
 		return nil, err
 	}
 
-	if categoryID == 0 {
-		defaultCategory, err := s.CategoryRepo.FindByName(constants.DefaultCategoryName)
-		if err != nil {
-			return nil, errors.New("default category not found")
+	transaction, categories, err := s.newTransaction(user, input)
+	if err != nil {
+		return nil, err
+	}
+	kind := transaction.Type
+
+	if err := s.applyBaseAmount(user.ID, transaction); err != nil {
+		return nil, err
+	}
+
+	loc, err := s.location(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := budgetLines(transaction)
+	updatedBudgets := make([]*models.Budget, len(lines))
+	err = s.UnitOfWork.Do(func(repos Repositories) error {
+		if err := s.checkUnreconciled(loc, transaction); err != nil {
+			return err
 		}
-		categoryID = defaultCategory.ID
+		if !input.AllowDuplicate {
+			if err := s.flagDuplicate(repos.Transactions, user.ID, transaction); err != nil {
+				return err
+			}
+		}
+
+		if err := repos.Transactions.Create(transaction); err != nil {
+			return err
+		}
+
+		if !affectsBudget(kind) {
+			return nil
+		}
+		month, year := budgetPeriodOf(transaction.TransactionDate, loc)
+		budgets := s.BudgetService.WithRepo(repos.Budgets)
+		for i, line := range lines {
+			categoryID := line.CategoryID
+			if updatedBudgets[i], err = budgets.RecalculateBudget(user.ID, &categoryID, month, year); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if kind == models.TransactionTypeExpense {
+		names := make(map[uint]string, len(categories))
+		for _, category := range categories {
+			names[category.ID] = category.Name
+		}
+		for i, line := range lines {
+			s.notifyIfOverBudget(user, names[line.CategoryID], line.BaseAmount, updatedBudgets[i])
+		}
+	}
+
+	return transaction, nil
+}
+
+// ValidateTransaction checks input as AddTransaction would, without storing
+// anything, for callers that save it to book later. A default income
+// category that does not exist yet is not created.
+func (s *TransactionService) ValidateTransaction(username string, input TransactionInput) error {
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = s.buildTransaction(user, input, s.peekCategory)
+	return err
+}
+
+// newTransaction validates input and builds the user's transaction from it,
+// along with the categories it is booked against, the one it is filed under
+// first.
+func (s *TransactionService) newTransaction(user *models.User, input TransactionInput) (*models.Transaction, []*models.Category, error) {
+	return s.buildTransaction(user, input, s.resolveCategory)
+}
+
+// buildTransaction is newTransaction with the categories looked up through
+// resolve.
+func (s *TransactionService) buildTransaction(user *models.User, input TransactionInput, resolve categoryResolver) (*models.Transaction, []*models.Category, error) {
+	kind, err := ParseType(input.Type)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	categories, splits, err := s.resolveCategories(user, kind, input, resolve)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	account, err := s.resolveAccount(user, input.AccountID)
+	if err != nil {
+		return nil, nil, err
+	}
+	currency, err := accountCurrency(account, input.Currency)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkMinorUnits(input.Amount, splits, currency); err != nil {
+		return nil, nil, err
 	}
 
 	transaction := &models.Transaction{
 		UserID:          user.ID,
-		CategoryID:      categoryID,
-		Amount:          amount,
-		Description:     description,
-		TransactionDate: transactionDate,
+		CategoryID:      categories[0].ID,
+		AccountID:       accountIDOf(account),
+		Type:            kind,
+		Amount:          input.Amount,
+		Currency:        currency,
+		Description:     input.Description,
+		Notes:           input.Notes,
+		TransactionDate: input.TransactionDate,
+		ExternalID:      input.ExternalID,
+		Splits:          splits,
 	}
+	return transaction, categories, nil
+}
 
-	if err := s.Repo.Create(transaction); err != nil {
-		return nil, err
+// baseCurrency returns the currency the user's budgets and summaries are
+// kept in.
+func (s *TransactionService) baseCurrency(userID uint) (money.Currency, error) {
+	if s.Settings == nil {
+		return money.DefaultCurrency, nil
+	}
+
+	settings, err := s.Settings.SettingsForUserID(userID)
+	if err != nil {
+		return "", err
+	}
+	return settings.BaseCurrency, nil
+}
+
+// applyBaseAmount converts the transaction amount into the user's base
+// currency at the rate for the transaction date, and shares it between the
+// transaction's splits.
+func (s *TransactionService) applyBaseAmount(userID uint, transaction *models.Transaction) error {
+	base, err := s.baseCurrency(userID)
+	if err != nil {
+		return err
+	}
+
+	baseAmount, err := s.Converter.Convert(transaction.Amount, transaction.Currency, base, transaction.TransactionDate)
+	if err != nil {
+		return err
+	}
+
+	transaction.BaseAmount = baseAmount
+	allocateSplitBaseAmounts(transaction)
+	return nil
+}
+
+// BaseCurrencyChanged saves settings with their new base currency and
+// converts every stored base amount and budget of the user into it, all in
+// one database transaction.
+func (s *TransactionService) BaseCurrencyChanged(settings *models.UserSettings) error {
+	userID, base := settings.UserID, settings.BaseCurrency
+	return s.UnitOfWork.Do(func(repos Repositories) error {
+		if err := repos.Settings.Save(settings); err != nil {
+			return err
+		}
+
+		transactions, err := repos.Transactions.FindAllByUserID(userID)
+		if err != nil {
+			return err
+		}
+
+		for _, transaction := range transactions {
+			baseAmount, err := s.Converter.Convert(transaction.Amount, transaction.Currency, base, transaction.TransactionDate)
+			if err != nil {
+				return err
+			}
+			if err := repos.Transactions.UpdateBaseAmount(transaction.ID, baseAmount); err != nil {
+				return err
+			}
+
+			transaction.BaseAmount = baseAmount
+			allocateSplitBaseAmounts(transaction)
+			for _, split := range transaction.Splits {
+				if err := repos.Transactions.UpdateSplitBaseAmount(split.ID, split.BaseAmount); err != nil {
+					return err
+				}
+			}
+		}
+
+		return s.BudgetService.WithRepo(repos.Budgets).ChangeCurrency(userID, base, s.Converter)
+	})
+}
+
+func currencyOrDefault(currency money.Currency) money.Currency {
+	if currency == "" {
+		return money.DefaultCurrency
 	}
+	return currency
+}
 
-	if _, err := s.BudgetService.AddTransactionToBudget(user.ID, &categoryID, amount, transactionDate.Month().String(), transactionDate.Year()); err != nil {
+// budgetPeriodOf returns the budget month and year a transaction date falls
+// in on the user's calendar, which is also what the ledger queries use.
+func budgetPeriodOf(date time.Time, loc *time.Location) (string, int) {
+	local := date.In(loc)
+	return local.Month().String(), local.Year()
+}
+
+// Location returns the time zone of the user's calendar, in which dates given
+// without a time of day are read.
+func (s *TransactionService) Location(username string) (*time.Location, error) {
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
 		return nil, err
 	}
+	return s.location(user.ID)
+}
 
-	return transaction, nil
+// location returns the time zone of the user's calendar, or the server's when
+// the service has no settings to ask.
+func (s *TransactionService) location(userID uint) (*time.Location, error) {
+	loc, _, err := s.calendar(userID)
+	return loc, err
 }
 
-func (s *TransactionService) UpdateTransaction(id uint, amount float64, categoryID uint, description string, transactionDate time.Time) (*models.Transaction, error) {
+// calendar returns the time zone of the user's calendar and the day their
+// weeks start on, or the server's zone and Monday when the service has no
+// settings to ask.
+func (s *TransactionService) calendar(userID uint) (*time.Location, time.Weekday, error) {
+	if s.Settings == nil {
+		return time.Local, time.Weekday(user.DefaultFirstDayOfWeek), nil
+	}
+
+	settings, err := s.Settings.SettingsForUserID(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return user.Location(settings), user.WeekStart(settings), nil
+}
+
+// flagDuplicate marks a manually entered transaction that probably repeats a
+// stored one, unless the user's policy allows duplicates. Manual entries are
+// never dropped, even when imports would skip them.
+func (s *TransactionService) flagDuplicate(repo TransactionRepository, userID uint, transaction *models.Transaction) error {
+	policy, err := s.duplicatePolicy(userID)
+	if err != nil || policy.Action == user.DuplicateActionAllow {
+		return err
+	}
+
+	candidates, err := duplicateCandidates(repo, userID, policy, []*models.Transaction{transaction})
+	if err != nil {
+		return err
+	}
+
+	if match := policy.FindMatch(transaction, candidates); match != nil {
+		transaction.DuplicateOfID = &match.ID
+	}
+	return nil
+}
+
+// findOwnedTransaction loads a transaction and verifies that it belongs to the
+// user.
+func (s *TransactionService) findOwnedTransaction(username string, id uint) (*models.User, *models.Transaction, error) {
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	transaction, err := s.Repo.FindByID(id)
+	if err := checkOwnership(user, transaction, err); err != nil {
+		return nil, nil, err
+	}
+
+	return user, transaction, nil
+}
+
+// checkOwnership turns the result of a transaction lookup into
+// ErrTransactionNotFound or ErrTransactionAccessDenied where appropriate.
+func checkOwnership(user *models.User, transaction *models.Transaction, lookupErr error) error {
+	if lookupErr != nil {
+		if errors.Is(lookupErr, gorm.ErrRecordNotFound) {
+			return ErrTransactionNotFound
+		}
+		return lookupErr
+	}
+
+	if transaction.UserID != user.ID {
+		return ErrTransactionAccessDenied
+	}
+
+	return nil
+}
+
+// resolveCategory returns the category to book a transaction of type kind
+// against. A zero ID means the user's default category, or for income the
+// default income category, which is created the first time it is needed.
+// Any other ID must be one of the user's own categories.
+func (s *TransactionService) resolveCategory(user *models.User, categoryID uint, kind string) (*models.Category, error) {
+	if categoryID == 0 && kind == models.TransactionTypeIncome {
+		return s.defaultIncomeCategory(user)
+	}
+	if categoryID == 0 {
+		defaultCategory, err := s.CategoryRepo.FindByNameAndUserID(constants.DefaultCategoryName, user.ID)
+		if err != nil {
+			return nil, errors.New("default category not found")
+		}
+		return defaultCategory, nil
+	}
+
+	category, err := s.CategoryRepo.FindByID(categoryID)
+	if err != nil || category.UserID != user.ID {
+		return nil, ErrInvalidCategory
+	}
+
+	return category, nil
+}
+
+// peekCategory is resolveCategory for checks that must not change anything:
+// a default income category that does not exist yet stands in unsaved.
+func (s *TransactionService) peekCategory(user *models.User, categoryID uint, kind string) (*models.Category, error) {
+	if categoryID != 0 || kind != models.TransactionTypeIncome {
+		return s.resolveCategory(user, categoryID, kind)
+	}
+
+	category, err := s.CategoryRepo.FindByNameKindAndUserID(constants.DefaultIncomeCategoryName, models.CategoryKindIncome, user.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.Category{UserID: user.ID, Name: constants.DefaultIncomeCategoryName, Kind: models.CategoryKindIncome}, nil
+	}
+	return category, err
+}
+
+func (s *TransactionService) defaultIncomeCategory(user *models.User) (*models.Category, error) {
+	return s.findOrCreateCategory(user, constants.DefaultIncomeCategoryName, "Default category for income", models.CategoryKindIncome)
+}
+
+// notifyIfOverBudget sends a budget alert when the amount just added, in the
+// base currency, is what pushed the category budget over its limit.
+func (s *TransactionService) notifyIfOverBudget(user *models.User, categoryName string, added money.Amount, budget *models.Budget) {
+	if s.Notifier == nil || budget.AmountLimit <= 0 {
+		return
+	}
+
+	previousSpent := budget.SpentAmount - added
+	if budget.SpentAmount <= budget.AmountLimit || previousSpent > budget.AmountLimit {
+		return
+	}
+
+	msg, err := notify.BudgetAlertMessage(user.Email, notify.BudgetAlertData{
+		Username:     user.Username,
+		CategoryName: categoryName,
+		AmountLimit:  budget.AmountLimit,
+		SpentAmount:  budget.SpentAmount,
+		BudgetMonth:  budget.BudgetMonth,
+		BudgetYear:   budget.BudgetYear,
+	})
+	if err == nil {
+		err = s.Notifier.Send(msg)
+	}
+	if err != nil {
+		log.Printf("Failed to send budget alert to %s: %v", user.Email, err)
+	}
+}
+
+func (s *TransactionService) UpdateTransaction(username string, id uint, input TransactionInput) (*models.Transaction, error) {
+
+	user, err := s.UserRepo.FindByUsername(username)
 	if err != nil {
 		return nil, err
 	}
 
-	oldAmount := transaction.Amount
-	oldCategoryID := transaction.CategoryID
+	kind, err := ParseType(input.Type)
+	if err != nil {
+		return nil, err
+	}
 
-	transaction.Amount = amount
-	transaction.CategoryID = categoryID
-	transaction.Description = description
-	transaction.TransactionDate = transactionDate
+	categories, splits, err := s.resolveCategories(user, kind, input, s.resolveCategory)
+	if err != nil {
+		return nil, err
+	}
+	categoryID := categories[0].ID
+
+	account, err := s.resolveAccount(user, input.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	currency, err := accountCurrency(account, input.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMinorUnits(input.Amount, splits, currency); err != nil {
+		return nil, err
+	}
 
-	if err := s.Repo.Update(transaction); err != nil {
+	loc, err := s.location(user.ID)
+	if err != nil {
 		return nil, err
 	}
 
-	if oldCategoryID != categoryID {
-		if _, err := s.BudgetService.AddTransactionToBudget(transaction.UserID, &oldCategoryID, -oldAmount, transactionDate.Month().String(), transactionDate.Year()); err != nil {
-			return nil, err
+	var transaction *models.Transaction
+	err = s.UnitOfWork.Do(func(repos Repositories) error {
+		transaction, err = repos.Transactions.FindByIDForUpdate(id)
+		if err := checkOwnership(user, transaction, err); err != nil {
+			return err
 		}
-		if _, err := s.BudgetService.AddTransactionToBudget(transaction.UserID, &categoryID, amount, transactionDate.Month().String(), transactionDate.Year()); err != nil {
-			return nil, err
+		if transaction.TransferID != nil {
+			return fmt.Errorf("%w: edit both sides of a transfer together", ErrInvalidTransfer)
 		}
-	} else {
-		if _, err := s.BudgetService.AddTransactionToBudget(transaction.UserID, &categoryID, amount-oldAmount, transactionDate.Month().String(), transactionDate.Year()); err != nil {
-			return nil, err
+		if err := s.checkUnreconciled(loc, transaction); err != nil {
+			return err
+		}
+
+		oldLines, oldType := budgetLines(transaction), transaction.Type
+		oldMonth, oldYear := budgetPeriodOf(transaction.TransactionDate, loc)
+
+		transaction.Type = kind
+		transaction.Amount = input.Amount
+		transaction.Currency = currency
+		transaction.CategoryID = categoryID
+		transaction.AccountID = accountIDOf(account)
+		transaction.Description = input.Description
+		transaction.Notes = input.Notes
+		transaction.TransactionDate = input.TransactionDate
+		transaction.Splits = splits
+
+		if err := s.checkUnreconciled(loc, transaction); err != nil {
+			return err
+		}
+		if err := s.applyBaseAmount(user.ID, transaction); err != nil {
+			return err
+		}
+
+		if err := repos.Transactions.Update(transaction); err != nil {
+			return err
 		}
+
+		budgets := s.BudgetService.WithRepo(repos.Budgets)
+		month, year := budgetPeriodOf(transaction.TransactionDate, loc)
+		lines := budgetLines(transaction)
+
+		// The transaction may have moved to other categories or another
+		// month, or stopped counting towards budgets, so the budgets it left
+		// have to be rebuilt as well as the ones it joined.
+		joined := make(map[uint]bool, len(lines))
+		for _, line := range lines {
+			joined[line.CategoryID] = true
+		}
+		samePeriod := oldMonth == month && oldYear == year
+		if affectsBudget(oldType) {
+			for _, line := range oldLines {
+				oldCategoryID := line.CategoryID
+				if affectsBudget(kind) && samePeriod && joined[oldCategoryID] {
+					continue
+				}
+				if _, err := budgets.RecalculateBudget(transaction.UserID, &oldCategoryID, oldMonth, oldYear); err != nil {
+					return err
+				}
+			}
+		}
+
+		if !affectsBudget(kind) {
+			return nil
+		}
+		for _, line := range lines {
+			categoryID := line.CategoryID
+			if _, err := budgets.RecalculateBudget(transaction.UserID, &categoryID, month, year); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return transaction, nil
 }
 
-func (s *TransactionService) DeleteTransaction(transactionID uint) error {
+func (s *TransactionService) DeleteTransaction(username string, transactionID uint) error {
 
-	transaction, err := s.Repo.FindByID(transactionID)
+	user, err := s.UserRepo.FindByUsername(username)
 	if err != nil {
 		return err
 	}
 
-	if err := s.Repo.DeleteByID(transactionID); err != nil {
+	loc, err := s.location(user.ID)
+	if err != nil {
 		return err
 	}
 
-	if _, err := s.BudgetService.AddTransactionToBudget(transaction.UserID, &transaction.CategoryID, -transaction.Amount, transaction.TransactionDate.Month().String(), transaction.TransactionDate.Year()); err != nil {
-		return err
-	}
+	return s.UnitOfWork.Do(func(repos Repositories) error {
+		transaction, err := repos.Transactions.FindByIDForUpdate(transactionID)
+		if err := checkOwnership(user, transaction, err); err != nil {
+			return err
+		}
 
-	return nil
+		// Deleting either side of a transfer deletes the whole transfer.
+		if transaction.TransferID != nil {
+			transfer, err := findTransferForUpdate(repos.Transactions, user, transactionID)
+			if err != nil {
+				return err
+			}
+			if err := s.checkUnreconciled(loc, transfer.From, transfer.To); err != nil {
+				return err
+			}
+			return deleteTransfer(repos.Transactions, transfer)
+		}
+
+		if err := s.checkUnreconciled(loc, transaction); err != nil {
+			return err
+		}
+		if err := repos.Transactions.DeleteByID(transactionID); err != nil {
+			return err
+		}
+
+		if err := repos.Transactions.ClearDuplicatesOf(transactionID); err != nil {
+			return err
+		}
+
+		if !affectsBudget(transaction.Type) {
+			return nil
+		}
+		month, year := budgetPeriodOf(transaction.TransactionDate, loc)
+		budgets := s.BudgetService.WithRepo(repos.Budgets)
+		for _, line := range budgetLines(transaction) {
+			categoryID := line.CategoryID
+			if _, err := budgets.RecalculateBudget(transaction.UserID, &categoryID, month, year); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
-func (s *TransactionService) GetTransactionsForUser(username string) ([]*TransactionResponse, error) {
-	return s.Repo.FindAllByUsername(username)
+// ListTransactions returns a page of the user's transactions filtered and
+// ordered as described by query.
+func (s *TransactionService) ListTransactions(username string, query TransactionQuery) (*TransactionPage, error) {
+	if err := query.Normalize(); err != nil {
+		return nil, err
+	}
+
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Repo.FindPage(user.ID, query)
 }
 
-func (s *TransactionService) GetTransactionByID(id uint) (*models.Transaction, error) {
-	transaction, err := s.Repo.FindByID(id)
+func (s *TransactionService) GetTransactionByID(username string, id uint) (*models.Transaction, error) {
+	_, transaction, err := s.findOwnedTransaction(username, id)
 	if err != nil {
 		return nil, err
 	}
@@ -143,16 +649,40 @@ func (s *TransactionService) GetTransactionsByCategoryID(username string, catego
 
 }
 
+// spendingWeeks is how many weeks GetWeeklySpending reports.
+const spendingWeeks = 6
+
+// GetWeeklySpending returns the user's spending in the current week and the
+// five before it, newest first. Weeks start at midnight on the user's first
+// day of the week, in their time zone.
 func (s *TransactionService) GetWeeklySpending(username string) ([]WeeklySpending, error) {
 	user, err := s.UserRepo.FindByUsername(username)
 	if err != nil {
 		return nil, err
 	}
 
-	weeklySpending, err := s.Repo.GetWeeklySpending(user.ID)
+	loc, weekStart, err := s.calendar(user.ID)
 	if err != nil {
 		return nil, err
 	}
+	now := time.Now().In(loc)
+	year, month, day := now.Date()
+	start := time.Date(year, month, day-(int(now.Weekday())-int(weekStart)+7)%7, 0, 0, 0, 0, loc)
+
+	weeklySpending := make([]WeeklySpending, 0, spendingWeeks)
+	for i := 0; i < spendingWeeks; i++ {
+		spent, err := s.Repo.SumSpending(user.ID, start, start.AddDate(0, 0, 7))
+		if err != nil {
+			return nil, err
+		}
+		weeklySpending = append(weeklySpending, WeeklySpending{
+			Week:       (start.YearDay()-1)/7 + 1,
+			Year:       start.Year(),
+			Start:      start,
+			TotalSpent: spent,
+		})
+		start = start.AddDate(0, 0, -7)
+	}
 
 	return weeklySpending, nil
-}
\ No newline at end of file
+}
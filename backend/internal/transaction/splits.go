@@ -0,0 +1,126 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+)
+
+var ErrInvalidSplit = errors.New("invalid split")
+
+// categoryResolver returns the category to book a transaction of type kind
+// against, as resolveCategory does.
+type categoryResolver func(user *models.User, categoryID uint, kind string) (*models.Category, error)
+
+// SplitInput is one line of a split transaction: the part of the amount
+// booked against a category. A zero CategoryID means the default category.
+type SplitInput struct {
+	CategoryID  uint
+	Amount      money.Amount
+	Description string
+}
+
+// resolveCategories returns the categories a transaction of type kind is
+// booked against, the one it is filed under first, and the splits input
+// describes, if any. A split transaction needs at least two splits, each with
+// a positive amount in a category that suits the type, adding up to the
+// transaction amount; it is filed under its first split's category. Each
+// category is looked up through resolve.
+func (s *TransactionService) resolveCategories(user *models.User, kind string, input TransactionInput, resolve categoryResolver) ([]*models.Category, []models.TransactionSplit, error) {
+	if len(input.Splits) == 0 {
+		category, err := resolve(user, input.CategoryID, kind)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := checkAmountAndCategory(kind, input.Amount, category); err != nil {
+			return nil, nil, err
+		}
+		return []*models.Category{category}, nil, nil
+	}
+
+	if len(input.Splits) < 2 {
+		return nil, nil, fmt.Errorf("%w: a split transaction needs at least two splits", ErrInvalidSplit)
+	}
+
+	categories := make([]*models.Category, 0, len(input.Splits))
+	splits := make([]models.TransactionSplit, 0, len(input.Splits))
+	var total money.Amount
+	for _, split := range input.Splits {
+		if split.Amount <= 0 {
+			return nil, nil, fmt.Errorf("%w: split amounts must be positive", ErrInvalidSplit)
+		}
+
+		category, err := resolve(user, split.CategoryID, kind)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := checkAmountAndCategory(kind, split.Amount, category); err != nil {
+			return nil, nil, err
+		}
+
+		categories = append(categories, category)
+		splits = append(splits, models.TransactionSplit{
+			CategoryID:  category.ID,
+			Amount:      split.Amount,
+			Description: split.Description,
+		})
+		total += split.Amount
+	}
+
+	if total != input.Amount {
+		return nil, nil, fmt.Errorf("%w: splits add up to %s, not the amount of %s", ErrInvalidSplit, total, input.Amount)
+	}
+
+	return categories, splits, nil
+}
+
+// allocateSplitBaseAmounts shares the transaction's base amount between its
+// splits in proportion to their amounts. The last split takes the rounding
+// difference so that they add up exactly.
+func allocateSplitBaseAmounts(transaction *models.Transaction) {
+	if len(transaction.Splits) == 0 || transaction.Amount == 0 {
+		return
+	}
+
+	remaining := transaction.BaseAmount
+	last := len(transaction.Splits) - 1
+	for i := range transaction.Splits {
+		split := &transaction.Splits[i]
+		if i == last {
+			split.BaseAmount = remaining
+			break
+		}
+		split.BaseAmount = money.Amount(math.Round(float64(transaction.BaseAmount) * float64(split.Amount) / float64(transaction.Amount)))
+		remaining -= split.BaseAmount
+	}
+}
+
+// budgetLine is what a transaction adds to one category's budget, in the
+// base currency.
+type budgetLine struct {
+	CategoryID uint
+	BaseAmount money.Amount
+}
+
+// budgetLines returns the categories whose budgets the transaction counts
+// towards, in the order of its splits, with what it adds to each.
+func budgetLines(transaction *models.Transaction) []budgetLine {
+	if len(transaction.Splits) == 0 {
+		return []budgetLine{{CategoryID: transaction.CategoryID, BaseAmount: transaction.BaseAmount}}
+	}
+
+	var lines []budgetLine
+	index := make(map[uint]int)
+	for _, split := range transaction.Splits {
+		if i, ok := index[split.CategoryID]; ok {
+			lines[i].BaseAmount += split.BaseAmount
+			continue
+		}
+		index[split.CategoryID] = len(lines)
+		lines = append(lines, budgetLine{CategoryID: split.CategoryID, BaseAmount: split.BaseAmount})
+	}
+	return lines
+}
@@ -0,0 +1,290 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/constants"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidTransfer = errors.New("invalid transfer")
+
+// TransferInput describes money moved between two of the user's accounts.
+// Amount leaves FromAccountID in its currency. ToAmount arrives in
+// ToAccountID; it is only needed when the accounts have different
+// currencies, and is converted at the rate for the date when omitted.
+// CategoryID defaults to the Transfers category.
+type TransferInput struct {
+	FromAccountID   uint
+	ToAccountID     uint
+	CategoryID      uint
+	Amount          money.Amount
+	ToAmount        money.Amount
+	Description     string
+	Notes           string
+	TransactionDate time.Time
+}
+
+// Transfer is a transfer as its two linked transactions.
+type Transfer struct {
+	From *models.Transaction `json:"from"`
+	To   *models.Transaction `json:"to"`
+}
+
+// CreateTransfer books a transfer as a transaction out of one account and a
+// transaction into the other, stored together. Transfers never count
+// towards budgets or spending.
+func (s *TransactionService) CreateTransfer(username string, input TransferInput) (*Transfer, error) {
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer := &Transfer{
+		From: &models.Transaction{UserID: user.ID, Type: models.TransactionTypeTransfer},
+		To:   &models.Transaction{UserID: user.ID, Type: models.TransactionTypeTransfer, TransferIn: true},
+	}
+	if err := s.applyTransfer(user, transfer, input); err != nil {
+		return nil, err
+	}
+
+	loc, err := s.location(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.UnitOfWork.Do(func(repos Repositories) error {
+		if err := s.checkUnreconciled(loc, transfer.From, transfer.To); err != nil {
+			return err
+		}
+		if err := repos.Transactions.Create(transfer.From); err != nil {
+			return err
+		}
+		transfer.To.TransferID = &transfer.From.ID
+		if err := repos.Transactions.Create(transfer.To); err != nil {
+			return err
+		}
+		transfer.From.TransferID = &transfer.To.ID
+		return repos.Transactions.Update(transfer.From)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// GetTransfer returns the transfer that the transaction with the given ID is
+// either side of.
+func (s *TransactionService) GetTransfer(username string, id uint) (*Transfer, error) {
+	user, leg, err := s.findOwnedTransaction(username, id)
+	if err != nil {
+		return nil, err
+	}
+	if leg.TransferID == nil {
+		return nil, fmt.Errorf("%w: transaction %d is not part of a transfer", ErrInvalidTransfer, id)
+	}
+
+	peer, err := s.Repo.FindByID(*leg.TransferID)
+	if err := checkOwnership(user, peer, err); err != nil {
+		return nil, err
+	}
+	return pairOf(leg, peer), nil
+}
+
+// UpdateTransfer replaces both sides of the transfer that the transaction with
+// the given ID belongs to.
+func (s *TransactionService) UpdateTransfer(username string, id uint, input TransferInput) (*Transfer, error) {
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := s.location(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var transfer *Transfer
+	err = s.UnitOfWork.Do(func(repos Repositories) error {
+		transfer, err = findTransferForUpdate(repos.Transactions, user, id)
+		if err != nil {
+			return err
+		}
+		if err := s.checkUnreconciled(loc, transfer.From, transfer.To); err != nil {
+			return err
+		}
+
+		if err := s.applyTransfer(user, transfer, input); err != nil {
+			return err
+		}
+		if err := s.checkUnreconciled(loc, transfer.From, transfer.To); err != nil {
+			return err
+		}
+		if err := repos.Transactions.Update(transfer.From); err != nil {
+			return err
+		}
+		return repos.Transactions.Update(transfer.To)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// DeleteTransfer deletes both sides of the transfer that the transaction with
+// the given ID belongs to.
+func (s *TransactionService) DeleteTransfer(username string, id uint) error {
+	user, err := s.UserRepo.FindByUsername(username)
+	if err != nil {
+		return err
+	}
+
+	loc, err := s.location(user.ID)
+	if err != nil {
+		return err
+	}
+
+	return s.UnitOfWork.Do(func(repos Repositories) error {
+		transfer, err := findTransferForUpdate(repos.Transactions, user, id)
+		if err != nil {
+			return err
+		}
+		if err := s.checkUnreconciled(loc, transfer.From, transfer.To); err != nil {
+			return err
+		}
+		return deleteTransfer(repos.Transactions, transfer)
+	})
+}
+
+// applyTransfer validates input and copies it onto both sides of transfer.
+func (s *TransactionService) applyTransfer(user *models.User, transfer *Transfer, input TransferInput) error {
+	if input.FromAccountID == 0 || input.ToAccountID == 0 {
+		return fmt.Errorf("%w: from_account_id and to_account_id are required", ErrInvalidTransfer)
+	}
+	if input.FromAccountID == input.ToAccountID {
+		return fmt.Errorf("%w: cannot transfer to the same account", ErrInvalidTransfer)
+	}
+	if input.Amount <= 0 || input.ToAmount < 0 {
+		return fmt.Errorf("%w: amount must be positive", ErrInvalidTransfer)
+	}
+
+	from, err := s.resolveAccount(user, input.FromAccountID)
+	if err != nil {
+		return err
+	}
+	to, err := s.resolveAccount(user, input.ToAccountID)
+	if err != nil {
+		return err
+	}
+
+	category, err := s.transferCategory(user, input.CategoryID)
+	if err != nil {
+		return err
+	}
+
+	toAmount := input.ToAmount
+	switch {
+	case from.Currency == to.Currency && toAmount != 0 && toAmount != input.Amount:
+		return fmt.Errorf("%w: to_amount must equal amount between accounts in the same currency", ErrInvalidTransfer)
+	case toAmount == 0:
+		toAmount, err = s.Converter.Convert(input.Amount, from.Currency, to.Currency, input.TransactionDate)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, leg := range []struct {
+		transaction *models.Transaction
+		account     *models.Account
+		amount      money.Amount
+	}{{transfer.From, from, input.Amount}, {transfer.To, to, toAmount}} {
+		leg.transaction.AccountID = accountIDOf(leg.account)
+		leg.transaction.CategoryID = category.ID
+		leg.transaction.Amount = leg.amount
+		leg.transaction.Currency = leg.account.Currency
+		leg.transaction.Description = input.Description
+		leg.transaction.Notes = input.Notes
+		leg.transaction.TransactionDate = input.TransactionDate
+		if err := s.applyBaseAmount(user.ID, leg.transaction); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// transferCategory returns the category to book a transfer against: the
+// given one, or the Transfers category, which is created the first time it
+// is needed.
+func (s *TransactionService) transferCategory(user *models.User, categoryID uint) (*models.Category, error) {
+	if categoryID != 0 {
+		return s.resolveCategory(user, categoryID, models.TransactionTypeTransfer)
+	}
+	return s.findOrCreateCategory(user, constants.DefaultTransferCategoryName, "Default category for transfers between accounts", models.CategoryKindExpense)
+}
+
+// findTransferForUpdate locks both sides of the transfer that the transaction
+// with the given ID belongs to.
+func findTransferForUpdate(repo TransactionRepository, user *models.User, id uint) (*Transfer, error) {
+	leg, err := repo.FindByIDForUpdate(id)
+	if err := checkOwnership(user, leg, err); err != nil {
+		return nil, err
+	}
+	if leg.TransferID == nil {
+		return nil, fmt.Errorf("%w: transaction %d is not part of a transfer", ErrInvalidTransfer, id)
+	}
+
+	peer, err := repo.FindByIDForUpdate(*leg.TransferID)
+	if err := checkOwnership(user, peer, err); err != nil {
+		return nil, err
+	}
+	return pairOf(leg, peer), nil
+}
+
+// deleteTransfer removes both sides of transfer.
+func deleteTransfer(repo TransactionRepository, transfer *Transfer) error {
+	for _, leg := range []*models.Transaction{transfer.From, transfer.To} {
+		if err := repo.DeleteByID(leg.ID); err != nil {
+			return err
+		}
+		if err := repo.ClearDuplicatesOf(leg.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pairOf orders the two sides of a transfer.
+func pairOf(leg, peer *models.Transaction) *Transfer {
+	if leg.TransferIn {
+		return &Transfer{From: peer, To: leg}
+	}
+	return &Transfer{From: leg, To: peer}
+}
+
+// findOrCreateCategory returns the user's category with the given name and
+// kind, creating it when the user has none. A same-named category of the
+// other kind is not used, since transactions could not be booked against it.
+func (s *TransactionService) findOrCreateCategory(user *models.User, name, description, kind string) (*models.Category, error) {
+	category, err := s.CategoryRepo.FindByNameKindAndUserID(name, kind, user.ID)
+	if err == nil {
+		return category, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	category = &models.Category{
+		UserID:      user.ID,
+		Name:        name,
+		Description: description,
+		Kind:        kind,
+	}
+	if err := s.CategoryRepo.Create(category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
@@ -0,0 +1,97 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+)
+
+var (
+	ErrInvalidType   = errors.New("invalid transaction type")
+	ErrInvalidAmount = errors.New("invalid transaction amount")
+)
+
+// ParseType returns the named type for a transaction being recorded,
+// defaulting to expense. Transfers are only recorded in pairs, by
+// CreateTransfer, so they are rejected here.
+func ParseType(name string) (string, error) {
+	kind, err := parseStoredType(name)
+	if err != nil {
+		return "", err
+	}
+	if kind == models.TransactionTypeTransfer {
+		return "", fmt.Errorf("%w: record transfers between two accounts as a transfer", ErrInvalidType)
+	}
+	return kind, nil
+}
+
+// parseStoredType returns the named type of a stored transaction, transfers
+// included, defaulting to expense.
+func parseStoredType(name string) (string, error) {
+	switch kind := strings.ToLower(strings.TrimSpace(name)); kind {
+	case "":
+		return models.TransactionTypeExpense, nil
+	case models.TransactionTypeExpense, models.TransactionTypeIncome, models.TransactionTypeTransfer, models.TransactionTypeRefund:
+		return kind, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidType, name)
+	}
+}
+
+// typeOrDefault treats transactions stored before types existed as
+// expenses.
+func typeOrDefault(kind string) string {
+	if kind == "" {
+		return models.TransactionTypeExpense
+	}
+	return kind
+}
+
+// affectsBudget reports whether transactions of the type count towards
+// budgets: expenses add to the spent amount and refunds take from it.
+func affectsBudget(kind string) bool {
+	switch typeOrDefault(kind) {
+	case models.TransactionTypeExpense, models.TransactionTypeRefund:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkMinorUnits checks that amount and the amounts of splits are whole
+// minor units of currency, so that a yen amount has no fraction.
+func checkMinorUnits(amount money.Amount, splits []models.TransactionSplit, currency money.Currency) error {
+	fits := amount.FitsCurrency(currency)
+	for _, split := range splits {
+		fits = fits && split.Amount.FitsCurrency(currency)
+	}
+	if !fits {
+		return fmt.Errorf("%w: %s amounts have %d decimal places", ErrInvalidAmount, currency, currency.Exponent())
+	}
+	return nil
+}
+
+// checkAmountAndCategory validates the amount of a transaction of type kind
+// and the kind of the category it is booked against. Income goes into income
+// categories, expenses and refunds into expense categories, and transfers
+// anywhere.
+func checkAmountAndCategory(kind string, amount money.Amount, category *models.Category) error {
+	if amount < 0 {
+		return fmt.Errorf("%w: amount cannot be negative; record money received as income or a refund", ErrInvalidAmount)
+	}
+
+	categoryKind := category.Kind
+	if categoryKind == "" {
+		categoryKind = models.CategoryKindExpense
+	}
+	switch {
+	case kind == models.TransactionTypeIncome && categoryKind != models.CategoryKindIncome:
+		return fmt.Errorf("%w: income must be booked against an income category", ErrInvalidCategory)
+	case (kind == models.TransactionTypeExpense || kind == models.TransactionTypeRefund) && categoryKind != models.CategoryKindExpense:
+		return fmt.Errorf("%w: %s must be booked against an expense category", ErrInvalidCategory, kind)
+	}
+	return nil
+}
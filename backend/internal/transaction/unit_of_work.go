@@ -0,0 +1,41 @@
+package transaction
+
+import (
+	"github.com/shaikhjunaidx/pennywise-backend/internal/budget"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/user"
+	"gorm.io/gorm"
+)
+
+// Repositories are handed to a unit of work callback. They all share the same
+// database transaction.
+type Repositories struct {
+	Transactions TransactionRepository
+	Budgets      budget.BudgetRepository
+	Settings     user.SettingsRepository
+}
+
+// UnitOfWork runs fn atomically: either every write made through the given
+// repositories is committed or none of them is.
+type UnitOfWork interface {
+	Do(fn func(repos Repositories) error) error
+}
+
+type GormUnitOfWork struct {
+	DB *gorm.DB
+}
+
+var _ UnitOfWork = (*GormUnitOfWork)(nil)
+
+func NewUnitOfWork(db *gorm.DB) *GormUnitOfWork {
+	return &GormUnitOfWork{DB: db}
+}
+
+func (u *GormUnitOfWork) Do(fn func(repos Repositories) error) error {
+	return u.DB.Transaction(func(tx *gorm.DB) error {
+		return fn(Repositories{
+			Transactions: NewTransactionRepository(tx),
+			Budgets:      budget.NewBudgetRepository(tx),
+			Settings:     user.NewSettingsRepository(tx),
+		})
+	})
+}
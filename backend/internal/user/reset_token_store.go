@@ -0,0 +1,87 @@
+package user
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrInvalidResetToken = errors.New("invalid or expired token")
+
+// ResetTokenStore persists password reset tokens. Implementations only keep a
+// hash of each token and must allow a token to be consumed at most once.
+type ResetTokenStore interface {
+	Save(token *PasswordResetToken) error
+	Consume(token string) (*PasswordResetToken, error)
+	// Redeem consumes token and calls apply with it as one unit: if apply
+	// fails, the token stays valid. apply writes through users, which a
+	// store backed by a database replaces with a repository bound to the
+	// transaction the token is consumed in.
+	Redeem(token string, users UserRepository, apply func(resetToken *PasswordResetToken, users UserRepository) error) error
+}
+
+// InMemoryResetTokenStore keeps reset tokens in process memory. It is meant for
+// tests and single-instance development setups.
+type InMemoryResetTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]PasswordResetToken
+}
+
+var _ ResetTokenStore = (*InMemoryResetTokenStore)(nil)
+
+func NewInMemoryResetTokenStore() *InMemoryResetTokenStore {
+	return &InMemoryResetTokenStore{tokens: make(map[string]PasswordResetToken)}
+}
+
+func (s *InMemoryResetTokenStore) Save(token *PasswordResetToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[HashResetToken(token.Token)] = PasswordResetToken{
+		UserEmail: token.UserEmail,
+		ExpiresAt: token.ExpiresAt,
+	}
+	return nil
+}
+
+func (s *InMemoryResetTokenStore) Consume(token string) (*PasswordResetToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.consume(token)
+}
+
+// Redeem holds the store's lock while apply runs and puts the token back if
+// it fails.
+func (s *InMemoryResetTokenStore) Redeem(token string, users UserRepository, apply func(resetToken *PasswordResetToken, users UserRepository) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resetToken, err := s.consume(token)
+	if err != nil {
+		return err
+	}
+	if err := apply(resetToken, users); err != nil {
+		s.tokens[HashResetToken(token)] = PasswordResetToken{UserEmail: resetToken.UserEmail, ExpiresAt: resetToken.ExpiresAt}
+		return err
+	}
+	return nil
+}
+
+// consume removes token from the store, which must be locked.
+func (s *InMemoryResetTokenStore) consume(token string) (*PasswordResetToken, error) {
+	hash := HashResetToken(token)
+	resetToken, exists := s.tokens[hash]
+	if !exists {
+		return nil, ErrInvalidResetToken
+	}
+
+	delete(s.tokens, hash)
+
+	if time.Now().After(resetToken.ExpiresAt) {
+		return nil, ErrInvalidResetToken
+	}
+
+	resetToken.Token = token
+	return &resetToken, nil
+}
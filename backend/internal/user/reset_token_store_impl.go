@@ -0,0 +1,66 @@
+package user
+
+import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"gorm.io/gorm"
+)
+
+type GormResetTokenStore struct {
+	DB *gorm.DB
+}
+
+var _ ResetTokenStore = (*GormResetTokenStore)(nil)
+
+func NewGormResetTokenStore(db *gorm.DB) *GormResetTokenStore {
+	return &GormResetTokenStore{DB: db}
+}
+
+func (s *GormResetTokenStore) Save(token *PasswordResetToken) error {
+	return s.DB.Create(&models.PasswordResetToken{
+		UserEmail: token.UserEmail,
+		TokenHash: HashResetToken(token.Token),
+		ExpiresAt: token.ExpiresAt,
+	}).Error
+}
+
+// Consume marks the token as used with a conditional update so that two
+// concurrent requests cannot both redeem it.
+func (s *GormResetTokenStore) Consume(token string) (*PasswordResetToken, error) {
+	var record models.PasswordResetToken
+
+	if err := s.DB.Where("token_hash = ?", HashResetToken(token)).First(&record).Error; err != nil {
+		return nil, ErrInvalidResetToken
+	}
+
+	now := time.Now()
+	result := s.DB.Model(&models.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", record.ID, now).
+		Update("used_at", now)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrInvalidResetToken
+	}
+
+	return &PasswordResetToken{
+		Token:     token,
+		UserEmail: record.UserEmail,
+		ExpiresAt: record.ExpiresAt,
+	}, nil
+}
+
+// Redeem consumes the token and runs apply in one database transaction, so
+// that the token is only used up if apply succeeds.
+func (s *GormResetTokenStore) Redeem(token string, _ UserRepository, apply func(resetToken *PasswordResetToken, users UserRepository) error) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		resetToken, err := NewGormResetTokenStore(tx).Consume(token)
+		if err != nil {
+			return err
+		}
+		return apply(resetToken, NewUserRepository(tx))
+	})
+}
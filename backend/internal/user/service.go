@@ -2,9 +2,11 @@ package user
 
 import (
 	"errors"
+	"log"
 	"time"
 
 	"github.com/shaikhjunaidx/pennywise-backend/internal/constants"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/notify"
 	"github.com/shaikhjunaidx/pennywise-backend/models"
 )
 
@@ -12,21 +14,43 @@ type UserService struct {
 	Repo            UserRepository
 	CategoryService UserSignUpCategoryService
 	BudgetService   UserSignUpBudgetService
+	ResetTokens     ResetTokenStore
+	Notifier        notify.Notifier
+	Settings        SettingsRepository
+	CurrencyChanges BaseCurrencyListener
+	TimeZoneChanges TimeZoneListener
 }
 
+var ErrEmailNotFound = errors.New("email not found")
+
+// ResetTokenTTL is how long a password reset token stays valid.
+const ResetTokenTTL = 1 * time.Hour
+
+// NewUserService returns a service that keeps password reset tokens in
+// memory; set ResetTokens to keep them in the database instead.
 func NewUserService(repo UserRepository, categoryService UserSignUpCategoryService, budgetService UserSignUpBudgetService) *UserService {
 	return &UserService{
 		Repo:            repo,
 		CategoryService: categoryService,
 		BudgetService:   budgetService,
+		ResetTokens:     NewInMemoryResetTokenStore(),
 	}
 }
 
-// In-memory map that stores active reset tokens
-var passwordResetTokens = make(map[string]*PasswordResetToken)
-
 // SignUp registers a new user with a hashed password
 func (s *UserService) SignUp(username, email, password string) (*models.User, error) {
+	return s.SignUpInTimeZone(username, email, password, "")
+}
+
+// SignUpInTimeZone registers a new user whose calendar follows timeZone, so
+// that their first budget is for the month it is where they are. An empty
+// timeZone means the server's zone.
+func (s *UserService) SignUpInTimeZone(username, email, password, timeZone string) (*models.User, error) {
+	timeZone, err := parseTimeZone(timeZone)
+	if err != nil {
+		return nil, err
+	}
+
 	hashedPassword, err := HashPassword(password)
 	if err != nil {
 		return nil, err
@@ -42,10 +66,20 @@ func (s *UserService) SignUp(username, email, password string) (*models.User, er
 		return nil, err
 	}
 
+	if timeZone != "" && s.Settings != nil {
+		settings := defaultSettings(user.ID)
+		settings.TimeZone = timeZone
+		if err := s.Settings.Save(settings); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := s.addDefaultCategoryAndBudget(user); err != nil {
 		return nil, err
 	}
 
+	s.sendWelcome(user)
+
 	return user, nil
 }
 
@@ -57,8 +91,14 @@ func (s *UserService) addDefaultCategoryAndBudget(user *models.User) error {
 		return err
 	}
 
-	// Add the default budget
-	_, err = s.BudgetService.CreateBudget(user.Username, &category.ID, 0.0, time.Now().Format("01"), time.Now().Year())
+	settings, err := s.SettingsForUserID(user.ID)
+	if err != nil {
+		return err
+	}
+
+	// Add the default budget for the user's current month
+	now := time.Now().In(Location(settings))
+	_, err = s.BudgetService.CreateBudget(user.Username, &category.ID, 0.0, now.Format("01"), now.Year())
 	if err != nil {
 		return err
 	}
@@ -66,6 +106,18 @@ func (s *UserService) addDefaultCategoryAndBudget(user *models.User) error {
 	return nil
 }
 
+// sendWelcome emails the new user. Delivery problems are logged rather than
+// failing the signup.
+func (s *UserService) sendWelcome(user *models.User) {
+	msg, err := notify.WelcomeMessage(user.Email, notify.WelcomeData{Username: user.Username})
+	if err == nil {
+		err = s.notifier().Send(msg)
+	}
+	if err != nil {
+		log.Printf("Failed to send welcome email to %s: %v", user.Email, err)
+	}
+}
+
 // Login authenticates a user based on username and password
 func (s *UserService) Login(username, password string) (string, error) {
 	user, err := s.Repo.FindByUsername(username)
@@ -84,49 +136,72 @@ func (s *UserService) Login(username, password string) (string, error) {
 	return token, nil
 }
 
-// RequestPasswordReset generates a password reset token for the user
-func (s *UserService) RequestPasswordReset(email string) (string, error) {
+// notifier returns the service's notifier, or one that drops messages when
+// none is configured.
+func (s *UserService) notifier() notify.Notifier {
+	if s.Notifier == nil {
+		return notify.NopNotifier{}
+	}
+	return s.Notifier
+}
+
+// RequestPasswordReset generates a password reset token for the user and
+// sends it to their email address. Delivery problems are logged rather than
+// returned, so that callers cannot tell them apart from success.
+func (s *UserService) RequestPasswordReset(email string) error {
 	user, err := s.Repo.FindByEmail(email)
 	if err != nil {
-		return "", errors.New("email not found")
+		return ErrEmailNotFound
 	}
 
 	token, err := GenerateResetToken()
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	StoreResetToken(token, user.Email)
-
-	// Placeholder for sending the token via email in the future
-	return token, nil
-}
+	resetToken := &PasswordResetToken{
+		Token:     token,
+		UserEmail: user.Email,
+		ExpiresAt: time.Now().Add(ResetTokenTTL),
+	}
 
-// ResetPassword allows the user to reset their password using a valid token
-func (s *UserService) ResetPassword(token, newPassword string) error {
-	resetToken, err := ValidateResetToken(token)
-	if err != nil {
+	if err := s.ResetTokens.Save(resetToken); err != nil {
 		return err
 	}
 
-	user, err := s.Repo.FindByEmail(resetToken.UserEmail)
+	msg, err := notify.PasswordResetMessage(user.Email, notify.PasswordResetData{
+		Username:  user.Username,
+		Token:     resetToken.Token,
+		ExpiresAt: resetToken.ExpiresAt,
+	})
 	if err != nil {
 		return err
 	}
 
-	hashedPassword, err := HashPassword(newPassword)
-	if err != nil {
-		return err
+	if err := s.notifier().Send(msg); err != nil {
+		log.Printf("Failed to send password reset email to %s: %v", user.Email, err)
 	}
+	return nil
+}
 
-	user.PasswordHash = hashedPassword
-	if err := s.Repo.Update(user); err != nil {
+// ResetPassword allows the user to reset their password using a valid token.
+// The token is consumed together with the password change, so a failed
+// change leaves it usable.
+func (s *UserService) ResetPassword(token, newPassword string) error {
+	hashedPassword, err := HashPassword(newPassword)
+	if err != nil {
 		return err
 	}
 
-	InvalidateResetToken(token)
+	return s.ResetTokens.Redeem(token, s.Repo, func(resetToken *PasswordResetToken, users UserRepository) error {
+		user, err := users.FindByEmail(resetToken.UserEmail)
+		if err != nil {
+			return err
+		}
 
-	return nil
+		user.PasswordHash = hashedPassword
+		return users.Update(user)
+	})
 }
 
 func (s *UserService) FindByUsername(username string) (*models.User, error) {
@@ -0,0 +1,218 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"gorm.io/gorm"
+)
+
+// Actions taken when an imported transaction looks like one already stored.
+const (
+	DuplicateActionFlag  = "flag"
+	DuplicateActionSkip  = "skip"
+	DuplicateActionAllow = "allow"
+)
+
+// DefaultDuplicateWindowDays is the duplicate match window for users who
+// have not chosen one.
+const DefaultDuplicateWindowDays = 3
+
+// maxDuplicateWindowDays bounds the window so a match stays plausible.
+const maxDuplicateWindowDays = 31
+
+// DefaultLocale and DefaultFirstDayOfWeek apply to users who have not chosen
+// their own.
+const (
+	DefaultLocale         = "en-US"
+	DefaultFirstDayOfWeek = int(time.Monday)
+)
+
+// localePattern accepts BCP 47 language tags such as "en", "en-GB" or
+// "zh-Hant-TW".
+var localePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+var ErrInvalidSettings = errors.New("invalid settings")
+
+// SettingsUpdate lists the settings a user can change. Nil fields are left
+// as they are.
+type SettingsUpdate struct {
+	CarryOverBudgetLimits *bool   `json:"carry_over_budget_limits,omitempty"`
+	BaseCurrency          *string `json:"base_currency,omitempty" example:"EUR"`
+
+	DuplicateWindowDays        *int    `json:"duplicate_window_days,omitempty" example:"3"`
+	DuplicateIgnoreDescription *bool   `json:"duplicate_ignore_description,omitempty"`
+	DuplicateAction            *string `json:"duplicate_action,omitempty" enums:"flag,skip,allow" example:"skip"`
+
+	TimeZone       *string `json:"time_zone,omitempty" example:"America/New_York"`
+	Locale         *string `json:"locale,omitempty" example:"en-GB"`
+	FirstDayOfWeek *int    `json:"first_day_of_week,omitempty" minimum:"0" maximum:"6" example:"0"`
+}
+
+// defaultSettings returns the settings used for users who have never saved
+// any.
+func defaultSettings(userID uint) *models.UserSettings {
+	return &models.UserSettings{
+		UserID:                userID,
+		CarryOverBudgetLimits: false,
+		BaseCurrency:          money.DefaultCurrency,
+		DuplicateWindowDays:   DefaultDuplicateWindowDays,
+		DuplicateAction:       DuplicateActionFlag,
+		Locale:                DefaultLocale,
+		FirstDayOfWeek:        DefaultFirstDayOfWeek,
+	}
+}
+
+// Location returns the time zone the user's calendar follows. Users without
+// a valid zone follow the server's.
+func Location(settings *models.UserSettings) *time.Location {
+	if settings == nil || settings.TimeZone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(settings.TimeZone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// WeekStart returns the day the user's weeks start on.
+func WeekStart(settings *models.UserSettings) time.Weekday {
+	if settings == nil || settings.FirstDayOfWeek < 0 || settings.FirstDayOfWeek > 6 {
+		return time.Weekday(DefaultFirstDayOfWeek)
+	}
+	return time.Weekday(settings.FirstDayOfWeek)
+}
+
+// parseTimeZone checks that name is a time zone the server knows. The empty
+// name stands for the server's own zone.
+func parseTimeZone(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil
+	}
+	if name == "Local" {
+		return "", fmt.Errorf("%w: time_zone must be an IANA zone name", ErrInvalidSettings)
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return "", fmt.Errorf("%w: unknown time_zone %q", ErrInvalidSettings, name)
+	}
+	return name, nil
+}
+
+// SettingsForUserID returns the user's saved settings, or the defaults when
+// none have been saved or no settings store is configured.
+func (s *UserService) SettingsForUserID(userID uint) (*models.UserSettings, error) {
+	if s.Settings == nil {
+		return defaultSettings(userID), nil
+	}
+
+	settings, err := s.Settings.FindByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return defaultSettings(userID), nil
+		}
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (s *UserService) GetSettings(username string) (*models.UserSettings, error) {
+	user, err := s.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.SettingsForUserID(user.ID)
+}
+
+// UpdateSettings applies the update and saves it. Changing the base currency
+// saves the settings together with the user's converted amounts, so a failed
+// conversion saves nothing. Changing the time zone rebuilds the budgets on
+// the new calendar; if that fails the previous zone is restored.
+func (s *UserService) UpdateSettings(username string, update SettingsUpdate) (*models.UserSettings, error) {
+	if s.Settings == nil {
+		return nil, errors.New("settings store not configured")
+	}
+
+	settings, err := s.GetSettings(username)
+	if err != nil {
+		return nil, err
+	}
+
+	previousBase := settings.BaseCurrency
+	previousTimeZone := settings.TimeZone
+
+	if update.CarryOverBudgetLimits != nil {
+		settings.CarryOverBudgetLimits = *update.CarryOverBudgetLimits
+	}
+	if update.BaseCurrency != nil {
+		base, err := money.ParseCurrency(*update.BaseCurrency)
+		if err != nil {
+			return nil, err
+		}
+		settings.BaseCurrency = base
+	}
+	if update.DuplicateWindowDays != nil {
+		if *update.DuplicateWindowDays < 0 || *update.DuplicateWindowDays > maxDuplicateWindowDays {
+			return nil, fmt.Errorf("%w: duplicate_window_days must be between 0 and %d", ErrInvalidSettings, maxDuplicateWindowDays)
+		}
+		settings.DuplicateWindowDays = *update.DuplicateWindowDays
+	}
+	if update.DuplicateIgnoreDescription != nil {
+		settings.DuplicateIgnoreDescription = *update.DuplicateIgnoreDescription
+	}
+	if update.DuplicateAction != nil {
+		switch action := strings.ToLower(*update.DuplicateAction); action {
+		case DuplicateActionFlag, DuplicateActionSkip, DuplicateActionAllow:
+			settings.DuplicateAction = action
+		default:
+			return nil, fmt.Errorf("%w: duplicate_action must be flag, skip or allow", ErrInvalidSettings)
+		}
+	}
+	if update.TimeZone != nil {
+		timeZone, err := parseTimeZone(*update.TimeZone)
+		if err != nil {
+			return nil, err
+		}
+		settings.TimeZone = timeZone
+	}
+	if update.Locale != nil {
+		locale := strings.TrimSpace(*update.Locale)
+		if !localePattern.MatchString(locale) {
+			return nil, fmt.Errorf("%w: locale must be a language tag such as en-US", ErrInvalidSettings)
+		}
+		settings.Locale = locale
+	}
+	if update.FirstDayOfWeek != nil {
+		if *update.FirstDayOfWeek < 0 || *update.FirstDayOfWeek > 6 {
+			return nil, fmt.Errorf("%w: first_day_of_week must be between 0 (Sunday) and 6 (Saturday)", ErrInvalidSettings)
+		}
+		settings.FirstDayOfWeek = *update.FirstDayOfWeek
+	}
+
+	if settings.BaseCurrency != previousBase && s.CurrencyChanges != nil {
+		if err := s.CurrencyChanges.BaseCurrencyChanged(settings); err != nil {
+			return nil, err
+		}
+	} else if err := s.Settings.Save(settings); err != nil {
+		return nil, err
+	}
+
+	if settings.TimeZone != previousTimeZone && s.TimeZoneChanges != nil {
+		if err := s.TimeZoneChanges.TimeZoneChanged(settings.UserID); err != nil {
+			settings.TimeZone = previousTimeZone
+			if restoreErr := s.Settings.Save(settings); restoreErr != nil {
+				return nil, errors.Join(err, restoreErr)
+			}
+			return nil, err
+		}
+	}
+
+	return settings, nil
+}
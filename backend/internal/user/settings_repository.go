@@ -0,0 +1,8 @@
+package user
+
+import "github.com/shaikhjunaidx/pennywise-backend/models"
+
+type SettingsRepository interface {
+	FindByUserID(userID uint) (*models.UserSettings, error)
+	Save(settings *models.UserSettings) error
+}
@@ -0,0 +1,26 @@
+package user
+
+import (
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"gorm.io/gorm"
+)
+
+type SettingsRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewSettingsRepository(db *gorm.DB) *SettingsRepositoryImpl {
+	return &SettingsRepositoryImpl{DB: db}
+}
+
+func (r *SettingsRepositoryImpl) FindByUserID(userID uint) (*models.UserSettings, error) {
+	var settings models.UserSettings
+	if err := r.DB.Where("user_id = ?", userID).First(&settings).Error; err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *SettingsRepositoryImpl) Save(settings *models.UserSettings) error {
+	return r.DB.Save(settings).Error
+}
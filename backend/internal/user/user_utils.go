@@ -2,6 +2,7 @@ package user
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"log"
@@ -70,25 +71,9 @@ func GenerateResetToken() (string, error) {
 	return hex.EncodeToString(b), nil
 }
 
-// Stores the reset token in the in-memory map.
-func StoreResetToken(token, email string) {
-	passwordResetTokens[token] = &PasswordResetToken{
-		Token:     token,
-		UserEmail: email,
-		ExpiresAt: time.Now().Add(1 * time.Hour),
-	}
-}
-
-// Validates the reset token, checking its existence and expiration.
-func ValidateResetToken(token string) (*PasswordResetToken, error) {
-	resetToken, exists := passwordResetTokens[token]
-	if !exists || time.Now().After(resetToken.ExpiresAt) {
-		return nil, errors.New("invalid or expired token")
-	}
-	return resetToken, nil
-}
-
-// Removes the token from the in-memory map after it has been used.
-func InvalidateResetToken(token string) {
-	delete(passwordResetTokens, token)
+// HashResetToken returns the hex encoded SHA-256 digest that is persisted in
+// place of the raw reset token.
+func HashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
@@ -1,6 +1,9 @@
 package user
 
-import "github.com/shaikhjunaidx/pennywise-backend/models"
+import (
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+)
 
 type UserSignUpCategoryService interface {
 	AddCategory(username, name, description string) (*models.Category, error)
@@ -8,5 +11,18 @@ type UserSignUpCategoryService interface {
 }
 
 type UserSignUpBudgetService interface {
-	CreateBudget(username string, categoryID *uint, amountLimit float64, month string, year int) (*models.Budget, error)
+	CreateBudget(username string, categoryID *uint, amountLimit money.Amount, month string, year int) (*models.Budget, error)
+}
+
+// BaseCurrencyListener is told when a user switches base currency. It saves
+// the settings together with the converted base amounts, so that neither
+// is stored without the other.
+type BaseCurrencyListener interface {
+	BaseCurrencyChanged(settings *models.UserSettings) error
+}
+
+// TimeZoneListener is told when a user moves to another time zone so that
+// month totals can be rebuilt on the new calendar.
+type TimeZoneListener interface {
+	TimeZoneChanged(userID uint) error
 }
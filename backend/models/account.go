@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+)
+
+// Account types.
+const (
+	AccountTypeChecking   = "checking"
+	AccountTypeSavings    = "savings"
+	AccountTypeCreditCard = "credit_card"
+	AccountTypeCash       = "cash"
+)
+
+// Account is where money is held, such as a bank account, a credit card or a
+// wallet. Its balance is OpeningBalance plus the transactions booked to it,
+// in Currency; a credit card that is owed money has a negative balance.
+// ReconciledThrough is the last statement date the balance was matched
+// against, and ReconciledBalance the balance on that date.
+type Account struct {
+	ID                uint           `json:"id" gorm:"primaryKey"`
+	UserID            uint           `json:"user_id" gorm:"not null;index"`
+	User              User           `json:"-" gorm:"foreignKey:UserID"`
+	Name              string         `json:"name" gorm:"size:100;not null" example:"Everyday Checking"`
+	Type              string         `json:"type" gorm:"size:20;not null;default:checking" enums:"checking,savings,credit_card,cash" example:"checking"`
+	Currency          money.Currency `json:"currency" gorm:"size:3;not null;default:USD" swaggertype:"string" example:"USD"`
+	OpeningBalance    money.Amount   `json:"opening_balance" gorm:"not null;default:0" swaggertype:"number" example:"1000.00"`
+	ReconciledBalance money.Amount   `json:"reconciled_balance" gorm:"not null;default:0" swaggertype:"number"`
+	ReconciledThrough *time.Time     `json:"reconciled_through,omitempty"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
+
+// Reconciliation records that an account's balance matched a bank statement
+// ending on StatementDate.
+type Reconciliation struct {
+	ID               uint         `json:"id" gorm:"primaryKey"`
+	AccountID        uint         `json:"account_id" gorm:"not null;index"`
+	Account          Account      `json:"-" gorm:"foreignKey:AccountID"`
+	StatementDate    time.Time    `json:"statement_date" gorm:"not null"`
+	StatementBalance money.Amount `json:"statement_balance" gorm:"not null" swaggertype:"number"`
+	CreatedAt        time.Time    `json:"created_at"`
+}
@@ -1,18 +1,27 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+)
 
 type Budget struct {
-	ID              uint      `json:"id" gorm:"primaryKey"`
-	UserID          uint      `json:"user_id" gorm:"not null"`
-	User            User      `json:"-" gorm:"foreignKey:UserID"`
-	CategoryID      *uint     `json:"category_id,omitempty"`
-	Category        Category  `json:"-" gorm:"foreignKey:CategoryID"`
-	AmountLimit     float64   `json:"amount_limit" gorm:"not null"`
-	SpentAmount     float64   `json:"spent_amount" gorm:"not null"`
-	RemainingAmount float64   `json:"remaining_amount" gorm:"not null"`
-	BudgetMonth     string    `json:"budget_month" gorm:"size:2;not null"`
-	BudgetYear      int       `json:"budget_year" gorm:"not null"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID               uint           `json:"id" gorm:"primaryKey"`
+	UserID           uint           `json:"user_id" gorm:"not null"`
+	User             User           `json:"-" gorm:"foreignKey:UserID"`
+	CategoryID       *uint          `json:"category_id,omitempty"`
+	Category         Category       `json:"-" gorm:"foreignKey:CategoryID"`
+	AmountLimit      money.Amount   `json:"amount_limit" gorm:"not null" swaggertype:"number"`
+	SpentAmount      money.Amount   `json:"spent_amount" gorm:"not null" swaggertype:"number"`
+	RemainingAmount  money.Amount   `json:"remaining_amount" gorm:"not null" swaggertype:"number"`
+	Currency         money.Currency `json:"currency" gorm:"size:3;not null;default:USD" swaggertype:"string" example:"USD"`
+	CarryOverAmount  money.Amount   `json:"carry_over_amount" gorm:"not null;default:0" swaggertype:"number"`
+	RolloverPositive bool           `json:"rollover_positive" gorm:"not null;default:false"`
+	RolloverNegative bool           `json:"rollover_negative" gorm:"not null;default:false"`
+	RolledOver       bool           `json:"rolled_over" gorm:"not null;default:false"`
+	BudgetMonth      string         `json:"budget_month" gorm:"size:2;not null"`
+	BudgetYear       int            `json:"budget_year" gorm:"not null"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
 }
@@ -2,12 +2,24 @@ package models
 
 import "time"
 
+// Category kinds. Income is booked against income categories and spending
+// against expense categories.
+const (
+	CategoryKindExpense = "expense"
+	CategoryKindIncome  = "income"
+)
+
+// Category groups transactions and budgets. ParentID files it under another
+// of the user's categories of the same kind, such as Groceries under Food; a
+// budget for the parent counts the spending of all its subcategories.
 type Category struct {
 	ID          uint      `json:"id" gorm:"primaryKey"`
 	UserID      uint      `json:"user_id" gorm:"not null"`
 	User        User      `json:"-" gorm:"foreignKey:UserID"`
+	ParentID    *uint     `json:"parent_id,omitempty" gorm:"index"`
 	Name        string    `json:"name" gorm:"not null"`
 	Description string    `json:"description,omitempty"`
+	Kind        string    `json:"kind" gorm:"size:10;not null;default:expense" enums:"expense,income" example:"expense"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
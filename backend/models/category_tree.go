@@ -0,0 +1,67 @@
+package models
+
+import "sort"
+
+// CategoryTree maps each of a user's subcategories to its parent category.
+// Top-level categories do not appear as keys. Budgets use it to roll spending
+// up to parent categories, and categories to keep the tree free of cycles.
+type CategoryTree map[uint]uint
+
+// NewCategoryTree builds the tree the given categories form.
+func NewCategoryTree(categories []*Category) CategoryTree {
+	tree := make(CategoryTree)
+	for _, category := range categories {
+		if category.ParentID != nil {
+			tree[category.ID] = *category.ParentID
+		}
+	}
+	return tree
+}
+
+// Ancestors returns the categories above id, nearest first.
+func (t CategoryTree) Ancestors(id uint) []uint {
+	var ancestors []uint
+	seen := map[uint]bool{id: true}
+	for parent, ok := t[id]; ok && !seen[parent]; parent, ok = t[parent] {
+		seen[parent] = true
+		ancestors = append(ancestors, parent)
+	}
+	return ancestors
+}
+
+// IsAncestor reports whether ancestor is somewhere above id.
+func (t CategoryTree) IsAncestor(ancestor, id uint) bool {
+	for _, above := range t.Ancestors(id) {
+		if above == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// Children returns the categories directly under id, in ID order.
+func (t CategoryTree) Children(id uint) []uint {
+	var children []uint
+	for child, parent := range t {
+		if parent == id {
+			children = append(children, child)
+		}
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i] < children[j] })
+	return children
+}
+
+// Subtree returns id and every category below it.
+func (t CategoryTree) Subtree(id uint) []uint {
+	ids := []uint{id}
+	seen := map[uint]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range t.Children(ids[i]) {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids
+}
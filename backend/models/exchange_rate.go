@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+)
+
+// ExchangeRate records that one unit of BaseCurrency bought Rate units of
+// QuoteCurrency on RateDate.
+type ExchangeRate struct {
+	ID            uint           `json:"id" gorm:"primaryKey"`
+	BaseCurrency  money.Currency `json:"base_currency" gorm:"size:3;not null;uniqueIndex:idx_exchange_rate_pair_date" swaggertype:"string"`
+	QuoteCurrency money.Currency `json:"quote_currency" gorm:"size:3;not null;uniqueIndex:idx_exchange_rate_pair_date" swaggertype:"string"`
+	RateDate      time.Time      `json:"rate_date" gorm:"type:date;not null;uniqueIndex:idx_exchange_rate_pair_date"`
+	Rate          float64        `json:"rate" gorm:"not null"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+)
+
+// ImportMapping describes how the columns of one bank's CSV export map onto
+// transaction fields. Columns are matched against the header row by name,
+// ignoring case.
+type ImportMapping struct {
+	ID                uint           `json:"id" gorm:"primaryKey"`
+	UserID            uint           `json:"user_id" gorm:"not null;uniqueIndex:idx_import_mapping_user_name"`
+	Name              string         `json:"name" gorm:"size:100;not null;uniqueIndex:idx_import_mapping_user_name" example:"My Bank"`
+	Delimiter         string         `json:"delimiter" gorm:"size:1" example:","`
+	DateColumn        string         `json:"date_column" gorm:"size:100;not null" example:"Posting Date"`
+	DateFormat        string         `json:"date_format" gorm:"size:20" example:"DD/MM/YYYY"`
+	AmountColumn      string         `json:"amount_column" gorm:"size:100" example:"Amount"`
+	DebitColumn       string         `json:"debit_column" gorm:"size:100"`
+	CreditColumn      string         `json:"credit_column" gorm:"size:100"`
+	NegateAmounts     bool           `json:"negate_amounts" gorm:"not null;default:false"`
+	DescriptionColumn string         `json:"description_column" gorm:"size:100" example:"Description"`
+	CategoryColumn    string         `json:"category_column" gorm:"size:100"`
+	CurrencyColumn    string         `json:"currency_column" gorm:"size:100"`
+	ExternalIDColumn  string         `json:"external_id_column" gorm:"size:100" example:"Transaction ID"`
+	TypeColumn        string         `json:"type_column" gorm:"size:100"`
+	Currency          money.Currency `json:"currency" gorm:"size:3" swaggertype:"string" example:"USD"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+)
+
+// Recurrence frequencies.
+const (
+	FrequencyDaily   = "daily"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+	FrequencyYearly  = "yearly"
+)
+
+// RecurringRule is a transaction that repeats, such as rent or a
+// subscription. It occurs every Interval days, weeks, months or years, as
+// Frequency says, from StartDate until EndDate if there is one. Monthly and
+// yearly rules fall on DayOfMonth, or on StartDate's day when it is zero;
+// in shorter months they fall on the last day instead.
+// LastDate is the last occurrence that has been booked or skipped, and
+// NextDate the next one due, or nil once the rule has ended.
+type RecurringRule struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	UserID      uint           `json:"user_id" gorm:"not null;index"`
+	User        User           `json:"-" gorm:"foreignKey:UserID"`
+	Type        string         `json:"type" gorm:"size:10;not null;default:expense" enums:"expense,income,refund" example:"expense"`
+	CategoryID  uint           `json:"category_id" gorm:"not null"`
+	AccountID   *uint          `json:"account_id,omitempty"`
+	Amount      money.Amount   `json:"amount" gorm:"not null" swaggertype:"number" example:"15.49"`
+	Currency    money.Currency `json:"currency,omitempty" gorm:"size:3" swaggertype:"string" example:"USD"`
+	Description string         `json:"description" example:"Netflix"`
+	Notes       string         `json:"notes,omitempty" gorm:"type:text"`
+	Frequency   string         `json:"frequency" gorm:"size:10;not null" enums:"daily,weekly,monthly,yearly" example:"monthly"`
+	Interval    int            `json:"interval" gorm:"column:repeat_interval;not null;default:1" example:"1"`
+	DayOfMonth  int            `json:"day_of_month,omitempty" gorm:"not null;default:0" example:"31"`
+	StartDate   time.Time      `json:"start_date" gorm:"not null"`
+	EndDate     *time.Time     `json:"end_date,omitempty"`
+	LastDate    *time.Time     `json:"last_date,omitempty"`
+	NextDate    *time.Time     `json:"next_date,omitempty" gorm:"index"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// RecurringOccurrence records what happened to, or is to happen to, one
+// occurrence of a rule on Date. Skipped occurrences are not booked; Amount,
+// Description and Notes, when set, replace the rule's for this occurrence
+// only. TransactionID is the transaction the occurrence was booked as.
+type RecurringOccurrence struct {
+	ID              uint          `json:"id" gorm:"primaryKey"`
+	RecurringRuleID uint          `json:"recurring_rule_id" gorm:"not null;uniqueIndex:idx_recurring_occurrence"`
+	Date            time.Time     `json:"date" gorm:"not null;uniqueIndex:idx_recurring_occurrence"`
+	Skipped         bool          `json:"skipped" gorm:"not null;default:false"`
+	Amount          *money.Amount `json:"amount,omitempty" swaggertype:"number"`
+	Description     *string       `json:"description,omitempty"`
+	Notes           *string       `json:"notes,omitempty" gorm:"type:text"`
+	TransactionID   *uint         `json:"transaction_id,omitempty"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+}
@@ -1,16 +1,69 @@
 package models
 
-import "time"
+import (
+	"time"
 
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+)
+
+// Transaction types. Amounts are positive whatever the type; the type says
+// which way the money went. Budgets count expenses, less refunds; income and
+// transfers between the user's own accounts leave them alone.
+const (
+	TransactionTypeExpense  = "expense"
+	TransactionTypeIncome   = "income"
+	TransactionTypeTransfer = "transfer"
+	TransactionTypeRefund   = "refund"
+)
+
+// Transaction is a single amount spent, received or moved, as its Type says.
+// Notes is free text the user keeps alongside the description; both are
+// searchable. AccountID is the account the money came from or went to, when
+// the user tracks accounts.
+// ExternalID is the bank's identifier for imported transactions, such as the
+// OFX FITID. DuplicateOfID points at an earlier transaction this one probably
+// repeats, until the user dismisses the match.
+// A transfer between two of the user's accounts is stored as two linked
+// transfer transactions, one in each account, whose TransferID points at the
+// other. TransferIn marks the one that receives the money.
+// A transaction split across several categories, such as one receipt for
+// groceries and household goods, has Splits that sum to its Amount; it is then
+// filed under the first split's category.
 type Transaction struct {
-	ID              uint      `json:"id" gorm:"primaryKey"`
-	UserID          uint      `json:"user_id" gorm:"not null"`
-	User            User      `json:"-" gorm:"foreignKey:UserID"`
-	CategoryID      uint      `json:"category_id"`
-	Category        Category  `json:"-" gorm:"foreignKey:CategoryID"`
-	Amount          float64   `json:"amount" gorm:"not null"`
-	Description     string    `json:"description,omitempty"`
-	TransactionDate time.Time `json:"transaction_date" gorm:"not null"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID              uint               `json:"id" gorm:"primaryKey"`
+	UserID          uint               `json:"user_id" gorm:"not null"`
+	User            User               `json:"-" gorm:"foreignKey:UserID"`
+	CategoryID      uint               `json:"category_id"`
+	Category        Category           `json:"-" gorm:"foreignKey:CategoryID"`
+	AccountID       *uint              `json:"account_id,omitempty" gorm:"index"`
+	Account         *Account           `json:"-" gorm:"foreignKey:AccountID"`
+	Type            string             `json:"type" gorm:"size:10;not null;default:expense;index" enums:"expense,income,transfer,refund" example:"expense"`
+	Amount          money.Amount       `json:"amount" gorm:"not null" swaggertype:"number" example:"12.50"`
+	Currency        money.Currency     `json:"currency" gorm:"size:3;not null;default:USD" swaggertype:"string" example:"USD"`
+	BaseAmount      money.Amount       `json:"base_amount" gorm:"not null;default:0" swaggertype:"number" example:"12.50"`
+	Description     string             `json:"description,omitempty"`
+	Notes           string             `json:"notes,omitempty" gorm:"type:text"`
+	TransactionDate time.Time          `json:"transaction_date" gorm:"not null"`
+	ExternalID      string             `json:"external_id,omitempty" gorm:"size:255;index"`
+	DuplicateOfID   *uint              `json:"duplicate_of,omitempty" gorm:"index"`
+	TransferID      *uint              `json:"transfer_id,omitempty" gorm:"index"`
+	TransferIn      bool               `json:"transfer_in,omitempty" gorm:"not null;default:false"`
+	Splits          []TransactionSplit `json:"splits,omitempty" gorm:"foreignKey:TransactionID"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+}
+
+// TransactionSplit is the part of a split transaction booked against one
+// category. Amount is in the transaction's currency and BaseAmount in the
+// user's base currency; across the splits both add up to the transaction's.
+type TransactionSplit struct {
+	ID            uint         `json:"id" gorm:"primaryKey"`
+	TransactionID uint         `json:"transaction_id" gorm:"not null;index"`
+	CategoryID    uint         `json:"category_id" gorm:"not null;index"`
+	Category      Category     `json:"-" gorm:"foreignKey:CategoryID"`
+	Amount        money.Amount `json:"amount" gorm:"not null" swaggertype:"number" example:"42.10"`
+	BaseAmount    money.Amount `json:"base_amount" gorm:"not null;default:0" swaggertype:"number" example:"42.10"`
+	Description   string       `json:"description,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
 }
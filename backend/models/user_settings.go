@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+)
+
+// UserSettings holds per-user preferences. Users without a row get the
+// defaults returned by the user service.
+//
+// The Duplicate fields form the duplicate match policy: transactions with the
+// same amount and currency at most DuplicateWindowDays apart match when their
+// normalised descriptions agree or DuplicateIgnoreDescription is set.
+// DuplicateAction says whether imports flag, skip or allow such matches.
+//
+// TimeZone is an IANA zone name such as "Europe/Berlin"; budget months and
+// report periods follow the user's calendar in that zone. An empty TimeZone
+// means the server's zone. FirstDayOfWeek is 0 for Sunday through 6 for
+// Saturday. Locale is a BCP 47 tag that clients use to format dates and
+// amounts.
+type UserSettings struct {
+	ID                         uint           `json:"-" gorm:"primaryKey"`
+	UserID                     uint           `json:"-" gorm:"not null;uniqueIndex"`
+	CarryOverBudgetLimits      bool           `json:"carry_over_budget_limits" gorm:"not null;default:false"`
+	BaseCurrency               money.Currency `json:"base_currency" gorm:"size:3;not null;default:USD" swaggertype:"string" example:"USD"`
+	DuplicateWindowDays        int            `json:"duplicate_window_days" gorm:"not null" example:"3"`
+	DuplicateIgnoreDescription bool           `json:"duplicate_ignore_description" gorm:"not null;default:false"`
+	DuplicateAction            string         `json:"duplicate_action" gorm:"size:10;not null;default:flag" enums:"flag,skip,allow" example:"flag"`
+	TimeZone                   string         `json:"time_zone" gorm:"size:64;not null;default:''" example:"Europe/Berlin"`
+	Locale                     string         `json:"locale" gorm:"size:35;not null;default:en-US" example:"en-US"`
+	FirstDayOfWeek             int            `json:"first_day_of_week" gorm:"not null" minimum:"0" maximum:"6" example:"1"`
+	CreatedAt                  time.Time      `json:"-"`
+	UpdatedAt                  time.Time      `json:"updated_at"`
+}
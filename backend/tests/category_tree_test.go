@@ -0,0 +1,32 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategoryTree(t *testing.T) {
+	food, groceries, produce, restaurants := uint(1), uint(2), uint(3), uint(4)
+	tree := models.NewCategoryTree([]*models.Category{
+		{ID: food},
+		{ID: groceries, ParentID: &food},
+		{ID: produce, ParentID: &groceries},
+		{ID: restaurants, ParentID: &food},
+	})
+
+	assert.Equal(t, []uint{groceries, food}, tree.Ancestors(produce))
+	assert.Empty(t, tree.Ancestors(food))
+	assert.True(t, tree.IsAncestor(food, produce))
+	assert.False(t, tree.IsAncestor(restaurants, produce))
+	assert.Equal(t, []uint{groceries, restaurants}, tree.Children(food))
+	assert.Equal(t, []uint{food, groceries, restaurants, produce}, tree.Subtree(food))
+}
+
+func TestCategoryTree_StopsAtCycles(t *testing.T) {
+	tree := models.CategoryTree{1: 2, 2: 1}
+
+	assert.Equal(t, []uint{2}, tree.Ancestors(1))
+	assert.Equal(t, []uint{1, 2}, tree.Subtree(1))
+}
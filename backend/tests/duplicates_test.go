@@ -0,0 +1,56 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeDescription(t *testing.T) {
+	assert.Equal(t, "tesco stores", transaction.NormalizeDescription("  TESCO-STORES 3041 "))
+	assert.Equal(t, "card purchase amazon mktp", transaction.NormalizeDescription("CARD PURCHASE *AMAZON MKTP 12/10"))
+	assert.Equal(t, "", transaction.NormalizeDescription("1234"))
+}
+
+func TestDuplicatePolicy_Matches(t *testing.T) {
+	day := time.Date(2024, 10, 1, 12, 0, 0, 0, time.Local)
+	stored := &models.Transaction{ID: 1, Amount: money.FromFloat(23.40), Currency: "USD", Description: "TESCO STORES 3041", TransactionDate: day}
+	policy := transaction.DuplicatePolicy{WindowDays: 2}
+
+	cases := []struct {
+		name      string
+		candidate models.Transaction
+		policy    transaction.DuplicatePolicy
+		want      bool
+	}{
+		{"same purchase, next day", models.Transaction{Amount: money.FromFloat(23.40), Currency: "USD", Description: "Tesco Stores", TransactionDate: day.AddDate(0, 0, 1)}, policy, true},
+		{"outside window", models.Transaction{Amount: money.FromFloat(23.40), Currency: "USD", Description: "Tesco Stores", TransactionDate: day.AddDate(0, 0, 3)}, policy, false},
+		{"different amount", models.Transaction{Amount: money.FromFloat(23.41), Currency: "USD", Description: "Tesco Stores", TransactionDate: day}, policy, false},
+		{"different currency", models.Transaction{Amount: money.FromFloat(23.40), Currency: "EUR", Description: "Tesco Stores", TransactionDate: day}, policy, false},
+		{"different description", models.Transaction{Amount: money.FromFloat(23.40), Currency: "USD", Description: "Coffee", TransactionDate: day}, policy, false},
+		{"description ignored", models.Transaction{Amount: money.FromFloat(23.40), Currency: "USD", Description: "Coffee", TransactionDate: day}, transaction.DuplicatePolicy{WindowDays: 2, IgnoreDescription: true}, true},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.want, c.policy.Matches(&c.candidate, stored), c.name)
+	}
+}
+
+func TestDuplicatePolicy_MatchesExternalID(t *testing.T) {
+	day := time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local)
+	stored := &models.Transaction{ID: 1, Amount: money.FromFloat(5), Currency: "USD", Description: "Coffee", TransactionDate: day, ExternalID: "FIT-1"}
+	policy := transaction.DuplicatePolicy{WindowDays: 3}
+
+	sameID := &models.Transaction{Amount: money.FromFloat(5.5), Currency: "USD", Description: "Coffee shop", TransactionDate: day.AddDate(0, 1, 0), ExternalID: "FIT-1"}
+	otherID := &models.Transaction{Amount: money.FromFloat(5), Currency: "USD", Description: "Coffee", TransactionDate: day, ExternalID: "FIT-2"}
+	noID := &models.Transaction{Amount: money.FromFloat(5), Currency: "USD", Description: "Coffee", TransactionDate: day}
+
+	assert.True(t, policy.Matches(sameID, stored))
+	assert.False(t, policy.Matches(otherID, stored))
+	assert.True(t, policy.Matches(noID, stored))
+	assert.Equal(t, stored, policy.FindMatch(noID, []*models.Transaction{stored}))
+}
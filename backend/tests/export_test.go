@@ -0,0 +1,141 @@
+package test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/export"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/importer"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func exportDocument() *export.Document {
+	return &export.Document{
+		Version:      export.DocumentVersion,
+		ExportedAt:   time.Date(2024, 11, 1, 9, 0, 0, 0, time.Local),
+		BaseCurrency: "EUR",
+		Categories:   []export.Category{{ID: 4, Name: "Groceries"}},
+		Transactions: []export.Transaction{
+			{ID: 1, TransactionDate: time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local), Type: "expense", Amount: money.FromFloat(12.5), Currency: "USD", BaseAmount: money.FromFloat(11.4), Description: "Market, Main St", CategoryID: 4, Category: "Groceries", ExternalID: "FIT-1"},
+			{ID: 2, TransactionDate: time.Date(2024, 10, 3, 0, 0, 0, 0, time.Local), Type: "refund", Amount: money.FromFloat(20), Currency: "EUR", BaseAmount: money.FromFloat(20), Description: "Refund & more", CategoryID: 4, Category: "Groceries"},
+		},
+	}
+}
+
+func TestParseExportFormat(t *testing.T) {
+	format, err := export.ParseFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, export.FormatJSON, format)
+
+	format, err = export.ParseFormat("OFX")
+	assert.NoError(t, err)
+	assert.Equal(t, export.FormatOFX, format)
+
+	_, err = export.ParseFormat("xlsx")
+	assert.ErrorIs(t, err, export.ErrUnsupportedFormat)
+
+	_, err = export.ParseDataset("users")
+	assert.ErrorIs(t, err, export.ErrInvalidDataset)
+}
+
+func TestExport_JSONRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, export.WriteJSON(&buf, exportDocument()))
+
+	rows, err := importer.ParseJSON(&buf)
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, money.FromFloat(12.5), rows[0].Amount)
+	assert.Equal(t, money.Currency("USD"), rows[0].Currency)
+	assert.Equal(t, "Groceries", rows[0].Category)
+	assert.Equal(t, "FIT-1", rows[0].ExternalID)
+	assert.True(t, rows[1].TransactionDate.Equal(time.Date(2024, 10, 3, 0, 0, 0, 0, time.Local)))
+	assert.Equal(t, "refund", rows[1].Type)
+	assert.Empty(t, rows[1].Error)
+}
+
+func TestParseJSON_RejectsUnknownVersion(t *testing.T) {
+	_, err := importer.ParseJSON(strings.NewReader(`{"version": 99, "transactions": []}`))
+	assert.ErrorIs(t, err, importer.ErrInvalidFile)
+}
+
+func TestExport_CSVRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, export.WriteCSV(&buf, export.DatasetTransactions, exportDocument()))
+
+	rows, err := importer.ParseCSV(&buf, &importer.DefaultMapping, time.Local)
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "Market, Main St", rows[0].Description)
+	assert.Equal(t, money.FromFloat(12.5), rows[0].Amount)
+	assert.Equal(t, "FIT-1", rows[0].ExternalID)
+	assert.Equal(t, money.FromFloat(20), rows[1].Amount)
+	assert.Equal(t, "refund", rows[1].Type)
+	assert.Empty(t, rows[1].Error)
+}
+
+func TestExport_CSVIncludesTransferPair(t *testing.T) {
+	outID, inID := uint(5), uint(6)
+	doc := exportDocument()
+	doc.Transactions = append(doc.Transactions,
+		export.Transaction{ID: outID, TransactionDate: time.Date(2024, 10, 4, 0, 0, 0, 0, time.Local), Type: "transfer", Amount: money.FromFloat(100), Currency: "EUR", BaseAmount: money.FromFloat(100), TransferID: &inID},
+		export.Transaction{ID: inID, TransactionDate: time.Date(2024, 10, 4, 0, 0, 0, 0, time.Local), Type: "transfer", Amount: money.FromFloat(100), Currency: "EUR", BaseAmount: money.FromFloat(100), TransferID: &outID, TransferIn: true},
+	)
+
+	var buf bytes.Buffer
+	assert.NoError(t, export.WriteCSV(&buf, export.DatasetTransactions, doc))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 5)
+	assert.True(t, strings.HasSuffix(lines[0], ",transfer_id,transfer_in,splits"))
+	assert.True(t, strings.HasSuffix(lines[1], ",expense,,,"))
+	assert.True(t, strings.HasSuffix(lines[3], ",transfer,6,,"))
+	assert.True(t, strings.HasSuffix(lines[4], ",transfer,5,true,"))
+}
+
+func TestExport_CSVIncludesSplits(t *testing.T) {
+	doc := exportDocument()
+	doc.Transactions[0].Splits = []export.Split{
+		{CategoryID: 4, Category: "Groceries", Amount: money.FromFloat(10), BaseAmount: money.FromFloat(9.12)},
+		{CategoryID: 7, Category: "Household", Amount: money.FromFloat(2.5), BaseAmount: money.FromFloat(2.28)},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, export.WriteCSV(&buf, export.DatasetTransactions, doc))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.True(t, strings.HasSuffix(lines[1], ",expense,,,Groceries=10.00; Household=2.50"))
+	assert.True(t, strings.HasSuffix(lines[2], ",refund,,,"))
+}
+
+func TestExport_OFXRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, export.WriteOFX(&buf, exportDocument()))
+
+	rows, err := importer.ParseOFX(&buf, time.Local)
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, money.FromFloat(11.4), rows[0].Amount)
+	assert.Equal(t, money.Currency("EUR"), rows[0].Currency)
+	assert.Equal(t, "FIT-1", rows[0].ExternalID)
+	assert.Equal(t, "PW-2", rows[1].ExternalID)
+	assert.Equal(t, "Refund & more", rows[1].Description)
+	assert.Equal(t, money.FromFloat(-20), rows[1].Amount)
+}
+
+func TestParseJSON_VersionOneNegativeAmountsAreIncome(t *testing.T) {
+	rows, err := importer.ParseJSON(strings.NewReader(`{"version": 1, "transactions": [
+		{"transaction_date": "2024-10-03T00:00:00Z", "amount": -20, "description": "Salary"}
+	]}`))
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 1)
+	assert.Empty(t, rows[0].Type)
+	assert.Equal(t, money.FromFloat(-20), rows[0].Amount)
+}
@@ -0,0 +1,143 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/fx"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/shaikhjunaidx/pennywise-backend/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestParseCSV(t *testing.T) {
+	input := "date,base,quote,rate\n2024-10-01,usd,EUR,0.9\n2024-10-02,GBP,USD,1.31\n"
+
+	rates, err := fx.ParseCSV(strings.NewReader(input))
+
+	assert.NoError(t, err)
+	assert.Len(t, rates, 2)
+	assert.Equal(t, money.Currency("USD"), rates[0].BaseCurrency)
+	assert.Equal(t, money.Currency("EUR"), rates[0].QuoteCurrency)
+	assert.Equal(t, 0.9, rates[0].Rate)
+	assert.Equal(t, time.Date(2024, 10, 2, 0, 0, 0, 0, time.UTC), rates[1].RateDate)
+}
+
+func TestParseCSV_Invalid(t *testing.T) {
+	for name, input := range map[string]string{
+		"missing column":   "date,base,rate\n2024-10-01,USD,0.9\n",
+		"bad date":         "date,base,quote,rate\n01/10/2024,USD,EUR,0.9\n",
+		"unknown currency": "date,base,quote,rate\n2024-10-01,USD,XXX,0.9\n",
+		"negative rate":    "date,base,quote,rate\n2024-10-01,USD,EUR,-1\n",
+	} {
+		_, err := fx.ParseCSV(strings.NewReader(input))
+		assert.ErrorIs(t, err, fx.ErrInvalidRateFile, name)
+	}
+}
+
+func TestParseECBXML(t *testing.T) {
+	input := `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time="2024-10-01">
+			<Cube currency="USD" rate="1.1119"/>
+			<Cube currency="ZZZ" rate="2.5"/>
+			<Cube currency="GBP" rate="0.83178"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+	rates, err := fx.ParseECBXML(strings.NewReader(input))
+
+	assert.NoError(t, err)
+	assert.Len(t, rates, 2)
+	assert.Equal(t, fx.PivotCurrency, rates[0].BaseCurrency)
+	assert.Equal(t, money.Currency("USD"), rates[0].QuoteCurrency)
+	assert.Equal(t, 1.1119, rates[0].Rate)
+	assert.Equal(t, money.Currency("GBP"), rates[1].QuoteCurrency)
+}
+
+func expectNoRate(rates *mocks.MockRateRepository, base, quote money.Currency, date time.Time) {
+	rates.On("FindLatest", base, quote, date).Return((*models.ExchangeRate)(nil), gorm.ErrRecordNotFound)
+}
+
+func expectRate(rates *mocks.MockRateRepository, base, quote money.Currency, date time.Time, rate float64) {
+	rates.On("FindLatest", base, quote, date).Return(&models.ExchangeRate{BaseCurrency: base, QuoteCurrency: quote, Rate: rate}, nil)
+}
+
+func TestConverter_Convert(t *testing.T) {
+	date := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	rates := new(mocks.MockRateRepository)
+	expectRate(rates, "USD", "EUR", date, 0.9)
+	converter := fx.NewConverter(rates)
+
+	amount, err := converter.Convert(money.FromFloat(10), "USD", "EUR", date)
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromFloat(9), amount)
+}
+
+func TestConverter_Convert_RoundsToMinorUnit(t *testing.T) {
+	date := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	rates := new(mocks.MockRateRepository)
+	expectRate(rates, "USD", "JPY", date, 149.37)
+	converter := fx.NewConverter(rates)
+
+	amount, err := converter.Convert(money.FromFloat(10.01), "USD", "JPY", date)
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromFloat(1495), amount)
+}
+
+func TestConverter_Convert_SameCurrency(t *testing.T) {
+	converter := fx.NewConverter(new(mocks.MockRateRepository))
+
+	amount, err := converter.Convert(money.FromFloat(12.34), "USD", "USD", time.Now())
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromFloat(12.34), amount)
+}
+
+func TestConverter_Convert_Inverse(t *testing.T) {
+	date := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	rates := new(mocks.MockRateRepository)
+	expectNoRate(rates, "EUR", "USD", date)
+	expectRate(rates, "USD", "EUR", date, 0.8)
+	converter := fx.NewConverter(rates)
+
+	amount, err := converter.Convert(money.FromFloat(8), "EUR", "USD", date)
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromFloat(10), amount)
+}
+
+func TestConverter_Convert_CrossRate(t *testing.T) {
+	date := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	rates := new(mocks.MockRateRepository)
+	expectNoRate(rates, "GBP", "USD", date)
+	expectNoRate(rates, "USD", "GBP", date)
+	expectNoRate(rates, "GBP", "EUR", date)
+	expectRate(rates, "EUR", "GBP", date, 0.8)
+	expectRate(rates, "EUR", "USD", date, 1.1)
+	converter := fx.NewConverter(rates)
+
+	amount, err := converter.Convert(money.FromFloat(8), "GBP", "USD", date)
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromFloat(11), amount)
+}
+
+func TestConverter_Convert_RateNotFound(t *testing.T) {
+	rates := new(mocks.MockRateRepository)
+	rates.On("FindLatest", mock.Anything, mock.Anything, mock.Anything).Return((*models.ExchangeRate)(nil), gorm.ErrRecordNotFound)
+	converter := fx.NewConverter(rates)
+
+	_, err := converter.Convert(money.FromFloat(1), "GBP", "USD", time.Now())
+
+	assert.ErrorIs(t, err, fx.ErrRateNotFound)
+}
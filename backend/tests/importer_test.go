@@ -0,0 +1,156 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/importer"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFormat(t *testing.T) {
+	format, err := importer.ParseFormat("", "statement.QFX")
+	assert.NoError(t, err)
+	assert.Equal(t, importer.FormatOFX, format)
+
+	format, err = importer.ParseFormat("qif", "statement.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, importer.FormatQIF, format)
+
+	_, err = importer.ParseFormat("", "statement.pdf")
+	assert.ErrorIs(t, err, importer.ErrUnsupportedFormat)
+}
+
+func TestParseCSV_DefaultMapping(t *testing.T) {
+	input := "Date,Amount,Description,Category,Currency\n" +
+		"2024-10-01,12.50,Coffee,Food,EUR\n" +
+		"2024-10-02,\"1,200.00\",Rent,,\n" +
+		"\n" +
+		"not a date,5,Broken,,\n"
+
+	rows, err := importer.ParseCSV(strings.NewReader(input), &importer.DefaultMapping, time.Local)
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local), rows[0].TransactionDate)
+	assert.Equal(t, money.FromFloat(12.5), rows[0].Amount)
+	assert.Equal(t, money.Currency("EUR"), rows[0].Currency)
+	assert.Equal(t, "Food", rows[0].Category)
+	assert.Equal(t, money.FromFloat(1200), rows[1].Amount)
+	assert.Empty(t, rows[1].Error)
+	assert.Equal(t, 5, rows[2].Line)
+	assert.Contains(t, rows[2].Error, "invalid date")
+}
+
+func TestParseCSV_BankMapping(t *testing.T) {
+	mapping := &models.ImportMapping{
+		Delimiter:         ";",
+		DateColumn:        "Booking Date",
+		DateFormat:        "DD.MM.YYYY",
+		DebitColumn:       "Debit",
+		CreditColumn:      "Credit",
+		DescriptionColumn: "Text",
+		Currency:          "EUR",
+	}
+	input := "Booking Date;Text;Debit;Credit\n" +
+		"03.10.2024;Supermarket;-45.10;\n" +
+		"04.10.2024;Refund;;(5.00)\n"
+
+	rows, err := importer.ParseCSV(strings.NewReader(input), mapping, time.Local)
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, time.Date(2024, 10, 3, 0, 0, 0, 0, time.Local), rows[0].TransactionDate)
+	assert.Equal(t, money.FromFloat(45.10), rows[0].Amount)
+	assert.Equal(t, money.Currency("EUR"), rows[0].Currency)
+	assert.Equal(t, money.FromFloat(-5), rows[1].Amount)
+}
+
+func TestParseCSV_MissingColumn(t *testing.T) {
+	_, err := importer.ParseCSV(strings.NewReader("when,what\n2024-10-01,x\n"), &importer.DefaultMapping, time.Local)
+
+	assert.ErrorIs(t, err, importer.ErrInvalidFile)
+}
+
+func TestParseOFX_SGML(t *testing.T) {
+	input := `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>GBP
+<BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20241001120000.000[-5:EST]
+<TRNAMT>-23.40
+<FITID>1001
+<NAME>TESCO STORES
+<MEMO>Card purchase
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20241002
+<TRNAMT>100.00
+<FITID>1002
+<NAME>Salary &amp; bonus
+</STMTTRN>
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>`
+
+	rows, err := importer.ParseOFX(strings.NewReader(input), time.Local)
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local), rows[0].TransactionDate)
+	assert.Equal(t, money.FromFloat(23.40), rows[0].Amount)
+	assert.Equal(t, money.Currency("GBP"), rows[0].Currency)
+	assert.Equal(t, "TESCO STORES - Card purchase", rows[0].Description)
+	assert.Equal(t, money.FromFloat(-100), rows[1].Amount)
+	assert.Equal(t, "Salary & bonus", rows[1].Description)
+}
+
+func TestParseOFX_NotOFX(t *testing.T) {
+	_, err := importer.ParseOFX(strings.NewReader("date,amount\n"), time.Local)
+
+	assert.ErrorIs(t, err, importer.ErrInvalidFile)
+}
+
+func TestParseQIF(t *testing.T) {
+	input := "!Type:Bank\n" +
+		"D10/ 1'24\n" +
+		"T-1,234.56\n" +
+		"PLandlord\n" +
+		"LRent\n" +
+		"^\n" +
+		"D10/15/2024\n" +
+		"T20.00\n" +
+		"PRefund\n" +
+		"^\n" +
+		"Dyesterday\n" +
+		"T1\n" +
+		"^\n"
+
+	rows, err := importer.ParseQIF(strings.NewReader(input), "", time.Local)
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local), rows[0].TransactionDate)
+	assert.Equal(t, money.FromFloat(1234.56), rows[0].Amount)
+	assert.Equal(t, "Landlord", rows[0].Description)
+	assert.Equal(t, "Rent", rows[0].Category)
+	assert.Equal(t, money.FromFloat(-20), rows[1].Amount)
+	assert.Contains(t, rows[2].Error, "invalid date")
+}
+
+func TestParseQIF_DayFirst(t *testing.T) {
+	rows, err := importer.ParseQIF(strings.NewReader("!Type:CCard\nD02/10/2024\nT-9.99\n^\n"), "DD/MM/YYYY", time.Local)
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 10, 2, 0, 0, 0, 0, time.Local), rows[0].TransactionDate)
+}
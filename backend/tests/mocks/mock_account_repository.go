@@ -0,0 +1,78 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/account"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAccountRepository struct {
+	mock.Mock
+}
+
+func (m *MockAccountRepository) Create(account *models.Account) error {
+	args := m.Called(account)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) Update(account *models.Account) error {
+	args := m.Called(account)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) DeleteByID(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) FindByID(id uint) (*models.Account, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) FindAllByUserID(userID uint) ([]*models.Account, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]*models.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) LedgerTotals(userID uint) (map[uint]money.Amount, error) {
+	args := m.Called(userID)
+	return args.Get(0).(map[uint]money.Amount), args.Error(1)
+}
+
+func (m *MockAccountRepository) LedgerTotal(accountID uint, before time.Time) (money.Amount, error) {
+	args := m.Called(accountID, before)
+	return args.Get(0).(money.Amount), args.Error(1)
+}
+
+func (m *MockAccountRepository) FindTransactions(accountID uint, from, before time.Time) ([]*models.Transaction, error) {
+	args := m.Called(accountID, from, before)
+	return args.Get(0).([]*models.Transaction), args.Error(1)
+}
+
+func (m *MockAccountRepository) CountTransactions(accountID uint) (int64, error) {
+	args := m.Called(accountID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAccountRepository) SaveReconciliation(account *models.Account, reconciliation *models.Reconciliation) error {
+	args := m.Called(account, reconciliation)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) FindReconciliations(accountID uint) ([]*models.Reconciliation, error) {
+	args := m.Called(accountID)
+	return args.Get(0).([]*models.Reconciliation), args.Error(1)
+}
+
+// WithLockedAccount runs fn against the mock itself.
+func (m *MockAccountRepository) WithLockedAccount(locked *models.Account, fn func(repo account.AccountRepository) error) error {
+	args := m.Called(locked)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(m)
+}
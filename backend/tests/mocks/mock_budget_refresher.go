@@ -0,0 +1,12 @@
+package mocks
+
+import "github.com/stretchr/testify/mock"
+
+type MockBudgetRefresher struct {
+	mock.Mock
+}
+
+func (m *MockBudgetRefresher) RecalculateCategories(userID uint, categoryIDs []uint) error {
+	args := m.Called(userID, categoryIDs)
+	return args.Error(0)
+}
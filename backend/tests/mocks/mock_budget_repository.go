@@ -1,12 +1,19 @@
 package mocks
 
 import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
 	"github.com/shaikhjunaidx/pennywise-backend/models"
 	"github.com/stretchr/testify/mock"
 )
 
+// MockBudgetRepository mocks the budget repository. CategoryParents is
+// returned by FindCategoryParents without recording a call, so tests that do
+// not care about subcategories need not expect it.
 type MockBudgetRepository struct {
 	mock.Mock
+	CategoryParents map[uint]uint
 }
 
 func (m *MockBudgetRepository) Create(budget *models.Budget) error {
@@ -19,6 +26,21 @@ func (m *MockBudgetRepository) Update(budget *models.Budget) error {
 	return args.Error(0)
 }
 
+func (m *MockBudgetRepository) SumLedgerSpent(userID uint, categoryID *uint, start, end time.Time) (money.Amount, error) {
+	args := m.Called(userID, categoryID, start, end)
+	return args.Get(0).(money.Amount), args.Error(1)
+}
+
+func (m *MockBudgetRepository) RecalculateSpent(id uint, start, end time.Time) error {
+	args := m.Called(id, start, end)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) UpdateAmountLimit(id uint, amountLimit money.Amount) error {
+	args := m.Called(id, amountLimit)
+	return args.Error(0)
+}
+
 func (m *MockBudgetRepository) DeleteByID(id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
@@ -39,7 +61,31 @@ func (m *MockBudgetRepository) FindByUserIDAndCategoryID(userID uint, categoryID
 	return args.Get(0).(*models.Budget), args.Error(1)
 }
 
+func (m *MockBudgetRepository) FindByUserIDAndCategoryIDForUpdate(userID uint, categoryID *uint, month string, year int) (*models.Budget, error) {
+	args := m.Called(userID, categoryID, month, year)
+	return args.Get(0).(*models.Budget), args.Error(1)
+}
+
 func (m *MockBudgetRepository) FindAllByUserIDAndMonthYear(userID uint, month string, year int) ([]*models.Budget, error) {
 	args := m.Called(userID, month, year)
 	return args.Get(0).([]*models.Budget), args.Error(1)
 }
+
+func (m *MockBudgetRepository) FindForReconciliation(userID *uint) ([]*models.Budget, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]*models.Budget), args.Error(1)
+}
+
+func (m *MockBudgetRepository) UpdateRollover(id uint, positive, negative bool) error {
+	args := m.Called(id, positive, negative)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) FindCategoryBudgetsForPeriod(month string, year int) ([]*models.Budget, error) {
+	args := m.Called(month, year)
+	return args.Get(0).([]*models.Budget), args.Error(1)
+}
+
+func (m *MockBudgetRepository) FindCategoryParents(userID uint) (map[uint]uint, error) {
+	return m.CategoryParents, nil
+}
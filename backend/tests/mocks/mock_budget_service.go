@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
 	"github.com/shaikhjunaidx/pennywise-backend/models"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,23 +10,23 @@ type MockBudgetService struct {
 	mock.Mock
 }
 
-func (m *MockBudgetService) CreateBudget(username string, categoryID *uint, amountLimit float64, month string, year int) (*models.Budget, error) {
+func (m *MockBudgetService) CreateBudget(username string, categoryID *uint, amountLimit money.Amount, month string, year int) (*models.Budget, error) {
 	args := m.Called(username, categoryID, amountLimit, month, year)
 	return args.Get(0).(*models.Budget), args.Error(1)
 }
 
-func (m *MockBudgetService) UpdateBudget(budgetID uint, amountLimit float64) (*models.Budget, error) {
-	args := m.Called(budgetID, amountLimit)
+func (m *MockBudgetService) UpdateBudget(username string, budgetID uint, amountLimit money.Amount) (*models.Budget, error) {
+	args := m.Called(username, budgetID, amountLimit)
 	return args.Get(0).(*models.Budget), args.Error(1)
 }
 
-func (m *MockBudgetService) DeleteBudget(budgetID uint) error {
-	args := m.Called(budgetID)
+func (m *MockBudgetService) DeleteBudget(username string, budgetID uint) error {
+	args := m.Called(username, budgetID)
 	return args.Error(0)
 }
 
-func (m *MockBudgetService) GetBudgetByID(budgetID uint) (*models.Budget, error) {
-	args := m.Called(budgetID)
+func (m *MockBudgetService) GetBudgetByID(username string, budgetID uint) (*models.Budget, error) {
+	args := m.Called(username, budgetID)
 	return args.Get(0).(*models.Budget), args.Error(1)
 }
 
@@ -39,8 +40,8 @@ func (m *MockBudgetService) GetBudgetForUserAndCategory(username string, categor
 	return args.Get(0).(*models.Budget), args.Error(1)
 }
 
-func (m *MockBudgetService) AddTransactionToBudget(userID uint, categoryID *uint, transactionAmount float64, month string, year int) (*models.Budget, error) {
-	args := m.Called(userID, categoryID, transactionAmount, month, year)
+func (m *MockBudgetService) RecalculateBudget(userID uint, categoryID *uint, month string, year int) (*models.Budget, error) {
+	args := m.Called(userID, categoryID, month, year)
 	return args.Get(0).(*models.Budget), args.Error(1)
 }
 
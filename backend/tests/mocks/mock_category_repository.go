@@ -44,7 +44,17 @@ func (m *MockCategoryRepository) FindAllByUserID(userID uint) ([]*models.Categor
 	return args.Get(0).([]*models.Category), args.Error(1)
 }
 
+func (m *MockCategoryRepository) FindAllByUserIDForUpdate(userID uint) ([]*models.Category, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]*models.Category), args.Error(1)
+}
+
 func (m *MockCategoryRepository) FindByNameAndUserID(name string, userID uint) (*models.Category, error) {
-	args := m.Called(name)
+	args := m.Called(name, userID)
+	return args.Get(0).(*models.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) FindByNameKindAndUserID(name, kind string, userID uint) (*models.Category, error) {
+	args := m.Called(name, kind, userID)
 	return args.Get(0).(*models.Category), args.Error(1)
 }
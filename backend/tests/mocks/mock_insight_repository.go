@@ -0,0 +1,17 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockInsightRepository struct {
+	mock.Mock
+}
+
+func (m *MockInsightRepository) FindExpensesSince(userID uint, since time.Time) ([]*models.Transaction, error) {
+	args := m.Called(userID, since)
+	return args.Get(0).([]*models.Transaction), args.Error(1)
+}
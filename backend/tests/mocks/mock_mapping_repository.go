@@ -0,0 +1,30 @@
+package mocks
+
+import (
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMappingRepository struct {
+	mock.Mock
+}
+
+func (m *MockMappingRepository) Save(mapping *models.ImportMapping) error {
+	args := m.Called(mapping)
+	return args.Error(0)
+}
+
+func (m *MockMappingRepository) DeleteByID(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockMappingRepository) FindByID(id uint) (*models.ImportMapping, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.ImportMapping), args.Error(1)
+}
+
+func (m *MockMappingRepository) FindAllByUserID(userID uint) ([]*models.ImportMapping, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]*models.ImportMapping), args.Error(1)
+}
@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	"github.com/shaikhjunaidx/pennywise-backend/internal/notify"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) Send(msg notify.Message) error {
+	args := m.Called(msg)
+	return args.Error(0)
+}
@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRateRepository struct {
+	mock.Mock
+}
+
+func (m *MockRateRepository) Upsert(rates []models.ExchangeRate) error {
+	args := m.Called(rates)
+	return args.Error(0)
+}
+
+func (m *MockRateRepository) FindLatest(base, quote money.Currency, date time.Time) (*models.ExchangeRate, error) {
+	args := m.Called(base, quote, date)
+	return args.Get(0).(*models.ExchangeRate), args.Error(1)
+}
+
+type MockBaseCurrencyListener struct {
+	mock.Mock
+}
+
+func (m *MockBaseCurrencyListener) BaseCurrencyChanged(settings *models.UserSettings) error {
+	args := m.Called(settings)
+	return args.Error(0)
+}
+
+type MockTimeZoneListener struct {
+	mock.Mock
+}
+
+func (m *MockTimeZoneListener) TimeZoneChanged(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
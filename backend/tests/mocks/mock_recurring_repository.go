@@ -0,0 +1,82 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/recurring"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRecurringRepository struct {
+	mock.Mock
+}
+
+func (m *MockRecurringRepository) Create(rule *models.RecurringRule) error {
+	args := m.Called(rule)
+	return args.Error(0)
+}
+
+func (m *MockRecurringRepository) Update(rule *models.RecurringRule) error {
+	args := m.Called(rule)
+	return args.Error(0)
+}
+
+func (m *MockRecurringRepository) DeleteByID(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockRecurringRepository) FindByID(id uint) (*models.RecurringRule, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.RecurringRule), args.Error(1)
+}
+
+func (m *MockRecurringRepository) FindAllByUserID(userID uint) ([]*models.RecurringRule, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]*models.RecurringRule), args.Error(1)
+}
+
+func (m *MockRecurringRepository) FindDue(now time.Time) ([]*models.RecurringRule, error) {
+	args := m.Called(now)
+	return args.Get(0).([]*models.RecurringRule), args.Error(1)
+}
+
+func (m *MockRecurringRepository) FindOccurrence(ruleID uint, date time.Time) (*models.RecurringOccurrence, error) {
+	args := m.Called(ruleID, date)
+	return args.Get(0).(*models.RecurringOccurrence), args.Error(1)
+}
+
+func (m *MockRecurringRepository) FindOccurrences(ruleIDs []uint, from, to time.Time) ([]*models.RecurringOccurrence, error) {
+	args := m.Called(ruleIDs, from, to)
+	return args.Get(0).([]*models.RecurringOccurrence), args.Error(1)
+}
+
+func (m *MockRecurringRepository) SaveOccurrence(occurrence *models.RecurringOccurrence) error {
+	args := m.Called(occurrence)
+	return args.Error(0)
+}
+
+func (m *MockRecurringRepository) DeleteOccurrence(ruleID uint, date time.Time) error {
+	args := m.Called(ruleID, date)
+	return args.Error(0)
+}
+
+func (m *MockRecurringRepository) FindBookedTransactionID(userID uint, externalID string) (uint, error) {
+	args := m.Called(userID, externalID)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockRecurringRepository) SaveProgress(rule *models.RecurringRule, occurrence *models.RecurringOccurrence) error {
+	args := m.Called(rule, occurrence)
+	return args.Error(0)
+}
+
+// WithLockedRule runs fn against the mock itself.
+func (m *MockRecurringRepository) WithLockedRule(rule *models.RecurringRule, fn func(repo recurring.RecurringRepository) error) error {
+	args := m.Called(rule)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(m)
+}
@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/reports"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReportRepository struct {
+	mock.Mock
+}
+
+func (m *MockReportRepository) SpendingByCategoryMonth(userID uint, period reports.Range) ([]reports.CategoryMonthTotal, error) {
+	args := m.Called(userID, period)
+	return args.Get(0).([]reports.CategoryMonthTotal), args.Error(1)
+}
+
+func (m *MockReportRepository) TotalsByMonth(userID uint, period reports.Range) ([]reports.MonthTotals, error) {
+	args := m.Called(userID, period)
+	return args.Get(0).([]reports.MonthTotals), args.Error(1)
+}
+
+func (m *MockReportRepository) TotalSpending(userID uint, period reports.Range) (money.Amount, error) {
+	args := m.Called(userID, period)
+	return args.Get(0).(money.Amount), args.Error(1)
+}
+
+func (m *MockReportRepository) TopCategories(userID uint, period reports.Range, limit int) ([]reports.CategoryTotal, error) {
+	args := m.Called(userID, period, limit)
+	return args.Get(0).([]reports.CategoryTotal), args.Error(1)
+}
+
+func (m *MockReportRepository) TopMerchants(userID uint, period reports.Range, limit int) ([]reports.MerchantTotal, error) {
+	args := m.Called(userID, period, limit)
+	return args.Get(0).([]reports.MerchantTotal), args.Error(1)
+}
+
+func (m *MockReportRepository) TotalsByBucket(userID uint, buckets []reports.Bucket, categoryIDs []uint) ([]reports.BucketTotal, error) {
+	args := m.Called(userID, buckets, categoryIDs)
+	return args.Get(0).([]reports.BucketTotal), args.Error(1)
+}
@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"github.com/shaikhjunaidx/pennywise-backend/internal/recurring"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRuleStore struct {
+	mock.Mock
+}
+
+func (m *MockRuleStore) GetRules(username string) ([]*models.RecurringRule, error) {
+	args := m.Called(username)
+	return args.Get(0).([]*models.RecurringRule), args.Error(1)
+}
+
+func (m *MockRuleStore) CreateRule(username string, input recurring.RuleInput) (*models.RecurringRule, error) {
+	args := m.Called(username, input)
+	return args.Get(0).(*models.RecurringRule), args.Error(1)
+}
@@ -0,0 +1,20 @@
+package mocks
+
+import (
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSettingsRepository struct {
+	mock.Mock
+}
+
+func (m *MockSettingsRepository) FindByUserID(userID uint) (*models.UserSettings, error) {
+	args := m.Called(userID)
+	return args.Get(0).(*models.UserSettings), args.Error(1)
+}
+
+func (m *MockSettingsRepository) Save(settings *models.UserSettings) error {
+	args := m.Called(settings)
+	return args.Error(0)
+}
@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTransactionBooker struct {
+	mock.Mock
+}
+
+func (m *MockTransactionBooker) AddTransaction(username string, input transaction.TransactionInput) (*models.Transaction, error) {
+	args := m.Called(username, input)
+	return args.Get(0).(*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionBooker) ValidateTransaction(username string, input transaction.TransactionInput) error {
+	args := m.Called(username, input)
+	return args.Error(0)
+}
@@ -1,6 +1,9 @@
 package mocks
 
 import (
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
 	"github.com/shaikhjunaidx/pennywise-backend/models"
 	"github.com/stretchr/testify/mock"
@@ -30,23 +33,93 @@ func (m *MockTransactionRepository) FindByID(id uint) (*models.Transaction, erro
 	return args.Get(0).(*models.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionRepository) FindAllByUsername(username string) ([]*transaction.TransactionResponse, error) {
-	args := m.Called(username)
-	return args.Get(0).([]*transaction.TransactionResponse), args.Error(1)
+func (m *MockTransactionRepository) CreateAll(transactions []*models.Transaction) error {
+	args := m.Called(transactions)
+	return args.Error(0)
 }
 
-func (m *MockTransactionRepository) FindAllByUserIDAndCategoryID(userID, categoryID uint) ([]*transaction.TransactionResponse, error) {
-	args := m.Called(userID, categoryID)
-	return args.Get(0).([]*transaction.TransactionResponse), args.Error(1)
+func (m *MockTransactionRepository) FindByIDForUpdate(id uint) (*models.Transaction, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionRepository) GetWeeklySpending(userID uint) ([]transaction.WeeklySpending, error) {
+func (m *MockTransactionRepository) FindAllByUserID(userID uint) ([]*models.Transaction, error) {
 	args := m.Called(userID)
+	return args.Get(0).([]*models.Transaction), args.Error(1)
+}
+
+// EachInRange calls fn with each transaction returned for the call.
+func (m *MockTransactionRepository) EachInRange(userID uint, from, before time.Time, categoryIDs []uint, fn func(*models.Transaction) error) error {
+	args := m.Called(userID, from, before, categoryIDs)
+	if transactions, ok := args.Get(0).([]*models.Transaction); ok {
+		for _, t := range transactions {
+			if err := fn(t); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockTransactionRepository) FindAllByIDs(ids []uint) ([]*models.Transaction, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) FindDuplicateCandidates(userID uint, from, to time.Time, externalIDs []string) ([]*models.Transaction, error) {
+	args := m.Called(userID, from, to, externalIDs)
+	return args.Get(0).([]*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) FindFlaggedDuplicates(userID uint) ([]*models.Transaction, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) ClearDuplicatesOf(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) ClearDuplicateFlag(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
 
-	if weeklySpending, ok := args.Get(0).([]transaction.WeeklySpending); ok {
-		return weeklySpending, args.Error(1)
+func (m *MockTransactionRepository) UpdateBaseAmount(id uint, baseAmount money.Amount) error {
+	args := m.Called(id, baseAmount)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) UpdateSplitBaseAmount(id uint, baseAmount money.Amount) error {
+	args := m.Called(id, baseAmount)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) FindPage(userID uint, query transaction.TransactionQuery) (*transaction.TransactionPage, error) {
+	args := m.Called(userID, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
 	}
+	return args.Get(0).(*transaction.TransactionPage), args.Error(1)
+}
+
+func (m *MockTransactionRepository) Search(userID uint, terms []string, limit int) ([]*models.Transaction, error) {
+	args := m.Called(userID, terms, limit)
+	return args.Get(0).([]*models.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) SearchFragments(userID uint, groups [][]string, exclude []uint, limit int) ([]*models.Transaction, error) {
+	args := m.Called(userID, groups, exclude, limit)
+	return args.Get(0).([]*models.Transaction), args.Error(1)
+}
 
-	return nil, args.Error(1)
+func (m *MockTransactionRepository) FindAllByUserIDAndCategoryID(userID, categoryID uint) ([]*transaction.TransactionResponse, error) {
+	args := m.Called(userID, categoryID)
+	return args.Get(0).([]*transaction.TransactionResponse), args.Error(1)
+}
 
+func (m *MockTransactionRepository) SumSpending(userID uint, start, end time.Time) (money.Amount, error) {
+	args := m.Called(userID, start, end)
+	return args.Get(0).(money.Amount), args.Error(1)
 }
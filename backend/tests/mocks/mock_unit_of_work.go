@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"github.com/shaikhjunaidx/pennywise-backend/internal/budget"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/category"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/user"
+)
+
+// MockUnitOfWork runs the callback directly against the given repositories.
+type MockUnitOfWork struct {
+	Transactions transaction.TransactionRepository
+	Budgets      budget.BudgetRepository
+	Settings     user.SettingsRepository
+}
+
+func (u *MockUnitOfWork) Do(fn func(repos transaction.Repositories) error) error {
+	return fn(transaction.Repositories{
+		Transactions: u.Transactions,
+		Budgets:      u.Budgets,
+		Settings:     u.Settings,
+	})
+}
+
+// MockCategoryUnitOfWork runs the callback directly against the given
+// repositories.
+type MockCategoryUnitOfWork struct {
+	Categories category.CategoryRepository
+	Budgets    category.BudgetRefresher
+}
+
+func (u *MockCategoryUnitOfWork) Do(fn func(repos category.Repositories) error) error {
+	return fn(category.Repositories{
+		Categories: u.Categories,
+		Budgets:    u.Budgets,
+	})
+}
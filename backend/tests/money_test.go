@@ -0,0 +1,103 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAmount(t *testing.T) {
+	cases := map[string]money.Amount{
+		"12.34":  1234,
+		"12.3":   1230,
+		"12":     1200,
+		"-0.05":  -5,
+		".5":     50,
+		"+1000":  100000,
+		" 7.00 ": 700,
+	}
+	for input, expected := range cases {
+		amount, err := money.Parse(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, amount, input)
+	}
+
+	for _, input := range []string{"", "1.234", "abc", "1,50", "--1", "."} {
+		_, err := money.Parse(input)
+		assert.ErrorIs(t, err, money.ErrInvalidAmount, input)
+	}
+}
+
+func TestAmount_SumsExactly(t *testing.T) {
+	var total money.Amount
+	for i := 0; i < 10; i++ {
+		amount, _ := money.Parse("0.10")
+		total += amount
+	}
+
+	assert.Equal(t, "1.00", total.String())
+}
+
+func TestAmount_JSON(t *testing.T) {
+	var payload struct {
+		Amount money.Amount `json:"amount"`
+	}
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount": 19.99}`), &payload))
+	assert.Equal(t, money.Amount(1999), payload.Amount)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount": "-4.5"}`), &payload))
+	assert.Equal(t, money.Amount(-450), payload.Amount)
+
+	encoded, err := json.Marshal(payload)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"amount": -4.50}`, string(encoded))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"amount": 0.001}`), &payload))
+}
+
+func TestAmount_Scan(t *testing.T) {
+	var amount money.Amount
+
+	assert.NoError(t, amount.Scan(int64(1250)))
+	assert.Equal(t, money.Amount(1250), amount)
+
+	// MySQL returns SUM over BIGINT columns as DECIMAL text.
+	assert.NoError(t, amount.Scan([]byte("98765")))
+	assert.Equal(t, money.Amount(98765), amount)
+
+	assert.NoError(t, amount.Scan(nil))
+	assert.Equal(t, money.Amount(0), amount)
+}
+
+func TestParseCurrency(t *testing.T) {
+	currency, err := money.ParseCurrency("eur")
+	assert.NoError(t, err)
+	assert.Equal(t, money.Currency("EUR"), currency)
+
+	currency, err = money.ParseCurrency("")
+	assert.NoError(t, err)
+	assert.Equal(t, money.DefaultCurrency, currency)
+
+	_, err = money.ParseCurrency("XYZ")
+	assert.ErrorIs(t, err, money.ErrUnknownCurrency)
+
+	// Three decimal places do not fit an Amount.
+	_, err = money.ParseCurrency("KWD")
+	assert.ErrorIs(t, err, money.ErrUnknownCurrency)
+}
+
+func TestAmount_RoundTo(t *testing.T) {
+	assert.Equal(t, 0, money.Currency("JPY").Exponent())
+	assert.Equal(t, 2, money.Currency("EUR").Exponent())
+
+	assert.Equal(t, money.FromFloat(1235), money.FromFloat(1234.5).RoundTo("JPY"))
+	assert.Equal(t, money.FromFloat(-1234), money.FromFloat(-1234.49).RoundTo("JPY"))
+	assert.Equal(t, money.FromFloat(12.34), money.FromFloat(12.34).RoundTo("EUR"))
+
+	assert.True(t, money.FromFloat(1500).FitsCurrency("JPY"))
+	assert.False(t, money.FromFloat(1500.5).FitsCurrency("JPY"))
+	assert.True(t, money.FromFloat(12.34).FitsCurrency("USD"))
+}
@@ -0,0 +1,91 @@
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/notify"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordResetMessage(t *testing.T) {
+	expiresAt := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	msg, err := notify.PasswordResetMessage("john.doe@example.com", notify.PasswordResetData{
+		Username:  "john_doe",
+		Token:     "abc123",
+		ExpiresAt: expiresAt,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "john.doe@example.com", msg.To)
+	assert.Contains(t, msg.Body, "abc123")
+	assert.Contains(t, msg.Body, "2024-09-01 12:00 UTC")
+	assert.True(t, msg.Sensitive)
+}
+
+func TestBudgetAlertMessage(t *testing.T) {
+	msg, err := notify.BudgetAlertMessage("john.doe@example.com", notify.BudgetAlertData{
+		Username:     "john_doe",
+		CategoryName: "Groceries",
+		AmountLimit:  500,
+		SpentAmount:  money.FromFloat(512.5),
+		BudgetMonth:  "09",
+		BudgetYear:   2024,
+	})
+
+	assert.NoError(t, err)
+	assert.Contains(t, msg.Subject, "Groceries")
+	assert.Contains(t, msg.Body, "512.50")
+	assert.Contains(t, msg.Body, "09/2024")
+}
+
+func TestLogNotifier_WritesMessage(t *testing.T) {
+	var buf bytes.Buffer
+	notifier := notify.NewLogNotifier(&buf)
+
+	err := notifier.Send(notify.Message{To: "john.doe@example.com", Subject: "Hello", Body: "Body text"})
+
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "To: john.doe@example.com")
+	assert.Contains(t, buf.String(), "Subject: Hello")
+	assert.Contains(t, buf.String(), "Body text")
+}
+
+func TestLogNotifier_WithholdsSensitiveBody(t *testing.T) {
+	var buf bytes.Buffer
+	notifier := notify.NewLogNotifier(&buf)
+
+	msg, err := notify.PasswordResetMessage("john.doe@example.com", notify.PasswordResetData{
+		Username:  "john_doe",
+		Token:     "abc123",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, notifier.Send(msg))
+	assert.Contains(t, buf.String(), "To: john.doe@example.com")
+	assert.NotContains(t, buf.String(), "abc123")
+}
+
+func TestFileNotifier_WritesSensitiveBody(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail.log")
+	notifier, err := notify.NewFileNotifier(path)
+	assert.NoError(t, err)
+
+	msg, err := notify.PasswordResetMessage("john.doe@example.com", notify.PasswordResetData{
+		Username:  "john_doe",
+		Token:     "abc123",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, notifier.Send(msg))
+	written, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(written), "abc123")
+}
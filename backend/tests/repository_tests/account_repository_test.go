@@ -0,0 +1,66 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/account"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountRepository_LedgerTotals(t *testing.T) {
+	transactionRepo, db := setupTransactionTestRepo(t)
+	repo := account.NewAccountRepository(db)
+	user := createUser(t, db)
+	groceries := createCategoryGroceries(t, db, user.ID)
+
+	checking := &models.Account{UserID: user.ID, Name: "Checking", Type: models.AccountTypeChecking, Currency: "USD", OpeningBalance: money.FromFloat(100)}
+	assert.NoError(t, repo.Create(checking))
+
+	for _, tx := range []models.Transaction{
+		{Type: models.TransactionTypeExpense, Amount: money.FromFloat(30), TransactionDate: time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local)},
+		{Type: models.TransactionTypeIncome, Amount: money.FromFloat(500), TransactionDate: time.Date(2024, 10, 15, 0, 0, 0, 0, time.Local)},
+		{Type: models.TransactionTypeRefund, Amount: money.FromFloat(5), TransactionDate: time.Date(2024, 11, 2, 0, 0, 0, 0, time.Local)},
+	} {
+		tx.UserID, tx.CategoryID, tx.AccountID, tx.BaseAmount = user.ID, groceries.ID, &checking.ID, tx.Amount
+		assert.NoError(t, db.Create(&tx).Error)
+	}
+	// Transactions outside any account do not count.
+	createTransaction(t, transactionRepo, user.ID, groceries.ID, 70, "Cash")
+
+	totals, err := repo.LedgerTotals(user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[uint]money.Amount{checking.ID: money.FromFloat(475)}, totals)
+
+	total, err := repo.LedgerTotal(checking.ID, time.Date(2024, 11, 1, 0, 0, 0, 0, time.Local))
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromFloat(470), total)
+
+	count, err := repo.CountTransactions(checking.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+}
+
+func TestAccountRepository_LedgerTotals_Transfers(t *testing.T) {
+	_, db := setupTransactionTestRepo(t)
+	repo := account.NewAccountRepository(db)
+	user := createUser(t, db)
+	groceries := createCategoryGroceries(t, db, user.ID)
+
+	checking := &models.Account{UserID: user.ID, Name: "Checking", Type: models.AccountTypeChecking, Currency: "USD"}
+	savings := &models.Account{UserID: user.ID, Name: "Savings", Type: models.AccountTypeSavings, Currency: "USD"}
+	assert.NoError(t, repo.Create(checking))
+	assert.NoError(t, repo.Create(savings))
+
+	date := time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local)
+	out := models.Transaction{UserID: user.ID, CategoryID: groceries.ID, AccountID: &checking.ID, Type: models.TransactionTypeTransfer, Amount: money.FromFloat(200), BaseAmount: money.FromFloat(200), TransactionDate: date}
+	assert.NoError(t, db.Create(&out).Error)
+	in := models.Transaction{UserID: user.ID, CategoryID: groceries.ID, AccountID: &savings.ID, Type: models.TransactionTypeTransfer, TransferID: &out.ID, TransferIn: true, Amount: money.FromFloat(200), BaseAmount: money.FromFloat(200), TransactionDate: date}
+	assert.NoError(t, db.Create(&in).Error)
+
+	totals, err := repo.LedgerTotals(user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[uint]money.Amount{checking.ID: money.FromFloat(-200), savings.ID: money.FromFloat(200)}, totals)
+}
@@ -1,10 +1,14 @@
 package test
 
 import (
+	"io"
 	"testing"
+	"time"
 
 	"github.com/shaikhjunaidx/pennywise-backend/internal/budget"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/category"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/notify"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/user"
 	"github.com/shaikhjunaidx/pennywise-backend/models"
 	"github.com/shaikhjunaidx/pennywise-backend/testutils"
@@ -24,7 +28,9 @@ func setupBudgetTestRepo(t *testing.T) (*budget.BudgetRepositoryImpl, *gorm.DB)
 
 func setupTestUserService(db *gorm.DB) *user.UserService {
 	userRepo := user.NewUserRepository(db)
-	return user.NewUserService(userRepo, nil, nil)
+	userService := user.NewUserService(userRepo, nil, nil)
+	userService.Notifier = notify.NewLogNotifier(io.Discard)
+	return userService
 }
 
 func setupTestCategoryService(db *gorm.DB, userService *user.UserService) *category.CategoryService {
@@ -42,9 +48,9 @@ func createTestBudget(t *testing.T, repo *budget.BudgetRepositoryImpl, user *mod
 	budget := &models.Budget{
 		UserID:          user.ID,
 		CategoryID:      categoryID,
-		AmountLimit:     amountLimit,
-		SpentAmount:     0.0,
-		RemainingAmount: amountLimit,
+		AmountLimit:     money.FromFloat(amountLimit),
+		SpentAmount:     0,
+		RemainingAmount: money.FromFloat(amountLimit),
 		BudgetMonth:     "09",
 		BudgetYear:      2024,
 	}
@@ -92,6 +98,25 @@ func TestBudgetRepository_CreateOverallBudget(t *testing.T) {
 	_ = createTestBudget(t, repo, user, nil, 1000.0)
 }
 
+func TestBudgetRepository_Create_RejectsSecondBudgetForPeriod(t *testing.T) {
+	repo, db := setupBudgetTestRepo(t)
+
+	userService := setupTestUserService(db)
+	categoryService := setupTestCategoryService(db, userService)
+	budgetService := budget.NewBudgetService(repo, userService)
+
+	userService.CategoryService = categoryService
+	userService.BudgetService = budgetService
+
+	user, err := userService.SignUp("john_doe", "john.doe@example.com", "password")
+	assert.NoError(t, err)
+
+	// Overall budgets have no category and are still one per month.
+	_ = createTestBudget(t, repo, user, nil, 1000.0)
+	err = repo.Create(&models.Budget{UserID: user.ID, AmountLimit: money.FromFloat(500.0), BudgetMonth: "09", BudgetYear: 2024})
+	assert.ErrorIs(t, err, budget.ErrBudgetExists)
+}
+
 func TestBudgetRepository_FindByID(t *testing.T) {
 	repo, db := setupBudgetTestRepo(t)
 
@@ -184,18 +209,18 @@ func TestBudgetRepository_Update(t *testing.T) {
 
 	// Create and update a budget
 	budget := createTestBudget(t, repo, user, category, 1000.0)
-	budget.AmountLimit = 1200.0
-	budget.SpentAmount = 200.0
-	budget.RemainingAmount = 1000.0
+	budget.AmountLimit = money.FromFloat(1200.0)
+	budget.SpentAmount = money.FromFloat(200.0)
+	budget.RemainingAmount = money.FromFloat(1000.0)
 	err = repo.Update(budget)
 	assert.NoError(t, err)
 
 	// Verify the update
 	updatedBudget, err := repo.FindByID(budget.ID)
 	assert.NoError(t, err)
-	assert.Equal(t, 1200.0, updatedBudget.AmountLimit)
-	assert.Equal(t, 200.0, updatedBudget.SpentAmount)
-	assert.Equal(t, 1000.0, updatedBudget.RemainingAmount)
+	assert.Equal(t, money.FromFloat(1200.0), updatedBudget.AmountLimit)
+	assert.Equal(t, money.FromFloat(200.0), updatedBudget.SpentAmount)
+	assert.Equal(t, money.FromFloat(1000.0), updatedBudget.RemainingAmount)
 }
 
 func TestBudgetRepository_DeleteByID(t *testing.T) {
@@ -249,6 +274,37 @@ func TestBudgetRepository_FindByUserIDAndCategoryID(t *testing.T) {
 	assert.Equal(t, createdBudget.ID, foundBudget.ID)
 }
 
+func TestBudgetRepository_FindByUserIDAndCategoryIDForUpdate_SeesConcurrentBudget(t *testing.T) {
+	db, setupTx := testutils.SetupTestDB()
+	setupTx.Rollback()
+
+	// The other request commits, so this test cleans up after itself.
+	user := &models.User{Username: "concurrent_budget", Email: "concurrent.budget@example.com", PasswordHash: "x"}
+	assert.NoError(t, db.Create(user).Error)
+	t.Cleanup(func() {
+		db.Where("user_id = ?", user.ID).Delete(&models.Budget{})
+		db.Delete(user)
+	})
+
+	tx := db.Begin()
+	defer tx.Rollback()
+	repo := budget.NewBudgetRepository(tx)
+
+	// The first read fixes the transaction's snapshot.
+	_, err := repo.FindByUserIDAndCategoryID(user.ID, nil, "09", 2024)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	opened := &models.Budget{UserID: user.ID, AmountLimit: money.FromFloat(500), BudgetMonth: "09", BudgetYear: 2024}
+	assert.NoError(t, budget.NewBudgetRepository(db).Create(opened))
+
+	_, err = repo.FindByUserIDAndCategoryID(user.ID, nil, "09", 2024)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	found, err := repo.FindByUserIDAndCategoryIDForUpdate(user.ID, nil, "09", 2024)
+	assert.NoError(t, err)
+	assert.Equal(t, opened.ID, found.ID)
+}
+
 func TestBudgetRepository_UpdateOverallBudget(t *testing.T) {
 	repo, db := setupBudgetTestRepo(t)
 
@@ -265,16 +321,47 @@ func TestBudgetRepository_UpdateOverallBudget(t *testing.T) {
 
 	// Create and update an overall budget (categoryID = nil)
 	budget := createTestBudget(t, repo, user, nil, 1000.0)
-	budget.AmountLimit = 1200.0
-	budget.SpentAmount = 200.0
-	budget.RemainingAmount = 1000.0
+	budget.AmountLimit = money.FromFloat(1200.0)
+	budget.SpentAmount = money.FromFloat(200.0)
+	budget.RemainingAmount = money.FromFloat(1000.0)
 	err = repo.Update(budget)
 	assert.NoError(t, err)
 
 	// Verify the update
 	updatedBudget, err := repo.FindByID(budget.ID)
 	assert.NoError(t, err)
-	assert.Equal(t, 1200.0, updatedBudget.AmountLimit)
-	assert.Equal(t, 200.0, updatedBudget.SpentAmount)
-	assert.Equal(t, 1000.0, updatedBudget.RemainingAmount)
+	assert.Equal(t, money.FromFloat(1200.0), updatedBudget.AmountLimit)
+	assert.Equal(t, money.FromFloat(200.0), updatedBudget.SpentAmount)
+	assert.Equal(t, money.FromFloat(1000.0), updatedBudget.RemainingAmount)
+}
+
+func TestBudgetRepository_SumLedgerSpent_RollsUpSubcategories(t *testing.T) {
+	repo, db := setupBudgetTestRepo(t)
+	user := createUser(t, db)
+	food := &models.Category{UserID: user.ID, Name: "Food"}
+	assert.NoError(t, db.Create(food).Error)
+	groceries := &models.Category{UserID: user.ID, Name: "Groceries", ParentID: &food.ID}
+	assert.NoError(t, db.Create(groceries).Error)
+	produce := &models.Category{UserID: user.ID, Name: "Produce", ParentID: &groceries.ID}
+	assert.NoError(t, db.Create(produce).Error)
+
+	date := time.Date(2024, time.September, 10, 12, 0, 0, 0, time.Local)
+	for _, tx := range []*models.Transaction{
+		{UserID: user.ID, CategoryID: food.ID, Amount: money.FromFloat(10.0), BaseAmount: money.FromFloat(10.0), TransactionDate: date},
+		{UserID: user.ID, CategoryID: groceries.ID, Amount: money.FromFloat(20.0), BaseAmount: money.FromFloat(20.0), TransactionDate: date},
+		{UserID: user.ID, CategoryID: produce.ID, Amount: money.FromFloat(30.0), BaseAmount: money.FromFloat(30.0), TransactionDate: date},
+	} {
+		assert.NoError(t, db.Create(tx).Error)
+	}
+
+	start, end, err := budget.Period("September", 2024, time.Local)
+	assert.NoError(t, err)
+
+	spent, err := repo.SumLedgerSpent(user.ID, &food.ID, start, end)
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromFloat(60.0), spent)
+
+	spent, err = repo.SumLedgerSpent(user.ID, &groceries.ID, start, end)
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromFloat(50.0), spent)
 }
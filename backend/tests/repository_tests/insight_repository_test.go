@@ -0,0 +1,37 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/insights"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsightRepository_FindExpensesSince(t *testing.T) {
+	_, db := setupTransactionTestRepo(t)
+	repo := insights.NewInsightRepository(db)
+	user := createUser(t, db)
+	groceries := createCategoryGroceries(t, db, user.ID)
+
+	for _, tx := range []models.Transaction{
+		{Type: models.TransactionTypeExpense, Description: "Too old", TransactionDate: time.Date(2024, 8, 31, 0, 0, 0, 0, time.Local)},
+		{Type: models.TransactionTypeExpense, Description: "Second", TransactionDate: time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local)},
+		{Type: models.TransactionTypeIncome, Description: "Salary", TransactionDate: time.Date(2024, 9, 15, 0, 0, 0, 0, time.Local)},
+		{Type: models.TransactionTypeExpense, Description: "First", TransactionDate: time.Date(2024, 9, 1, 0, 0, 0, 0, time.Local)},
+	} {
+		tx.UserID, tx.CategoryID, tx.Amount, tx.BaseAmount = user.ID, groceries.ID, money.FromFloat(10), money.FromFloat(10)
+		assert.NoError(t, db.Create(&tx).Error)
+	}
+
+	expenses, err := repo.FindExpensesSince(user.ID, time.Date(2024, 9, 1, 0, 0, 0, 0, time.Local))
+
+	assert.NoError(t, err)
+	var descriptions []string
+	for _, expense := range expenses {
+		descriptions = append(descriptions, expense.Description)
+	}
+	assert.Equal(t, []string{"First", "Second"}, descriptions)
+}
@@ -0,0 +1,75 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/recurring"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecurringRepository_FindDue(t *testing.T) {
+	_, db := setupTransactionTestRepo(t)
+	repo := recurring.NewRecurringRepository(db)
+	user := createUser(t, db)
+	groceries := createCategoryGroceries(t, db, user.ID)
+
+	due := time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local)
+	later := time.Date(2024, 11, 1, 0, 0, 0, 0, time.Local)
+	for _, next := range []*time.Time{&due, &later, nil} {
+		rule := &models.RecurringRule{UserID: user.ID, CategoryID: groceries.ID, Type: models.TransactionTypeExpense, Amount: money.FromFloat(10),
+			Frequency: models.FrequencyMonthly, Interval: 1, StartDate: due, NextDate: next}
+		assert.NoError(t, repo.Create(rule))
+	}
+
+	rules, err := repo.FindDue(time.Date(2024, 10, 15, 0, 0, 0, 0, time.Local))
+
+	assert.NoError(t, err)
+	assert.Len(t, rules, 1)
+	assert.True(t, due.Equal(*rules[0].NextDate))
+	assert.Equal(t, user.Username, rules[0].User.Username)
+}
+
+func TestRecurringRepository_Occurrences(t *testing.T) {
+	transactionRepo, db := setupTransactionTestRepo(t)
+	repo := recurring.NewRecurringRepository(db)
+	user := createUser(t, db)
+	groceries := createCategoryGroceries(t, db, user.ID)
+
+	start := time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local)
+	rule := &models.RecurringRule{UserID: user.ID, CategoryID: groceries.ID, Type: models.TransactionTypeExpense, Amount: money.FromFloat(10),
+		Frequency: models.FrequencyMonthly, Interval: 1, StartDate: start, NextDate: &start}
+	assert.NoError(t, repo.Create(rule))
+
+	booked := createTransaction(t, transactionRepo, user.ID, groceries.ID, 10, "Gym")
+	booked.ExternalID = "recurring:1:2024-10-01"
+	assert.NoError(t, db.Save(booked).Error)
+
+	next := start.AddDate(0, 1, 0)
+	rule.LastDate, rule.NextDate = &start, &next
+	occurrence := &models.RecurringOccurrence{RecurringRuleID: rule.ID, Date: start, TransactionID: &booked.ID}
+	assert.NoError(t, repo.SaveProgress(rule, occurrence))
+	assert.NoError(t, repo.SaveOccurrence(&models.RecurringOccurrence{RecurringRuleID: rule.ID, Date: next, Skipped: true}))
+
+	id, err := repo.FindBookedTransactionID(user.ID, "recurring:1:2024-10-01")
+	assert.NoError(t, err)
+	assert.Equal(t, booked.ID, id)
+
+	id, err = repo.FindBookedTransactionID(user.ID, "recurring:1:2024-11-01")
+	assert.NoError(t, err)
+	assert.Zero(t, id)
+
+	found, err := repo.FindOccurrence(rule.ID, next)
+	assert.NoError(t, err)
+	assert.True(t, found.Skipped)
+
+	occurrences, err := repo.FindOccurrences([]uint{rule.ID}, next, next.AddDate(0, 1, 0))
+	assert.NoError(t, err)
+	assert.Len(t, occurrences, 1)
+
+	assert.NoError(t, repo.DeleteByID(rule.ID))
+	_, err = repo.FindOccurrence(rule.ID, start)
+	assert.Error(t, err)
+}
@@ -0,0 +1,105 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/reports"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportRepository_Totals(t *testing.T) {
+	_, db := setupTransactionTestRepo(t)
+	repo := reports.NewReportRepository(db)
+	user := createUser(t, db)
+	groceries := createCategoryGroceries(t, db, user.ID)
+	utilities := createCategoryUtilities(t, db, user.ID)
+
+	for _, tx := range []models.Transaction{
+		{CategoryID: groceries.ID, BaseAmount: money.FromFloat(40), Description: "Market", TransactionDate: time.Date(2024, 9, 3, 0, 0, 0, 0, time.Local)},
+		{CategoryID: groceries.ID, Type: models.TransactionTypeRefund, BaseAmount: money.FromFloat(10), Description: "Market", TransactionDate: time.Date(2024, 9, 9, 0, 0, 0, 0, time.Local)},
+		{CategoryID: groceries.ID, BaseAmount: money.FromFloat(5), Description: "MARKET #0042", TransactionDate: time.Date(2024, 9, 12, 0, 0, 0, 0, time.Local)},
+		{CategoryID: utilities.ID, Type: models.TransactionTypeIncome, BaseAmount: money.FromFloat(500), Description: "Salary", TransactionDate: time.Date(2024, 9, 25, 0, 0, 0, 0, time.Local)},
+		{CategoryID: utilities.ID, BaseAmount: money.FromFloat(90), Description: "Power Co", TransactionDate: time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local)},
+	} {
+		tx.UserID, tx.Amount = user.ID, tx.BaseAmount
+		assert.NoError(t, db.Create(&tx).Error)
+	}
+
+	period := reports.Range{From: time.Date(2024, 9, 1, 0, 0, 0, 0, time.Local), To: time.Date(2024, 11, 1, 0, 0, 0, 0, time.Local)}
+
+	months, err := repo.TotalsByMonth(user.ID, period)
+	assert.NoError(t, err)
+	assert.Equal(t, []reports.MonthTotals{
+		{Year: 2024, Month: 9, Expense: money.FromFloat(35), Income: money.FromFloat(500)},
+		{Year: 2024, Month: 10, Expense: money.FromFloat(90)},
+	}, months)
+
+	categories, err := repo.SpendingByCategoryMonth(user.ID, period)
+	assert.NoError(t, err)
+	assert.Len(t, categories, 2)
+	assert.Equal(t, money.FromFloat(35), categories[0].Total)
+
+	top, err := repo.TopCategories(user.ID, period, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Utilities", top[0].CategoryName)
+
+	merchants, err := repo.TopMerchants(user.ID, period, 5)
+	assert.NoError(t, err)
+	assert.Len(t, merchants, 2)
+	assert.Equal(t, reports.MerchantTotal{Merchant: "Power Co", Total: money.FromFloat(90), Count: 1}, merchants[0])
+	// Spellings of the same merchant are counted together.
+	assert.Equal(t, money.FromFloat(35), merchants[1].Total)
+	assert.Equal(t, int64(3), merchants[1].Count)
+}
+
+func TestReportRepository_TotalsByBucket(t *testing.T) {
+	_, db := setupTransactionTestRepo(t)
+	repo := reports.NewReportRepository(db)
+	user := createUser(t, db)
+	groceries := createCategoryGroceries(t, db, user.ID)
+
+	for _, date := range []time.Time{
+		time.Date(2024, 10, 1, 9, 0, 0, 0, time.Local),
+		time.Date(2024, 10, 1, 18, 0, 0, 0, time.Local),
+		time.Date(2024, 10, 3, 12, 0, 0, 0, time.Local),
+	} {
+		tx := models.Transaction{UserID: user.ID, CategoryID: groceries.ID, Amount: money.FromFloat(5), BaseAmount: money.FromFloat(5), TransactionDate: date}
+		assert.NoError(t, db.Create(&tx).Error)
+	}
+
+	buckets, err := reports.Buckets(reports.GranularityDay, reports.Range{
+		From: time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local),
+		To:   time.Date(2024, 10, 4, 0, 0, 0, 0, time.Local),
+	}, time.Local, time.Monday)
+	assert.NoError(t, err)
+
+	totals, err := repo.TotalsByBucket(user.ID, buckets, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []reports.BucketTotal{
+		{Index: 0, Total: money.FromFloat(10), Count: 2},
+		{Index: 2, Total: money.FromFloat(5), Count: 1},
+	}, totals)
+}
+
+func TestReportRepository_TotalsByMonth_FollowsRangeTimeZone(t *testing.T) {
+	_, db := setupTransactionTestRepo(t)
+	repo := reports.NewReportRepository(db)
+	user := createUser(t, db)
+	groceries := createCategoryGroceries(t, db, user.ID)
+
+	// Late on 31 October in Los Angeles is already November in UTC.
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	assert.NoError(t, err)
+	tx := models.Transaction{UserID: user.ID, CategoryID: groceries.ID, Amount: money.FromFloat(20), BaseAmount: money.FromFloat(20),
+		TransactionDate: time.Date(2024, 10, 31, 22, 0, 0, 0, losAngeles)}
+	assert.NoError(t, db.Create(&tx).Error)
+
+	period := reports.Range{From: time.Date(2024, 10, 1, 0, 0, 0, 0, losAngeles), To: time.Date(2024, 12, 1, 0, 0, 0, 0, losAngeles)}
+	months, err := repo.TotalsByMonth(user.ID, period)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []reports.MonthTotals{{Year: 2024, Month: 10, Expense: money.FromFloat(20)}}, months)
+}
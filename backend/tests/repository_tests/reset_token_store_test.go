@@ -0,0 +1,97 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/user"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/shaikhjunaidx/pennywise-backend/testutils"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupResetTokenStore(t *testing.T) (*user.GormResetTokenStore, *gorm.DB) {
+	_, tx := testutils.SetupTestDB()
+	t.Cleanup(func() {
+		tx.Rollback()
+	})
+	return user.NewGormResetTokenStore(tx), tx
+}
+
+func TestGormResetTokenStore_StoresHashOnly(t *testing.T) {
+	store, db := setupResetTokenStore(t)
+
+	err := store.Save(&user.PasswordResetToken{
+		Token:     "plain-token",
+		UserEmail: "john.doe@example.com",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	var record models.PasswordResetToken
+	assert.NoError(t, db.Where("user_email = ?", "john.doe@example.com").First(&record).Error)
+	assert.Equal(t, user.HashResetToken("plain-token"), record.TokenHash)
+	assert.NotEqual(t, "plain-token", record.TokenHash)
+}
+
+func TestGormResetTokenStore_ConsumeOnce(t *testing.T) {
+	store, _ := setupResetTokenStore(t)
+
+	err := store.Save(&user.PasswordResetToken{
+		Token:     "plain-token",
+		UserEmail: "john.doe@example.com",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	resetToken, err := store.Consume("plain-token")
+	assert.NoError(t, err)
+	assert.Equal(t, "john.doe@example.com", resetToken.UserEmail)
+
+	_, err = store.Consume("plain-token")
+	assert.ErrorIs(t, err, user.ErrInvalidResetToken)
+}
+
+func TestGormResetTokenStore_ExpiredToken(t *testing.T) {
+	store, _ := setupResetTokenStore(t)
+
+	err := store.Save(&user.PasswordResetToken{
+		Token:     "expired-token",
+		UserEmail: "john.doe@example.com",
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+	assert.NoError(t, err)
+
+	_, err = store.Consume("expired-token")
+	assert.ErrorIs(t, err, user.ErrInvalidResetToken)
+}
+
+func TestGormResetTokenStore_RedeemRollsBackOnFailure(t *testing.T) {
+	store, _ := setupResetTokenStore(t)
+
+	err := store.Save(&user.PasswordResetToken{
+		Token:     "plain-token",
+		UserEmail: "john.doe@example.com",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	failure := errors.New("update failed")
+	err = store.Redeem("plain-token", nil, func(*user.PasswordResetToken, user.UserRepository) error {
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	// The failed redemption left the token usable.
+	err = store.Redeem("plain-token", nil, func(resetToken *user.PasswordResetToken, users user.UserRepository) error {
+		assert.Equal(t, "john.doe@example.com", resetToken.UserEmail)
+		assert.NotNil(t, users)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	_, err = store.Consume("plain-token")
+	assert.ErrorIs(t, err, user.ErrInvalidResetToken)
+}
@@ -4,6 +4,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/shaikhjunaidx/pennywise-backend/internal/budget"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
 	"github.com/shaikhjunaidx/pennywise-backend/models"
 	"github.com/shaikhjunaidx/pennywise-backend/testutils"
@@ -57,7 +59,7 @@ func createTransaction(t *testing.T, repo *transaction.TransactionRepositoryImpl
 	transaction := &models.Transaction{
 		UserID:          userID,
 		CategoryID:      categoryID,
-		Amount:          amount,
+		Amount:          money.FromFloat(amount),
 		Description:     description,
 		TransactionDate: time.Now(),
 	}
@@ -94,14 +96,14 @@ func TestTransactionRepository_Update(t *testing.T) {
 	transaction := createTransaction(t, repo, user.ID, category.ID, 100.0, "Groceries")
 
 	// Update the transaction
-	transaction.Amount = 200.0
+	transaction.Amount = money.FromFloat(200.0)
 	transaction.Description = "Updated Groceries"
 	err := repo.Update(transaction)
 	assert.NoError(t, err)
 
 	updatedTransaction, err := repo.FindByID(transaction.ID)
 	assert.NoError(t, err)
-	assert.Equal(t, 200.0, updatedTransaction.Amount)
+	assert.Equal(t, money.FromFloat(200.0), updatedTransaction.Amount)
 	assert.Equal(t, "Updated Groceries", updatedTransaction.Description)
 }
 
@@ -119,19 +121,6 @@ func TestTransactionRepository_DeleteByID(t *testing.T) {
 	assert.Nil(t, deletedTransaction)
 }
 
-func TestTransactionRepository_FindAllByUsername(t *testing.T) {
-	repo, db := setupTransactionTestRepo(t)
-	user := createUser(t, db)
-	category := createCategoryGroceries(t, db, user.ID)
-
-	createTransaction(t, repo, user.ID, category.ID, 50.0, "Dinner")
-	createTransaction(t, repo, user.ID, category.ID, 150.0, "Utilities")
-
-	transactions, err := repo.FindAllByUsername(user.Username)
-	assert.NoError(t, err)
-	assert.Len(t, transactions, 2) // We expect 2 transactions for the user
-}
-
 func TestTransactionRepository_FindAllByUserIDAndCategoryID(t *testing.T) {
 	repo, db := setupTransactionTestRepo(t)
 
@@ -140,7 +129,7 @@ func TestTransactionRepository_FindAllByUserIDAndCategoryID(t *testing.T) {
 
 	// Create two categories
 	category1 := createCategoryGroceries(t, db, user.ID)
-	category2 := createCategoryUtilities(t, db, user.ID, )
+	category2 := createCategoryUtilities(t, db, user.ID)
 
 	// Create transactions for the first category
 	createTransaction(t, repo, user.ID, category1.ID, 50.0, "Groceries Shopping")
@@ -158,7 +147,7 @@ func TestTransactionRepository_FindAllByUserIDAndCategoryID(t *testing.T) {
 	// Verify the details of the transactions returned for the first category
 	for _, transaction := range transactionsForCategory1 {
 		assert.Equal(t, category1.ID, transaction.CategoryID)
-		assert.Contains(t, []float64{50.0, 100.0}, transaction.Amount)
+		assert.Contains(t, []money.Amount{money.FromFloat(50.0), money.FromFloat(100.0)}, transaction.Amount)
 		assert.Contains(t, []string{"Groceries Shopping", "Weekly Groceries"}, transaction.Description)
 	}
 
@@ -170,7 +159,7 @@ func TestTransactionRepository_FindAllByUserIDAndCategoryID(t *testing.T) {
 	// Verify the details of the transactions returned for the second category
 	for _, transaction := range transactionsForCategory2 {
 		assert.Equal(t, category2.ID, transaction.CategoryID)
-		assert.Contains(t, []float64{150.0, 75.0}, transaction.Amount)
+		assert.Contains(t, []money.Amount{money.FromFloat(150.0), money.FromFloat(75.0)}, transaction.Amount)
 		assert.Contains(t, []string{"Electricity Bill", "Water Bill"}, transaction.Description)
 	}
 
@@ -181,4 +170,168 @@ func TestTransactionRepository_FindAllByUserIDAndCategoryID(t *testing.T) {
 	assert.Len(t, transactionsForNonExistentCategory, 0) // Expect no transactions for a non-existent category
 }
 
+func TestTransactionRepository_FindPage(t *testing.T) {
+	repo, db := setupTransactionTestRepo(t)
+	user := createUser(t, db)
+	groceries := createCategoryGroceries(t, db, user.ID)
+	utilities := createCategoryUtilities(t, db, user.ID)
+
+	createTransaction(t, repo, user.ID, groceries.ID, 12.0, "Bakery")
+	createTransaction(t, repo, user.ID, groceries.ID, 30.0, "Market 100%")
+	createTransaction(t, repo, user.ID, groceries.ID, 8.0, "Corner shop")
+	createTransaction(t, repo, user.ID, utilities.ID, 60.0, "Electricity")
+
+	query := transaction.TransactionQuery{CategoryIDs: []uint{groceries.ID}, Sort: transaction.SortByDescription, Limit: 2}
+	assert.NoError(t, query.Normalize())
+
+	page, err := repo.FindPage(user.ID, query)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), page.Total)
+	assert.Len(t, page.Transactions, 2)
+	assert.Equal(t, "Bakery", page.Transactions[0].Description)
+	assert.Equal(t, "Corner shop", page.Transactions[1].Description)
+	assert.NotEmpty(t, page.NextCursor)
+
+	query.Cursor = page.NextCursor
+	page, err = repo.FindPage(user.ID, query)
+	assert.NoError(t, err)
+	assert.Len(t, page.Transactions, 1)
+	assert.Equal(t, "Market 100%", page.Transactions[0].Description)
+	assert.Empty(t, page.NextCursor)
+
+	// Wildcards in the search text match literally.
+	search := transaction.TransactionQuery{Search: "100%"}
+	assert.NoError(t, search.Normalize())
+	page, err = repo.FindPage(user.ID, search)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+}
+
+func TestTransactionRepository_SearchFragments(t *testing.T) {
+	repo, db := setupTransactionTestRepo(t)
+	user := createUser(t, db)
+	groceries := createCategoryGroceries(t, db, user.ID)
+
+	exact := createTransaction(t, repo, user.ID, groceries.ID, 12.0, "Amazon refund")
+	createTransaction(t, repo, user.ID, groceries.ID, 30.0, "Amazn Marketplace refnd")
+	createTransaction(t, repo, user.ID, groceries.ID, 8.0, "Amazon order")
 
+	found, err := repo.SearchFragments(user.ID, [][]string{{"ama", "zon"}, {"ref", "und"}}, []uint{exact.ID}, 10)
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, "Amazn Marketplace refnd", found[0].Description)
+}
+
+func TestTransactionRepository_Splits(t *testing.T) {
+	repo, db := setupTransactionTestRepo(t)
+	user := createUser(t, db)
+	groceries := createCategoryGroceries(t, db, user.ID)
+	utilities := createCategoryUtilities(t, db, user.ID)
+
+	receipt := &models.Transaction{
+		UserID:          user.ID,
+		CategoryID:      groceries.ID,
+		Amount:          money.FromFloat(100.0),
+		BaseAmount:      money.FromFloat(100.0),
+		Description:     "Costco",
+		TransactionDate: time.Now(),
+		Splits: []models.TransactionSplit{
+			{CategoryID: groceries.ID, Amount: money.FromFloat(70.0), BaseAmount: money.FromFloat(70.0)},
+			{CategoryID: utilities.ID, Amount: money.FromFloat(30.0), BaseAmount: money.FromFloat(30.0)},
+		},
+	}
+	assert.NoError(t, repo.Create(receipt))
+	assert.Len(t, receipt.Splits, 2)
+
+	// The receipt shows up under both categories, with the part in each.
+	inUtilities, err := repo.FindAllByUserIDAndCategoryID(user.ID, utilities.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, inUtilities, 1) {
+		assert.Equal(t, receipt.ID, inUtilities[0].ID)
+		assert.Equal(t, money.FromFloat(30.0), *inUtilities[0].SplitAmount)
+	}
+
+	query := transaction.TransactionQuery{CategoryIDs: []uint{utilities.ID}}
+	assert.NoError(t, query.Normalize())
+	page, err := repo.FindPage(user.ID, query)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+
+	// Each category's budget counts only its own split.
+	budgets := budget.NewBudgetRepository(db)
+	start, end, err := budget.Period(receipt.TransactionDate.Month().String(), receipt.TransactionDate.Year(), time.Local)
+	assert.NoError(t, err)
+	spent, err := budgets.SumLedgerSpent(user.ID, &utilities.ID, start, end)
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromFloat(30.0), spent)
+	spent, err = budgets.SumLedgerSpent(user.ID, nil, start, end)
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromFloat(100.0), spent)
+
+	// Saving the receipt without splits leaves it in one category.
+	receipt.Splits = nil
+	assert.NoError(t, repo.Update(receipt))
+	inUtilities, err = repo.FindAllByUserIDAndCategoryID(user.ID, utilities.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, inUtilities)
+
+	var stored int64
+	db.Model(&models.TransactionSplit{}).Where("transaction_id = ?", receipt.ID).Count(&stored)
+	assert.Zero(t, stored)
+}
+
+func TestTransactionRepository_EachInRange(t *testing.T) {
+	repo, db := setupTransactionTestRepo(t)
+	user := createUser(t, db)
+	category := createCategoryGroceries(t, db, user.ID)
+
+	for i, day := range []int{3, 1, 2, 9} {
+		transaction := &models.Transaction{
+			UserID:          user.ID,
+			CategoryID:      category.ID,
+			Amount:          money.FromFloat(float64(i + 1)),
+			TransactionDate: time.Date(2024, 10, day, 12, 0, 0, 0, time.Local),
+		}
+		assert.NoError(t, repo.Create(transaction))
+	}
+
+	var days []int
+	err := repo.EachInRange(user.ID, time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local), time.Date(2024, 10, 4, 0, 0, 0, 0, time.Local), nil,
+		func(transaction *models.Transaction) error {
+			days = append(days, transaction.TransactionDate.Day())
+			return nil
+		})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, days)
+}
+
+func TestTransactionRepository_EachInRange_IncludesSplitTransactions(t *testing.T) {
+	repo, db := setupTransactionTestRepo(t)
+	user := createUser(t, db)
+	groceries := createCategoryGroceries(t, db, user.ID)
+	utilities := createCategoryUtilities(t, db, user.ID)
+
+	receipt := &models.Transaction{
+		UserID:          user.ID,
+		CategoryID:      groceries.ID,
+		Amount:          money.FromFloat(100.0),
+		TransactionDate: time.Date(2024, 10, 2, 12, 0, 0, 0, time.Local),
+		Splits: []models.TransactionSplit{
+			{CategoryID: groceries.ID, Amount: money.FromFloat(70.0)},
+			{CategoryID: utilities.ID, Amount: money.FromFloat(30.0)},
+		},
+	}
+	assert.NoError(t, repo.Create(receipt))
+	createTransaction(t, repo, user.ID, groceries.ID, 20.0, "Market")
+
+	var ids []uint
+	err := repo.EachInRange(user.ID, time.Time{}, time.Time{}, []uint{utilities.ID}, func(transaction *models.Transaction) error {
+		ids = append(ids, transaction.ID)
+		assert.Len(t, transaction.Splits, 2)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{receipt.ID}, ids)
+}
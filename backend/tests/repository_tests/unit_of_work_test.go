@@ -0,0 +1,64 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitOfWork_RollsBackOnError(t *testing.T) {
+	_, db := setupTransactionTestRepo(t)
+	user := createUser(t, db)
+	category := createCategoryGroceries(t, db, user.ID)
+	unitOfWork := transaction.NewUnitOfWork(db)
+
+	failure := errors.New("budget update failed")
+	var created *models.Transaction
+
+	err := unitOfWork.Do(func(repos transaction.Repositories) error {
+		created = &models.Transaction{UserID: user.ID, CategoryID: category.ID, Amount: money.FromFloat(25.0), Description: "Lunch"}
+		if err := repos.Transactions.Create(created); err != nil {
+			return err
+		}
+		return failure
+	})
+
+	assert.ErrorIs(t, err, failure)
+
+	var count int64
+	db.Model(&models.Transaction{}).Where("id = ?", created.ID).Count(&count)
+	assert.Zero(t, count)
+}
+
+func TestBudgetRepository_RecalculateSpent(t *testing.T) {
+	repo, db := setupBudgetTestRepo(t)
+	user := createUser(t, db)
+	category := createCategoryGroceries(t, db, user.ID)
+	budget := createTestBudget(t, repo, user, category, 1000.0)
+
+	september := time.Date(2024, time.September, 10, 12, 0, 0, 0, time.Local)
+	october := time.Date(2024, time.October, 1, 0, 0, 0, 0, time.Local)
+	for _, tx := range []*models.Transaction{
+		{UserID: user.ID, CategoryID: category.ID, Amount: money.FromFloat(150.0), TransactionDate: september},
+		{UserID: user.ID, CategoryID: category.ID, Type: models.TransactionTypeRefund, Amount: money.FromFloat(50.0), TransactionDate: september},
+		{UserID: user.ID, CategoryID: category.ID, Amount: money.FromFloat(999.0), TransactionDate: october},
+	} {
+		assert.NoError(t, db.Create(tx).Error)
+	}
+
+	// Stale totals left behind by an earlier bug should be overwritten.
+	db.Model(&models.Budget{}).Where("id = ?", budget.ID).Update("spent_amount", money.FromFloat(42.0))
+
+	start := time.Date(2024, time.September, 1, 0, 0, 0, 0, time.Local)
+	assert.NoError(t, repo.RecalculateSpent(budget.ID, start, start.AddDate(0, 1, 0)))
+
+	updated, err := repo.FindByID(budget.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromFloat(100.0), updated.SpentAmount)
+	assert.Equal(t, money.FromFloat(900.0), updated.RemainingAmount)
+}
@@ -0,0 +1,48 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/scheduler"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingJob struct {
+	name string
+	err  error
+	runs []time.Time
+}
+
+func (j *recordingJob) Name() string { return j.name }
+
+func (j *recordingJob) Run(ctx context.Context, now time.Time) error {
+	j.runs = append(j.runs, now)
+	return j.err
+}
+
+func TestScheduler_RunOnce_RunsEveryJobDespiteFailures(t *testing.T) {
+	now := time.Date(2024, 10, 1, 0, 5, 0, 0, time.UTC)
+	failing := &recordingJob{name: "failing", err: errors.New("boom")}
+	healthy := &recordingJob{name: "healthy"}
+
+	s := scheduler.New(time.Hour, failing, healthy)
+	s.Now = func() time.Time { return now }
+
+	s.RunOnce(context.Background())
+
+	assert.Equal(t, []time.Time{now}, failing.runs)
+	assert.Equal(t, []time.Time{now}, healthy.runs)
+}
+
+func TestScheduler_RunOnce_StopsWhenCancelled(t *testing.T) {
+	job := &recordingJob{name: "job"}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	scheduler.New(time.Hour, job).RunOnce(ctx)
+
+	assert.Empty(t, job.runs)
+}
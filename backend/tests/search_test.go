@@ -0,0 +1,36 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTerms(t *testing.T) {
+	assert.Equal(t, []string{"amazon", "refund", "2024"}, transaction.SearchTerms("  Amazon refund (2024)!"))
+	assert.Empty(t, transaction.SearchTerms(" -- "))
+}
+
+func TestScoreMatch(t *testing.T) {
+	refund := &models.Transaction{Description: "AMAZON MKTPLACE", Notes: "Refund for headphones"}
+
+	cases := []struct {
+		text  string
+		score float64
+	}{
+		{"amazon", 1},
+		{"amaz", 0.8},
+		{"mktplace", 1},
+		{"phones", 0.7},
+		{"amazn", 0.5},
+		{"amazon refund", 1},
+		{"amazon rent", 0},
+		{"amx", 0},
+	}
+
+	for _, c := range cases {
+		assert.InDelta(t, c.score, transaction.ScoreMatch(transaction.SearchTerms(c.text), refund), 0.001, c.text)
+	}
+}
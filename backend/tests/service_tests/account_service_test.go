@@ -0,0 +1,167 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/account"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/shaikhjunaidx/pennywise-backend/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func setUpAccountService() (*account.AccountService, *mocks.MockAccountRepository, *mocks.MockUserRepository) {
+	mockRepo := new(mocks.MockAccountRepository)
+	mockUserRepo := &mocks.MockUserRepository{
+		Users: make(map[string]*models.User),
+	}
+
+	return account.NewAccountService(mockRepo, mockUserRepo), mockRepo, mockUserRepo
+}
+
+func TestAccountService_CreateAccount(t *testing.T) {
+	service, mockRepo, mockUserRepo := setUpAccountService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	mockRepo.On("Create", mock.Anything).Return(nil)
+
+	created, err := service.CreateAccount(user.Username, account.AccountInput{Name: " Visa ", Type: "Credit_Card", OpeningBalance: money.FromFloat(-250)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Visa", created.Name)
+	assert.Equal(t, models.AccountTypeCreditCard, created.Type)
+	assert.Equal(t, money.DefaultCurrency, created.Currency)
+	assert.Equal(t, user.ID, created.UserID)
+
+	_, err = service.CreateAccount(user.Username, account.AccountInput{Name: "Piggy bank", Type: "jar"})
+	assert.ErrorIs(t, err, account.ErrInvalidAccount)
+}
+
+func TestAccountService_GetAccounts_AddsOpeningBalance(t *testing.T) {
+	service, mockRepo, mockUserRepo := setUpAccountService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	checking := &models.Account{ID: 1, UserID: user.ID, Name: "Checking", OpeningBalance: money.FromFloat(100)}
+	cash := &models.Account{ID: 2, UserID: user.ID, Name: "Cash", OpeningBalance: money.FromFloat(20)}
+	mockRepo.On("FindAllByUserID", user.ID).Return([]*models.Account{checking, cash}, nil)
+	mockRepo.On("LedgerTotals", user.ID).Return(map[uint]money.Amount{1: money.FromFloat(-40)}, nil)
+
+	accounts, err := service.GetAccounts(user.Username)
+
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 2)
+	assert.Equal(t, money.FromFloat(60), accounts[0].Balance)
+	assert.Equal(t, money.FromFloat(20), accounts[1].Balance)
+}
+
+func TestAccountService_GetRegister_RunningBalance(t *testing.T) {
+	service, mockRepo, mockUserRepo := setUpAccountService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	checking := &models.Account{ID: 1, UserID: user.ID, OpeningBalance: money.FromFloat(100)}
+	mockRepo.On("FindByID", checking.ID).Return(checking, nil)
+	mockRepo.On("FindTransactions", checking.ID, time.Time{}, time.Time{}).Return([]*models.Transaction{
+		{ID: 1, Type: models.TransactionTypeExpense, Amount: money.FromFloat(30)},
+		{ID: 2, Type: models.TransactionTypeIncome, Amount: money.FromFloat(200)},
+		{ID: 3, Type: models.TransactionTypeRefund, Amount: money.FromFloat(5)},
+	}, nil)
+
+	register, err := service.GetRegister(user.Username, checking.ID, time.Time{}, time.Time{})
+
+	assert.NoError(t, err)
+	assert.Len(t, register, 3)
+	assert.Equal(t, money.FromFloat(70), register[0].Balance)
+	assert.Equal(t, money.FromFloat(270), register[1].Balance)
+	assert.Equal(t, money.FromFloat(275), register[2].Balance)
+	mockRepo.AssertNotCalled(t, "LedgerTotal", mock.Anything, mock.Anything)
+}
+
+func TestAccountService_GetRegister_Range(t *testing.T) {
+	service, mockRepo, mockUserRepo := setUpAccountService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	checking := &models.Account{ID: 1, UserID: user.ID, OpeningBalance: money.FromFloat(100)}
+	from := time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local)
+	before := time.Date(2024, 11, 1, 0, 0, 0, 0, time.Local)
+
+	mockRepo.On("FindByID", checking.ID).Return(checking, nil)
+	// Everything booked before October is summed up by the database.
+	mockRepo.On("LedgerTotal", checking.ID, from).Return(money.FromFloat(400), nil)
+	mockRepo.On("FindTransactions", checking.ID, from, before).Return([]*models.Transaction{
+		{ID: 7, Type: models.TransactionTypeExpense, Amount: money.FromFloat(50)},
+	}, nil)
+
+	register, err := service.GetRegister(user.Username, checking.ID,
+		time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 10, 31, 0, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Len(t, register, 1)
+	assert.Equal(t, money.FromFloat(450), register[0].Balance)
+}
+
+func TestAccountService_GetRegister_OtherUsersAccount(t *testing.T) {
+	service, mockRepo, mockUserRepo := setUpAccountService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	mockRepo.On("FindByID", uint(5)).Return(&models.Account{ID: 5, UserID: 2}, nil)
+	mockRepo.On("FindByID", uint(6)).Return((*models.Account)(nil), gorm.ErrRecordNotFound)
+
+	_, err := service.GetRegister(user.Username, 5, time.Time{}, time.Time{})
+	assert.ErrorIs(t, err, account.ErrAccountAccessDenied)
+
+	_, err = service.GetRegister(user.Username, 6, time.Time{}, time.Time{})
+	assert.ErrorIs(t, err, account.ErrAccountNotFound)
+}
+
+func TestAccountService_Reconcile(t *testing.T) {
+	service, mockRepo, mockUserRepo := setUpAccountService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	checking := &models.Account{ID: 1, UserID: user.ID, OpeningBalance: money.FromFloat(100)}
+	statementDate := time.Date(2024, 10, 31, 0, 0, 0, 0, time.Local)
+	purchase := &models.Transaction{ID: 1, Type: models.TransactionTypeExpense, Amount: money.FromFloat(30), TransactionDate: statementDate.Add(20 * time.Hour)}
+
+	mockRepo.On("FindByID", checking.ID).Return(checking, nil)
+	mockRepo.On("WithLockedAccount", checking).Return(nil)
+	mockRepo.On("LedgerTotal", checking.ID, statementDate.AddDate(0, 0, 1)).Return(money.FromFloat(-30), nil)
+	mockRepo.On("FindTransactions", checking.ID, time.Time{}, statementDate.AddDate(0, 0, 1)).Return([]*models.Transaction{purchase}, nil)
+
+	// A statement that disagrees is reported but not recorded.
+	result, err := service.Reconcile(user.Username, checking.ID, statementDate, money.FromFloat(75))
+
+	assert.NoError(t, err)
+	assert.False(t, result.Reconciled)
+	assert.Equal(t, money.FromFloat(70), result.LedgerBalance)
+	assert.Equal(t, money.FromFloat(5), result.Difference)
+	assert.Equal(t, []*models.Transaction{purchase}, result.Unreconciled)
+	mockRepo.AssertNotCalled(t, "SaveReconciliation", mock.Anything, mock.Anything)
+
+	mockRepo.On("SaveReconciliation", checking, mock.Anything).Return(nil)
+
+	result, err = service.Reconcile(user.Username, checking.ID, statementDate, money.FromFloat(70))
+
+	assert.NoError(t, err)
+	assert.True(t, result.Reconciled)
+	mockRepo.AssertCalled(t, "WithLockedAccount", checking)
+	assert.True(t, checking.ReconciledThrough.Equal(statementDate))
+	assert.Equal(t, money.FromFloat(70), checking.ReconciledBalance)
+
+	_, err = service.Reconcile(user.Username, checking.ID, statementDate.AddDate(0, 0, -1), money.FromFloat(70))
+	assert.ErrorIs(t, err, account.ErrInvalidAccount)
+}
+
+func TestAccountService_DeleteAccount_WithTransactions(t *testing.T) {
+	service, mockRepo, mockUserRepo := setUpAccountService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	mockRepo.On("FindByID", uint(1)).Return(&models.Account{ID: 1, UserID: user.ID}, nil)
+	mockRepo.On("CountTransactions", uint(1)).Return(int64(3), nil)
+
+	err := service.DeleteAccount(user.Username, 1)
+
+	assert.ErrorIs(t, err, account.ErrAccountInUse)
+	mockRepo.AssertNotCalled(t, "DeleteByID", mock.Anything)
+}
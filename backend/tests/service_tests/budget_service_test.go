@@ -2,12 +2,15 @@ package test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/shaikhjunaidx/pennywise-backend/internal/budget"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
 	"github.com/shaikhjunaidx/pennywise-backend/models"
 	"github.com/shaikhjunaidx/pennywise-backend/tests/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 func setupBudgetService() (*budget.BudgetService, *mocks.MockBudgetRepository) {
@@ -35,9 +38,9 @@ func TestBudgetService_CreateBudget(t *testing.T) {
 	budget := &models.Budget{
 		UserID:          user.ID,
 		CategoryID:      nil, // Assuming this is an overall budget
-		AmountLimit:     1000.0,
+		AmountLimit:     money.FromFloat(1000.0),
 		SpentAmount:     0,
-		RemainingAmount: 1000.0,
+		RemainingAmount: money.FromFloat(1000.0),
 		BudgetMonth:     "09",
 		BudgetYear:      2024,
 	}
@@ -54,27 +57,45 @@ func TestBudgetService_CreateBudget(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestBudgetService_CreateBudget_Exists(t *testing.T) {
+	service, mockRepo := setupBudgetService()
+
+	username := "john_doe"
+	createBudgetTestUser(service.UserService.Repo.(*mocks.MockUserRepository), username, 1)
+
+	mockRepo.On("Create", mock.MatchedBy(func(b *models.Budget) bool { return b.BudgetMonth == "09" })).Return(budget.ErrBudgetExists)
+
+	_, err := service.CreateBudget(username, nil, money.FromFloat(1000), "September", 2024)
+	assert.ErrorIs(t, err, budget.ErrBudgetExists)
+
+	_, err = service.CreateBudget(username, nil, money.FromFloat(1000), "13", 2024)
+	assert.ErrorIs(t, err, budget.ErrInvalidMonth)
+}
+
 func TestBudgetService_UpdateBudget(t *testing.T) {
 	service, mockRepo := setupBudgetService()
 
+	username := "john_doe"
+	createBudgetTestUser(service.UserService.Repo.(*mocks.MockUserRepository), username, 1)
+
 	budgetID := uint(1)
-	newAmountLimit := 2000.0
+	newAmountLimit := money.FromFloat(2000.0)
 
 	existingBudget := &models.Budget{
 		ID:              budgetID,
 		UserID:          1,
 		CategoryID:      nil,
-		AmountLimit:     1000.0,
-		SpentAmount:     500.0,
-		RemainingAmount: 500.0,
+		AmountLimit:     money.FromFloat(1000.0),
+		SpentAmount:     money.FromFloat(500.0),
+		RemainingAmount: money.FromFloat(500.0),
 		BudgetMonth:     "09",
 		BudgetYear:      2024,
 	}
 
 	mockRepo.On("FindByID", budgetID).Return(existingBudget, nil)
-	mockRepo.On("Update", mock.Anything).Return(nil)
+	mockRepo.On("UpdateAmountLimit", budgetID, newAmountLimit).Return(nil)
 
-	result, err := service.UpdateBudget(budgetID, newAmountLimit)
+	result, err := service.UpdateBudget(username, budgetID, newAmountLimit)
 
 	assert.NoError(t, err)
 	assert.Equal(t, newAmountLimit, result.AmountLimit)
@@ -83,14 +104,59 @@ func TestBudgetService_UpdateBudget(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
-func TestBudgetService_AddTransactionToBudget(t *testing.T) {
+func TestBudgetService_GetBudgetByID_OtherUsersBudget(t *testing.T) {
+	service, mockRepo := setupBudgetService()
+
+	username := "john_doe"
+	createBudgetTestUser(service.UserService.Repo.(*mocks.MockUserRepository), username, 1)
+
+	otherUsersBudget := &models.Budget{ID: 7, UserID: 2, AmountLimit: money.FromFloat(300.0), BudgetMonth: "09", BudgetYear: 2024}
+	mockRepo.On("FindByID", otherUsersBudget.ID).Return(otherUsersBudget, nil)
+
+	result, err := service.GetBudgetByID(username, otherUsersBudget.ID)
+
+	assert.ErrorIs(t, err, budget.ErrBudgetAccessDenied)
+	assert.Nil(t, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBudgetService_DeleteBudget_OtherUsersBudget(t *testing.T) {
+	service, mockRepo := setupBudgetService()
+
+	username := "john_doe"
+	createBudgetTestUser(service.UserService.Repo.(*mocks.MockUserRepository), username, 1)
+
+	otherUsersBudget := &models.Budget{ID: 7, UserID: 2}
+	mockRepo.On("FindByID", otherUsersBudget.ID).Return(otherUsersBudget, nil)
+
+	err := service.DeleteBudget(username, otherUsersBudget.ID)
+
+	assert.ErrorIs(t, err, budget.ErrBudgetAccessDenied)
+	mockRepo.AssertNotCalled(t, "DeleteByID", otherUsersBudget.ID)
+}
+
+func TestBudgetService_UpdateBudget_NotFound(t *testing.T) {
+	service, mockRepo := setupBudgetService()
+
+	username := "john_doe"
+	createBudgetTestUser(service.UserService.Repo.(*mocks.MockUserRepository), username, 1)
+
+	mockRepo.On("FindByID", uint(42)).Return((*models.Budget)(nil), gorm.ErrRecordNotFound)
+
+	result, err := service.UpdateBudget(username, 42, money.FromFloat(100.0))
+
+	assert.ErrorIs(t, err, budget.ErrBudgetNotFound)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "UpdateAmountLimit", mock.Anything, mock.Anything)
+}
+
+func TestBudgetService_RecalculateBudget(t *testing.T) {
 	service, mockRepo := setupBudgetService()
 
 	username := "john_doe"
 	user := createBudgetTestUser(service.UserService.Repo.(*mocks.MockUserRepository), username, 1)
 
 	categoryID := uint(1)
-	transactionAmount := 200.0
 	month := "09"
 	year := 2024
 
@@ -98,22 +164,30 @@ func TestBudgetService_AddTransactionToBudget(t *testing.T) {
 		ID:              1,
 		UserID:          user.ID,
 		CategoryID:      &categoryID,
-		AmountLimit:     1000.0,
-		SpentAmount:     300.0,
-		RemainingAmount: 700.0,
+		AmountLimit:     money.FromFloat(1000.0),
+		SpentAmount:     money.FromFloat(300.0),
+		RemainingAmount: money.FromFloat(700.0),
 		BudgetMonth:     month,
 		BudgetYear:      year,
 	}
 
 	expectedBudget := &models.Budget{
-		SpentAmount:     500.0,
-		RemainingAmount: 500.0,
+		ID:              existingBudget.ID,
+		UserID:          user.ID,
+		CategoryID:      &categoryID,
+		AmountLimit:     money.FromFloat(1000.0),
+		SpentAmount:     money.FromFloat(500.0),
+		RemainingAmount: money.FromFloat(500.0),
+		BudgetMonth:     month,
+		BudgetYear:      year,
 	}
 
 	mockRepo.On("FindByUserIDAndCategoryID", user.ID, &categoryID, month, year).Return(existingBudget, nil)
-	mockRepo.On("Update", mock.Anything).Return(nil)
+	mockRepo.On("RecalculateSpent", existingBudget.ID, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("FindByUserIDAndCategoryID", user.ID, (*uint)(nil), month, year).Return((*models.Budget)(nil), gorm.ErrRecordNotFound)
+	mockRepo.On("FindByID", existingBudget.ID).Return(expectedBudget, nil)
 
-	result, err := service.AddTransactionToBudget(user.ID, &categoryID, transactionAmount, month, year)
+	result, err := service.RecalculateBudget(user.ID, &categoryID, month, year)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedBudget.SpentAmount, result.SpentAmount)
@@ -121,3 +195,358 @@ func TestBudgetService_AddTransactionToBudget(t *testing.T) {
 
 	mockRepo.AssertExpectations(t)
 }
+
+func TestBudgetService_RecalculateBudget_RefreshesOverallBudget(t *testing.T) {
+	service, mockRepo := setupBudgetService()
+
+	categoryID := uint(1)
+	month := "09"
+	year := 2024
+
+	categoryBudget := &models.Budget{ID: 1, UserID: 1, CategoryID: &categoryID, BudgetMonth: month, BudgetYear: year}
+	overallBudget := &models.Budget{ID: 2, UserID: 1, BudgetMonth: month, BudgetYear: year}
+
+	mockRepo.On("FindByUserIDAndCategoryID", uint(1), &categoryID, month, year).Return(categoryBudget, nil)
+	mockRepo.On("FindByUserIDAndCategoryID", uint(1), (*uint)(nil), month, year).Return(overallBudget, nil)
+	mockRepo.On("RecalculateSpent", categoryBudget.ID, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("RecalculateSpent", overallBudget.ID, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("FindByID", categoryBudget.ID).Return(categoryBudget, nil)
+
+	_, err := service.RecalculateBudget(1, &categoryID, month, year)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBudgetService_ReconcileForUser(t *testing.T) {
+	service, mockRepo := setupBudgetService()
+
+	username := "john_doe"
+	user := createBudgetTestUser(service.UserService.Repo.(*mocks.MockUserRepository), username, 1)
+
+	groceries, rent := uint(1), uint(2)
+	inSync := &models.Budget{ID: 1, UserID: user.ID, CategoryID: &groceries, AmountLimit: money.FromFloat(500), SpentAmount: money.FromFloat(120), RemainingAmount: money.FromFloat(380), BudgetMonth: "09", BudgetYear: 2024}
+	drifted := &models.Budget{ID: 2, UserID: user.ID, CategoryID: &rent, AmountLimit: money.FromFloat(1000), SpentAmount: money.FromFloat(300), RemainingAmount: money.FromFloat(700), BudgetMonth: "09", BudgetYear: 2024}
+
+	mockRepo.On("FindForReconciliation", &user.ID).Return([]*models.Budget{inSync, drifted}, nil)
+	september := time.Date(2024, time.September, 1, 0, 0, 0, 0, time.Local)
+	mockRepo.On("SumLedgerSpent", user.ID, &groceries, september, september.AddDate(0, 1, 0)).Return(money.FromFloat(120.0), nil)
+	mockRepo.On("SumLedgerSpent", user.ID, &rent, september, september.AddDate(0, 1, 0)).Return(money.FromFloat(900.0), nil)
+
+	report, err := service.ReconcileForUser(username, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.BudgetsChecked)
+	assert.Len(t, report.Drifts, 1)
+	assert.Equal(t, drifted.ID, report.Drifts[0].BudgetID)
+	assert.Equal(t, money.FromFloat(300.0), report.Drifts[0].RecordedSpent)
+	assert.Equal(t, money.FromFloat(900.0), report.Drifts[0].LedgerSpent)
+	mockRepo.AssertNotCalled(t, "RecalculateSpent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBudgetService_ReconcileAll_Repair(t *testing.T) {
+	service, mockRepo := setupBudgetService()
+
+	categoryID := uint(1)
+	drifted := &models.Budget{ID: 7, UserID: 3, CategoryID: &categoryID, AmountLimit: money.FromFloat(200), SpentAmount: money.FromFloat(50), RemainingAmount: money.FromFloat(150), BudgetMonth: "10", BudgetYear: 2024}
+
+	mockRepo.On("FindForReconciliation", (*uint)(nil)).Return([]*models.Budget{drifted}, nil)
+	october := time.Date(2024, time.October, 1, 0, 0, 0, 0, time.Local)
+	mockRepo.On("SumLedgerSpent", uint(3), &categoryID, october, october.AddDate(0, 1, 0)).Return(money.FromFloat(75.0), nil)
+	mockRepo.On("RecalculateSpent", drifted.ID, mock.Anything, mock.Anything).Return(nil)
+
+	report, err := service.ReconcileAll(true)
+
+	assert.NoError(t, err)
+	assert.True(t, report.Repaired)
+	assert.Len(t, report.Drifts, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBudgetService_EnsureBudget_CreatesZeroLimitBudget(t *testing.T) {
+	service, mockRepo := setupBudgetService()
+
+	categoryID := uint(1)
+	mockRepo.On("FindByUserIDAndCategoryID", uint(1), &categoryID, "October", 2024).Return((*models.Budget)(nil), gorm.ErrRecordNotFound)
+	mockRepo.On("Create", mock.Anything).Return(nil)
+
+	result, err := service.EnsureBudget(1, &categoryID, "October", 2024)
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.Amount(0), result.AmountLimit)
+	assert.Equal(t, "10", result.BudgetMonth)
+	assert.Equal(t, 2024, result.BudgetYear)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBudgetService_EnsureBudget_CreatedConcurrently(t *testing.T) {
+	service, mockRepo := setupBudgetService()
+
+	categoryID := uint(1)
+	opened := &models.Budget{ID: 4, UserID: 1, CategoryID: &categoryID, BudgetMonth: "10", BudgetYear: 2024}
+	mockRepo.On("FindByUserIDAndCategoryID", uint(1), &categoryID, "October", 2024).Return((*models.Budget)(nil), gorm.ErrRecordNotFound).Once()
+	mockRepo.On("Create", mock.Anything).Return(budget.ErrBudgetExists)
+	mockRepo.On("FindByUserIDAndCategoryIDForUpdate", uint(1), &categoryID, "October", 2024).Return(opened, nil).Once()
+
+	result, err := service.EnsureBudget(1, &categoryID, "October", 2024)
+
+	assert.NoError(t, err)
+	assert.Equal(t, opened, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBudgetService_EnsureBudget_CarriesPreviousLimit(t *testing.T) {
+	service, mockRepo := setupBudgetService()
+	mockSettings := new(mocks.MockSettingsRepository)
+	service.UserService.Settings = mockSettings
+
+	categoryID := uint(1)
+	previous := &models.Budget{ID: 3, UserID: 1, CategoryID: &categoryID, AmountLimit: money.FromFloat(400), BudgetMonth: "12", BudgetYear: 2023}
+
+	mockSettings.On("FindByUserID", uint(1)).Return(&models.UserSettings{UserID: 1, CarryOverBudgetLimits: true}, nil)
+	mockRepo.On("FindByUserIDAndCategoryID", uint(1), &categoryID, "01", 2024).Return((*models.Budget)(nil), gorm.ErrRecordNotFound)
+	mockRepo.On("FindByUserIDAndCategoryID", uint(1), &categoryID, "12", 2023).Return(previous, nil)
+	mockRepo.On("Create", mock.Anything).Return(nil)
+
+	result, err := service.EnsureBudget(1, &categoryID, "01", 2024)
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromFloat(400.0), result.AmountLimit)
+	assert.Equal(t, money.FromFloat(400.0), result.RemainingAmount)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBudgetService_RolloverMonth(t *testing.T) {
+	service, mockRepo := setupBudgetService()
+
+	groceries, rent, fun := uint(1), uint(2), uint(3)
+	underspent := &models.Budget{ID: 1, UserID: 1, CategoryID: &groceries, AmountLimit: money.FromFloat(300), RemainingAmount: money.FromFloat(50), RolloverPositive: true, BudgetMonth: "09", BudgetYear: 2024}
+	overspent := &models.Budget{ID: 2, UserID: 1, CategoryID: &rent, AmountLimit: money.FromFloat(1000), RemainingAmount: money.FromFloat(-20), RolloverPositive: true, BudgetMonth: "09", BudgetYear: 2024}
+	alreadyRolled := &models.Budget{ID: 3, UserID: 1, CategoryID: &fun, AmountLimit: money.FromFloat(100), BudgetMonth: "09", BudgetYear: 2024}
+
+	mockRepo.On("FindCategoryBudgetsForPeriod", "09", 2024).Return([]*models.Budget{underspent, overspent, alreadyRolled}, nil)
+	mockRepo.On("FindByUserIDAndCategoryID", uint(1), &groceries, "10", 2024).Return((*models.Budget)(nil), gorm.ErrRecordNotFound)
+	mockRepo.On("FindByUserIDAndCategoryID", uint(1), &rent, "10", 2024).Return((*models.Budget)(nil), gorm.ErrRecordNotFound)
+	mockRepo.On("FindByUserIDAndCategoryID", uint(1), &fun, "10", 2024).Return(&models.Budget{ID: 9, RolledOver: true}, nil)
+	mockRepo.On("FindByID", underspent.ID).Return(underspent, nil)
+	mockRepo.On("FindByID", overspent.ID).Return(overspent, nil)
+	mockRepo.On("RecalculateSpent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	var created []*models.Budget
+	mockRepo.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		created = append(created, args.Get(0).(*models.Budget))
+	}).Return(nil)
+
+	result, err := service.RolloverMonth("10", 2024)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, 1, result.Skipped)
+
+	assert.Len(t, created, 2)
+	assert.Equal(t, money.FromFloat(50.0), created[0].CarryOverAmount)
+	assert.Equal(t, money.FromFloat(350.0), created[0].RemainingAmount)
+	assert.True(t, created[0].RolloverPositive)
+	// Rent only rolls over positive remainders, so the overspend is dropped.
+	assert.Equal(t, money.Amount(0), created[1].CarryOverAmount)
+	assert.Equal(t, "10", created[1].BudgetMonth)
+}
+
+func TestBudgetService_RolloverMonth_FillsBudgetOpenedByEarlyTransaction(t *testing.T) {
+	service, mockRepo := setupBudgetService()
+
+	groceries := uint(1)
+	previous := &models.Budget{ID: 1, UserID: 1, CategoryID: &groceries, AmountLimit: money.FromFloat(300), RemainingAmount: money.FromFloat(50), RolloverPositive: true, BudgetMonth: "09", BudgetYear: 2024}
+	// A transaction posted on the 1st opened October's budget with no limit
+	// before the rollover ran.
+	opened := &models.Budget{ID: 7, UserID: 1, CategoryID: &groceries, SpentAmount: money.FromFloat(20), RemainingAmount: money.FromFloat(-20), BudgetMonth: "10", BudgetYear: 2024}
+
+	mockRepo.On("FindCategoryBudgetsForPeriod", "09", 2024).Return([]*models.Budget{previous}, nil)
+	mockRepo.On("FindByUserIDAndCategoryID", uint(1), &groceries, "10", 2024).Return(opened, nil)
+	mockRepo.On("FindByID", previous.ID).Return(previous, nil)
+	mockRepo.On("RecalculateSpent", previous.ID, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("RecalculateSpent", opened.ID, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("Update", opened).Return(nil)
+
+	result, err := service.RolloverMonth("10", 2024)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Created)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, money.FromFloat(300.0), opened.AmountLimit)
+	assert.Equal(t, money.FromFloat(50.0), opened.CarryOverAmount)
+	assert.Equal(t, money.FromFloat(330.0), opened.RemainingAmount)
+	assert.True(t, opened.RolloverPositive)
+	assert.True(t, opened.RolledOver)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBudgetService_RolloverDue_WaitsForUsersMonthToStart(t *testing.T) {
+	service, mockRepo := setupBudgetService()
+	mockSettings := new(mocks.MockSettingsRepository)
+	service.UserService.Settings = mockSettings
+
+	groceries := uint(1)
+	auckland := &models.Budget{ID: 1, UserID: 1, CategoryID: &groceries, AmountLimit: money.FromFloat(300), BudgetMonth: "09", BudgetYear: 2024}
+	losAngeles := &models.Budget{ID: 2, UserID: 2, CategoryID: &groceries, AmountLimit: money.FromFloat(200), BudgetMonth: "09", BudgetYear: 2024}
+
+	mockSettings.On("FindByUserID", uint(1)).Return(&models.UserSettings{UserID: 1, TimeZone: "Pacific/Auckland"}, nil)
+	mockSettings.On("FindByUserID", uint(2)).Return(&models.UserSettings{UserID: 2, TimeZone: "America/Los_Angeles"}, nil)
+	mockRepo.On("FindCategoryBudgetsForPeriod", "08", 2024).Return([]*models.Budget{}, nil)
+	mockRepo.On("FindCategoryBudgetsForPeriod", "09", 2024).Return([]*models.Budget{auckland, losAngeles}, nil)
+	mockRepo.On("FindByUserIDAndCategoryID", uint(1), &groceries, "10", 2024).Return((*models.Budget)(nil), gorm.ErrRecordNotFound)
+	mockRepo.On("FindByID", auckland.ID).Return(auckland, nil)
+	mockRepo.On("RecalculateSpent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("Create", mock.Anything).Return(nil)
+
+	// October has begun in Auckland but not yet in Los Angeles.
+	result, err := service.RolloverDue(time.Date(2024, 9, 30, 14, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Pending)
+	mockRepo.AssertNotCalled(t, "FindByUserIDAndCategoryID", uint(2), &groceries, "10", 2024)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBudgetService_UpdateRolloverSettings(t *testing.T) {
+	service, mockRepo := setupBudgetService()
+
+	username := "john_doe"
+	user := createBudgetTestUser(service.UserService.Repo.(*mocks.MockUserRepository), username, 1)
+	existing := &models.Budget{ID: 4, UserID: user.ID}
+
+	mockRepo.On("FindByID", existing.ID).Return(existing, nil)
+	mockRepo.On("UpdateRollover", existing.ID, true, true).Return(nil)
+
+	result, err := service.UpdateRolloverSettings(username, existing.ID, true, true)
+
+	assert.NoError(t, err)
+	assert.True(t, result.RolloverPositive)
+	assert.True(t, result.RolloverNegative)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBudgetService_CalculateOverallBudget_UsesUserTimeZone(t *testing.T) {
+	service, mockRepo := setupBudgetService()
+	mockSettings := new(mocks.MockSettingsRepository)
+	service.UserService.Settings = mockSettings
+
+	username := "john_doe"
+	user := createBudgetTestUser(service.UserService.Repo.(*mocks.MockUserRepository), username, 1)
+
+	// Kiritimati is fourteen hours ahead of UTC, so it is often already the
+	// next month there.
+	kiritimati, err := time.LoadLocation("Pacific/Kiritimati")
+	assert.NoError(t, err)
+	now := time.Now().In(kiritimati)
+
+	mockSettings.On("FindByUserID", user.ID).Return(&models.UserSettings{UserID: user.ID, BaseCurrency: "USD", TimeZone: "Pacific/Kiritimati"}, nil)
+	mockRepo.On("FindAllByUserIDAndMonthYear", user.ID, now.Format("01"), now.Year()).Return([]*models.Budget{
+		{UserID: user.ID, AmountLimit: money.FromFloat(300), SpentAmount: money.FromFloat(100), RemainingAmount: money.FromFloat(200)},
+	}, nil)
+
+	result, err := service.CalculateOverallBudget(username)
+
+	assert.NoError(t, err)
+	assert.Equal(t, now.Format("01"), result.BudgetMonth)
+	assert.Equal(t, now.Year(), result.BudgetYear)
+	assert.Equal(t, money.FromFloat(200), result.RemainingAmount)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBudgetService_RecalculateBudget_RefreshesParentBudgets(t *testing.T) {
+	service, mockRepo := setupBudgetService()
+
+	food, groceries, produce := uint(1), uint(2), uint(3)
+	mockRepo.CategoryParents = map[uint]uint{groceries: food, produce: groceries}
+	month := "09"
+	year := 2024
+
+	produceBudget := &models.Budget{ID: 1, UserID: 1, CategoryID: &produce, BudgetMonth: month, BudgetYear: year}
+	foodBudget := &models.Budget{ID: 2, UserID: 1, CategoryID: &food, BudgetMonth: month, BudgetYear: year}
+
+	mockRepo.On("FindByUserIDAndCategoryID", uint(1), &produce, month, year).Return(produceBudget, nil)
+	mockRepo.On("FindByUserIDAndCategoryID", uint(1), &groceries, month, year).Return((*models.Budget)(nil), gorm.ErrRecordNotFound)
+	mockRepo.On("FindByUserIDAndCategoryID", uint(1), &food, month, year).Return(foodBudget, nil)
+	mockRepo.On("FindByUserIDAndCategoryID", uint(1), (*uint)(nil), month, year).Return((*models.Budget)(nil), gorm.ErrRecordNotFound)
+	mockRepo.On("RecalculateSpent", produceBudget.ID, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("RecalculateSpent", foodBudget.ID, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("FindByID", produceBudget.ID).Return(produceBudget, nil)
+
+	_, err := service.RecalculateBudget(1, &produce, month, year)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBudgetService_CalculateOverallBudget_CountsRolledUpSpendingOnce(t *testing.T) {
+	service, mockRepo := setupBudgetService()
+
+	username := "john_doe"
+	user := createBudgetTestUser(service.UserService.Repo.(*mocks.MockUserRepository), username, 1)
+	food, groceries := uint(1), uint(2)
+	mockRepo.CategoryParents = map[uint]uint{groceries: food}
+	now := time.Now()
+
+	mockRepo.On("FindAllByUserIDAndMonthYear", user.ID, now.Format("01"), now.Year()).Return([]*models.Budget{
+		{UserID: user.ID, CategoryID: &food, AmountLimit: money.FromFloat(500), SpentAmount: money.FromFloat(200), RemainingAmount: money.FromFloat(300)},
+		{UserID: user.ID, CategoryID: &groceries, AmountLimit: money.FromFloat(300), SpentAmount: money.FromFloat(150), RemainingAmount: money.FromFloat(150)},
+	}, nil)
+
+	result, err := service.CalculateOverallBudget(username)
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromFloat(500), result.AmountLimit)
+	assert.Equal(t, money.FromFloat(200), result.SpentAmount)
+}
+
+func TestBudgetService_CalculateOverallBudget_UnlimitedParentCountsSubtreeOnce(t *testing.T) {
+	service, mockRepo := setupBudgetService()
+
+	username := "john_doe"
+	user := createBudgetTestUser(service.UserService.Repo.(*mocks.MockUserRepository), username, 1)
+	household, food, groceries, restaurants := uint(1), uint(2), uint(3), uint(4)
+	mockRepo.CategoryParents = map[uint]uint{food: household, groceries: food, restaurants: food}
+	now := time.Now()
+
+	// Household and Food have no limit and were opened by their own
+	// spending; each budget's spent amount includes its subcategories.
+	mockRepo.On("FindAllByUserIDAndMonthYear", user.ID, now.Format("01"), now.Year()).Return([]*models.Budget{
+		{UserID: user.ID, CategoryID: &household, SpentAmount: money.FromFloat(190)},
+		{UserID: user.ID, CategoryID: &food, SpentAmount: money.FromFloat(180)},
+		{UserID: user.ID, CategoryID: &groceries, SpentAmount: money.FromFloat(120)},
+		{UserID: user.ID, CategoryID: &restaurants, AmountLimit: money.FromFloat(100), SpentAmount: money.FromFloat(40), RemainingAmount: money.FromFloat(60)},
+	}, nil)
+
+	result, err := service.CalculateOverallBudget(username)
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromFloat(100), result.AmountLimit)
+	assert.Equal(t, money.FromFloat(40), result.SpentAmount)
+	assert.Equal(t, money.FromFloat(150), result.UncategorizedTotal)
+}
+
+func TestBudgetService_GetBudgetHistoryForCategory_NestsSubcategories(t *testing.T) {
+	service, mockRepo := setupBudgetService()
+
+	username := "john_doe"
+	user := createBudgetTestUser(service.UserService.Repo.(*mocks.MockUserRepository), username, 1)
+	food, groceries, restaurants := uint(1), uint(2), uint(3)
+	mockRepo.CategoryParents = map[uint]uint{groceries: food, restaurants: food}
+
+	mockRepo.On("FindByUserIDAndCategoryID", user.ID, mock.Anything, mock.Anything, mock.Anything).Return((*models.Budget)(nil), gorm.ErrRecordNotFound)
+
+	result, err := service.GetBudgetHistoryForCategory(username, food)
+
+	assert.NoError(t, err)
+	assert.Equal(t, food, result.CategoryID)
+	assert.Len(t, result.History, 4)
+	if assert.Len(t, result.Children, 2) {
+		assert.Equal(t, groceries, result.Children[0].CategoryID)
+		assert.Equal(t, restaurants, result.Children[1].CategoryID)
+		assert.Len(t, result.Children[0].History, 4)
+		assert.Empty(t, result.Children[0].Children)
+	}
+}
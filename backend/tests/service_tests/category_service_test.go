@@ -1,6 +1,7 @@
 package test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/shaikhjunaidx/pennywise-backend/internal/category"
@@ -9,6 +10,7 @@ import (
 	"github.com/shaikhjunaidx/pennywise-backend/tests/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 func setupCategoryService() (*category.CategoryService, *mocks.MockCategoryRepository, *mocks.MockUserRepository) {
@@ -35,6 +37,7 @@ func TestCategoryService_AddCategory(t *testing.T) {
 		Description: "Expenses for groceries",
 	}
 
+	mockRepo.On("FindByNameAndUserID", category.Name, user.ID).Return((*models.Category)(nil), gorm.ErrRecordNotFound)
 	mockRepo.On("Create", mock.Anything).Return(nil)
 
 	result, err := service.AddCategory(username, category.Name, category.Description)
@@ -118,10 +121,10 @@ func TestCategoryService_UpdateCategory(t *testing.T) {
 	updatedName := "Updated Groceries"
 	updatedDescription := "Updated description"
 
-	mockRepo.On("FindByID", categoryID).Return(existingCategory, nil)
+	mockRepo.On("FindAllByUserIDForUpdate", user.ID).Return([]*models.Category{existingCategory}, nil)
 	mockRepo.On("Update", mock.Anything).Return(nil)
 
-	result, err := service.UpdateCategory(username, categoryID, updatedName, updatedDescription)
+	result, err := service.UpdateCategory(username, categoryID, updatedName, updatedDescription, nil)
 
 	assert.NoError(t, err)
 	assert.Equal(t, updatedName, result.Name)
@@ -144,6 +147,7 @@ func TestCategoryService_DeleteCategory(t *testing.T) {
 	}
 
 	mockRepo.On("FindByID", categoryID).Return(existingCategory, nil)
+	mockRepo.On("FindAllByUserID", user.ID).Return([]*models.Category{existingCategory}, nil)
 	mockRepo.On("DeleteByID", categoryID).Return(nil)
 
 	err := service.DeleteCategory(username, categoryID)
@@ -174,3 +178,194 @@ func TestCategoryService_FindByName(t *testing.T) {
 
 	mockRepo.AssertExpectations(t)
 }
+
+func TestCategoryService_AddSubcategory(t *testing.T) {
+	service, mockRepo, mockUserRepo := setupCategoryService()
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	food := &models.Category{ID: 1, UserID: user.ID, Name: "Food", Kind: models.CategoryKindExpense}
+
+	mockRepo.On("FindByID", food.ID).Return(food, nil)
+	mockRepo.On("FindByNameAndUserID", "Groceries", user.ID).Return((*models.Category)(nil), gorm.ErrRecordNotFound)
+	mockRepo.On("Create", mock.Anything).Return(nil)
+
+	result, err := service.AddSubcategory(username, "Groceries", "", "", &food.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, food.ID, *result.ParentID)
+	assert.Equal(t, models.CategoryKindExpense, result.Kind)
+
+	// Income cannot be filed under an expense category.
+	_, err = service.AddSubcategory(username, "Cashback", "", models.CategoryKindIncome, &food.ID)
+	assert.ErrorIs(t, err, category.ErrInvalidParent)
+}
+
+func TestCategoryService_AddSubcategory_NameTakenElsewhere(t *testing.T) {
+	service, mockRepo, mockUserRepo := setupCategoryService()
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	food := &models.Category{ID: 1, UserID: user.ID, Name: "Food", Kind: models.CategoryKindExpense}
+	snacks := &models.Category{ID: 2, UserID: user.ID, Name: "Snacks", Kind: models.CategoryKindExpense}
+
+	mockRepo.On("FindByID", food.ID).Return(food, nil)
+	mockRepo.On("FindByNameAndUserID", "Snacks", user.ID).Return(snacks, nil)
+
+	// Snacks exists at the top level, so it cannot be added under Food.
+	_, err := service.AddSubcategory(username, "Snacks", "", "", &food.ID)
+	assert.ErrorIs(t, err, category.ErrCategoryExists)
+
+	// Asking for it where it already is returns it.
+	result, err := service.AddSubcategory(username, "Snacks", "", "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, snacks, result)
+
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCategoryService_UpdateCategory_RejectsCycle(t *testing.T) {
+	service, mockRepo, mockUserRepo := setupCategoryService()
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	food := &models.Category{ID: 1, UserID: user.ID, Name: "Food"}
+	groceries := &models.Category{ID: 2, UserID: user.ID, Name: "Groceries", ParentID: &food.ID}
+	produce := &models.Category{ID: 3, UserID: user.ID, Name: "Produce", ParentID: &groceries.ID}
+
+	mockRepo.On("FindAllByUserIDForUpdate", user.ID).Return([]*models.Category{food, groceries, produce}, nil)
+
+	_, err := service.UpdateCategory(username, food.ID, food.Name, "", &produce.ID)
+	assert.ErrorIs(t, err, category.ErrCategoryCycle)
+
+	_, err = service.UpdateCategory(username, food.ID, food.Name, "", &food.ID)
+	assert.ErrorIs(t, err, category.ErrCategoryCycle)
+
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestCategoryService_UpdateCategory_MoveRecalculatesBudgets(t *testing.T) {
+	service, mockRepo, mockUserRepo := setupCategoryService()
+	budgets := new(mocks.MockBudgetRefresher)
+	service.UnitOfWork = &mocks.MockCategoryUnitOfWork{Categories: mockRepo, Budgets: budgets}
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	food := &models.Category{ID: 1, UserID: user.ID, Name: "Food"}
+	household := &models.Category{ID: 2, UserID: user.ID, Name: "Household"}
+	snacks := &models.Category{ID: 3, UserID: user.ID, Name: "Snacks", ParentID: &household.ID}
+
+	mockRepo.On("FindAllByUserIDForUpdate", user.ID).Return([]*models.Category{food, household, snacks}, nil)
+	mockRepo.On("Update", snacks).Return(nil)
+	budgets.On("RecalculateCategories", user.ID, []uint{household.ID, food.ID}).Return(nil)
+
+	result, err := service.UpdateCategory(username, snacks.ID, "Snacks", "", &food.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, food.ID, *result.ParentID)
+	budgets.AssertExpectations(t)
+}
+
+func TestCategoryService_UpdateCategory_ParentIDOmittedOrZero(t *testing.T) {
+	service, mockRepo, mockUserRepo := setupCategoryService()
+	budgets := new(mocks.MockBudgetRefresher)
+	service.UnitOfWork = &mocks.MockCategoryUnitOfWork{Categories: mockRepo, Budgets: budgets}
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	food := &models.Category{ID: 1, UserID: user.ID, Name: "Food"}
+	snacks := &models.Category{ID: 2, UserID: user.ID, Name: "Snacks", ParentID: &food.ID}
+
+	mockRepo.On("FindAllByUserIDForUpdate", user.ID).Return([]*models.Category{food, snacks}, nil)
+	mockRepo.On("Update", snacks).Return(nil)
+
+	// A rename without parent_id keeps the category under Food.
+	result, err := service.UpdateCategory(username, snacks.ID, "Treats", "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, food.ID, *result.ParentID)
+	budgets.AssertNotCalled(t, "RecalculateCategories", mock.Anything, mock.Anything)
+
+	// A zero parent_id moves it to the top level.
+	budgets.On("RecalculateCategories", user.ID, []uint{food.ID}).Return(nil)
+	top := uint(0)
+	result, err = service.UpdateCategory(username, snacks.ID, "Treats", "", &top)
+	assert.NoError(t, err)
+	assert.Nil(t, result.ParentID)
+	budgets.AssertExpectations(t)
+}
+
+func TestCategoryService_UpdateCategory_ReportsRecalculationFailure(t *testing.T) {
+	service, mockRepo, mockUserRepo := setupCategoryService()
+	budgets := new(mocks.MockBudgetRefresher)
+	service.UnitOfWork = &mocks.MockCategoryUnitOfWork{Categories: mockRepo, Budgets: budgets}
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	food := &models.Category{ID: 1, UserID: user.ID, Name: "Food"}
+	snacks := &models.Category{ID: 2, UserID: user.ID, Name: "Snacks"}
+
+	mockRepo.On("FindAllByUserIDForUpdate", user.ID).Return([]*models.Category{food, snacks}, nil)
+	mockRepo.On("Update", snacks).Return(nil)
+	budgets.On("RecalculateCategories", user.ID, []uint{food.ID}).Return(errors.New("deadlock"))
+
+	_, err := service.UpdateCategory(username, snacks.ID, "Snacks", "", &food.ID)
+
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, category.ErrCategoryNotFound)
+}
+
+func TestCategoryService_UpdateCategory_OtherUsersCategory(t *testing.T) {
+	service, mockRepo, mockUserRepo := setupCategoryService()
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	mockRepo.On("FindAllByUserIDForUpdate", user.ID).Return([]*models.Category{}, nil)
+
+	_, err := service.UpdateCategory(username, 7, "Snacks", "", nil)
+
+	assert.ErrorIs(t, err, category.ErrCategoryNotFound)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestCategoryService_DeleteCategory_WithSubcategories(t *testing.T) {
+	service, mockRepo, mockUserRepo := setupCategoryService()
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	food := &models.Category{ID: 1, UserID: user.ID, Name: "Food"}
+	groceries := &models.Category{ID: 2, UserID: user.ID, Name: "Groceries", ParentID: &food.ID}
+
+	mockRepo.On("FindByID", food.ID).Return(food, nil)
+	mockRepo.On("FindAllByUserID", user.ID).Return([]*models.Category{food, groceries}, nil)
+
+	err := service.DeleteCategory(username, food.ID)
+
+	assert.ErrorIs(t, err, category.ErrCategoryHasChildren)
+	mockRepo.AssertNotCalled(t, "DeleteByID", food.ID)
+}
+
+func TestCategoryService_GetCategoryTree(t *testing.T) {
+	service, mockRepo, mockUserRepo := setupCategoryService()
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	food := &models.Category{ID: 1, UserID: user.ID, Name: "Food"}
+	groceries := &models.Category{ID: 2, UserID: user.ID, Name: "Groceries", ParentID: &food.ID}
+	restaurants := &models.Category{ID: 3, UserID: user.ID, Name: "Restaurants", ParentID: &food.ID}
+	utilities := &models.Category{ID: 4, UserID: user.ID, Name: "Utilities"}
+
+	mockRepo.On("FindAllByUserID", user.ID).Return([]*models.Category{restaurants, utilities, groceries, food}, nil)
+
+	tree, err := service.GetCategoryTree(username)
+
+	assert.NoError(t, err)
+	if assert.Len(t, tree, 2) {
+		assert.Equal(t, "Food", tree[0].Name)
+		assert.Equal(t, "Utilities", tree[1].Name)
+		if assert.Len(t, tree[0].Children, 2) {
+			assert.Equal(t, "Groceries", tree[0].Children[0].Name)
+			assert.Equal(t, "Restaurants", tree[0].Children[1].Name)
+		}
+		assert.Empty(t, tree[1].Children)
+	}
+}
@@ -0,0 +1,109 @@
+package test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/export"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/importer"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/shaikhjunaidx/pennywise-backend/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setUpExportService() (*export.ExportService, *mocks.MockTransactionRepository, *mocks.MockUserRepository, *mocks.MockCategoryRepository, *mocks.MockBudgetRepository) {
+	mockRepo := new(mocks.MockTransactionRepository)
+	mockUserRepo := &mocks.MockUserRepository{
+		Users: make(map[string]*models.User),
+	}
+	mockCategoryRepo := new(mocks.MockCategoryRepository)
+	mockBudgetRepo := new(mocks.MockBudgetRepository)
+
+	service := export.NewExportService(mockUserRepo, mockCategoryRepo, mockRepo, mockBudgetRepo)
+
+	return service, mockRepo, mockUserRepo, mockCategoryRepo, mockBudgetRepo
+}
+
+func TestExportService_Collect_AppliesFilter(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockCategoryRepo, mockBudgetRepo := setUpExportService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	groceries, travel := uint(4), uint(5)
+	mockCategoryRepo.On("FindAllByUserID", user.ID).Return([]*models.Category{
+		{ID: groceries, UserID: user.ID, Name: "Groceries"},
+		{ID: travel, UserID: user.ID, Name: "Travel"},
+	}, nil)
+	mockBudgetRepo.On("FindAllByUserID", user.ID).Return([]*models.Budget{
+		{ID: 1, UserID: user.ID, CategoryID: &groceries, BudgetMonth: "09", BudgetYear: 2024},
+		{ID: 2, UserID: user.ID, CategoryID: &groceries, BudgetMonth: "10", BudgetYear: 2024},
+		{ID: 3, UserID: user.ID, CategoryID: &travel, BudgetMonth: "10", BudgetYear: 2024},
+		{ID: 4, UserID: user.ID, BudgetMonth: "10", BudgetYear: 2024},
+	}, nil)
+	// The date range and categories are applied by the store.
+	mockRepo.On("EachInRange", user.ID, time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local), time.Date(2024, 11, 1, 0, 0, 0, 0, time.Local), []uint{groceries}).
+		Return([]*models.Transaction{
+			{ID: 11, UserID: user.ID, CategoryID: groceries, TransactionDate: time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local)},
+			{ID: 12, UserID: user.ID, CategoryID: groceries, TransactionDate: time.Date(2024, 10, 31, 18, 0, 0, 0, time.Local)},
+		}, nil)
+
+	doc, err := service.Collect(user.Username, export.Filter{
+		From:        time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local),
+		To:          time.Date(2024, 10, 31, 0, 0, 0, 0, time.Local),
+		CategoryIDs: []uint{groceries},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []export.Category{{ID: groceries, Name: "Groceries"}}, doc.Categories)
+	assert.Len(t, doc.Budgets, 1)
+	assert.Equal(t, uint(2), doc.Budgets[0].ID)
+
+	// Transactions are only read when the document is written.
+	mockRepo.AssertNotCalled(t, "EachInRange", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	var transactions []export.Transaction
+	assert.NoError(t, doc.EachTransaction(func(transaction export.Transaction) error {
+		transactions = append(transactions, transaction)
+		return nil
+	}))
+	assert.Len(t, transactions, 2)
+	assert.Equal(t, uint(11), transactions[0].ID)
+	assert.Equal(t, uint(12), transactions[1].ID)
+	assert.Equal(t, "Groceries", transactions[0].Category)
+}
+
+func TestExportService_Collect_WritesStreamedTransactions(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockCategoryRepo, mockBudgetRepo := setUpExportService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	mockCategoryRepo.On("FindAllByUserID", user.ID).Return([]*models.Category{{ID: 4, UserID: user.ID, Name: "Groceries"}}, nil)
+	mockBudgetRepo.On("FindAllByUserID", user.ID).Return([]*models.Budget{}, nil)
+	mockRepo.On("EachInRange", user.ID, time.Time{}, time.Time{}, []uint(nil)).Return([]*models.Transaction{
+		{ID: 1, UserID: user.ID, CategoryID: 4, Type: "expense", Amount: money.FromFloat(12.5), Currency: "EUR", TransactionDate: time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local)},
+		{ID: 2, UserID: user.ID, CategoryID: 4, Type: "expense", Amount: money.FromFloat(3), Currency: "EUR", TransactionDate: time.Date(2024, 10, 2, 0, 0, 0, 0, time.Local)},
+	}, nil)
+
+	doc, err := service.Collect(user.Username, export.Filter{})
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, export.WriteJSON(&buf, doc))
+
+	rows, err := importer.ParseJSON(&buf)
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, money.FromFloat(12.5), rows[0].Amount)
+	assert.Equal(t, "Groceries", rows[1].Category)
+}
+
+func TestExportService_Collect_InvalidRange(t *testing.T) {
+	service, _, _, _, _ := setUpExportService()
+
+	_, err := service.Collect("john_doe", export.Filter{
+		From: time.Date(2024, 10, 31, 0, 0, 0, 0, time.Local),
+		To:   time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local),
+	})
+
+	assert.ErrorIs(t, err, export.ErrInvalidFilter)
+}
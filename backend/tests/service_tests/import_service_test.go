@@ -0,0 +1,280 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/importer"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/user"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/shaikhjunaidx/pennywise-backend/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func setUpImportService() (*importer.ImportService, *mocks.MockMappingRepository, *mocks.MockTransactionRepository, *mocks.MockUserRepository, *mocks.MockCategoryRepository, *mocks.MockBudgetRepository) {
+	transactionService, mockRepo, mockUserRepo, mockCategoryRepo, mockBudgetRepo := setUpTransactionService()
+	mockMappings := new(mocks.MockMappingRepository)
+
+	service := importer.NewImportService(mockMappings, mockUserRepo, mockCategoryRepo, transactionService)
+
+	return service, mockMappings, mockRepo, mockUserRepo, mockCategoryRepo, mockBudgetRepo
+}
+
+func TestImportService_Preview_MatchesCategories(t *testing.T) {
+	service, _, mockRepo, mockUserRepo, mockCategoryRepo, _ := setUpImportService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	mockCategoryRepo.On("FindByNameKindAndUserID", "Groceries", models.CategoryKindExpense, user.ID).Return(&models.Category{ID: 4, UserID: user.ID, Name: "Groceries"}, nil)
+	mockCategoryRepo.On("FindByNameKindAndUserID", "Travel", models.CategoryKindExpense, user.ID).Return((*models.Category)(nil), gorm.ErrRecordNotFound)
+
+	expectNoDuplicateCandidates(mockRepo, user.ID)
+
+	input := "date,amount,description,category\n" +
+		"2024-10-01,10.00,Market,Groceries\n" +
+		"2024-10-02,20.00,Train,Travel\n" +
+		"2024-10-03,oops,Bakery,Groceries\n"
+
+	preview, err := service.Preview(user.Username, importer.FormatCSV, strings.NewReader(input), importer.PreviewOptions{Currency: "GBP"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, preview.Valid)
+	assert.Equal(t, 1, preview.Invalid)
+	assert.Equal(t, uint(4), preview.Rows[0].CategoryID)
+	assert.Equal(t, uint(0), preview.Rows[1].CategoryID)
+	assert.Equal(t, money.Currency("GBP"), preview.Rows[1].Currency)
+	mockCategoryRepo.AssertNumberOfCalls(t, "FindByNameKindAndUserID", 2)
+}
+
+func TestImportService_Preview_ReadsDatesInUserTimeZone(t *testing.T) {
+	service, _, mockRepo, mockUserRepo, _, _ := setUpImportService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	service.Transactions.Settings = settingsWithTimeZone(user.ID, "America/Los_Angeles")
+	expectNoDuplicateCandidates(mockRepo, user.ID)
+
+	preview, err := service.Preview(user.Username, importer.FormatCSV, strings.NewReader("date,amount\n2024-10-01,10.00\n"), importer.PreviewOptions{})
+
+	assert.NoError(t, err)
+	losAngeles, _ := time.LoadLocation("America/Los_Angeles")
+	assert.True(t, time.Date(2024, 10, 1, 0, 0, 0, 0, losAngeles).Equal(preview.Rows[0].TransactionDate))
+}
+
+func TestImportService_Preview_MatchesIncomeCategories(t *testing.T) {
+	service, _, mockRepo, mockUserRepo, mockCategoryRepo, _ := setUpImportService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	mockCategoryRepo.On("FindByNameKindAndUserID", "Salary", models.CategoryKindIncome, user.ID).Return(&models.Category{ID: 6, UserID: user.ID, Name: "Salary", Kind: models.CategoryKindIncome}, nil)
+	mockCategoryRepo.On("FindByNameKindAndUserID", "Groceries", models.CategoryKindIncome, user.ID).Return((*models.Category)(nil), gorm.ErrRecordNotFound)
+
+	expectNoDuplicateCandidates(mockRepo, user.ID)
+
+	// Money received is imported as income, so it only matches income
+	// categories and otherwise goes to the default income category.
+	input := "date,amount,description,category\n" +
+		"2024-10-01,-2500.00,Payroll,Salary\n" +
+		"2024-10-02,-12.00,Market,Groceries\n"
+
+	preview, err := service.Preview(user.Username, importer.FormatCSV, strings.NewReader(input), importer.PreviewOptions{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, preview.Valid)
+	assert.Equal(t, uint(6), preview.Rows[0].CategoryID)
+	assert.Equal(t, uint(0), preview.Rows[1].CategoryID)
+}
+
+func TestImportService_Preview_RejectsTransfers(t *testing.T) {
+	service, _, mockRepo, mockUserRepo, _, _ := setUpImportService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	expectNoDuplicateCandidates(mockRepo, user.ID)
+
+	input := "date,amount,description,type\n" +
+		"2024-10-01,10.00,Market,expense\n" +
+		"2024-10-02,500.00,To savings,transfer\n"
+
+	preview, err := service.Preview(user.Username, importer.FormatCSV, strings.NewReader(input), importer.PreviewOptions{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, preview.Valid)
+	assert.Equal(t, 1, preview.Invalid)
+	assert.Contains(t, preview.Rows[1].Error, "transfer")
+}
+
+func TestImportService_Preview_OtherUsersMapping(t *testing.T) {
+	service, mockMappings, _, mockUserRepo, _, _ := setUpImportService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	mockMappings.On("FindByID", uint(3)).Return(&models.ImportMapping{ID: 3, UserID: 2}, nil)
+
+	_, err := service.Preview(user.Username, importer.FormatCSV, strings.NewReader(""), importer.PreviewOptions{MappingID: 3})
+
+	assert.ErrorIs(t, err, importer.ErrMappingAccessDenied)
+}
+
+func TestImportService_Commit(t *testing.T) {
+	service, _, mockRepo, mockUserRepo, mockCategoryRepo, mockBudgetRepo := setUpImportService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	groceries := createTestCategory(mockCategoryRepo, user.ID, 4, "Groceries")
+	rent := createTestCategory(mockCategoryRepo, user.ID, 5, "Rent")
+
+	october := time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local)
+	november := time.Date(2024, 11, 1, 0, 0, 0, 0, time.Local)
+	rows := []importer.Row{
+		{Line: 2, TransactionDate: october, Amount: money.FromFloat(10), CategoryID: groceries.ID},
+		{Line: 3, TransactionDate: october.AddDate(0, 0, 5), Amount: money.FromFloat(15)},
+		{Line: 4, TransactionDate: november, Amount: money.FromFloat(20), CategoryID: groceries.ID},
+		{Line: 5, Error: "invalid date"},
+		{Line: 6, TransactionDate: october, Amount: money.FromFloat(900), CategoryID: rent.ID},
+	}
+
+	expectNoDuplicateCandidates(mockRepo, user.ID)
+	mockRepo.On("CreateAll", mock.MatchedBy(func(created []*models.Transaction) bool {
+		return len(created) == 4 && created[1].CategoryID == groceries.ID
+	})).Return(nil)
+
+	// Three budgets are touched: groceries in October and November, and
+	// rent in October. Each is rebuilt exactly once.
+	for i, period := range []struct {
+		categoryID uint
+		date       time.Time
+	}{{groceries.ID, october}, {groceries.ID, november}, {rent.ID, october}} {
+		budgetRow := &models.Budget{ID: uint(10 + i), UserID: user.ID}
+		categoryID := period.categoryID
+		mockBudgetRepo.On("FindByUserIDAndCategoryID", user.ID, &categoryID, period.date.Month().String(), period.date.Year()).Return(budgetRow, nil).Once()
+		mockBudgetRepo.On("RecalculateSpent", budgetRow.ID, mock.Anything, mock.Anything).Return(nil).Once()
+		mockBudgetRepo.On("FindByID", budgetRow.ID).Return(budgetRow, nil).Once()
+	}
+	expectNoOverallBudget(mockBudgetRepo, user.ID, october)
+	expectNoOverallBudget(mockBudgetRepo, user.ID, november)
+
+	result, err := service.Commit(user.Username, rows, groceries.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &transaction.ImportResult{Imported: 4, BudgetsUpdated: 3}, result)
+	mockRepo.AssertExpectations(t)
+	mockBudgetRepo.AssertExpectations(t)
+}
+
+func TestImportService_Commit_ReportsLine(t *testing.T) {
+	service, _, mockRepo, mockUserRepo, mockCategoryRepo, _ := setUpImportService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	createTestCategory(mockCategoryRepo, user.ID, 4, "Groceries")
+	mockCategoryRepo.On("FindByID", uint(99)).Return(&models.Category{ID: 99, UserID: 2}, nil)
+
+	rows := []importer.Row{
+		{Line: 2, Error: "invalid amount"},
+		{Line: 3, TransactionDate: time.Now(), Amount: money.FromFloat(10), CategoryID: 4},
+		{Line: 4, TransactionDate: time.Now(), Amount: money.FromFloat(10), CategoryID: 99},
+	}
+
+	_, err := service.Commit(user.Username, rows, 0)
+
+	assert.ErrorIs(t, err, transaction.ErrInvalidCategory)
+	assert.Contains(t, err.Error(), "line 4")
+	mockRepo.AssertNotCalled(t, "CreateAll", mock.Anything)
+}
+
+func TestImportService_Commit_NothingToImport(t *testing.T) {
+	service, _, _, mockUserRepo, _, _ := setUpImportService()
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+
+	_, err := service.Commit(user.Username, []importer.Row{{Line: 2, Error: "invalid date"}}, 0)
+
+	assert.ErrorIs(t, err, transaction.ErrNothingToImport)
+}
+
+func TestImportService_SaveMapping_Validates(t *testing.T) {
+	service, mockMappings, _, mockUserRepo, _, _ := setUpImportService()
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+
+	_, err := service.SaveMapping(user.Username, &models.ImportMapping{Name: "Bank", DateColumn: "Date"})
+
+	assert.ErrorIs(t, err, importer.ErrInvalidMapping)
+	mockMappings.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestImportService_SaveMapping(t *testing.T) {
+	service, mockMappings, _, mockUserRepo, _, _ := setUpImportService()
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+
+	mockMappings.On("Save", mock.MatchedBy(func(mapping *models.ImportMapping) bool {
+		return mapping.UserID == user.ID && mapping.Currency == "EUR"
+	})).Return(nil)
+
+	mapping, err := service.SaveMapping(user.Username, &models.ImportMapping{Name: " Bank ", DateColumn: "Date", AmountColumn: "Amount", Currency: "eur"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Bank", mapping.Name)
+	mockMappings.AssertExpectations(t)
+}
+
+func TestImportService_Preview_MarksDuplicates(t *testing.T) {
+	service, _, mockRepo, mockUserRepo, _, _ := setUpImportService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	stored := &models.Transaction{ID: 8, UserID: user.ID, Amount: money.FromFloat(10), Currency: money.DefaultCurrency, Description: "Market", TransactionDate: time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local)}
+	mockRepo.On("FindDuplicateCandidates", user.ID, mock.Anything, mock.Anything, []string(nil)).Return([]*models.Transaction{stored}, nil)
+
+	input := "date,amount,description\n" +
+		"2024-10-01,10.00,Market\n" +
+		"2024-10-01,12.00,Market\n"
+
+	preview, err := service.Preview(user.Username, importer.FormatCSV, strings.NewReader(input), importer.PreviewOptions{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, preview.Duplicates)
+	assert.Equal(t, stored.ID, preview.Rows[0].DuplicateOf)
+	assert.Zero(t, preview.Rows[1].DuplicateOf)
+}
+
+func TestImportService_Commit_SkipsDuplicates(t *testing.T) {
+	service, _, mockRepo, mockUserRepo, mockCategoryRepo, mockBudgetRepo := setUpImportService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	groceries := createTestCategory(mockCategoryRepo, user.ID, 4, "Groceries")
+	service.Transactions.Settings = settingsWithDuplicateAction(user.ID, "skip")
+
+	october := time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local)
+	stored := &models.Transaction{ID: 8, UserID: user.ID, Amount: money.FromFloat(10), Currency: money.DefaultCurrency, TransactionDate: october, ExternalID: "FIT-1"}
+	rows := []importer.Row{
+		{Line: 1, TransactionDate: october, Amount: money.FromFloat(10), ExternalID: "FIT-1"},
+		{Line: 2, TransactionDate: october, Amount: money.FromFloat(10), ExternalID: "FIT-2"},
+		{Line: 3, TransactionDate: october, Amount: money.FromFloat(10), ExternalID: "FIT-1", AllowDuplicate: true},
+	}
+
+	mockRepo.On("FindDuplicateCandidates", user.ID, mock.Anything, mock.Anything, []string{"FIT-1", "FIT-2", "FIT-1"}).Return([]*models.Transaction{stored}, nil)
+	mockRepo.On("CreateAll", mock.MatchedBy(func(created []*models.Transaction) bool {
+		return len(created) == 2 && created[0].ExternalID == "FIT-2" && created[1].DuplicateOfID == nil
+	})).Return(nil)
+	budgetRow := &models.Budget{ID: 10, UserID: user.ID}
+	mockBudgetRepo.On("FindByUserIDAndCategoryID", user.ID, &groceries.ID, october.Month().String(), october.Year()).Return(budgetRow, nil)
+	mockBudgetRepo.On("RecalculateSpent", budgetRow.ID, mock.Anything, mock.Anything).Return(nil)
+	mockBudgetRepo.On("FindByID", budgetRow.ID).Return(budgetRow, nil)
+	expectNoOverallBudget(mockBudgetRepo, user.ID, october)
+
+	result, err := service.Commit(user.Username, rows, groceries.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &transaction.ImportResult{Imported: 2, Skipped: 1, BudgetsUpdated: 1}, result)
+	mockRepo.AssertExpectations(t)
+}
+
+// settingsWithDuplicateAction returns a settings provider with the default
+// duplicate policy except for the action.
+func settingsWithDuplicateAction(userID uint, action string) *user.UserService {
+	mockSettings := new(mocks.MockSettingsRepository)
+	mockSettings.On("FindByUserID", userID).Return(&models.UserSettings{
+		UserID:              userID,
+		BaseCurrency:        money.DefaultCurrency,
+		DuplicateWindowDays: user.DefaultDuplicateWindowDays,
+		DuplicateAction:     action,
+	}, nil)
+	return &user.UserService{Settings: mockSettings}
+}
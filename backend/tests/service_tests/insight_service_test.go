@@ -0,0 +1,140 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/insights"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/recurring"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/shaikhjunaidx/pennywise-backend/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setUpInsightService() (*insights.InsightService, *mocks.MockInsightRepository, *mocks.MockUserRepository, *mocks.MockRuleStore) {
+	mockRepo := new(mocks.MockInsightRepository)
+	mockUserRepo := &mocks.MockUserRepository{
+		Users: make(map[string]*models.User),
+	}
+	mockRules := new(mocks.MockRuleStore)
+
+	return insights.NewInsightService(mockRepo, mockUserRepo, mockRules), mockRepo, mockUserRepo, mockRules
+}
+
+func charge(categoryID uint, description string, amount float64, date time.Time) *models.Transaction {
+	return &models.Transaction{CategoryID: categoryID, Type: models.TransactionTypeExpense, Description: description,
+		Amount: money.FromFloat(amount), Currency: "USD", TransactionDate: date.Add(14 * time.Hour)}
+}
+
+// subscriptionHistory is a user's expenses up to mid-October 2024: a
+// monthly streaming service whose price went up, a weekly meal kit, a gym
+// membership that was cancelled in the spring, and one-off shopping.
+func subscriptionHistory() []*models.Transaction {
+	return []*models.Transaction{
+		charge(4, "GYM FLEX 0042", 45, day(2024, 1, 3)),
+		charge(4, "GYM FLEX 0043", 45, day(2024, 2, 3)),
+		charge(4, "GYM FLEX 0044", 45, day(2024, 3, 3)),
+		charge(5, "Amazon", 120, day(2024, 6, 2)),
+		charge(3, "NETFLIX.COM 1234", 15.49, day(2024, 6, 30)),
+		charge(3, "NETFLIX.COM 1235", 15.49, day(2024, 7, 31)),
+		charge(3, "NETFLIX.COM 1236", 15.49, day(2024, 8, 31)),
+		charge(5, "Amazon", 35, day(2024, 9, 12)),
+		charge(3, "NETFLIX.COM 1237", 17.99, day(2024, 9, 30)),
+		charge(6, "Meal Kit Co", 60, day(2024, 9, 24)),
+		charge(6, "Meal Kit Co", 60, day(2024, 10, 1)),
+		charge(6, "Meal Kit Co", 62, day(2024, 10, 8)),
+	}
+}
+
+func TestInsightService_DetectSubscriptions(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockRules := setUpInsightService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	now := day(2024, 10, 14).Add(10 * time.Hour)
+	mockRepo.On("FindExpensesSince", user.ID, day(2022, 9, 14)).Return(subscriptionHistory(), nil)
+	mockRules.On("GetRules", user.Username).Return([]*models.RecurringRule{}, nil)
+
+	subscriptions, err := service.DetectSubscriptions(user.Username, now)
+
+	assert.NoError(t, err)
+	assert.Len(t, subscriptions, 2)
+
+	mealKit := subscriptions[0]
+	assert.Equal(t, "USD:meal kit co", mealKit.Key)
+	assert.Equal(t, models.FrequencyWeekly, mealKit.Frequency)
+	assert.Equal(t, 3, mealKit.Charges)
+	assert.Equal(t, day(2024, 10, 15), mealKit.NextExpected)
+	assert.Zero(t, mealKit.DayOfMonth)
+	assert.Equal(t, money.FromFloat(62*52), mealKit.AnnualCost)
+
+	netflix := subscriptions[1]
+	assert.Equal(t, "USD:netflix com", netflix.Key)
+	assert.Equal(t, "NETFLIX.COM 1237", netflix.Merchant)
+	assert.Equal(t, models.FrequencyMonthly, netflix.Frequency)
+	assert.Equal(t, 1, netflix.Interval)
+	assert.Equal(t, 4, netflix.Charges)
+	assert.Equal(t, money.FromFloat(17.99), netflix.Amount)
+	assert.Equal(t, day(2024, 6, 30), netflix.FirstCharge)
+	// Billed on the 31st, and on the last day of shorter months.
+	assert.Equal(t, 31, netflix.DayOfMonth)
+	assert.Equal(t, day(2024, 10, 31), netflix.NextExpected)
+	assert.Equal(t, money.FromFloat(17.99*12), netflix.AnnualCost)
+	assert.Equal(t, uint(3), netflix.CategoryID)
+}
+
+func TestInsightService_DetectSubscriptions_SkipsMerchantsWithRules(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockRules := setUpInsightService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	now := day(2024, 10, 14)
+	mockRepo.On("FindExpensesSince", user.ID, mock.Anything).Return(subscriptionHistory(), nil)
+	mockRules.On("GetRules", user.Username).Return([]*models.RecurringRule{{Description: "Netflix.com"}}, nil)
+
+	subscriptions, err := service.DetectSubscriptions(user.Username, now)
+
+	assert.NoError(t, err)
+	assert.Len(t, subscriptions, 1)
+	assert.Equal(t, "USD:meal kit co", subscriptions[0].Key)
+}
+
+func TestInsightService_ConvertSubscription(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockRules := setUpInsightService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	now := day(2024, 10, 14)
+	mockRepo.On("FindExpensesSince", user.ID, mock.Anything).Return(subscriptionHistory(), nil)
+	mockRules.On("GetRules", user.Username).Return([]*models.RecurringRule{}, nil)
+	rule := &models.RecurringRule{ID: 12}
+	mockRules.On("CreateRule", user.Username, recurring.RuleInput{
+		Type: models.TransactionTypeExpense, CategoryID: 3, Amount: money.FromFloat(17.99), Currency: "USD",
+		Description: "NETFLIX.COM 1237", Frequency: models.FrequencyMonthly, Interval: 1, DayOfMonth: 31, StartDate: day(2024, 10, 31),
+	}).Return(rule, nil)
+
+	created, err := service.ConvertSubscription(user.Username, "USD:netflix com", now)
+	assert.NoError(t, err)
+	assert.Equal(t, rule, created)
+
+	_, err = service.ConvertSubscription(user.Username, "USD:gym flex", now)
+	assert.ErrorIs(t, err, insights.ErrSubscriptionNotFound)
+}
+
+func TestInsightService_ConvertSubscription_StartsAfterToday(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockRules := setUpInsightService()
+
+	// The meal kit was due on the 15th but has not been charged yet.
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	now := day(2024, 10, 17).Add(9 * time.Hour)
+	mockRepo.On("FindExpensesSince", user.ID, mock.Anything).Return(subscriptionHistory(), nil)
+	mockRules.On("GetRules", user.Username).Return([]*models.RecurringRule{}, nil)
+	rule := &models.RecurringRule{ID: 13}
+	mockRules.On("CreateRule", user.Username, recurring.RuleInput{
+		Type: models.TransactionTypeExpense, CategoryID: 6, Amount: money.FromFloat(62), Currency: "USD",
+		Description: "Meal Kit Co", Frequency: models.FrequencyWeekly, Interval: 1, StartDate: day(2024, 10, 22),
+	}).Return(rule, nil)
+
+	created, err := service.ConvertSubscription(user.Username, "USD:meal kit co", now)
+	assert.NoError(t, err)
+	assert.Equal(t, rule, created)
+}
@@ -0,0 +1,208 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/recurring"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/shaikhjunaidx/pennywise-backend/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func setUpRecurringService() (*recurring.RecurringService, *mocks.MockRecurringRepository, *mocks.MockUserRepository, *mocks.MockTransactionBooker) {
+	mockRepo := new(mocks.MockRecurringRepository)
+	mockUserRepo := &mocks.MockUserRepository{
+		Users: make(map[string]*models.User),
+	}
+	mockBooker := new(mocks.MockTransactionBooker)
+
+	return recurring.NewRecurringService(mockRepo, mockUserRepo, mockBooker), mockRepo, mockUserRepo, mockBooker
+}
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.Local)
+}
+
+func TestRecurringService_CreateRule(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockBooker := setUpRecurringService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	mockBooker.On("ValidateTransaction", user.Username, mock.Anything).Return(nil)
+	mockRepo.On("Create", mock.Anything).Return(nil)
+
+	rule, err := service.CreateRule(user.Username, recurring.RuleInput{CategoryID: 2, Amount: money.FromFloat(1200), Description: "Rent", DayOfMonth: 1, StartDate: day(2024, 10, 15)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.FrequencyMonthly, rule.Frequency)
+	assert.Equal(t, 1, rule.Interval)
+	assert.Equal(t, models.TransactionTypeExpense, rule.Type)
+	assert.Equal(t, day(2024, 11, 1), *rule.NextDate)
+
+	_, err = service.CreateRule(user.Username, recurring.RuleInput{Type: "transfer", Amount: money.FromFloat(100), StartDate: day(2024, 10, 1)})
+	assert.ErrorIs(t, err, transaction.ErrInvalidType)
+
+	_, err = service.CreateRule(user.Username, recurring.RuleInput{Frequency: "weekly", DayOfMonth: 3, Amount: money.FromFloat(10), StartDate: day(2024, 10, 1)})
+	assert.ErrorIs(t, err, recurring.ErrInvalidRule)
+
+	end := day(2024, 9, 1)
+	_, err = service.CreateRule(user.Username, recurring.RuleInput{Amount: money.FromFloat(10), StartDate: day(2024, 10, 1), EndDate: &end})
+	assert.ErrorIs(t, err, recurring.ErrInvalidRule)
+}
+
+func TestRecurringService_CreateRule_ChecksTransaction(t *testing.T) {
+	service, _, mockUserRepo, mockBooker := setUpRecurringService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	mockBooker.On("ValidateTransaction", user.Username, mock.Anything).Return(transaction.ErrInvalidCategory)
+
+	_, err := service.CreateRule(user.Username, recurring.RuleInput{CategoryID: 9, Amount: money.FromFloat(15.49), StartDate: day(2024, 10, 1)})
+
+	assert.ErrorIs(t, err, transaction.ErrInvalidCategory)
+}
+
+func TestRecurringService_GetUpcoming_ClampsToMonthEnd(t *testing.T) {
+	service, mockRepo, mockUserRepo, _ := setUpRecurringService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	next := day(2024, 1, 31)
+	rule := &models.RecurringRule{ID: 3, UserID: user.ID, Amount: money.FromFloat(50), Description: "Gym",
+		Frequency: models.FrequencyMonthly, Interval: 1, StartDate: day(2024, 1, 31), NextDate: &next}
+	mockRepo.On("FindAllByUserID", user.ID).Return([]*models.RecurringRule{rule}, nil)
+	skipped := &models.RecurringOccurrence{RecurringRuleID: rule.ID, Date: day(2024, 3, 31), Skipped: true}
+	mockRepo.On("FindOccurrences", []uint{rule.ID}, day(2024, 1, 31), day(2024, 5, 1)).Return([]*models.RecurringOccurrence{skipped}, nil)
+
+	upcoming, err := service.GetUpcoming(user.Username, day(2024, 1, 31).Add(9*time.Hour), 91)
+
+	assert.NoError(t, err)
+	var dates []time.Time
+	for _, occurrence := range upcoming {
+		dates = append(dates, occurrence.Date)
+	}
+	assert.Equal(t, []time.Time{day(2024, 1, 31), day(2024, 2, 29), day(2024, 3, 31), day(2024, 4, 30)}, dates)
+	assert.False(t, upcoming[1].Skipped)
+	assert.True(t, upcoming[2].Skipped)
+}
+
+func TestRecurringService_MaterializeDue(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockBooker := setUpRecurringService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	next := day(2024, 10, 1)
+	rule := &models.RecurringRule{ID: 3, UserID: user.ID, User: *user, CategoryID: 2, Amount: money.FromFloat(15.49), Description: "Netflix",
+		Type: models.TransactionTypeExpense, Frequency: models.FrequencyWeekly, Interval: 1, StartDate: day(2024, 9, 24), NextDate: &next}
+	now := day(2024, 10, 16).Add(12 * time.Hour)
+	mockRepo.On("FindDue", now).Return([]*models.RecurringRule{rule}, nil)
+	mockRepo.On("WithLockedRule", rule).Return(nil)
+
+	notFound := (*models.RecurringOccurrence)(nil)
+	mockRepo.On("FindOccurrence", rule.ID, day(2024, 10, 1)).Return(notFound, gorm.ErrRecordNotFound)
+	mockRepo.On("FindOccurrence", rule.ID, day(2024, 10, 8)).Return(&models.RecurringOccurrence{ID: 7, RecurringRuleID: rule.ID, Date: day(2024, 10, 8), Skipped: true}, nil)
+	cheaper := money.FromFloat(9.99)
+	mockRepo.On("FindOccurrence", rule.ID, day(2024, 10, 15)).Return(&models.RecurringOccurrence{ID: 8, RecurringRuleID: rule.ID, Date: day(2024, 10, 15), Amount: &cheaper}, nil)
+
+	// The first occurrence was booked by a run that stopped before saving.
+	mockRepo.On("FindBookedTransactionID", user.ID, "recurring:3:2024-10-01").Return(uint(40), nil)
+	mockRepo.On("FindBookedTransactionID", user.ID, "recurring:3:2024-10-15").Return(uint(0), nil)
+	mockBooker.On("AddTransaction", user.Username, transaction.TransactionInput{
+		Type: models.TransactionTypeExpense, CategoryID: 2, Amount: cheaper, Description: "Netflix",
+		TransactionDate: day(2024, 10, 15), ExternalID: "recurring:3:2024-10-15", AllowDuplicate: true,
+	}).Return(&models.Transaction{ID: 41}, nil)
+	mockRepo.On("SaveProgress", rule, mock.Anything).Return(nil)
+
+	result, err := service.MaterializeDue(now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &recurring.MaterializeResult{Booked: 2, Skipped: 1}, result)
+	assert.Equal(t, day(2024, 10, 15), *rule.LastDate)
+	assert.Equal(t, day(2024, 10, 22), *rule.NextDate)
+	mockBooker.AssertNumberOfCalls(t, "AddTransaction", 1)
+	mockRepo.AssertNumberOfCalls(t, "SaveProgress", 3)
+}
+
+func TestRecurringService_MaterializeDue_StopsAtEndDate(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockBooker := setUpRecurringService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	next, end := day(2024, 12, 31), day(2025, 1, 15)
+	rule := &models.RecurringRule{ID: 4, UserID: user.ID, User: *user, Amount: money.FromFloat(99), Frequency: models.FrequencyYearly,
+		Interval: 1, StartDate: day(2023, 12, 31), EndDate: &end, NextDate: &next}
+	now := day(2025, 1, 2)
+	mockRepo.On("FindDue", now).Return([]*models.RecurringRule{rule}, nil)
+	mockRepo.On("WithLockedRule", rule).Return(nil)
+	mockRepo.On("FindOccurrence", rule.ID, next).Return((*models.RecurringOccurrence)(nil), gorm.ErrRecordNotFound)
+	mockRepo.On("FindBookedTransactionID", user.ID, "recurring:4:2024-12-31").Return(uint(0), nil)
+	mockBooker.On("AddTransaction", user.Username, mock.Anything).Return(&models.Transaction{ID: 50}, nil)
+	mockRepo.On("SaveProgress", rule, mock.Anything).Return(nil)
+
+	_, err := service.MaterializeDue(now)
+
+	assert.NoError(t, err)
+	assert.Nil(t, rule.NextDate)
+}
+
+func TestRecurringService_MaterializeDue_RuleDeletedMeanwhile(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockBooker := setUpRecurringService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	next := day(2024, 10, 1)
+	rule := &models.RecurringRule{ID: 5, UserID: user.ID, User: *user, Amount: money.FromFloat(20), Frequency: models.FrequencyMonthly,
+		Interval: 1, StartDate: next, NextDate: &next}
+	now := day(2024, 10, 2)
+	mockRepo.On("FindDue", now).Return([]*models.RecurringRule{rule}, nil)
+	mockRepo.On("WithLockedRule", rule).Return(gorm.ErrRecordNotFound)
+
+	result, err := service.MaterializeDue(now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &recurring.MaterializeResult{}, result)
+	mockBooker.AssertNotCalled(t, "AddTransaction", mock.Anything, mock.Anything)
+}
+
+func TestRecurringService_UpdateOccurrence(t *testing.T) {
+	service, mockRepo, mockUserRepo, _ := setUpRecurringService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	next := day(2024, 11, 1)
+	rule := &models.RecurringRule{ID: 3, UserID: user.ID, Amount: money.FromFloat(1200), Description: "Rent",
+		Frequency: models.FrequencyMonthly, Interval: 1, StartDate: day(2024, 1, 1), NextDate: &next}
+	mockRepo.On("FindByID", rule.ID).Return(rule, nil)
+
+	_, err := service.UpdateOccurrence(user.Username, rule.ID, day(2024, 12, 2), recurring.OccurrenceInput{Skip: true})
+	assert.ErrorIs(t, err, recurring.ErrInvalidOccurrence)
+
+	_, err = service.UpdateOccurrence(user.Username, rule.ID, day(2024, 10, 1), recurring.OccurrenceInput{Skip: true})
+	assert.ErrorIs(t, err, recurring.ErrInvalidOccurrence)
+
+	mockRepo.On("FindOccurrence", rule.ID, day(2024, 12, 1)).Return((*models.RecurringOccurrence)(nil), gorm.ErrRecordNotFound)
+	mockRepo.On("SaveOccurrence", mock.Anything).Return(nil)
+	amount := money.FromFloat(1250)
+
+	occurrence, err := service.UpdateOccurrence(user.Username, rule.ID, time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC), recurring.OccurrenceInput{Amount: &amount})
+
+	assert.NoError(t, err)
+	assert.Equal(t, day(2024, 12, 1), occurrence.Date)
+	assert.Equal(t, amount, occurrence.Amount)
+	assert.True(t, occurrence.Modified)
+	saved := mockRepo.Calls[len(mockRepo.Calls)-1].Arguments.Get(0).(*models.RecurringOccurrence)
+	assert.Equal(t, rule.ID, saved.RecurringRuleID)
+	assert.Equal(t, &amount, saved.Amount)
+}
+
+func TestRecurringService_GetRule_OtherUsersRule(t *testing.T) {
+	service, mockRepo, mockUserRepo, _ := setUpRecurringService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	mockRepo.On("FindByID", uint(8)).Return(&models.RecurringRule{ID: 8, UserID: 2}, nil)
+	mockRepo.On("FindByID", uint(9)).Return((*models.RecurringRule)(nil), gorm.ErrRecordNotFound)
+
+	_, err := service.GetRule(user.Username, 8)
+	assert.ErrorIs(t, err, recurring.ErrRuleAccessDenied)
+
+	_, err = service.GetRule(user.Username, 9)
+	assert.ErrorIs(t, err, recurring.ErrRuleNotFound)
+}
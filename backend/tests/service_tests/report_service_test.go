@@ -0,0 +1,171 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/reports"
+	"github.com/shaikhjunaidx/pennywise-backend/models"
+	"github.com/shaikhjunaidx/pennywise-backend/tests/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setUpReportService() (*reports.ReportService, *mocks.MockReportRepository, *mocks.MockUserRepository) {
+	mockRepo := new(mocks.MockReportRepository)
+	mockUserRepo := &mocks.MockUserRepository{
+		Users: make(map[string]*models.User),
+	}
+
+	return reports.NewReportService(mockRepo, mockUserRepo), mockRepo, mockUserRepo
+}
+
+func TestReportService_MonthlySummaries(t *testing.T) {
+	service, mockRepo, mockUserRepo := setUpReportService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	period := reports.Range{
+		From: time.Date(2024, 8, 1, 0, 0, 0, 0, time.Local),
+		To:   time.Date(2024, 11, 1, 0, 0, 0, 0, time.Local),
+	}
+	mockRepo.On("TotalsByMonth", user.ID, period).Return([]reports.MonthTotals{
+		{Year: 2024, Month: 8, Expense: money.FromFloat(200), Income: money.FromFloat(1000)},
+		{Year: 2024, Month: 10, Expense: money.FromFloat(150), Income: money.FromFloat(50)},
+	}, nil)
+
+	summaries, err := service.MonthlySummaries(user.Username, period)
+
+	assert.NoError(t, err)
+	assert.Len(t, summaries, 3)
+	assert.Equal(t, money.FromFloat(800), summaries[0].Net)
+	assert.Nil(t, summaries[0].ExpenseChangePercent)
+
+	// September had no transactions but is still reported.
+	assert.Equal(t, 9, summaries[1].Month)
+	assert.Equal(t, money.FromFloat(-200), summaries[1].ExpenseChange)
+	assert.Equal(t, -100.0, *summaries[1].ExpenseChangePercent)
+
+	assert.Equal(t, money.FromFloat(150), summaries[2].ExpenseChange)
+	assert.Nil(t, summaries[2].ExpenseChangePercent)
+	assert.Equal(t, money.FromFloat(-100), summaries[2].Net)
+}
+
+func TestReportService_SpendingByCategory_ChangesPerCategory(t *testing.T) {
+	service, mockRepo, mockUserRepo := setUpReportService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	period := reports.Range{
+		From: time.Date(2024, 8, 1, 0, 0, 0, 0, time.Local),
+		To:   time.Date(2024, 11, 1, 0, 0, 0, 0, time.Local),
+	}
+	mockRepo.On("SpendingByCategoryMonth", user.ID, period).Return([]reports.CategoryMonthTotal{
+		{CategoryID: 4, CategoryName: "Rent", Year: 2024, Month: 8, Total: money.FromFloat(600)},
+		{CategoryID: 5, CategoryName: "Groceries", Year: 2024, Month: 8, Total: money.FromFloat(200)},
+		{CategoryID: 4, CategoryName: "Rent", Year: 2024, Month: 9, Total: money.FromFloat(600)},
+		{CategoryID: 5, CategoryName: "Groceries", Year: 2024, Month: 10, Total: money.FromFloat(300)},
+	}, nil)
+
+	totals, err := service.SpendingByCategory(user.Username, period)
+
+	assert.NoError(t, err)
+	assert.Len(t, totals, 6)
+	assert.Nil(t, totals[0].ChangePercent)
+
+	// Groceries had no spending in September, which shows as a drop.
+	assert.Equal(t, 9, totals[3].Month)
+	assert.Equal(t, "Groceries", totals[3].CategoryName)
+	assert.Equal(t, money.FromFloat(-200), totals[3].Change)
+	assert.Equal(t, -100.0, *totals[3].ChangePercent)
+	assert.Equal(t, 0.0, *totals[2].ChangePercent)
+
+	// In October Groceries leads, and Rent dropped to nothing.
+	assert.Equal(t, "Groceries", totals[4].CategoryName)
+	assert.Equal(t, money.FromFloat(300), totals[4].Change)
+	assert.Nil(t, totals[4].ChangePercent)
+	assert.Equal(t, money.FromFloat(-600), totals[5].Change)
+}
+
+func TestReportService_CashFlow(t *testing.T) {
+	service, mockRepo, mockUserRepo := setUpReportService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	period := reports.Range{
+		From: time.Date(2024, 9, 1, 0, 0, 0, 0, time.Local),
+		To:   time.Date(2024, 11, 1, 0, 0, 0, 0, time.Local),
+	}
+	mockRepo.On("TotalsByMonth", user.ID, period).Return([]reports.MonthTotals{
+		{Year: 2024, Month: 9, Expense: money.FromFloat(1500), Income: money.FromFloat(2000)},
+		{Year: 2024, Month: 10, Expense: money.FromFloat(300)},
+	}, nil)
+
+	flow, err := service.CashFlow(user.Username, period)
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromFloat(2000), flow.Income)
+	assert.Equal(t, money.FromFloat(1800), flow.Expense)
+	assert.Equal(t, money.FromFloat(200), flow.Net)
+	assert.Equal(t, 0.1, *flow.SavingsRate)
+	assert.Len(t, flow.Months, 2)
+	assert.Equal(t, 0.25, *flow.Months[0].SavingsRate)
+	// Without income there is no rate to report.
+	assert.Nil(t, flow.Months[1].SavingsRate)
+}
+
+func TestReportService_TopCategories(t *testing.T) {
+	service, mockRepo, mockUserRepo := setUpReportService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	period := reports.DefaultRange(time.Now())
+	mockRepo.On("TopCategories", user.ID, period, reports.DefaultTopLimit).Return([]reports.CategoryTotal{
+		{CategoryID: 4, CategoryName: "Rent", Total: money.FromFloat(600)},
+		{CategoryID: 5, CategoryName: "Groceries", Total: money.FromFloat(300)},
+	}, nil)
+	mockRepo.On("TotalSpending", user.ID, period).Return(money.FromFloat(1200), nil)
+
+	totals, err := service.TopCategories(user.Username, period, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, totals[0].Share)
+	assert.Equal(t, 0.25, totals[1].Share)
+}
+
+func TestReportService_InvalidRequests(t *testing.T) {
+	service, _, mockUserRepo := setUpReportService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	now := time.Now()
+
+	_, err := service.SpendingByCategory(user.Username, reports.Range{From: now, To: now.AddDate(0, 0, -1)})
+	assert.ErrorIs(t, err, reports.ErrInvalidRange)
+
+	_, err = service.MonthlySummaries(user.Username, reports.Range{From: now.AddDate(-20, 0, 0), To: now})
+	assert.ErrorIs(t, err, reports.ErrInvalidRange)
+
+	_, err = service.TopMerchants(user.Username, reports.DefaultRange(now), reports.MaxTopLimit+1)
+	assert.ErrorIs(t, err, reports.ErrInvalidLimit)
+}
+
+func TestReportService_Trend_FillsEmptyBuckets(t *testing.T) {
+	service, mockRepo, mockUserRepo := setUpReportService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	query := reports.TrendQuery{
+		Granularity: reports.GranularityMonth,
+		Range:       reports.Range{From: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		CategoryIDs: []uint{4},
+		Location:    time.UTC,
+	}
+	mockRepo.On("TotalsByBucket", user.ID, mock.AnythingOfType("[]reports.Bucket"), []uint{4}).Return([]reports.BucketTotal{
+		{Index: 1, Total: money.FromFloat(42), Count: 3},
+	}, nil)
+
+	points, err := service.Trend(user.Username, query)
+
+	assert.NoError(t, err)
+	assert.Len(t, points, 3)
+	assert.Zero(t, points[0].Total)
+	assert.Equal(t, money.FromFloat(42), points[1].Total)
+	assert.Equal(t, int64(3), points[1].Count)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), points[2].Start)
+}
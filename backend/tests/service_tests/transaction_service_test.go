@@ -5,12 +5,16 @@ import (
 	"time"
 
 	"github.com/shaikhjunaidx/pennywise-backend/internal/budget"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/constants"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/fx"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/user"
 	"github.com/shaikhjunaidx/pennywise-backend/models"
 	"github.com/shaikhjunaidx/pennywise-backend/tests/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 func setUpTransactionService() (*transaction.TransactionService, *mocks.MockTransactionRepository, *mocks.MockUserRepository, *mocks.MockCategoryRepository, *mocks.MockBudgetRepository) {
@@ -20,11 +24,12 @@ func setUpTransactionService() (*transaction.TransactionService, *mocks.MockTran
 		Users: make(map[string]*models.User),
 	}
 	mockBudgetRepo := new(mocks.MockBudgetRepository)
+	unitOfWork := &mocks.MockUnitOfWork{Transactions: mockRepo, Budgets: mockBudgetRepo}
 
 	userService := &user.UserService{Repo: mockUserRepo}
 	budgetService := budget.NewBudgetService(mockBudgetRepo, userService)
 
-	service := transaction.NewTransactionService(mockRepo, mockUserRepo, mockCategoryRepo, budgetService)
+	service := transaction.NewTransactionService(mockRepo, mockUserRepo, mockCategoryRepo, budgetService, unitOfWork)
 	return service, mockRepo, mockUserRepo, mockCategoryRepo, mockBudgetRepo
 }
 
@@ -42,53 +47,328 @@ func createTestTransaction(userID, categoryID uint, amount float64, description
 	return &models.Transaction{
 		UserID:          userID,
 		CategoryID:      categoryID,
-		Amount:          amount,
+		Amount:          money.FromFloat(amount),
 		Description:     description,
 		TransactionDate: time.Now(),
 	}
 }
 
+func createTestTransactionResponse(userID, categoryID uint, amount float64, description string) *transaction.TransactionResponse {
+	return &transaction.TransactionResponse{
+		UserID:      userID,
+		CategoryID:  categoryID,
+		Amount:      money.FromFloat(amount),
+		Description: description,
+	}
+}
+
+func createTestCategory(mockCategoryRepo *mocks.MockCategoryRepository, userID, id uint, name string) *models.Category {
+	category := &models.Category{ID: id, UserID: userID, Name: name}
+	mockCategoryRepo.On("FindByID", id).Return(category, nil)
+	return category
+}
+
+// expectNoOverallBudget tells the budget mock that the user has no overall
+// budget for the period the date falls in.
+func expectNoOverallBudget(mockBudgetRepo *mocks.MockBudgetRepository, userID uint, date time.Time) {
+	mockBudgetRepo.On("FindByUserIDAndCategoryID", userID, (*uint)(nil), date.Month().String(), date.Year()).Return((*models.Budget)(nil), gorm.ErrRecordNotFound)
+}
+
+// expectNoDuplicateCandidates tells the transaction mock that the user has no
+// stored transactions a new one could duplicate.
+func expectNoDuplicateCandidates(mockRepo *mocks.MockTransactionRepository, userID uint) {
+	mockRepo.On("FindDuplicateCandidates", userID, mock.Anything, mock.Anything, mock.Anything).Return([]*models.Transaction{}, nil)
+}
+
 func TestTransactionService_AddTransaction(t *testing.T) {
-	service, mockRepo, mockUserRepo, _, mockBudgetRepo := setUpTransactionService()
+	service, mockRepo, mockUserRepo, mockCategoryRepo, mockBudgetRepo := setUpTransactionService()
 
 	username := "john_doe"
 	user := createTestUser(mockUserRepo, username, 1)
-	transaction := createTestTransaction(user.ID, 1, 100.0, "Groceries")
+	createTestCategory(mockCategoryRepo, user.ID, 1, "Groceries")
+	tx := createTestTransaction(user.ID, 1, 100.0, "Groceries")
+
+	budgetRow := &models.Budget{ID: 10, UserID: user.ID}
 
+	expectNoDuplicateCandidates(mockRepo, user.ID)
 	mockRepo.On("Create", mock.Anything).Return(nil)
-	mockBudgetRepo.On("FindByUserIDAndCategoryID", user.ID, &transaction.CategoryID, transaction.TransactionDate.Month().String(), transaction.TransactionDate.Year()).Return(&models.Budget{}, nil)
-	mockBudgetRepo.On("Update", mock.AnythingOfType("*models.Budget")).Return(nil)
+	mockBudgetRepo.On("FindByUserIDAndCategoryID", user.ID, &tx.CategoryID, tx.TransactionDate.Month().String(), tx.TransactionDate.Year()).Return(budgetRow, nil)
+	mockBudgetRepo.On("RecalculateSpent", budgetRow.ID, mock.Anything, mock.Anything).Return(nil)
+	expectNoOverallBudget(mockBudgetRepo, user.ID, tx.TransactionDate)
+	mockBudgetRepo.On("FindByID", budgetRow.ID).Return(budgetRow, nil)
 
-	result, err := service.AddTransaction(username, transaction.CategoryID, transaction.Amount, transaction.Description, transaction.TransactionDate)
+	result, err := service.AddTransaction(username, transaction.TransactionInput{
+		CategoryID:      tx.CategoryID,
+		Amount:          tx.Amount,
+		Description:     tx.Description,
+		TransactionDate: tx.TransactionDate,
+	})
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
-	assert.Equal(t, transaction.Amount, result.Amount)
+	assert.Equal(t, tx.Amount, result.Amount)
+	assert.Equal(t, money.DefaultCurrency, result.Currency)
+
+	mockRepo.AssertExpectations(t)
+	mockBudgetRepo.AssertExpectations(t)
+}
+
+// settingsWithBaseCurrency returns a settings provider that reports base as
+// the user's base currency.
+func settingsWithBaseCurrency(userID uint, base money.Currency) *user.UserService {
+	mockSettings := new(mocks.MockSettingsRepository)
+	mockSettings.On("FindByUserID", userID).Return(&models.UserSettings{UserID: userID, BaseCurrency: base}, nil)
+	return &user.UserService{Settings: mockSettings}
+}
+
+func TestTransactionService_AddTransaction_ConvertsToBaseCurrency(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockCategoryRepo, mockBudgetRepo := setUpTransactionService()
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	createTestCategory(mockCategoryRepo, user.ID, 1, "Groceries")
+	tx := createTestTransaction(user.ID, 1, 50.0, "Groceries")
+
+	service.Settings = settingsWithBaseCurrency(user.ID, "USD")
+
+	rates := new(mocks.MockRateRepository)
+	rates.On("FindLatest", money.Currency("EUR"), money.Currency("USD"), tx.TransactionDate).
+		Return(&models.ExchangeRate{BaseCurrency: "EUR", QuoteCurrency: "USD", Rate: 1.1}, nil)
+	service.Converter = fx.NewConverter(rates)
+
+	budgetRow := &models.Budget{ID: 10, UserID: user.ID}
+
+	expectNoDuplicateCandidates(mockRepo, user.ID)
+	mockRepo.On("Create", mock.MatchedBy(func(created *models.Transaction) bool {
+		return created.Currency == "EUR" && created.BaseAmount == money.FromFloat(55)
+	})).Return(nil)
+	mockBudgetRepo.On("FindByUserIDAndCategoryID", user.ID, &tx.CategoryID, tx.TransactionDate.Month().String(), tx.TransactionDate.Year()).Return(budgetRow, nil)
+	mockBudgetRepo.On("RecalculateSpent", budgetRow.ID, mock.Anything, mock.Anything).Return(nil)
+	expectNoOverallBudget(mockBudgetRepo, user.ID, tx.TransactionDate)
+	mockBudgetRepo.On("FindByID", budgetRow.ID).Return(budgetRow, nil)
+
+	result, err := service.AddTransaction(username, transaction.TransactionInput{
+		CategoryID:      tx.CategoryID,
+		Amount:          tx.Amount,
+		Currency:        "EUR",
+		Description:     tx.Description,
+		TransactionDate: tx.TransactionDate,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromFloat(50), result.Amount)
+	assert.Equal(t, money.FromFloat(55), result.BaseAmount)
+	mockRepo.AssertExpectations(t)
+}
+
+// settingsWithTimeZone returns a settings provider that reports timeZone as
+// the user's time zone.
+func settingsWithTimeZone(userID uint, timeZone string) *user.UserService {
+	mockSettings := new(mocks.MockSettingsRepository)
+	mockSettings.On("FindByUserID", userID).Return(&models.UserSettings{UserID: userID, BaseCurrency: money.DefaultCurrency, TimeZone: timeZone}, nil)
+	return &user.UserService{Settings: mockSettings}
+}
+
+func TestTransactionService_AddTransaction_UsesUserTimeZone(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockCategoryRepo, mockBudgetRepo := setUpTransactionService()
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	createTestCategory(mockCategoryRepo, user.ID, 1, "Groceries")
+
+	service.Settings = settingsWithTimeZone(user.ID, "America/Los_Angeles")
+
+	// Early on October 1st in UTC is still September 30th in Los Angeles.
+	date := time.Date(2024, 10, 1, 3, 0, 0, 0, time.UTC)
+	categoryID := uint(1)
+	budgetRow := &models.Budget{ID: 10, UserID: user.ID}
+
+	expectNoDuplicateCandidates(mockRepo, user.ID)
+	mockRepo.On("Create", mock.Anything).Return(nil)
+	mockBudgetRepo.On("FindByUserIDAndCategoryID", user.ID, &categoryID, "September", 2024).Return(budgetRow, nil)
+	mockBudgetRepo.On("RecalculateSpent", budgetRow.ID, mock.Anything, mock.Anything).Return(nil)
+	mockBudgetRepo.On("FindByUserIDAndCategoryID", user.ID, (*uint)(nil), "September", 2024).Return((*models.Budget)(nil), gorm.ErrRecordNotFound)
+	mockBudgetRepo.On("FindByID", budgetRow.ID).Return(budgetRow, nil)
+
+	_, err := service.AddTransaction(username, transaction.TransactionInput{
+		CategoryID:      categoryID,
+		Amount:          money.FromFloat(20),
+		Description:     "Late dinner",
+		TransactionDate: date,
+	})
+
+	assert.NoError(t, err)
+	mockBudgetRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_AddTransaction_MissingRate(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockCategoryRepo, _ := setUpTransactionService()
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	createTestCategory(mockCategoryRepo, user.ID, 1, "Groceries")
+
+	_, err := service.AddTransaction(username, transaction.TransactionInput{
+		CategoryID:      1,
+		Amount:          money.FromFloat(10),
+		Currency:        "JPY",
+		TransactionDate: time.Now(),
+	})
+
+	assert.ErrorIs(t, err, fx.ErrRateNotFound)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestTransactionService_AddTransaction_RejectsFractionalYen(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockCategoryRepo, _ := setUpTransactionService()
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	createTestCategory(mockCategoryRepo, user.ID, 1, "Groceries")
+
+	_, err := service.AddTransaction(username, transaction.TransactionInput{
+		CategoryID:      1,
+		Amount:          money.FromFloat(1500.5),
+		Currency:        "JPY",
+		TransactionDate: time.Now(),
+	})
 
+	assert.ErrorIs(t, err, transaction.ErrInvalidAmount)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestTransactionService_BaseCurrencyChanged(t *testing.T) {
+	service, mockRepo, _, _, mockBudgetRepo := setUpTransactionService()
+
+	userID := uint(1)
+	date := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	usd := &models.Transaction{ID: 1, UserID: userID, Amount: money.FromFloat(10), Currency: "USD", TransactionDate: date}
+	eur := &models.Transaction{ID: 2, UserID: userID, Amount: money.FromFloat(20), Currency: "EUR", TransactionDate: date}
+	budgetRow := &models.Budget{ID: 10, UserID: userID, AmountLimit: money.FromFloat(100), Currency: "USD", BudgetMonth: "10", BudgetYear: 2024}
+
+	rates := new(mocks.MockRateRepository)
+	rates.On("FindLatest", money.Currency("USD"), money.Currency("EUR"), mock.Anything).
+		Return(&models.ExchangeRate{BaseCurrency: "USD", QuoteCurrency: "EUR", Rate: 0.9}, nil)
+	service.Converter = fx.NewConverter(rates)
+
+	mockRepo.On("FindAllByUserID", userID).Return([]*models.Transaction{usd, eur}, nil)
+	mockRepo.On("UpdateBaseAmount", usd.ID, money.FromFloat(9)).Return(nil)
+	mockRepo.On("UpdateBaseAmount", eur.ID, money.FromFloat(20)).Return(nil)
+	mockBudgetRepo.On("FindForReconciliation", &userID).Return([]*models.Budget{budgetRow}, nil)
+	mockBudgetRepo.On("Update", mock.MatchedBy(func(updated *models.Budget) bool {
+		return updated.Currency == "EUR" && updated.AmountLimit == money.FromFloat(90)
+	})).Return(nil)
+	mockBudgetRepo.On("RecalculateSpent", budgetRow.ID, mock.Anything, mock.Anything).Return(nil)
+
+	mockSettings := new(mocks.MockSettingsRepository)
+	service.UnitOfWork.(*mocks.MockUnitOfWork).Settings = mockSettings
+	settings := &models.UserSettings{UserID: userID, BaseCurrency: "EUR"}
+	mockSettings.On("Save", settings).Return(nil)
+
+	err := service.BaseCurrencyChanged(settings)
+
+	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 	mockBudgetRepo.AssertExpectations(t)
+	mockSettings.AssertExpectations(t)
+}
+
+func TestTransactionService_AddTransaction_FlagsDuplicate(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockCategoryRepo, mockBudgetRepo := setUpTransactionService()
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	createTestCategory(mockCategoryRepo, user.ID, 1, "Groceries")
+	tx := createTestTransaction(user.ID, 1, 23.40, "TESCO STORES 3041")
+	stored := &models.Transaction{ID: 7, UserID: user.ID, Amount: tx.Amount, Currency: money.DefaultCurrency, Description: "Tesco Stores", TransactionDate: tx.TransactionDate.AddDate(0, 0, -1)}
+
+	budgetRow := &models.Budget{ID: 10, UserID: user.ID}
+
+	mockRepo.On("FindDuplicateCandidates", user.ID, mock.Anything, mock.Anything, []string(nil)).Return([]*models.Transaction{stored}, nil)
+	mockRepo.On("Create", mock.MatchedBy(func(created *models.Transaction) bool {
+		return created.DuplicateOfID != nil && *created.DuplicateOfID == stored.ID
+	})).Return(nil)
+	mockBudgetRepo.On("FindByUserIDAndCategoryID", user.ID, &tx.CategoryID, tx.TransactionDate.Month().String(), tx.TransactionDate.Year()).Return(budgetRow, nil)
+	mockBudgetRepo.On("RecalculateSpent", budgetRow.ID, mock.Anything, mock.Anything).Return(nil)
+	expectNoOverallBudget(mockBudgetRepo, user.ID, tx.TransactionDate)
+	mockBudgetRepo.On("FindByID", budgetRow.ID).Return(budgetRow, nil)
+
+	result, err := service.AddTransaction(username, transaction.TransactionInput{
+		CategoryID:      tx.CategoryID,
+		Amount:          tx.Amount,
+		Description:     tx.Description,
+		TransactionDate: tx.TransactionDate,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &stored.ID, result.DuplicateOfID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_AddTransaction_OtherUsersCategory(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockCategoryRepo, _ := setUpTransactionService()
+
+	username := "john_doe"
+	createTestUser(mockUserRepo, username, 1)
+	createTestCategory(mockCategoryRepo, 2, 5, "Someone else's groceries")
+
+	result, err := service.AddTransaction(username, transaction.TransactionInput{CategoryID: 5, Amount: money.FromFloat(100.0), Description: "Groceries", TransactionDate: time.Now()})
+
+	assert.ErrorIs(t, err, transaction.ErrInvalidCategory)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestTransactionService_AddTransaction_BudgetUpdateFails(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockCategoryRepo, mockBudgetRepo := setUpTransactionService()
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	createTestCategory(mockCategoryRepo, user.ID, 1, "Groceries")
+	transactionDate := time.Now()
+	budgetRow := &models.Budget{ID: 10, UserID: user.ID}
+
+	expectNoDuplicateCandidates(mockRepo, user.ID)
+	mockRepo.On("Create", mock.Anything).Return(nil)
+	mockBudgetRepo.On("FindByUserIDAndCategoryID", user.ID, mock.Anything, transactionDate.Month().String(), transactionDate.Year()).Return(budgetRow, nil)
+	mockBudgetRepo.On("RecalculateSpent", budgetRow.ID, mock.Anything, mock.Anything).Return(assert.AnError)
+
+	result, err := service.AddTransaction(username, transaction.TransactionInput{CategoryID: 1, Amount: money.FromFloat(100.0), Description: "Groceries", TransactionDate: transactionDate})
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, result)
 }
 
 func TestTransactionService_UpdateTransaction(t *testing.T) {
-	service, mockRepo, mockUserRepo, _, mockBudgetRepo := setUpTransactionService()
+	service, mockRepo, mockUserRepo, mockCategoryRepo, mockBudgetRepo := setUpTransactionService()
 
 	username := "john_doe"
 	user := createTestUser(mockUserRepo, username, 1)
+	createTestCategory(mockCategoryRepo, user.ID, 2, "Groceries")
 
-	transaction := createTestTransaction(user.ID, 2, 100.0, "Groceries")
-	transaction.ID = 1
+	tx := createTestTransaction(user.ID, 2, 100.0, "Groceries")
+	tx.ID = 1
 
-	updatedAmount := 200.0
+	updatedAmount := money.FromFloat(200.0)
 	updatedCategoryID := uint(2)
 	updatedDescription := "Updated Groceries"
 	updatedTransactionDate := time.Now().AddDate(0, 0, 1)
 
-	mockRepo.On("FindByID", transaction.ID).Return(transaction, nil)
-	mockRepo.On("Update", transaction).Return(nil)
-	mockBudgetRepo.On("FindByUserIDAndCategoryID", user.ID, &updatedCategoryID, updatedTransactionDate.Month().String(), updatedTransactionDate.Year()).Return(&models.Budget{}, nil)
-	mockBudgetRepo.On("Update", mock.AnythingOfType("*models.Budget")).Return(nil)
+	budgetRow := &models.Budget{ID: 10, UserID: user.ID}
+
+	mockRepo.On("FindByIDForUpdate", tx.ID).Return(tx, nil)
+	mockRepo.On("Update", tx).Return(nil)
+	mockBudgetRepo.On("FindByUserIDAndCategoryID", user.ID, &updatedCategoryID, updatedTransactionDate.Month().String(), updatedTransactionDate.Year()).Return(budgetRow, nil)
+	mockBudgetRepo.On("RecalculateSpent", budgetRow.ID, mock.Anything, mock.Anything).Return(nil)
+	expectNoOverallBudget(mockBudgetRepo, user.ID, updatedTransactionDate)
+	mockBudgetRepo.On("FindByID", budgetRow.ID).Return(budgetRow, nil)
 
-	result, err := service.UpdateTransaction(transaction.ID, updatedAmount, updatedCategoryID, updatedDescription, updatedTransactionDate)
+	result, err := service.UpdateTransaction(username, tx.ID, transaction.TransactionInput{
+		CategoryID:      updatedCategoryID,
+		Amount:          updatedAmount,
+		Description:     updatedDescription,
+		TransactionDate: updatedTransactionDate,
+	})
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -99,6 +379,40 @@ func TestTransactionService_UpdateTransaction(t *testing.T) {
 	mockBudgetRepo.AssertExpectations(t)
 }
 
+func TestTransactionService_UpdateTransaction_MovesMonth(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockCategoryRepo, mockBudgetRepo := setUpTransactionService()
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	categoryID := uint(2)
+	createTestCategory(mockCategoryRepo, user.ID, categoryID, "Groceries")
+
+	tx := createTestTransaction(user.ID, categoryID, 100.0, "Groceries")
+	tx.ID = 1
+	oldDate := tx.TransactionDate
+	newDate := oldDate.AddDate(0, -1, 0)
+
+	oldBudget := &models.Budget{ID: 10, UserID: user.ID}
+	newBudget := &models.Budget{ID: 11, UserID: user.ID}
+
+	mockRepo.On("FindByIDForUpdate", tx.ID).Return(tx, nil)
+	mockRepo.On("Update", tx).Return(nil)
+	mockBudgetRepo.On("FindByUserIDAndCategoryID", user.ID, &categoryID, oldDate.Month().String(), oldDate.Year()).Return(oldBudget, nil)
+	mockBudgetRepo.On("FindByUserIDAndCategoryID", user.ID, &categoryID, newDate.Month().String(), newDate.Year()).Return(newBudget, nil)
+	expectNoOverallBudget(mockBudgetRepo, user.ID, oldDate)
+	expectNoOverallBudget(mockBudgetRepo, user.ID, newDate)
+	mockBudgetRepo.On("RecalculateSpent", oldBudget.ID, mock.Anything, mock.Anything).Return(nil)
+	mockBudgetRepo.On("RecalculateSpent", newBudget.ID, mock.Anything, mock.Anything).Return(nil)
+	mockBudgetRepo.On("FindByID", oldBudget.ID).Return(oldBudget, nil)
+	mockBudgetRepo.On("FindByID", newBudget.ID).Return(newBudget, nil)
+
+	result, err := service.UpdateTransaction(username, tx.ID, transaction.TransactionInput{CategoryID: categoryID, Amount: money.FromFloat(100.0), Description: "Groceries", TransactionDate: newDate})
+
+	assert.NoError(t, err)
+	assert.Equal(t, newDate, result.TransactionDate)
+	mockBudgetRepo.AssertExpectations(t)
+}
+
 func TestTransactionService_DeleteTransaction(t *testing.T) {
 	service, mockRepo, mockUserRepo, _, mockBudgetRepo := setUpTransactionService()
 
@@ -108,49 +422,52 @@ func TestTransactionService_DeleteTransaction(t *testing.T) {
 	transaction := createTestTransaction(user.ID, 2, 100.0, "Groceries")
 	transaction.ID = transactionID
 
-	mockRepo.On("FindByID", transactionID).Return(transaction, nil)
+	budgetRow := &models.Budget{ID: 10, UserID: user.ID}
+
+	mockRepo.On("FindByIDForUpdate", transactionID).Return(transaction, nil)
 	mockRepo.On("DeleteByID", transactionID).Return(nil)
-	mockBudgetRepo.On("FindByUserIDAndCategoryID", user.ID, &transaction.CategoryID, transaction.TransactionDate.Month().String(), transaction.TransactionDate.Year()).Return(&models.Budget{}, nil)
-	mockBudgetRepo.On("Update", mock.AnythingOfType("*models.Budget")).Return(nil)
+	mockRepo.On("ClearDuplicatesOf", transactionID).Return(nil)
+	mockBudgetRepo.On("FindByUserIDAndCategoryID", user.ID, &transaction.CategoryID, transaction.TransactionDate.Month().String(), transaction.TransactionDate.Year()).Return(budgetRow, nil)
+	mockBudgetRepo.On("RecalculateSpent", budgetRow.ID, mock.Anything, mock.Anything).Return(nil)
+	expectNoOverallBudget(mockBudgetRepo, user.ID, transaction.TransactionDate)
+	mockBudgetRepo.On("FindByID", budgetRow.ID).Return(budgetRow, nil)
 
-	err := service.DeleteTransaction(transactionID)
+	err := service.DeleteTransaction(username, transactionID)
 	assert.NoError(t, err)
 
 	mockRepo.AssertExpectations(t)
 	mockBudgetRepo.AssertExpectations(t)
 }
 
-func TestTransactionService_GetTransactionsForUser(t *testing.T) {
+func TestTransactionService_DeleteTransaction_OtherUsersTransaction(t *testing.T) {
 	service, mockRepo, mockUserRepo, _, _ := setUpTransactionService()
 
 	username := "john_doe"
-	user := createTestUser(mockUserRepo, username, 1)
-
-	transactions := []*models.Transaction{
-		createTestTransaction(user.ID, 2, 50.0, "Dinner"),
-		createTestTransaction(user.ID, 3, 150.0, "Utilities"),
-	}
-
-	mockRepo.On("FindAllByUsername", username).Return(transactions, nil)
+	createTestUser(mockUserRepo, username, 1)
+	otherUsersTransaction := createTestTransaction(2, 3, 100.0, "Rent")
+	otherUsersTransaction.ID = 9
 
-	result, err := service.GetTransactionsForUser(username)
+	mockRepo.On("FindByIDForUpdate", otherUsersTransaction.ID).Return(otherUsersTransaction, nil)
 
-	assert.NoError(t, err)
-	assert.Equal(t, transactions, result)
+	err := service.DeleteTransaction(username, otherUsersTransaction.ID)
 
-	mockRepo.AssertExpectations(t)
+	assert.ErrorIs(t, err, transaction.ErrTransactionAccessDenied)
+	mockRepo.AssertNotCalled(t, "DeleteByID", otherUsersTransaction.ID)
 }
 
 func TestTransactionService_GetTransactionByID_Success(t *testing.T) {
-	service, mockRepo, _, _, _ := setUpTransactionService()
+	service, mockRepo, mockUserRepo, _, _ := setUpTransactionService()
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
 
 	transactionID := uint(1)
-	expectedTransaction := createTestTransaction(1, 2, 100.0, "Groceries")
+	expectedTransaction := createTestTransaction(user.ID, 2, 100.0, "Groceries")
 	expectedTransaction.ID = transactionID
 
 	mockRepo.On("FindByID", transactionID).Return(expectedTransaction, nil)
 
-	result, err := service.GetTransactionByID(transactionID)
+	result, err := service.GetTransactionByID(username, transactionID)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedTransaction, result)
@@ -159,15 +476,18 @@ func TestTransactionService_GetTransactionByID_Success(t *testing.T) {
 }
 
 func TestTransactionService_GetTransactionByID_NotFound(t *testing.T) {
-	service, mockRepo, _, _, _ := setUpTransactionService()
+	service, mockRepo, mockUserRepo, _, _ := setUpTransactionService()
+
+	username := "john_doe"
+	createTestUser(mockUserRepo, username, 1)
 
 	transactionID := uint(1)
 
-	mockRepo.On("FindByID", transactionID).Return((*models.Transaction)(nil), assert.AnError)
+	mockRepo.On("FindByID", transactionID).Return((*models.Transaction)(nil), gorm.ErrRecordNotFound)
 
-	result, err := service.GetTransactionByID(transactionID)
+	result, err := service.GetTransactionByID(username, transactionID)
 
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, transaction.ErrTransactionNotFound)
 	assert.Nil(t, result)
 
 	mockRepo.AssertExpectations(t)
@@ -197,13 +517,13 @@ func TestTransactionService_FindAllByUserIDAndCategoryID(t *testing.T) {
 	mockCategoryRepo.On("FindByID", category2.ID).Return(category2, nil)
 
 	// Create transactions using helper
-	transactionsForCategory1 := []*models.Transaction{
-		createTestTransaction(user.ID, category1.ID, 50.0, "Groceries Shopping"),
-		createTestTransaction(user.ID, category1.ID, 100.0, "Weekly Groceries"),
+	transactionsForCategory1 := []*transaction.TransactionResponse{
+		createTestTransactionResponse(user.ID, category1.ID, 50.0, "Groceries Shopping"),
+		createTestTransactionResponse(user.ID, category1.ID, 100.0, "Weekly Groceries"),
 	}
-	transactionsForCategory2 := []*models.Transaction{
-		createTestTransaction(user.ID, category2.ID, 150.0, "Electricity Bill"),
-		createTestTransaction(user.ID, category2.ID, 75.0, "Water Bill"),
+	transactionsForCategory2 := []*transaction.TransactionResponse{
+		createTestTransactionResponse(user.ID, category2.ID, 150.0, "Electricity Bill"),
+		createTestTransactionResponse(user.ID, category2.ID, 75.0, "Water Bill"),
 	}
 
 	// Mock repository behavior
@@ -216,7 +536,7 @@ func TestTransactionService_FindAllByUserIDAndCategoryID(t *testing.T) {
 	assert.Len(t, result, 2)
 	for _, transaction := range result {
 		assert.Equal(t, category1.ID, transaction.CategoryID)
-		assert.Contains(t, []float64{50.0, 100.0}, transaction.Amount)
+		assert.Contains(t, []money.Amount{money.FromFloat(50.0), money.FromFloat(100.0)}, transaction.Amount)
 		assert.Contains(t, []string{"Groceries Shopping", "Weekly Groceries"}, transaction.Description)
 	}
 
@@ -226,14 +546,540 @@ func TestTransactionService_FindAllByUserIDAndCategoryID(t *testing.T) {
 	assert.Len(t, result, 2)
 	for _, transaction := range result {
 		assert.Equal(t, category2.ID, transaction.CategoryID)
-		assert.Contains(t, []float64{150.0, 75.0}, transaction.Amount)
+		assert.Contains(t, []money.Amount{money.FromFloat(150.0), money.FromFloat(75.0)}, transaction.Amount)
 		assert.Contains(t, []string{"Electricity Bill", "Water Bill"}, transaction.Description)
 	}
 
 	// Test for a non-existent category
 	nonExistentCategoryID := uint(999)
-	mockRepo.On("FindAllByUserIDAndCategoryID", user.ID, nonExistentCategoryID).Return([]*models.Transaction{}, nil)
+	mockRepo.On("FindAllByUserIDAndCategoryID", user.ID, nonExistentCategoryID).Return([]*transaction.TransactionResponse{}, nil)
 	result, err = service.GetTransactionsByCategoryID(username, nonExistentCategoryID)
 	assert.NoError(t, err)
 	assert.Len(t, result, 0)
 }
+
+func TestTransactionService_GetDuplicatePairs(t *testing.T) {
+	service, mockRepo, mockUserRepo, _, _ := setUpTransactionService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	originalID := uint(3)
+	original := &models.Transaction{ID: originalID, UserID: user.ID}
+	flagged := &models.Transaction{ID: 4, UserID: user.ID, DuplicateOfID: &originalID}
+
+	mockRepo.On("FindFlaggedDuplicates", user.ID).Return([]*models.Transaction{flagged}, nil)
+	mockRepo.On("FindAllByIDs", []uint{originalID}).Return([]*models.Transaction{original}, nil)
+
+	pairs, err := service.GetDuplicatePairs(user.Username)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []transaction.DuplicatePair{{Transaction: flagged, DuplicateOf: original}}, pairs)
+}
+
+func TestTransactionService_DismissDuplicate(t *testing.T) {
+	service, mockRepo, mockUserRepo, _, _ := setUpTransactionService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	originalID := uint(3)
+	flagged := &models.Transaction{ID: 4, UserID: user.ID, DuplicateOfID: &originalID}
+
+	mockRepo.On("FindByID", flagged.ID).Return(flagged, nil)
+	mockRepo.On("ClearDuplicateFlag", flagged.ID).Return(nil)
+
+	result, err := service.DismissDuplicate(user.Username, flagged.ID)
+
+	assert.NoError(t, err)
+	assert.Nil(t, result.DuplicateOfID)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+
+	_, err = service.DismissDuplicate(user.Username, flagged.ID)
+	assert.ErrorIs(t, err, transaction.ErrNotDuplicate)
+}
+
+func TestTransactionService_ListTransactions(t *testing.T) {
+	service, mockRepo, mockUserRepo, _, _ := setUpTransactionService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	page := &transaction.TransactionPage{Transactions: []*transaction.TransactionResponse{{ID: 3}}, Total: 1}
+	mockRepo.On("FindPage", user.ID, transaction.TransactionQuery{
+		Search:     "coffee",
+		Sort:       transaction.SortByDate,
+		Descending: true,
+		Limit:      transaction.DefaultPageSize,
+	}).Return(page, nil)
+
+	result, err := service.ListTransactions(user.Username, transaction.TransactionQuery{Search: "coffee"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, page, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_ListTransactions_InvalidSort(t *testing.T) {
+	service, mockRepo, mockUserRepo, _, _ := setUpTransactionService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+
+	_, err := service.ListTransactions(user.Username, transaction.TransactionQuery{Sort: "password"})
+
+	assert.ErrorIs(t, err, transaction.ErrInvalidQuery)
+	mockRepo.AssertNotCalled(t, "FindPage", mock.Anything, mock.Anything)
+}
+
+func TestTransactionService_SearchTransactions(t *testing.T) {
+	service, mockRepo, mockUserRepo, _, _ := setUpTransactionService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+	spring := time.Date(2024, 4, 12, 0, 0, 0, 0, time.Local)
+	exact := &models.Transaction{ID: 1, UserID: user.ID, Description: "Amazon refund", TransactionDate: spring}
+	typo := &models.Transaction{ID: 2, UserID: user.ID, Description: "Amazn Marketplace refnd", TransactionDate: spring.AddDate(0, 1, 0)}
+	other := &models.Transaction{ID: 3, UserID: user.ID, Description: "Rent", TransactionDate: spring}
+
+	mockRepo.On("Search", user.ID, []string{"amazon", "refund"}, 10).Return([]*models.Transaction{exact}, nil)
+	mockRepo.On("SearchFragments", user.ID, [][]string{{"ama", "zon"}, {"ref", "und"}}, []uint{exact.ID}, 100).Return([]*models.Transaction{typo, other}, nil)
+
+	results, err := service.SearchTransactions(user.Username, "Amazon refund", 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, exact.ID, results[0].ID)
+	assert.Equal(t, 1.0, results[0].Score)
+	assert.Equal(t, typo.ID, results[1].ID)
+	assert.Equal(t, 0.5, results[1].Score)
+}
+
+func TestTransactionService_SearchTransactions_RequiresText(t *testing.T) {
+	service, _, mockUserRepo, _, _ := setUpTransactionService()
+
+	user := createTestUser(mockUserRepo, "john_doe", 1)
+
+	_, err := service.SearchTransactions(user.Username, "  ", 0)
+
+	assert.ErrorIs(t, err, transaction.ErrInvalidQuery)
+}
+
+func TestTransactionService_AddTransaction_IncomeSkipsBudgets(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockCategoryRepo, mockBudgetRepo := setUpTransactionService()
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	salary := &models.Category{ID: 3, UserID: user.ID, Name: "Income", Kind: models.CategoryKindIncome}
+	mockCategoryRepo.On("FindByNameKindAndUserID", "Income", models.CategoryKindIncome, user.ID).Return(salary, nil)
+
+	expectNoDuplicateCandidates(mockRepo, user.ID)
+	mockRepo.On("Create", mock.Anything).Return(nil)
+
+	result, err := service.AddTransaction(username, transaction.TransactionInput{
+		Type:            "Income",
+		Amount:          money.FromFloat(2500),
+		Description:     "Salary",
+		TransactionDate: time.Now(),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.TransactionTypeIncome, result.Type)
+	assert.Equal(t, salary.ID, result.CategoryID)
+	mockBudgetRepo.AssertNotCalled(t, "FindByUserIDAndCategoryID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTransactionService_ValidateTransaction_CreatesNothing(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockCategoryRepo, _ := setUpTransactionService()
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	mockCategoryRepo.On("FindByNameKindAndUserID", "Income", models.CategoryKindIncome, user.ID).Return((*models.Category)(nil), gorm.ErrRecordNotFound)
+
+	err := service.ValidateTransaction(username, transaction.TransactionInput{
+		Type:            "Income",
+		Amount:          money.FromFloat(2500),
+		Description:     "Salary",
+		TransactionDate: time.Now(),
+	})
+
+	assert.NoError(t, err)
+	mockCategoryRepo.AssertNotCalled(t, "Create", mock.Anything)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestTransactionService_AddTransaction_ChecksTypeAndCategory(t *testing.T) {
+	service, _, mockUserRepo, mockCategoryRepo, _ := setUpTransactionService()
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	createTestCategory(mockCategoryRepo, user.ID, 1, "Groceries")
+
+	_, err := service.AddTransaction(username, transaction.TransactionInput{CategoryID: 1, Type: "gift", Amount: money.FromFloat(10), TransactionDate: time.Now()})
+	assert.ErrorIs(t, err, transaction.ErrInvalidType)
+
+	_, err = service.AddTransaction(username, transaction.TransactionInput{CategoryID: 1, Amount: money.FromFloat(-10), TransactionDate: time.Now()})
+	assert.ErrorIs(t, err, transaction.ErrInvalidAmount)
+
+	_, err = service.AddTransaction(username, transaction.TransactionInput{CategoryID: 1, Type: models.TransactionTypeIncome, Amount: money.FromFloat(10), TransactionDate: time.Now()})
+	assert.ErrorIs(t, err, transaction.ErrInvalidCategory)
+}
+
+func TestTransactionService_AddTransaction_ToAccount(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockCategoryRepo, mockBudgetRepo := setUpTransactionService()
+	mockAccountRepo := new(mocks.MockAccountRepository)
+	service.Accounts = mockAccountRepo
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	createTestCategory(mockCategoryRepo, user.ID, 1, "Groceries")
+	euros := &models.Account{ID: 4, UserID: user.ID, Currency: "EUR"}
+	mockAccountRepo.On("FindByID", euros.ID).Return(euros, nil)
+	mockAccountRepo.On("FindByID", uint(5)).Return(&models.Account{ID: 5, UserID: 2, Currency: "USD"}, nil)
+
+	_, err := service.AddTransaction(username, transaction.TransactionInput{CategoryID: 1, AccountID: euros.ID, Currency: "USD", Amount: money.FromFloat(10), TransactionDate: time.Now()})
+	assert.ErrorIs(t, err, transaction.ErrInvalidAccount)
+
+	_, err = service.AddTransaction(username, transaction.TransactionInput{CategoryID: 1, AccountID: 5, Amount: money.FromFloat(10), TransactionDate: time.Now()})
+	assert.ErrorIs(t, err, transaction.ErrInvalidAccount)
+
+	date := time.Now()
+	rates := new(mocks.MockRateRepository)
+	rates.On("FindLatest", money.Currency("EUR"), money.Currency("USD"), date).
+		Return(&models.ExchangeRate{BaseCurrency: "EUR", QuoteCurrency: "USD", Rate: 1.1}, nil)
+	service.Converter = fx.NewConverter(rates)
+	expectNoDuplicateCandidates(mockRepo, user.ID)
+	mockRepo.On("Create", mock.Anything).Return(nil)
+	budgetRow := &models.Budget{ID: 10, UserID: user.ID}
+	categoryID := uint(1)
+	mockBudgetRepo.On("FindByUserIDAndCategoryID", user.ID, &categoryID, date.Month().String(), date.Year()).Return(budgetRow, nil)
+	mockBudgetRepo.On("RecalculateSpent", budgetRow.ID, mock.Anything, mock.Anything).Return(nil)
+	expectNoOverallBudget(mockBudgetRepo, user.ID, date)
+	mockBudgetRepo.On("FindByID", budgetRow.ID).Return(budgetRow, nil)
+
+	result, err := service.AddTransaction(username, transaction.TransactionInput{CategoryID: 1, AccountID: euros.ID, Amount: money.FromFloat(10), TransactionDate: date})
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.Currency("EUR"), result.Currency)
+	assert.Equal(t, euros.ID, *result.AccountID)
+}
+
+func TestTransactionService_ReconciledTransactionIsLocked(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockCategoryRepo, _ := setUpTransactionService()
+	mockAccountRepo := new(mocks.MockAccountRepository)
+	service.Accounts = mockAccountRepo
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	createTestCategory(mockCategoryRepo, user.ID, 1, "Groceries")
+	through := time.Date(2024, 10, 31, 0, 0, 0, 0, time.Local)
+	checking := &models.Account{ID: 4, UserID: user.ID, Name: "Checking", Currency: money.DefaultCurrency, ReconciledThrough: &through}
+	mockAccountRepo.On("FindByID", checking.ID).Return(checking, nil)
+
+	// Booked late on the statement date, so the statement covered it.
+	reconciled := createTestTransaction(user.ID, 1, 40.0, "Groceries")
+	reconciled.ID = 3
+	reconciled.AccountID = &checking.ID
+	reconciled.TransactionDate = through.Add(22 * time.Hour)
+	mockRepo.On("FindByIDForUpdate", reconciled.ID).Return(reconciled, nil)
+
+	err := service.DeleteTransaction(username, reconciled.ID)
+	assert.ErrorIs(t, err, transaction.ErrReconciled)
+
+	_, err = service.UpdateTransaction(username, reconciled.ID, transaction.TransactionInput{CategoryID: 1, Amount: money.FromFloat(45), TransactionDate: reconciled.TransactionDate})
+	assert.ErrorIs(t, err, transaction.ErrReconciled)
+
+	// Nor can a later transaction be moved back into the reconciled period.
+	open := createTestTransaction(user.ID, 1, 15.0, "Groceries")
+	open.ID = 8
+	open.AccountID = &checking.ID
+	open.TransactionDate = through.AddDate(0, 0, 3)
+	mockRepo.On("FindByIDForUpdate", open.ID).Return(open, nil)
+
+	_, err = service.UpdateTransaction(username, open.ID, transaction.TransactionInput{CategoryID: 1, AccountID: checking.ID, Amount: money.FromFloat(15), TransactionDate: through})
+	assert.ErrorIs(t, err, transaction.ErrReconciled)
+
+	mockRepo.AssertNotCalled(t, "DeleteByID", mock.Anything)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestTransactionService_BackdatedTransactionIntoReconciledPeriod(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockCategoryRepo, _ := setUpTransactionService()
+	mockAccountRepo := new(mocks.MockAccountRepository)
+	service.Accounts = mockAccountRepo
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	createTestCategory(mockCategoryRepo, user.ID, 1, "Groceries")
+	through := time.Date(2024, 10, 31, 0, 0, 0, 0, time.Local)
+	checking := &models.Account{ID: 4, UserID: user.ID, Name: "Checking", Currency: money.DefaultCurrency, ReconciledThrough: &through}
+	savings := &models.Account{ID: 5, UserID: user.ID, Name: "Savings", Currency: money.DefaultCurrency}
+	mockAccountRepo.On("FindByID", checking.ID).Return(checking, nil)
+	mockAccountRepo.On("FindByID", savings.ID).Return(savings, nil)
+	mockCategoryRepo.On("FindByNameKindAndUserID", constants.DefaultTransferCategoryName, models.CategoryKindExpense, user.ID).
+		Return(&models.Category{ID: 7, UserID: user.ID, Name: constants.DefaultTransferCategoryName}, nil)
+
+	backdated := transaction.TransactionInput{CategoryID: 1, AccountID: checking.ID, Amount: money.FromFloat(25), TransactionDate: through.Add(18 * time.Hour)}
+
+	_, err := service.AddTransaction(username, backdated)
+	assert.ErrorIs(t, err, transaction.ErrReconciled)
+
+	later := backdated
+	later.TransactionDate = through.AddDate(0, 0, 1)
+	_, err = service.ImportTransactions(username, []transaction.TransactionInput{later, backdated})
+	assert.ErrorIs(t, err, transaction.ErrReconciled)
+	var rowErr *transaction.RowError
+	assert.ErrorAs(t, err, &rowErr)
+	assert.Equal(t, 1, rowErr.Index)
+
+	_, err = service.CreateTransfer(username, transaction.TransferInput{FromAccountID: savings.ID, ToAccountID: checking.ID, Amount: money.FromFloat(100), TransactionDate: through})
+	assert.ErrorIs(t, err, transaction.ErrReconciled)
+
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	mockRepo.AssertNotCalled(t, "CreateAll", mock.Anything)
+}
+
+func TestTransactionService_CreateTransfer(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockCategoryRepo, mockBudgetRepo := setUpTransactionService()
+	mockAccountRepo := new(mocks.MockAccountRepository)
+	service.Accounts = mockAccountRepo
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	checking := &models.Account{ID: 4, UserID: user.ID, Currency: "USD"}
+	savings := &models.Account{ID: 5, UserID: user.ID, Currency: "USD"}
+	mockAccountRepo.On("FindByID", checking.ID).Return(checking, nil)
+	mockAccountRepo.On("FindByID", savings.ID).Return(savings, nil)
+
+	mockCategoryRepo.On("FindByNameKindAndUserID", constants.DefaultTransferCategoryName, models.CategoryKindExpense, user.ID).Return((*models.Category)(nil), gorm.ErrRecordNotFound)
+	mockCategoryRepo.On("Create", mock.AnythingOfType("*models.Category")).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Category).ID = 7
+	}).Return(nil)
+
+	nextID := uint(20)
+	mockRepo.On("Create", mock.AnythingOfType("*models.Transaction")).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Transaction).ID = nextID
+		nextID++
+	}).Return(nil)
+	mockRepo.On("Update", mock.AnythingOfType("*models.Transaction")).Return(nil)
+
+	_, err := service.CreateTransfer(username, transaction.TransferInput{FromAccountID: checking.ID, ToAccountID: checking.ID, Amount: money.FromFloat(100), TransactionDate: time.Now()})
+	assert.ErrorIs(t, err, transaction.ErrInvalidTransfer)
+
+	_, err = service.CreateTransfer(username, transaction.TransferInput{FromAccountID: checking.ID, ToAccountID: savings.ID, Amount: money.FromFloat(100), ToAmount: money.FromFloat(90), TransactionDate: time.Now()})
+	assert.ErrorIs(t, err, transaction.ErrInvalidTransfer)
+
+	result, err := service.CreateTransfer(username, transaction.TransferInput{FromAccountID: checking.ID, ToAccountID: savings.ID, Amount: money.FromFloat(100), Description: "Savings", TransactionDate: time.Now()})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.TransactionTypeTransfer, result.From.Type)
+	assert.Equal(t, models.TransactionTypeTransfer, result.To.Type)
+	assert.Equal(t, checking.ID, *result.From.AccountID)
+	assert.Equal(t, savings.ID, *result.To.AccountID)
+	assert.Equal(t, result.To.ID, *result.From.TransferID)
+	assert.Equal(t, result.From.ID, *result.To.TransferID)
+	assert.False(t, result.From.TransferIn)
+	assert.True(t, result.To.TransferIn)
+	assert.Equal(t, uint(7), result.From.CategoryID)
+	assert.Equal(t, money.FromFloat(100), result.To.Amount)
+	mockBudgetRepo.AssertNotCalled(t, "FindByUserIDAndCategoryID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockBudgetRepo.AssertNotCalled(t, "RecalculateSpent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTransactionService_DeleteTransaction_DeletesWholeTransfer(t *testing.T) {
+	service, mockRepo, mockUserRepo, _, mockBudgetRepo := setUpTransactionService()
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	fromID, toID := uint(20), uint(21)
+	from := createTestTransaction(user.ID, 7, 100.0, "Savings")
+	from.ID, from.Type, from.TransferID = fromID, models.TransactionTypeTransfer, &toID
+	to := createTestTransaction(user.ID, 7, 100.0, "Savings")
+	to.ID, to.Type, to.TransferID, to.TransferIn = toID, models.TransactionTypeTransfer, &fromID, true
+
+	mockRepo.On("FindByIDForUpdate", toID).Return(to, nil)
+	mockRepo.On("FindByIDForUpdate", fromID).Return(from, nil)
+	mockRepo.On("DeleteByID", fromID).Return(nil)
+	mockRepo.On("DeleteByID", toID).Return(nil)
+	mockRepo.On("ClearDuplicatesOf", fromID).Return(nil)
+	mockRepo.On("ClearDuplicatesOf", toID).Return(nil)
+
+	err := service.DeleteTransaction(username, toID)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockBudgetRepo.AssertNotCalled(t, "RecalculateSpent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTransactionService_UpdateTransaction_RejectsTransferLeg(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockCategoryRepo, _ := setUpTransactionService()
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	createTestCategory(mockCategoryRepo, user.ID, 1, "Groceries")
+	peerID := uint(21)
+	leg := createTestTransaction(user.ID, 7, 100.0, "Savings")
+	leg.ID, leg.Type, leg.TransferID = 20, models.TransactionTypeTransfer, &peerID
+
+	mockRepo.On("FindByIDForUpdate", leg.ID).Return(leg, nil)
+
+	_, err := service.UpdateTransaction(username, leg.ID, transaction.TransactionInput{CategoryID: 1, Amount: money.FromFloat(50), TransactionDate: time.Now()})
+
+	assert.ErrorIs(t, err, transaction.ErrInvalidTransfer)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestTransactionService_AddTransaction_Splits(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockCategoryRepo, mockBudgetRepo := setUpTransactionService()
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	groceries := createTestCategory(mockCategoryRepo, user.ID, 1, "Groceries")
+	household := createTestCategory(mockCategoryRepo, user.ID, 2, "Household")
+	date := time.Now()
+
+	groceriesBudget := &models.Budget{ID: 10, UserID: user.ID}
+	householdBudget := &models.Budget{ID: 11, UserID: user.ID}
+
+	expectNoDuplicateCandidates(mockRepo, user.ID)
+	mockRepo.On("Create", mock.MatchedBy(func(created *models.Transaction) bool {
+		return len(created.Splits) == 2 &&
+			created.Splits[0].BaseAmount == money.FromFloat(66.67) &&
+			created.Splits[1].BaseAmount == money.FromFloat(33.33)
+	})).Return(nil)
+	mockBudgetRepo.On("FindByUserIDAndCategoryID", user.ID, &groceries.ID, date.Month().String(), date.Year()).Return(groceriesBudget, nil)
+	mockBudgetRepo.On("FindByUserIDAndCategoryID", user.ID, &household.ID, date.Month().String(), date.Year()).Return(householdBudget, nil)
+	mockBudgetRepo.On("RecalculateSpent", groceriesBudget.ID, mock.Anything, mock.Anything).Return(nil)
+	mockBudgetRepo.On("RecalculateSpent", householdBudget.ID, mock.Anything, mock.Anything).Return(nil)
+	mockBudgetRepo.On("FindByID", groceriesBudget.ID).Return(groceriesBudget, nil)
+	mockBudgetRepo.On("FindByID", householdBudget.ID).Return(householdBudget, nil)
+	expectNoOverallBudget(mockBudgetRepo, user.ID, date)
+
+	result, err := service.AddTransaction(username, transaction.TransactionInput{
+		Amount:          money.FromFloat(100.0),
+		Description:     "Costco",
+		TransactionDate: date,
+		Splits: []transaction.SplitInput{
+			{CategoryID: groceries.ID, Amount: money.FromFloat(66.67)},
+			{CategoryID: household.ID, Amount: money.FromFloat(33.33)},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, groceries.ID, result.CategoryID)
+	mockRepo.AssertExpectations(t)
+	mockBudgetRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_AddTransaction_InvalidSplits(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockCategoryRepo, _ := setUpTransactionService()
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	createTestCategory(mockCategoryRepo, user.ID, 1, "Groceries")
+	createTestCategory(mockCategoryRepo, user.ID, 2, "Household")
+
+	for name, splits := range map[string][]transaction.SplitInput{
+		"one split":       {{CategoryID: 1, Amount: money.FromFloat(100.0)}},
+		"short of amount": {{CategoryID: 1, Amount: money.FromFloat(60.0)}, {CategoryID: 2, Amount: money.FromFloat(30.0)}},
+		"zero split":      {{CategoryID: 1, Amount: money.FromFloat(100.0)}, {CategoryID: 2, Amount: 0}},
+	} {
+		_, err := service.AddTransaction(username, transaction.TransactionInput{
+			Amount:          money.FromFloat(100.0),
+			TransactionDate: time.Now(),
+			Splits:          splits,
+		})
+		assert.ErrorIs(t, err, transaction.ErrInvalidSplit, name)
+	}
+
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestTransactionService_RejectsTransferType(t *testing.T) {
+	service, mockRepo, mockUserRepo, _, _ := setUpTransactionService()
+
+	username := "john_doe"
+	createTestUser(mockUserRepo, username, 1)
+	input := transaction.TransactionInput{
+		Type:            models.TransactionTypeTransfer,
+		Amount:          money.FromFloat(100.0),
+		TransactionDate: time.Now(),
+	}
+
+	_, err := service.AddTransaction(username, input)
+	assert.ErrorIs(t, err, transaction.ErrInvalidType)
+
+	_, err = service.UpdateTransaction(username, 10, input)
+	assert.ErrorIs(t, err, transaction.ErrInvalidType)
+
+	_, err = service.ImportTransactions(username, []transaction.TransactionInput{input})
+	assert.ErrorIs(t, err, transaction.ErrInvalidType)
+
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestTransactionService_UpdateTransaction_RemovesSplits(t *testing.T) {
+	service, mockRepo, mockUserRepo, mockCategoryRepo, mockBudgetRepo := setUpTransactionService()
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	groceries := createTestCategory(mockCategoryRepo, user.ID, 1, "Groceries")
+	household := createTestCategory(mockCategoryRepo, user.ID, 2, "Household")
+
+	tx := createTestTransaction(user.ID, groceries.ID, 100.0, "Costco")
+	tx.ID = 1
+	tx.Splits = []models.TransactionSplit{
+		{ID: 1, TransactionID: tx.ID, CategoryID: groceries.ID, Amount: money.FromFloat(70.0)},
+		{ID: 2, TransactionID: tx.ID, CategoryID: household.ID, Amount: money.FromFloat(30.0)},
+	}
+	date := tx.TransactionDate
+
+	groceriesBudget := &models.Budget{ID: 10, UserID: user.ID}
+	householdBudget := &models.Budget{ID: 11, UserID: user.ID}
+
+	mockRepo.On("FindByIDForUpdate", tx.ID).Return(tx, nil)
+	mockRepo.On("Update", mock.MatchedBy(func(updated *models.Transaction) bool {
+		return len(updated.Splits) == 0
+	})).Return(nil)
+	mockBudgetRepo.On("FindByUserIDAndCategoryID", user.ID, &groceries.ID, date.Month().String(), date.Year()).Return(groceriesBudget, nil)
+	mockBudgetRepo.On("FindByUserIDAndCategoryID", user.ID, &household.ID, date.Month().String(), date.Year()).Return(householdBudget, nil)
+	mockBudgetRepo.On("RecalculateSpent", groceriesBudget.ID, mock.Anything, mock.Anything).Return(nil)
+	mockBudgetRepo.On("RecalculateSpent", householdBudget.ID, mock.Anything, mock.Anything).Return(nil)
+	mockBudgetRepo.On("FindByID", groceriesBudget.ID).Return(groceriesBudget, nil)
+	mockBudgetRepo.On("FindByID", householdBudget.ID).Return(householdBudget, nil)
+	expectNoOverallBudget(mockBudgetRepo, user.ID, date)
+
+	_, err := service.UpdateTransaction(username, tx.ID, transaction.TransactionInput{
+		CategoryID:      groceries.ID,
+		Amount:          money.FromFloat(100.0),
+		Description:     "Costco",
+		TransactionDate: date,
+	})
+
+	// The household budget loses its share as well as groceries gaining it.
+	assert.NoError(t, err)
+	mockBudgetRepo.AssertCalled(t, "RecalculateSpent", householdBudget.ID, mock.Anything, mock.Anything)
+	mockBudgetRepo.AssertCalled(t, "RecalculateSpent", groceriesBudget.ID, mock.Anything, mock.Anything)
+}
+
+func TestTransactionService_GetWeeklySpending_FollowsUserCalendar(t *testing.T) {
+	service, mockRepo, mockUserRepo, _, _ := setUpTransactionService()
+
+	mockSettings := new(mocks.MockSettingsRepository)
+	service.Settings = &user.UserService{Settings: mockSettings}
+
+	username := "john_doe"
+	user := createTestUser(mockUserRepo, username, 1)
+	mockSettings.On("FindByUserID", user.ID).Return(&models.UserSettings{UserID: user.ID, TimeZone: "Asia/Tokyo", FirstDayOfWeek: int(time.Sunday)}, nil)
+
+	mockRepo.On("SumSpending", user.ID, mock.Anything, mock.Anything).Return(money.FromFloat(25), nil)
+
+	weeks, err := service.GetWeeklySpending(username)
+
+	assert.NoError(t, err)
+	assert.Len(t, weeks, 6)
+	for i, week := range weeks {
+		start := week.Start.In(time.UTC)
+		// Sunday midnight in Tokyo is 15:00 on Saturday in UTC.
+		assert.Equal(t, time.Saturday, start.Weekday())
+		assert.Equal(t, 15, start.Hour())
+		assert.Equal(t, money.FromFloat(25), week.TotalSpent)
+		if i > 0 {
+			assert.Equal(t, weeks[i-1].Start.AddDate(0, 0, -7), week.Start)
+		}
+	}
+	mockRepo.AssertNumberOfCalls(t, "SumSpending", 6)
+}
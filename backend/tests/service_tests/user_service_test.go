@@ -1,17 +1,24 @@
 package test
 
 import (
+	"errors"
 	"log"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/joho/godotenv"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/fx"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/notify"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/user"
 	"github.com/shaikhjunaidx/pennywise-backend/models"
 	"github.com/shaikhjunaidx/pennywise-backend/tests/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 func setupUserService() *user.UserService {
@@ -41,6 +48,142 @@ func verifyJWTToken(t *testing.T, token string, expectedSubject string) {
 	assert.Equal(t, expectedSubject, claims.Subject)
 }
 
+func setupPasswordResetService() (*user.UserService, *mocks.MockUserRepository, *mocks.MockNotifier) {
+	service := setupUserService()
+	repo := service.Repo.(*mocks.MockUserRepository)
+	repo.Emails = make(map[string]*models.User)
+	notifier := new(mocks.MockNotifier)
+	service.ResetTokens = user.NewInMemoryResetTokenStore()
+	service.Notifier = notifier
+
+	existing := &models.User{ID: 1, Username: "john_doe", Email: "john.doe@example.com", PasswordHash: "old_hash"}
+	repo.Users[existing.Username] = existing
+	repo.Emails[existing.Email] = existing
+
+	return service, repo, notifier
+}
+
+// requestResetToken requests a reset for email and returns the token that was
+// delivered through the notifier.
+func requestResetToken(t *testing.T, service *user.UserService, notifier *mocks.MockNotifier, email string) string {
+	var sent notify.Message
+	notifier.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		sent = args.Get(0).(notify.Message)
+	}).Return(nil).Once()
+
+	assert.NoError(t, service.RequestPasswordReset(email))
+	assert.Equal(t, email, sent.To)
+
+	fields := strings.Fields(sent.Body)
+	for _, field := range fields {
+		if len(field) == 32 {
+			return field
+		}
+	}
+	t.Fatalf("reset token not found in message body: %q", sent.Body)
+	return ""
+}
+
+func TestUserService_ResetPassword(t *testing.T) {
+	service, repo, notifier := setupPasswordResetService()
+	repo.On("Update", mock.Anything).Return(nil)
+
+	token := requestResetToken(t, service, notifier, "john.doe@example.com")
+
+	err := service.ResetPassword(token, "newpassword123")
+	assert.NoError(t, err)
+	assert.NoError(t, user.ComparePasswords(repo.Users["john_doe"].PasswordHash, "newpassword123"))
+
+	repo.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+}
+
+func TestUserService_ResetPassword_TokenIsSingleUse(t *testing.T) {
+	service, repo, notifier := setupPasswordResetService()
+	repo.On("Update", mock.Anything).Return(nil)
+
+	token := requestResetToken(t, service, notifier, "john.doe@example.com")
+
+	assert.NoError(t, service.ResetPassword(token, "newpassword123"))
+
+	err := service.ResetPassword(token, "anotherpassword")
+	assert.ErrorIs(t, err, user.ErrInvalidResetToken)
+}
+
+func TestUserService_ResetPassword_FailedUpdateKeepsToken(t *testing.T) {
+	service, repo, notifier := setupPasswordResetService()
+	repo.On("Update", mock.Anything).Return(errors.New("database unavailable")).Once()
+	repo.On("Update", mock.Anything).Return(nil).Once()
+
+	token := requestResetToken(t, service, notifier, "john.doe@example.com")
+
+	assert.Error(t, service.ResetPassword(token, "newpassword123"))
+	assert.NoError(t, service.ResetPassword(token, "newpassword123"))
+	repo.AssertExpectations(t)
+}
+
+func TestUserService_ResetPassword_WithConstructedService(t *testing.T) {
+	repo := &mocks.MockUserRepository{Users: make(map[string]*models.User), Emails: make(map[string]*models.User)}
+	existing := &models.User{ID: 1, Username: "john_doe", Email: "john.doe@example.com", PasswordHash: "old_hash"}
+	repo.Users[existing.Username] = existing
+	repo.Emails[existing.Email] = existing
+	repo.On("Update", mock.Anything).Return(nil)
+
+	service := user.NewUserService(repo, nil, nil)
+	notifier := new(mocks.MockNotifier)
+	service.Notifier = notifier
+
+	token := requestResetToken(t, service, notifier, existing.Email)
+
+	assert.NoError(t, service.ResetPassword(token, "newpassword123"))
+}
+
+func TestUserService_ResetPassword_InvalidToken(t *testing.T) {
+	service, _, _ := setupPasswordResetService()
+
+	err := service.ResetPassword("invalidtoken", "newpassword123")
+	assert.ErrorIs(t, err, user.ErrInvalidResetToken)
+}
+
+func TestUserService_RequestPasswordReset_UnknownEmail(t *testing.T) {
+	service, _, notifier := setupPasswordResetService()
+
+	err := service.RequestPasswordReset("nobody@example.com")
+	assert.ErrorIs(t, err, user.ErrEmailNotFound)
+	notifier.AssertNotCalled(t, "Send", mock.Anything)
+}
+
+func TestUserService_RequestPasswordReset_DeliveryFailure(t *testing.T) {
+	service, _, notifier := setupPasswordResetService()
+	notifier.On("Send", mock.Anything).Return(errors.New("smtp unavailable"))
+
+	err := service.RequestPasswordReset("john.doe@example.com")
+	assert.NoError(t, err)
+	notifier.AssertExpectations(t)
+}
+
+func TestUserService_RequestPasswordReset_NoNotifier(t *testing.T) {
+	service, _, _ := setupPasswordResetService()
+	service.Notifier = nil
+
+	err := service.RequestPasswordReset("john.doe@example.com")
+	assert.NoError(t, err)
+}
+
+func TestInMemoryResetTokenStore_ExpiredToken(t *testing.T) {
+	store := user.NewInMemoryResetTokenStore()
+
+	err := store.Save(&user.PasswordResetToken{
+		Token:     "expired",
+		UserEmail: "john.doe@example.com",
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+	assert.NoError(t, err)
+
+	_, err = store.Consume("expired")
+	assert.ErrorIs(t, err, user.ErrInvalidResetToken)
+}
+
 // func TestSignupSuccess(t *testing.T) {
 // 	service := setupUserService()
 
@@ -141,3 +284,175 @@ func verifyJWTToken(t *testing.T, token string, expectedSubject string) {
 
 // 	assert.NotNil(t, err)
 // }
+
+func setupSettingsService() (*user.UserService, *mocks.MockSettingsRepository, *models.User) {
+	service := setupUserService()
+	mockSettings := new(mocks.MockSettingsRepository)
+	service.Settings = mockSettings
+
+	existing := &models.User{ID: 7, Username: "john_doe", Email: "john.doe@example.com"}
+	service.Repo.(*mocks.MockUserRepository).Users[existing.Username] = existing
+
+	return service, mockSettings, existing
+}
+
+func TestUserService_GetSettings_Defaults(t *testing.T) {
+	service, mockSettings, existing := setupSettingsService()
+
+	mockSettings.On("FindByUserID", existing.ID).Return((*models.UserSettings)(nil), gorm.ErrRecordNotFound)
+
+	settings, err := service.GetSettings(existing.Username)
+
+	assert.NoError(t, err)
+	assert.Equal(t, existing.ID, settings.UserID)
+	assert.False(t, settings.CarryOverBudgetLimits)
+}
+
+func TestUserService_UpdateSettings(t *testing.T) {
+	service, mockSettings, existing := setupSettingsService()
+
+	carryOver := true
+	mockSettings.On("FindByUserID", existing.ID).Return((*models.UserSettings)(nil), gorm.ErrRecordNotFound)
+	mockSettings.On("Save", mock.MatchedBy(func(settings *models.UserSettings) bool {
+		return settings.UserID == existing.ID && settings.CarryOverBudgetLimits
+	})).Return(nil)
+
+	settings, err := service.UpdateSettings(existing.Username, user.SettingsUpdate{CarryOverBudgetLimits: &carryOver})
+
+	assert.NoError(t, err)
+	assert.True(t, settings.CarryOverBudgetLimits)
+	mockSettings.AssertExpectations(t)
+}
+
+func TestUserService_UpdateSettings_BaseCurrencyNotifiesListener(t *testing.T) {
+	service, mockSettings, existing := setupSettingsService()
+	listener := new(mocks.MockBaseCurrencyListener)
+	service.CurrencyChanges = listener
+
+	base := "eur"
+	mockSettings.On("FindByUserID", existing.ID).Return((*models.UserSettings)(nil), gorm.ErrRecordNotFound)
+	listener.On("BaseCurrencyChanged", mock.MatchedBy(func(settings *models.UserSettings) bool {
+		return settings.UserID == existing.ID && settings.BaseCurrency == "EUR"
+	})).Return(nil)
+
+	settings, err := service.UpdateSettings(existing.Username, user.SettingsUpdate{BaseCurrency: &base})
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.Currency("EUR"), settings.BaseCurrency)
+	listener.AssertExpectations(t)
+	// The listener saves the settings along with the converted amounts.
+	mockSettings.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestUserService_UpdateSettings_BaseCurrencyConversionFails(t *testing.T) {
+	service, mockSettings, existing := setupSettingsService()
+	listener := new(mocks.MockBaseCurrencyListener)
+	service.CurrencyChanges = listener
+
+	base := "GBP"
+	mockSettings.On("FindByUserID", existing.ID).Return((*models.UserSettings)(nil), gorm.ErrRecordNotFound)
+	listener.On("BaseCurrencyChanged", mock.Anything).Return(fx.ErrRateNotFound)
+
+	_, err := service.UpdateSettings(existing.Username, user.SettingsUpdate{BaseCurrency: &base})
+
+	assert.ErrorIs(t, err, fx.ErrRateNotFound)
+	mockSettings.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestUserService_UpdateSettings_TimeZoneRebuildsBudgets(t *testing.T) {
+	service, mockSettings, existing := setupSettingsService()
+	listener := new(mocks.MockTimeZoneListener)
+	service.TimeZoneChanges = listener
+
+	timeZone, locale := "Asia/Tokyo", "ja-JP"
+	mockSettings.On("FindByUserID", existing.ID).Return(&models.UserSettings{UserID: existing.ID, TimeZone: "Europe/Berlin"}, nil)
+	mockSettings.On("Save", mock.Anything).Return(nil)
+	listener.On("TimeZoneChanged", existing.ID).Return(errors.New("database is down")).Once()
+
+	_, err := service.UpdateSettings(existing.Username, user.SettingsUpdate{TimeZone: &timeZone})
+
+	assert.Error(t, err)
+	saved := mockSettings.Calls[len(mockSettings.Calls)-1].Arguments.Get(0).(*models.UserSettings)
+	assert.Equal(t, "Europe/Berlin", saved.TimeZone)
+
+	// Settings that leave the zone alone do not rebuild anything.
+	_, err = service.UpdateSettings(existing.Username, user.SettingsUpdate{Locale: &locale})
+
+	assert.NoError(t, err)
+	listener.AssertNumberOfCalls(t, "TimeZoneChanged", 1)
+}
+
+func TestUserService_UpdateSettings_UnknownCurrency(t *testing.T) {
+	service, mockSettings, existing := setupSettingsService()
+
+	base := "XXX"
+	mockSettings.On("FindByUserID", existing.ID).Return((*models.UserSettings)(nil), gorm.ErrRecordNotFound)
+
+	_, err := service.UpdateSettings(existing.Username, user.SettingsUpdate{BaseCurrency: &base})
+
+	assert.ErrorIs(t, err, money.ErrUnknownCurrency)
+	mockSettings.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestUserService_UpdateSettings_DuplicatePolicy(t *testing.T) {
+	service, mockSettings, existing := setupSettingsService()
+
+	window, action := 5, "SKIP"
+	mockSettings.On("FindByUserID", existing.ID).Return((*models.UserSettings)(nil), gorm.ErrRecordNotFound)
+	mockSettings.On("Save", mock.Anything).Return(nil)
+
+	settings, err := service.UpdateSettings(existing.Username, user.SettingsUpdate{DuplicateWindowDays: &window, DuplicateAction: &action})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, settings.DuplicateWindowDays)
+	assert.Equal(t, user.DuplicateActionSkip, settings.DuplicateAction)
+
+	action = "delete"
+	_, err = service.UpdateSettings(existing.Username, user.SettingsUpdate{DuplicateAction: &action})
+	assert.ErrorIs(t, err, user.ErrInvalidSettings)
+
+	window = 90
+	_, err = service.UpdateSettings(existing.Username, user.SettingsUpdate{DuplicateWindowDays: &window})
+	assert.ErrorIs(t, err, user.ErrInvalidSettings)
+}
+
+func TestUserService_UpdateSettings_Calendar(t *testing.T) {
+	service, mockSettings, existing := setupSettingsService()
+
+	timeZone, locale, firstDay := "Europe/Berlin", "de-DE", 0
+	mockSettings.On("FindByUserID", existing.ID).Return((*models.UserSettings)(nil), gorm.ErrRecordNotFound)
+	mockSettings.On("Save", mock.Anything).Return(nil)
+
+	settings, err := service.UpdateSettings(existing.Username, user.SettingsUpdate{TimeZone: &timeZone, Locale: &locale, FirstDayOfWeek: &firstDay})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", settings.TimeZone)
+	assert.Equal(t, "de-DE", settings.Locale)
+	assert.Equal(t, time.Sunday, user.WeekStart(settings))
+	assert.Equal(t, "Europe/Berlin", user.Location(settings).String())
+
+	timeZone = "Mars/Olympus_Mons"
+	_, err = service.UpdateSettings(existing.Username, user.SettingsUpdate{TimeZone: &timeZone})
+	assert.ErrorIs(t, err, user.ErrInvalidSettings)
+
+	locale = "not a locale"
+	_, err = service.UpdateSettings(existing.Username, user.SettingsUpdate{Locale: &locale})
+	assert.ErrorIs(t, err, user.ErrInvalidSettings)
+
+	firstDay = 7
+	_, err = service.UpdateSettings(existing.Username, user.SettingsUpdate{FirstDayOfWeek: &firstDay})
+	assert.ErrorIs(t, err, user.ErrInvalidSettings)
+}
+
+func TestUserService_GetSettings_DefaultCalendar(t *testing.T) {
+	service, mockSettings, existing := setupSettingsService()
+
+	mockSettings.On("FindByUserID", existing.ID).Return((*models.UserSettings)(nil), gorm.ErrRecordNotFound)
+
+	settings, err := service.GetSettings(existing.Username)
+
+	assert.NoError(t, err)
+	assert.Equal(t, user.DefaultLocale, settings.Locale)
+	assert.Equal(t, time.Monday, user.WeekStart(settings))
+	assert.Equal(t, time.Local, user.Location(settings))
+}
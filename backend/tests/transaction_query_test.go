@@ -0,0 +1,71 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/money"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionQuery_NormalizeDefaults(t *testing.T) {
+	query := transaction.TransactionQuery{}
+
+	assert.NoError(t, query.Normalize())
+	assert.Equal(t, transaction.SortByDate, query.Sort)
+	assert.True(t, query.Descending)
+	assert.Equal(t, transaction.DefaultPageSize, query.Limit)
+	assert.Equal(t, "transactions.transaction_date", query.SortColumn())
+}
+
+func TestTransactionQuery_NormalizeRejectsInvalidQueries(t *testing.T) {
+	low, high := money.FromFloat(10), money.FromFloat(5)
+	queries := []transaction.TransactionQuery{
+		{Sort: "user_id"},
+		{Limit: transaction.MaxPageSize + 1},
+		{From: time.Date(2024, 10, 2, 0, 0, 0, 0, time.Local), To: time.Date(2024, 10, 1, 0, 0, 0, 0, time.Local)},
+		{MinAmount: &low, MaxAmount: &high},
+	}
+
+	for _, query := range queries {
+		assert.ErrorIs(t, query.Normalize(), transaction.ErrInvalidQuery)
+	}
+}
+
+func TestTransactionQuery_Cursor(t *testing.T) {
+	query := transaction.TransactionQuery{Sort: transaction.SortByAmount}
+	query.Cursor = query.EncodeCursor(&transaction.TransactionResponse{ID: 42, BaseAmount: money.FromFloat(12.5)})
+
+	assert.NoError(t, query.Normalize())
+	key, id := query.CursorKey()
+	assert.Equal(t, money.FromFloat(12.5), key)
+	assert.Equal(t, uint(42), id)
+
+	// A cursor only continues the sort it was issued for.
+	reversed := query
+	reversed.Descending = true
+	assert.ErrorIs(t, reversed.Normalize(), transaction.ErrInvalidCursor)
+
+	garbage := transaction.TransactionQuery{Cursor: "not-a-cursor"}
+	assert.ErrorIs(t, garbage.Normalize(), transaction.ErrInvalidCursor)
+}
+
+func TestTransactionQuery_CursorCarriesSortKey(t *testing.T) {
+	byDescription := transaction.TransactionQuery{Sort: transaction.SortByDescription}
+	byDescription.Cursor = byDescription.EncodeCursor(&transaction.TransactionResponse{ID: 7, Description: "Rent: October"})
+
+	assert.NoError(t, byDescription.Normalize())
+	key, id := byDescription.CursorKey()
+	assert.Equal(t, "Rent: October", key)
+	assert.Equal(t, uint(7), id)
+
+	byDate := transaction.TransactionQuery{}
+	assert.NoError(t, byDate.Normalize())
+	byDate.Cursor = byDate.EncodeCursor(&transaction.TransactionResponse{ID: 9, TransactionDate: "2024-10-01T09:30:00+02:00"})
+
+	assert.NoError(t, byDate.Normalize())
+	key, id = byDate.CursorKey()
+	assert.True(t, time.Date(2024, 10, 1, 7, 30, 0, 0, time.UTC).Equal(key.(time.Time)))
+	assert.Equal(t, uint(9), id)
+}
@@ -0,0 +1,93 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shaikhjunaidx/pennywise-backend/internal/reports"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGranularity(t *testing.T) {
+	granularity, err := reports.ParseGranularity("")
+	assert.NoError(t, err)
+	assert.Equal(t, reports.GranularityMonth, granularity)
+
+	_, err = reports.ParseGranularity("fortnight")
+	assert.ErrorIs(t, err, reports.ErrInvalidGranularity)
+}
+
+func TestBuckets_WeeksStartOnMonday(t *testing.T) {
+	period := reports.Range{
+		From: time.Date(2024, 10, 3, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	buckets, err := reports.Buckets(reports.GranularityWeek, period, time.UTC, time.Monday)
+
+	assert.NoError(t, err)
+	assert.Len(t, buckets, 3)
+	assert.Equal(t, time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC), buckets[0].Start)
+	assert.Equal(t, time.Date(2024, 10, 14, 0, 0, 0, 0, time.UTC), buckets[2].Start)
+	assert.Equal(t, time.Date(2024, 10, 21, 0, 0, 0, 0, time.UTC), buckets[2].End)
+}
+
+func TestBuckets_WeeksCanStartOnSunday(t *testing.T) {
+	period := reports.Range{
+		From: time.Date(2024, 10, 3, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	buckets, err := reports.Buckets(reports.GranularityWeek, period, time.UTC, time.Sunday)
+
+	assert.NoError(t, err)
+	assert.Len(t, buckets, 3)
+	assert.Equal(t, time.Date(2024, 9, 29, 0, 0, 0, 0, time.UTC), buckets[0].Start)
+	assert.Equal(t, time.Date(2024, 10, 20, 0, 0, 0, 0, time.UTC), buckets[2].End)
+}
+
+func TestBuckets_Quarters(t *testing.T) {
+	period := reports.Range{
+		From: time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	buckets, err := reports.Buckets(reports.GranularityQuarter, period, time.UTC, time.Monday)
+
+	assert.NoError(t, err)
+	assert.Len(t, buckets, 3)
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), buckets[0].Start)
+	assert.Equal(t, time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), buckets[2].Start)
+}
+
+func TestBuckets_FollowTimeZone(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	period := reports.Range{
+		From: time.Date(2024, 3, 9, 0, 0, 0, 0, newYork),
+		To:   time.Date(2024, 3, 11, 0, 0, 0, 0, newYork),
+	}
+
+	buckets, err := reports.Buckets(reports.GranularityDay, period, newYork, time.Monday)
+
+	assert.NoError(t, err)
+	assert.Len(t, buckets, 2)
+	// The clocks went forward on March 10th, which therefore lasted 23 hours.
+	assert.Equal(t, 23*time.Hour, buckets[1].End.Sub(buckets[1].Start))
+	assert.Equal(t, time.Date(2024, 3, 10, 5, 0, 0, 0, time.UTC), buckets[1].Start.UTC())
+}
+
+func TestBuckets_RejectsTooManyBuckets(t *testing.T) {
+	period := reports.Range{
+		From: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	_, err := reports.Buckets(reports.GranularityDay, period, time.UTC, time.Monday)
+	assert.ErrorIs(t, err, reports.ErrInvalidRange)
+
+	buckets, err := reports.Buckets(reports.GranularityYear, period, time.UTC, time.Monday)
+	assert.NoError(t, err)
+	assert.Len(t, buckets, 24)
+}
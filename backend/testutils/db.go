@@ -5,7 +5,9 @@ import (
 	"log"
 	"os"
 
+	"github.com/shaikhjunaidx/pennywise-backend/internal/budget"
 	"github.com/shaikhjunaidx/pennywise-backend/internal/config"
+	"github.com/shaikhjunaidx/pennywise-backend/internal/transaction"
 	"github.com/shaikhjunaidx/pennywise-backend/models"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -61,7 +63,13 @@ func connectToTestDB(cfg *config.Config) *gorm.DB {
 }
 
 func applyMigrations(db *gorm.DB) {
-	if err := db.AutoMigrate(&models.User{}, &models.Category{}, &models.Transaction{}, &models.Budget{}); err != nil {
+	if err := db.AutoMigrate(&models.User{}, &models.Category{}, &models.Transaction{}, &models.TransactionSplit{}, &models.Budget{}, &models.PasswordResetToken{}, &models.UserSettings{}, &models.ExchangeRate{}, &models.ImportMapping{}, &models.Account{}, &models.Reconciliation{}, &models.RecurringRule{}, &models.RecurringOccurrence{}); err != nil {
 		log.Fatalf("Could not migrate database schema: %v", err)
 	}
+	if err := transaction.EnsureSearchIndex(db); err != nil {
+		log.Fatalf("Could not create transaction search index: %v", err)
+	}
+	if err := budget.EnsureUniqueIndex(db); err != nil {
+		log.Fatalf("Could not create budget period index: %v", err)
+	}
 }
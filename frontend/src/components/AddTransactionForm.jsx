@@ -1,6 +1,6 @@
 import React, { useState, useEffect } from "react";
 import { jwtDecode } from 'jwt-decode';
-import { fetchCategories } from "../utils/fetchCategories,jsx";
+import { fetchCategories, flattenCategories } from "../utils/fetchCategories,jsx";
 
 const AddTransactionForm = ({ onAddTransaction }) => {
   const [formData, setFormData] = useState({
@@ -25,7 +25,7 @@ const AddTransactionForm = ({ onAddTransaction }) => {
     const getCategories = async () => {
       try {
         const data = await fetchCategories();
-        setCategories(data);
+        setCategories(flattenCategories(data));
       } catch (error) {
         console.error("Error fetching categories:", error.message);
       }
@@ -115,7 +115,7 @@ const AddTransactionForm = ({ onAddTransaction }) => {
               <option value="">Select a category</option>
               {categories.map((category) => (
                 <option key={category.id} value={category.id}>
-                  {category.name}
+                  {"\u00a0\u00a0".repeat(category.depth) + category.name}
                 </option>
               ))}
             </select>
@@ -8,7 +8,7 @@ import BudgetSummary from "../components/BudgetSummary";
 import './BudgetSummary.css';
 import AddBudgetForm from "../components/AddCategory";
 import './AddCategory.css';
-import { fetchCategories } from "../utils/fetchCategories,jsx";
+import { fetchCategories, flattenCategories } from "../utils/fetchCategories,jsx";
 import { Line } from 'react-chartjs-2';
 import {
   Chart as ChartJS,
@@ -29,6 +29,7 @@ ChartJS.register(LineElement, PointElement, CategoryScale, LinearScale, Title, T
 const Dashboard = () => {
   const [showAll, setShowAll] = useState(false);
   const [transactions, setTransactions] = useState([]);
+  const [nextCursor, setNextCursor] = useState(null);
 
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState(null);
@@ -45,35 +46,41 @@ const Dashboard = () => {
     navigate(`/category-summary/${categoryId}`);
   };
 
-  useEffect(() => {
-    const fetchTransactions = async () => {
-      try {
-        const token = localStorage.getItem("token");
-        if (!token) {
-          throw new Error("No token found");
-        }
-
-        const response = await fetch("http://localhost:8080/api/transactions", {
-          method: "GET",
-          headers: {
-            "Authorization": `Bearer ${token}`,
-            "Content-Type": "application/json"
-          }
-        });
+  // Fetches one page of transactions. Without a cursor it starts over from
+  // the newest; with one it appends the page that follows.
+  const fetchTransactions = async (cursor) => {
+    try {
+      const token = localStorage.getItem("token");
+      if (!token) {
+        throw new Error("No token found");
+      }
 
-        if (!response.ok) {
-          throw new Error("Failed to fetch transactions");
+      const url = cursor
+        ? `http://localhost:8080/api/transactions?cursor=${encodeURIComponent(cursor)}`
+        : "http://localhost:8080/api/transactions";
+      const response = await fetch(url, {
+        method: "GET",
+        headers: {
+          "Authorization": `Bearer ${token}`,
+          "Content-Type": "application/json"
         }
+      });
 
-        const data = await response.json();
-        setTransactions(data);
-      } catch (error) {
-        setError(error.message);
-      } finally {
-        setLoading(false);
+      if (!response.ok) {
+        throw new Error("Failed to fetch transactions");
       }
-    };
 
+      const data = await response.json();
+      setTransactions((previous) => cursor ? [...previous, ...data.transactions] : data.transactions);
+      setNextCursor(data.next_cursor || null);
+    } catch (error) {
+      setError(error.message);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  useEffect(() => {
     fetchTransactions();
   }, []);
 
@@ -170,7 +177,7 @@ const Dashboard = () => {
   useEffect(() => {
     const getCategories = async () => {
       try {
-        const data = await fetchCategories();
+        const data = flattenCategories(await fetchCategories());
         setCategories(data);
         const categoryLookup = {};
         data.forEach(category => {
@@ -189,6 +196,10 @@ const Dashboard = () => {
     setShowAll(true);
   };
 
+  const handleLoadMore = () => {
+    fetchTransactions(nextCursor);
+  };
+
   const handleAddTransactionClick = () => {
     setShowAddTransForm(true); 
   };
@@ -210,7 +221,7 @@ const Dashboard = () => {
   };
 
   const displayedTransactions = showAll
-  ? (transactions || []).slice(0, 6)
+  ? (transactions || [])
   : (transactions || []).slice(0, 6);
 
   return (
@@ -281,6 +292,8 @@ const Dashboard = () => {
             <div className="buttonsContainer ">
                 {!showAll && (<button className="showMoreButton" onClick={handleShowMore}>Show More</button>
                     )}
+                {showAll && nextCursor && (<button className="showMoreButton" onClick={handleLoadMore}>Load More</button>
+                    )}
                 <button className="AddTransaction" onClick={handleAddTransactionClick}>Add Transaction</button>
           </div>
           </div>